@@ -8,6 +8,7 @@ var rootCmd = &cobra.Command{}
 
 func init() {
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(schedulerCmd)
 	rootCmd.AddCommand(migrateCmd)
 }
 
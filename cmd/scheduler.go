@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"golang-trading/internal/repository"
+	"golang-trading/internal/service"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var schedulerCmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "Run job scheduler only, without HTTP server and telegram bot",
+	Run:   StartScheduler,
+}
+
+// StartScheduler menjalankan loop scheduler sebagai proses terpisah. Beberapa proses / replika aman jalan
+// bersamaan, setiap schedule diklaim satu instance lewat lease (SCHEDULER_INSTANCE_ID).
+func StartScheduler(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	appDep, err := NewAppDependency(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create app dependency: %v", err)
+	}
+
+	repo, err := repository.NewRepository(appDep.cfg, appDep.cache, appDep.db.DB, appDep.log)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+
+	services := service.NewService(
+		appDep.cfg,
+		appDep.log,
+		repo,
+		appDep.cache,
+		appDep.telegram,
+	)
+
+	services.SchedulerService.Run(ctx)
+	log.Println("Scheduler shut down gracefully")
+
+	if err := appDep.Close(); err != nil {
+		log.Fatalf("Failed to close app dependency: %v", err)
+	}
+}
@@ -57,6 +57,7 @@ func Start(cmd *cobra.Command, args []string) {
 		services,
 		appDep.cache,
 		repo.SystemParamRepo,
+		repo.MarketDataRegistry,
 	)
 
 	apiServer := NewHTTPServer(ctx, appDep, httpHandler)
@@ -70,11 +71,29 @@ func Start(cmd *cobra.Command, args []string) {
 		telegramHandler.Start()
 	}()
 
+	priceStreamDone := make(chan struct{})
+	go func() {
+		defer close(priceStreamDone)
+		services.PriceStreamService.Run(ctx)
+	}()
+
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		if !appDep.cfg.Scheduler.Enabled {
+			appDep.log.Info("Scheduler loop disabled, jobs only run via POST /api/v1/jobs/run")
+			return
+		}
+		services.SchedulerService.Run(ctx)
+	}()
+
 	// Wait for shutdown signal
 	<-ctx.Done()
 	log.Println("Shutting down gracefully...")
 
 	telegramHandler.Stop()
+	<-priceStreamDone
+	<-schedulerDone
 
 	if err := apiServer.Stop(); err != nil {
 		log.Fatalf("Failed to stop HTTP server: %v", err)
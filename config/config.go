@@ -23,6 +23,8 @@ type Config struct {
 	Trading       Trading
 	StockAnalyzer StockAnalyzer
 	Binance       Binance
+	MarketData    MarketData
+	PriceStream   PriceStream
 }
 
 type Logger struct {
@@ -47,6 +49,11 @@ type Database struct {
 type Scheduler struct {
 	MaxConcurrency  int
 	TimeoutDuration time.Duration
+	Enabled         bool          // loop scheduler berjalan di `start`, POST /api/v1/jobs/run tetap bisa dipakai
+	PollInterval    time.Duration // interval mengecek jadwal yang jatuh tempo
+	ShutdownTimeout time.Duration // batas menunggu job yang sedang jalan saat shutdown
+	InstanceID      string        // identitas replika di history & lease schedule, kosong = hostname-pid
+	LeaseDuration   time.Duration // lease schedule yang diklaim, diperpanjang selama job jalan
 }
 
 type API struct {
@@ -84,8 +91,20 @@ type TelegramConfig struct {
 	FeatureMyPosition   TelegramFeatureMyPosition
 }
 
+// PriceStream alert harga real-time lewat websocket untuk posisi crypto dengan price alert aktif
+type PriceStream struct {
+	Enabled                     bool
+	RefreshInterval             time.Duration // interval memuat ulang posisi & subscribe ulang symbol
+	ReconnectDelay              time.Duration
+	AlertCacheDuration          time.Duration
+	AlertResendThresholdPercent float64
+}
+
 type Binance struct {
 	BaseURL             string
+	FuturesBaseURL      string // USDⓈ-M futures (fapi)
+	WSURL               string // combined stream spot, contoh wss://stream.binance.com:9443/stream
+	FuturesWSURL        string // combined stream USDⓈ-M futures
 	Timeout             time.Duration
 	MaxRequestPerMinute int
 }
@@ -94,11 +113,31 @@ type YahooFinance struct {
 	BaseURL             string
 	Timeout             time.Duration
 	MaxRequestPerMinute int
+	Exchanges           []string // exchange saham yang datanya diambil dari yahoo finance
+}
+
+type MarketData struct {
+	// REST adapter generik (format OHLCV & ticker ala CCXT) untuk exchange di luar yahoo / binance
+	REST []MarketDataREST
+	// Fallbacks urutan nama provider per exchange, contoh IDX: yahoo_finance, candle_cache, rest:idx_vendor
+	Fallbacks map[string][]string
+	// PriceDivergencePercent batas selisih market price antar provider, 0 berarti cross-check nonaktif
+	PriceDivergencePercent float64
+	// provider dilewati selama FailureCooldown setelah gagal FailureThreshold kali berturut-turut
+	FailureThreshold int
+	FailureCooldown  time.Duration
+}
+
+type MarketDataREST struct {
+	Exchange            string
+	BaseURL             string
+	Timeout             time.Duration
+	MaxRequestPerMinute int
+	Intervals           []string // kosong berarti interval default adapter
 }
 
 type TelegramFeatureStockAnalyze struct {
 	AfterTimestampDuration time.Duration
-	ExpectedTFCount        int
 }
 
 type TelegramFeatureMyPosition struct {
@@ -119,11 +158,14 @@ type Trading struct {
 	MaxBuyList             int
 	BuySignalScore         float64
 	BuySignalCacheDuration time.Duration
+	MaxHoldingWarningDays  int // kirim peringatan N hari sebelum batas max holding posisi
 }
 
 type StockAnalyzer struct {
-	MaxConcurrency int
-	Timeout        time.Duration
+	MaxConcurrency      int
+	Timeout             time.Duration
+	MinDataQualityScore float64 // analisa gagal jika skor kualitas candle di bawah nilai ini, 0 berarti nonaktif
+	ClosedBarOnly       bool    // indikator dihitung tanpa bar terakhir yang belum selesai terbentuk (sesi bursa masih jalan)
 }
 
 func Load() (*Config, error) {
@@ -165,6 +207,11 @@ func Load() (*Config, error) {
 		Scheduler: Scheduler{
 			MaxConcurrency:  viper.GetInt("SCHEDULER_MAX_CONCURRENCY"),
 			TimeoutDuration: viper.GetDuration("SCHEDULER_TIMEOUT_DURATION"),
+			Enabled:         viper.GetBool("SCHEDULER_ENABLED"),
+			PollInterval:    viper.GetDuration("SCHEDULER_POLL_INTERVAL"),
+			ShutdownTimeout: viper.GetDuration("SCHEDULER_SHUTDOWN_TIMEOUT"),
+			InstanceID:      viper.GetString("SCHEDULER_INSTANCE_ID"),
+			LeaseDuration:   viper.GetDuration("SCHEDULER_LEASE_DURATION"),
 		},
 		TradingView: TradingView{
 			BaseURLScanner:         viper.GetString("TRADINGVIEW_BASE_URL_SCANNER"),
@@ -190,7 +237,6 @@ func Load() (*Config, error) {
 			RateLimitCleanupDuration:  viper.GetDuration("TELEGRAM_RATELIMIT_CLEANUP_DURATION"),
 			FeatureStockAnalyze: TelegramFeatureStockAnalyze{
 				AfterTimestampDuration: viper.GetDuration("TELEGRAM_FEATURE_STOCK_ANALYZE_AFTER_TIMESTAMP_DURATION"),
-				ExpectedTFCount:        viper.GetInt("TELEGRAM_FEATURE_STOCK_ANALYZE_EXPECTED_TF_COUNT"),
 			},
 			TimeoutAsyncDuration: viper.GetDuration("TELEGRAM_TIMEOUT_ASYNC_DURATION"),
 			FeatureMyPosition: TelegramFeatureMyPosition{
@@ -200,13 +246,31 @@ func Load() (*Config, error) {
 		},
 		Binance: Binance{
 			BaseURL:             viper.GetString("BINANCE_BASE_URL"),
+			FuturesBaseURL:      viper.GetString("BINANCE_FUTURES_BASE_URL"),
+			WSURL:               viper.GetString("BINANCE_WS_URL"),
+			FuturesWSURL:        viper.GetString("BINANCE_FUTURES_WS_URL"),
 			Timeout:             viper.GetDuration("BINANCE_TIMEOUT"),
 			MaxRequestPerMinute: viper.GetInt("BINANCE_MAX_REQUEST_PER_MINUTE"),
 		},
+		PriceStream: PriceStream{
+			Enabled:                     viper.GetBool("PRICE_STREAM_ENABLED"),
+			RefreshInterval:             viper.GetDuration("PRICE_STREAM_REFRESH_INTERVAL"),
+			ReconnectDelay:              viper.GetDuration("PRICE_STREAM_RECONNECT_DELAY"),
+			AlertCacheDuration:          viper.GetDuration("PRICE_STREAM_ALERT_CACHE_DURATION"),
+			AlertResendThresholdPercent: viper.GetFloat64("PRICE_STREAM_ALERT_RESEND_THRESHOLD_PERCENT"),
+		},
 		YahooFinance: YahooFinance{
 			BaseURL:             viper.GetString("YAHOO_FINANCE_BASE_URL"),
 			Timeout:             viper.GetDuration("YAHOO_FINANCE_TIMEOUT"),
 			MaxRequestPerMinute: viper.GetInt("YAHOO_FINANCE_MAX_REQUEST_PER_MINUTE"),
+			Exchanges:           splitList(strings.ToUpper(viper.GetString("YAHOO_FINANCE_EXCHANGES"))),
+		},
+		MarketData: MarketData{
+			REST:                   loadMarketDataREST(),
+			Fallbacks:              loadMarketDataFallbacks(),
+			PriceDivergencePercent: viper.GetFloat64("MARKET_DATA_PRICE_DIVERGENCE_PERCENT"),
+			FailureThreshold:       viper.GetInt("MARKET_DATA_FAILURE_THRESHOLD"),
+			FailureCooldown:        viper.GetDuration("MARKET_DATA_FAILURE_COOLDOWN"),
 		},
 		Gemini: Gemini{
 			APIKey:              viper.GetString("GEMINI_API_KEY"),
@@ -221,12 +285,53 @@ func Load() (*Config, error) {
 			MaxBuyList:             viper.GetInt("TRADING_MAX_BUY_LIST"),
 			BuySignalScore:         viper.GetFloat64("TRADING_BUY_SIGNAL_SCORE"),
 			BuySignalCacheDuration: viper.GetDuration("TRADING_BUY_SIGNAL_CACHE_DURATION"),
+			MaxHoldingWarningDays:  viper.GetInt("TRADING_MAX_HOLDING_WARNING_DAYS"),
 		},
 		StockAnalyzer: StockAnalyzer{
-			MaxConcurrency: viper.GetInt("STOCK_ANALYZER_MAX_CONCURRENCY"),
-			Timeout:        viper.GetDuration("STOCK_ANALYZER_TIMEOUT"),
+			MaxConcurrency:      viper.GetInt("STOCK_ANALYZER_MAX_CONCURRENCY"),
+			Timeout:             viper.GetDuration("STOCK_ANALYZER_TIMEOUT"),
+			MinDataQualityScore: viper.GetFloat64("STOCK_ANALYZER_MIN_DATA_QUALITY_SCORE"),
+			ClosedBarOnly:       viper.GetBool("STOCK_ANALYZER_CLOSED_BAR_ONLY"),
 		},
 	}
 
 	return &cfg, nil
 }
+
+// loadMarketDataREST membaca MARKET_DATA_REST_EXCHANGES (contoh: BYBIT,OKX) lalu konfigurasi
+// per exchange dari MARKET_DATA_REST_<EXCHANGE>_BASE_URL, _TIMEOUT, _MAX_REQUEST_PER_MINUTE dan _INTERVALS
+func loadMarketDataREST() []MarketDataREST {
+	var result []MarketDataREST
+	for _, exchange := range splitList(strings.ToUpper(viper.GetString("MARKET_DATA_REST_EXCHANGES"))) {
+		prefix := "MARKET_DATA_REST_" + exchange + "_"
+		result = append(result, MarketDataREST{
+			Exchange:            exchange,
+			BaseURL:             viper.GetString(prefix + "BASE_URL"),
+			Timeout:             viper.GetDuration(prefix + "TIMEOUT"),
+			MaxRequestPerMinute: viper.GetInt(prefix + "MAX_REQUEST_PER_MINUTE"),
+			Intervals:           splitList(viper.GetString(prefix + "INTERVALS")),
+		})
+	}
+	return result
+}
+
+// loadMarketDataFallbacks membaca MARKET_DATA_FALLBACK_EXCHANGES (contoh: IDX,NASDAQ) lalu
+// urutan provider per exchange dari MARKET_DATA_FALLBACK_<EXCHANGE> (contoh: yahoo_finance,candle_cache)
+func loadMarketDataFallbacks() map[string][]string {
+	result := make(map[string][]string)
+	for _, exchange := range splitList(strings.ToUpper(viper.GetString("MARKET_DATA_FALLBACK_EXCHANGES"))) {
+		result[exchange] = splitList(strings.ToLower(viper.GetString("MARKET_DATA_FALLBACK_" + exchange)))
+	}
+	return result
+}
+
+// splitList memecah nilai env yang dipisah koma, item kosong diabaikan
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
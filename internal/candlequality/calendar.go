@@ -0,0 +1,11 @@
+package candlequality
+
+import "time"
+
+// Calendar hari bursa untuk mendeteksi gap candle, diimplementasikan tradingcalendar.Calendar
+type Calendar interface {
+	// Is24x7 true untuk market yang tidak pernah tutup (crypto)
+	Is24x7() bool
+	Location() *time.Location
+	IsTradingDay(date time.Time) bool
+}
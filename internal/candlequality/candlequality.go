@@ -0,0 +1,299 @@
+package candlequality
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/utils"
+)
+
+const (
+	// perubahan close minimal (persen) agar satu bar dianggap spike, dinaikkan jika pasar memang volatil
+	minSpikePercent = 10.0
+	// batas spike = multiplier x median perubahan close absolut per bar
+	spikeMedianMultiplier = 8.0
+	// gap market 24/7 sampai jumlah bar ini diisi bar flat, gap lebih besar hanya ditandai
+	maxGapFillBars = 2
+	// jumlah temuan yang disimpan di report
+	maxReportedIssues = 20
+)
+
+// bobot penalti score per bar bermasalah
+const (
+	penaltyDropped    = 1.0
+	penaltyRepaired   = 0.5
+	penaltySpike      = 1.0
+	penaltyDuplicate  = 0.25
+	penaltyOutOfOrder = 0.25
+	penaltyMissing    = 0.5
+	penaltyFilled     = 0.25
+)
+
+type checker struct {
+	exchange string
+	interval string
+	calendar Calendar
+	report   dto.CandleQualityReport
+}
+
+// Check memvalidasi series candle lalu mengembalikan series yang sudah diperbaiki beserta report kualitasnya:
+// timestamp diurutkan & dobel dibuang, bar NaN / nol dibuang atau direkonstruksi dari close, high / low dikoreksi,
+// spike satu bar diinterpolasi dari bar tetangga, dan gap terhadap kalender bursa ditandai (diisi untuk gap kecil 24/7).
+func Check(exchange, interval string, candles []dto.StockOHLCV, calendar Calendar) ([]dto.StockOHLCV, dto.CandleQualityReport) {
+	c := &checker{
+		exchange: exchange,
+		interval: interval,
+		calendar: calendar,
+		report:   dto.CandleQualityReport{TotalBars: len(candles)},
+	}
+	if len(candles) == 0 {
+		return candles, c.report
+	}
+
+	series := c.sortAndDedupe(candles)
+	series = c.sanitize(series)
+	c.repairSpikes(series)
+	series = c.fillGaps(series)
+	c.report.Score = c.score()
+
+	return series, c.report
+}
+
+func (c *checker) sortAndDedupe(candles []dto.StockOHLCV) []dto.StockOHLCV {
+	sorted := slices.Clone(candles)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Timestamp < sorted[i-1].Timestamp {
+			c.report.OutOfOrderBars++
+			c.addIssue(dto.CandleIssueOutOfOrder, sorted[i].Timestamp, "", true)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	// timestamp sama: bar terakhir yang menang, sama seperti merge candle tersimpan dengan hasil fetch
+	result := make([]dto.StockOHLCV, 0, len(sorted))
+	for _, bar := range sorted {
+		if n := len(result); n > 0 && result[n-1].Timestamp == bar.Timestamp {
+			result[n-1] = bar
+			c.report.DuplicateBars++
+			c.addIssue(dto.CandleIssueDuplicate, bar.Timestamp, "", true)
+			continue
+		}
+		result = append(result, bar)
+	}
+	return result
+}
+
+func (c *checker) sanitize(series []dto.StockOHLCV) []dto.StockOHLCV {
+	result := make([]dto.StockOHLCV, 0, len(series))
+	for _, bar := range series {
+		if !isValidNumber(bar.Open, bar.High, bar.Low, bar.Close, bar.Volume) {
+			c.report.DroppedBars++
+			c.addIssue(dto.CandleIssueInvalid, bar.Timestamp, "NaN, Inf or negative value", false)
+			continue
+		}
+		if bar.Close == 0 {
+			c.report.DroppedBars++
+			c.addIssue(dto.CandleIssueZeroValue, bar.Timestamp, "close is zero", false)
+			continue
+		}
+
+		repaired := false
+		if bar.Open == 0 || bar.High == 0 || bar.Low == 0 {
+			if bar.Open == 0 {
+				bar.Open = bar.Close
+			}
+			if bar.High == 0 {
+				bar.High = math.Max(bar.Open, bar.Close)
+			}
+			if bar.Low == 0 {
+				bar.Low = math.Min(bar.Open, bar.Close)
+			}
+			repaired = true
+			c.addIssue(dto.CandleIssueZeroValue, bar.Timestamp, "open/high/low rebuilt from close", true)
+		}
+
+		bodyHigh, bodyLow := math.Max(bar.Open, bar.Close), math.Min(bar.Open, bar.Close)
+		if bar.High < bodyHigh || bar.Low > bodyLow {
+			c.addIssue(dto.CandleIssueOHLCInconsistent, bar.Timestamp, fmt.Sprintf("high %.6g low %.6g outside body", bar.High, bar.Low), true)
+			bar.High = math.Max(bar.High, bodyHigh)
+			bar.Low = math.Min(bar.Low, bodyLow)
+			repaired = true
+		}
+
+		if repaired {
+			c.report.RepairedBars++
+		}
+		result = append(result, bar)
+	}
+	return result
+}
+
+// repairSpikes close yang melonjak sendirian (bar sebelum & sesudah berdekatan) diganti interpolasi,
+// wick yang jauh melampaui body dan bar tetangga dipotong
+func (c *checker) repairSpikes(series []dto.StockOHLCV) {
+	if len(series) < 3 {
+		return
+	}
+
+	changes := make([]float64, 0, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		changes = append(changes, math.Abs(series[i].Close/series[i-1].Close-1))
+	}
+	threshold := math.Max(minSpikePercent/100, spikeMedianMultiplier*median(changes))
+
+	for i := 1; i < len(series)-1; i++ {
+		prev, bar, next := series[i-1], series[i], series[i+1]
+
+		if isCloseSpike(prev.Close, bar.Close, next.Close, threshold) {
+			c.report.SpikeBars++
+			c.addIssue(dto.CandleIssueSpike, bar.Timestamp, fmt.Sprintf("close %.6g vs neighbours %.6g / %.6g", bar.Close, prev.Close, next.Close), true)
+
+			bar.Open = prev.Close
+			bar.Close = (prev.Close + next.Close) / 2
+			bar.High = math.Max(bar.Open, bar.Close)
+			bar.Low = math.Min(bar.Open, bar.Close)
+			series[i] = bar
+			continue
+		}
+
+		bodyHigh, bodyLow := math.Max(bar.Open, bar.Close), math.Min(bar.Open, bar.Close)
+		neighbourHigh, neighbourLow := math.Max(prev.High, next.High), math.Min(prev.Low, next.Low)
+		wickHigh := bar.High > bodyHigh*(1+threshold) && bar.High > neighbourHigh*(1+threshold)
+		wickLow := bar.Low < bodyLow*(1-threshold) && bar.Low < neighbourLow*(1-threshold)
+		if !wickHigh && !wickLow {
+			continue
+		}
+
+		c.report.SpikeBars++
+		c.addIssue(dto.CandleIssueSpike, bar.Timestamp, fmt.Sprintf("wick high %.6g low %.6g", bar.High, bar.Low), true)
+		if wickHigh {
+			bar.High = math.Max(bodyHigh, neighbourHigh)
+		}
+		if wickLow {
+			bar.Low = math.Min(bodyLow, neighbourLow)
+		}
+		series[i] = bar
+	}
+}
+
+func (c *checker) fillGaps(series []dto.StockOHLCV) []dto.StockOHLCV {
+	step := utils.IntervalToDuration(c.interval)
+	if step == 0 || step >= 7*24*time.Hour || len(series) < 2 {
+		return series
+	}
+
+	result := make([]dto.StockOHLCV, 0, len(series))
+	result = append(result, series[0])
+	for i := 1; i < len(series); i++ {
+		prev, bar := series[i-1], series[i]
+		prevTime := utils.CandleTimestampToTime(c.exchange, prev.Timestamp)
+		missing := c.missingBars(prevTime, utils.CandleTimestampToTime(c.exchange, bar.Timestamp), step)
+
+		if missing > 0 {
+			c.report.MissingBars += missing
+			fill := c.calendar.Is24x7() && missing <= maxGapFillBars
+			c.addIssue(dto.CandleIssueGap, prev.Timestamp, fmt.Sprintf("%d bar(s) missing after this bar", missing), fill)
+
+			for k := 1; fill && k <= missing; k++ {
+				result = append(result, dto.StockOHLCV{
+					Timestamp: utils.TimeToCandleTimestamp(c.exchange, prevTime.Add(time.Duration(k)*step)),
+					Open:      prev.Close,
+					High:      prev.Close,
+					Low:       prev.Close,
+					Close:     prev.Close,
+				})
+				c.report.FilledBars++
+			}
+		}
+		result = append(result, bar)
+	}
+	return result
+}
+
+// missingBars jumlah bar yang seharusnya ada di antara from dan to
+func (c *checker) missingBars(from, to time.Time, step time.Duration) int {
+	if c.calendar.Is24x7() {
+		return max(int(to.Sub(from)/step)-1, 0)
+	}
+
+	// sesi intraday saham (pre-opening, istirahat siang) belum dimodelkan, gap hanya dihitung untuk candle harian
+	if step < 24*time.Hour {
+		return 0
+	}
+
+	loc := c.calendar.Location()
+	fromDay := utils.TruncateDay(from.In(loc))
+	toDay := utils.TruncateDay(to.In(loc))
+
+	missing := 0
+	for day := fromDay.AddDate(0, 0, 1); day.Before(toDay); day = day.AddDate(0, 0, 1) {
+		if c.calendar.IsTradingDay(day) {
+			missing++
+		}
+	}
+	return missing
+}
+
+func (c *checker) score() float64 {
+	expected := float64(c.report.TotalBars + c.report.MissingBars)
+	if expected == 0 {
+		return 0
+	}
+
+	penalty := float64(c.report.DroppedBars)*penaltyDropped +
+		float64(c.report.RepairedBars)*penaltyRepaired +
+		float64(c.report.SpikeBars)*penaltySpike +
+		float64(c.report.DuplicateBars)*penaltyDuplicate +
+		float64(c.report.OutOfOrderBars)*penaltyOutOfOrder +
+		float64(c.report.MissingBars-c.report.FilledBars)*penaltyMissing +
+		float64(c.report.FilledBars)*penaltyFilled
+
+	score := 100 * (1 - penalty/expected)
+	return math.Round(math.Max(0, math.Min(100, score))*100) / 100
+}
+
+func (c *checker) addIssue(issueType string, timestamp int64, detail string, repaired bool) {
+	if len(c.report.Issues) >= maxReportedIssues {
+		return
+	}
+	c.report.Issues = append(c.report.Issues, dto.CandleIssue{
+		Type:      issueType,
+		Timestamp: timestamp,
+		Detail:    detail,
+		Repaired:  repaired,
+	})
+}
+
+func isCloseSpike(prev, close, next, threshold float64) bool {
+	up := close/prev-1 > threshold && close/next-1 > threshold
+	down := 1-close/prev > threshold && 1-close/next > threshold
+	return (up || down) && math.Abs(next/prev-1) < threshold/2
+}
+
+func isValidNumber(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
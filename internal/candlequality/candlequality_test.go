@@ -0,0 +1,132 @@
+package candlequality
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/internal/tradingcalendar"
+	"golang-trading/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dailyBar(day time.Time, price float64) dto.StockOHLCV {
+	return dto.StockOHLCV{Timestamp: day.Unix(), Open: price, High: price + 1, Low: price - 1, Close: price, Volume: 1000}
+}
+
+func TestCheckSortAndDedupe(t *testing.T) {
+	// senin - rabu tanpa gap
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	candles := []dto.StockOHLCV{
+		dailyBar(monday, 100),
+		dailyBar(monday.AddDate(0, 0, 2), 102),
+		dailyBar(monday.AddDate(0, 0, 1), 101),
+		dailyBar(monday.AddDate(0, 0, 1), 101.5),
+	}
+
+	series, report := Check(common.EXCHANGE_IDX, "1d", candles, tradingcalendar.DefaultCalendar(common.EXCHANGE_IDX))
+	assert.Len(t, series, 3)
+	assert.Equal(t, 101.5, series[1].Close)
+	assert.Equal(t, 1, report.OutOfOrderBars)
+	assert.Equal(t, 1, report.DuplicateBars)
+	assert.Equal(t, 0, report.MissingBars)
+	assert.Less(t, report.Score, 100.0)
+}
+
+func TestCheckSanitize(t *testing.T) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	candles := []dto.StockOHLCV{
+		dailyBar(monday, 100),
+		{Timestamp: monday.AddDate(0, 0, 1).Unix()},
+		{Timestamp: monday.AddDate(0, 0, 2).Unix(), Open: math.NaN(), High: 101, Low: 99, Close: 100},
+		{Timestamp: monday.AddDate(0, 0, 3).Unix(), Close: 101},
+		{Timestamp: monday.AddDate(0, 0, 4).Unix(), Open: 100, High: 100, Low: 99, Close: 102},
+	}
+
+	series, report := Check(common.EXCHANGE_IDX, "1d", candles, tradingcalendar.DefaultCalendar(common.EXCHANGE_IDX))
+	assert.Len(t, series, 3)
+	assert.Equal(t, 2, report.DroppedBars)
+	assert.Equal(t, 2, report.RepairedBars)
+	// bar yang dibuang terhitung gap di hari bursa
+	assert.Equal(t, 2, report.MissingBars)
+
+	assert.Equal(t, dto.StockOHLCV{Timestamp: monday.AddDate(0, 0, 3).Unix(), Open: 101, High: 101, Low: 101, Close: 101}, series[1])
+	assert.Equal(t, 102.0, series[2].High)
+}
+
+func TestCheckRepairSpike(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []float64{100, 101, 100.5, 101.5, 250, 102, 101, 102.5}
+	candles := make([]dto.StockOHLCV, 0, len(prices))
+	for i, price := range prices {
+		ts := start.Add(time.Duration(i) * time.Hour).UnixMilli()
+		candles = append(candles, dto.StockOHLCV{Timestamp: ts, Open: price, High: price, Low: price, Close: price})
+	}
+
+	series, report := Check(common.EXCHANGE_BINANCE, "1h", candles, tradingcalendar.DefaultCalendar(common.EXCHANGE_BINANCE))
+	assert.Len(t, series, len(prices))
+	assert.Equal(t, 1, report.SpikeBars)
+	assert.InDelta(t, 101.75, series[4].Close, 1e-9)
+	assert.Equal(t, 101.5, series[4].Open)
+	assert.Equal(t, dto.CandleIssueSpike, report.Issues[0].Type)
+}
+
+func TestCheckDailyGapSkipsWeekend(t *testing.T) {
+	// timestamp daily yahoo finance = jam buka bursa (09:30 New York)
+	friday := time.Date(2025, 1, 10, 14, 30, 0, 0, time.UTC)
+	candles := []dto.StockOHLCV{
+		dailyBar(friday, 100),
+		dailyBar(friday.AddDate(0, 0, 3), 101), // senin
+		dailyBar(friday.AddDate(0, 0, 5), 102), // rabu, selasa hilang
+	}
+
+	series, report := Check(common.EXCHANGE_NASDAQ, "1d", candles, tradingcalendar.DefaultCalendar(common.EXCHANGE_NASDAQ))
+	assert.Len(t, series, 3)
+	assert.Equal(t, 1, report.MissingBars)
+	assert.Equal(t, 0, report.FilledBars)
+	assert.Equal(t, dto.CandleIssueGap, report.Issues[0].Type)
+	assert.False(t, report.Issues[0].Repaired)
+}
+
+func TestCheckDailyGapSkipsHoliday(t *testing.T) {
+	idx := tradingcalendar.Default(common.EXCHANGE_IDX)
+	idx.Holidays = []string{"2025-01-28", "2025-01-29"}
+	calendar, err := tradingcalendar.New(common.EXCHANGE_IDX, idx)
+	assert.NoError(t, err)
+
+	// timestamp daily yahoo finance IDX = 09:00 WIB
+	monday := time.Date(2025, 1, 27, 2, 0, 0, 0, time.UTC)
+	candles := []dto.StockOHLCV{
+		dailyBar(monday, 100),
+		dailyBar(monday.AddDate(0, 0, 3), 101), // kamis, selasa & rabu libur imlek
+	}
+
+	_, report := Check(common.EXCHANGE_IDX, "1d", candles, calendar)
+	assert.Equal(t, 0, report.MissingBars)
+	assert.Equal(t, 100.0, report.Score)
+}
+
+func TestCheckFillCryptoGap(t *testing.T) {
+	start := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+	hours := []int{0, 1, 3, 4, 9}
+	candles := make([]dto.StockOHLCV, 0, len(hours))
+	for _, h := range hours {
+		ts := start.Add(time.Duration(h) * time.Hour).UnixMilli()
+		candles = append(candles, dto.StockOHLCV{Timestamp: ts, Open: 100, High: 101, Low: 99, Close: 100})
+	}
+
+	series, report := Check(common.EXCHANGE_BINANCE, "1h", candles, tradingcalendar.DefaultCalendar(common.EXCHANGE_BINANCE))
+	// gap 1 bar diisi, gap 4 bar hanya ditandai
+	assert.Len(t, series, len(hours)+1)
+	assert.Equal(t, start.Add(2*time.Hour).UnixMilli(), series[2].Timestamp)
+	assert.Equal(t, 5, report.MissingBars)
+	assert.Equal(t, 1, report.FilledBars)
+}
+
+func TestCheckEmpty(t *testing.T) {
+	series, report := Check(common.EXCHANGE_IDX, "1d", nil, tradingcalendar.DefaultCalendar(common.EXCHANGE_IDX))
+	assert.Empty(t, series)
+	assert.Equal(t, 0.0, report.Score)
+}
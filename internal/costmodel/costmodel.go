@@ -0,0 +1,266 @@
+package costmodel
+
+import (
+	"math"
+
+	"golang-trading/pkg/common"
+)
+
+// Model biaya transaksi, slippage, tick dan lot per exchange.
+// Semua persen dalam satuan persen (0.15 = 0.15%).
+type Model struct {
+	Exchange        string
+	Currency        string  // mata uang harga & modal, exchange berbeda mata uang tidak bisa berbagi cash
+	BuyFeePercent   float64 // fee broker saat beli
+	SellFeePercent  float64 // fee broker + pajak saat jual
+	SlippagePercent float64 // asumsi slippage setiap eksekusi (beli lebih mahal, jual lebih murah)
+	LotSize         float64 // kelipatan quantity minimal (IDX 100 lembar)
+	QuantityStep    float64 // presisi quantity untuk aset fraksional (crypto)
+	Shortable       bool    // exchange mengizinkan posisi short
+	TickSize        func(price float64) float64
+}
+
+var models = map[string]Model{
+	// fee broker IDX rata-rata 0.15% beli, 0.25% jual (termasuk PPh final 0.1%)
+	common.EXCHANGE_IDX: {
+		Exchange:        common.EXCHANGE_IDX,
+		Currency:        "IDR",
+		BuyFeePercent:   0.15,
+		SellFeePercent:  0.25,
+		SlippagePercent: 0.1,
+		LotSize:         common.IDX_LOT_SIZE,
+		TickSize:        idxTickSize,
+	},
+	// mayoritas broker US sudah zero commission, sisa SEC / TAF fee diabaikan
+	common.EXCHANGE_NASDAQ: {
+		Exchange:        common.EXCHANGE_NASDAQ,
+		Currency:        "USD",
+		SlippagePercent: 0.05,
+		LotSize:         1,
+		Shortable:       true,
+		TickSize:        fixedTick(0.01),
+	},
+	common.EXCHANGE_NYSE: {
+		Exchange:        common.EXCHANGE_NYSE,
+		Currency:        "USD",
+		SlippagePercent: 0.05,
+		LotSize:         1,
+		Shortable:       true,
+		TickSize:        fixedTick(0.01),
+	},
+	// binance spot taker fee 0.1% (short lewat margin), tick size berbeda per pair sehingga harga tidak dibulatkan
+	common.EXCHANGE_BINANCE: {
+		Exchange:        common.EXCHANGE_BINANCE,
+		Currency:        "USDT",
+		BuyFeePercent:   0.1,
+		SellFeePercent:  0.1,
+		SlippagePercent: 0.05,
+		QuantityStep:    0.000001,
+		Shortable:       true,
+	},
+	// binance USDⓈ-M futures taker fee 0.05%, funding dihitung terpisah dari data derivatif
+	common.EXCHANGE_BINANCE_FUTURES: {
+		Exchange:        common.EXCHANGE_BINANCE_FUTURES,
+		Currency:        "USDT",
+		BuyFeePercent:   0.05,
+		SellFeePercent:  0.05,
+		SlippagePercent: 0.05,
+		QuantityStep:    0.000001,
+		Shortable:       true,
+	},
+	// bybit spot taker fee 0.1%
+	common.EXCHANGE_BYBIT: {
+		Exchange:        common.EXCHANGE_BYBIT,
+		Currency:        "USDT",
+		BuyFeePercent:   0.1,
+		SellFeePercent:  0.1,
+		SlippagePercent: 0.05,
+		QuantityStep:    0.000001,
+		Shortable:       true,
+	},
+}
+
+// ForExchange mengembalikan model biaya untuk exchange, exchange yang tidak dikenal tanpa biaya & pembulatan
+// dengan mata uang dianggap milik exchange itu sendiri
+func ForExchange(exchange string) Model {
+	if m, ok := models[exchange]; ok {
+		return m
+	}
+	return Model{Exchange: exchange, Currency: exchange}
+}
+
+// idxTickSize fraksi harga saham IDX
+func idxTickSize(price float64) float64 {
+	switch {
+	case price < 200:
+		return 1
+	case price < 500:
+		return 2
+	case price < 2000:
+		return 5
+	case price < 5000:
+		return 10
+	default:
+		return 25
+	}
+}
+
+func fixedTick(tick float64) func(float64) float64 {
+	return func(float64) float64 { return tick }
+}
+
+func (m Model) tick(price float64) float64 {
+	if m.TickSize == nil || price <= 0 {
+		return 0
+	}
+	return m.TickSize(price)
+}
+
+// RoundTick membulatkan harga ke fraksi terdekat
+func (m Model) RoundTick(price float64) float64 {
+	tick := m.tick(price)
+	if tick == 0 {
+		return price
+	}
+	return roundPrecision(math.Round(price/tick) * tick)
+}
+
+// RoundTickDown membulatkan harga ke fraksi di bawahnya
+func (m Model) RoundTickDown(price float64) float64 {
+	tick := m.tick(price)
+	if tick == 0 {
+		return price
+	}
+	return roundPrecision(math.Floor(roundPrecision(price/tick)) * tick)
+}
+
+// RoundTickUp membulatkan harga ke fraksi di atasnya
+func (m Model) RoundTickUp(price float64) float64 {
+	tick := m.tick(price)
+	if tick == 0 {
+		return price
+	}
+	return roundPrecision(math.Ceil(roundPrecision(price/tick)) * tick)
+}
+
+// RoundQuantity membulatkan quantity ke bawah sesuai lot / step exchange
+func (m Model) RoundQuantity(quantity float64) float64 {
+	step := m.LotSize
+	if step == 0 {
+		step = m.QuantityStep
+	}
+	if step == 0 || quantity <= 0 {
+		return math.Max(quantity, 0)
+	}
+	return roundPrecision(math.Floor(roundPrecision(quantity/step)) * step)
+}
+
+// BuyFillPrice harga beli setelah slippage, dibulatkan ke fraksi di atasnya
+func (m Model) BuyFillPrice(price float64) float64 {
+	return m.RoundTickUp(price * (1 + m.SlippagePercent/100))
+}
+
+// SellFillPrice harga jual setelah slippage, dibulatkan ke fraksi di bawahnya
+func (m Model) SellFillPrice(price float64) float64 {
+	return m.RoundTickDown(price * (1 - m.SlippagePercent/100))
+}
+
+// EntryFillPrice harga fill saat membuka posisi: beli untuk long, jual untuk short
+func (m Model) EntryFillPrice(price float64, short bool) float64 {
+	if short {
+		return m.SellFillPrice(price)
+	}
+	return m.BuyFillPrice(price)
+}
+
+// ExitFillPrice harga fill saat menutup posisi: jual untuk long, beli kembali untuk short
+func (m Model) ExitFillPrice(price float64, short bool) float64 {
+	if short {
+		return m.BuyFillPrice(price)
+	}
+	return m.SellFillPrice(price)
+}
+
+// BuyCost total uang keluar untuk membeli quantity di price termasuk fee
+func (m Model) BuyCost(price, quantity float64) float64 {
+	return price * quantity * (1 + m.BuyFeePercent/100)
+}
+
+// SellProceeds total uang masuk dari menjual quantity di price setelah fee & pajak
+func (m Model) SellProceeds(price, quantity float64) float64 {
+	return price * quantity * (1 - m.SellFeePercent/100)
+}
+
+// NetProfitLoss P/L bersih per lembar / unit setelah fee beli & jual
+func (m Model) NetProfitLoss(buyPrice, sellPrice float64) float64 {
+	return m.SellProceeds(sellPrice, 1) - m.BuyCost(buyPrice, 1)
+}
+
+// NetProfitLossPercent P/L bersih dalam persen terhadap modal beli (termasuk fee beli)
+func (m Model) NetProfitLossPercent(buyPrice, sellPrice float64) float64 {
+	cost := m.BuyCost(buyPrice, 1)
+	if cost == 0 {
+		return 0
+	}
+	return m.NetProfitLoss(buyPrice, sellPrice) / cost * 100
+}
+
+// PositionProfitLoss P/L bersih per unit dari entry ke exit sesuai arah posisi.
+// Short: jual di entryPrice lalu beli kembali di exitPrice.
+func (m Model) PositionProfitLoss(entryPrice, exitPrice float64, short bool) float64 {
+	if short {
+		return m.SellProceeds(entryPrice, 1) - m.BuyCost(exitPrice, 1)
+	}
+	return m.NetProfitLoss(entryPrice, exitPrice)
+}
+
+// PositionProfitLossPercent P/L bersih dalam persen terhadap nilai entry termasuk fee (margin 1x untuk short)
+func (m Model) PositionProfitLossPercent(entryPrice, exitPrice float64, short bool) float64 {
+	cost := m.BuyCost(entryPrice, 1)
+	if cost == 0 {
+		return 0
+	}
+	return m.PositionProfitLoss(entryPrice, exitPrice, short) / cost * 100
+}
+
+// PositionSize hasil perhitungan ukuran posisi
+type PositionSize struct {
+	Quantity        float64 // jumlah lembar / unit, sudah dibulatkan ke lot / step
+	Lots            float64 // jumlah lot (IDX), sama dengan quantity untuk exchange tanpa lot
+	CapitalRequired float64 // modal yang dibutuhkan termasuk fee beli
+	RiskAmount      float64 // uang yang hilang jika stop loss kena, termasuk fee
+}
+
+// PositionSize menghitung quantity agar kerugian saat stop loss kena tidak melebihi riskPercent dari accountSize.
+// Stop loss di atas entry dianggap posisi short (margin 1x). Quantity dibatasi modal yang tersedia
+// dan dibulatkan ke bawah sesuai lot / step exchange, hasil kosong jika akun terlalu kecil untuk satu lot.
+func (m Model) PositionSize(accountSize, riskPercent, entry, stopLoss float64) PositionSize {
+	if accountSize <= 0 || riskPercent <= 0 || entry <= 0 || stopLoss <= 0 || stopLoss == entry {
+		return PositionSize{}
+	}
+
+	riskPerUnit := -m.PositionProfitLoss(entry, stopLoss, stopLoss > entry)
+	quantity := accountSize * riskPercent / 100 / riskPerUnit
+	quantity = math.Min(quantity, accountSize/m.BuyCost(entry, 1))
+	quantity = m.RoundQuantity(quantity)
+	if quantity == 0 {
+		return PositionSize{}
+	}
+
+	lots := quantity
+	if m.LotSize > 0 {
+		lots = roundPrecision(quantity / m.LotSize)
+	}
+
+	return PositionSize{
+		Quantity:        quantity,
+		Lots:            lots,
+		CapitalRequired: m.BuyCost(entry, quantity),
+		RiskAmount:      riskPerUnit * quantity,
+	}
+}
+
+// roundPrecision menghilangkan sisa floating point (mis. 0.1*3 = 0.30000000000000004)
+func roundPrecision(v float64) float64 {
+	return math.Round(v*1e8) / 1e8
+}
@@ -0,0 +1,55 @@
+package costmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTickIDX(t *testing.T) {
+	m := ForExchange("IDX")
+	assert.Equal(t, 199.0, m.RoundTick(199.4))
+	assert.Equal(t, 252.0, m.RoundTickDown(253.9))
+	assert.Equal(t, 1505.0, m.RoundTickUp(1501))
+	assert.Equal(t, 4990.0, m.RoundTickDown(4999))
+	assert.Equal(t, 5025.0, m.RoundTickUp(5001))
+}
+
+func TestRoundQuantity(t *testing.T) {
+	assert.Equal(t, 1200.0, ForExchange("IDX").RoundQuantity(1299.9))
+	assert.Equal(t, 0.0, ForExchange("IDX").RoundQuantity(99))
+	assert.Equal(t, 12.0, ForExchange("NASDAQ").RoundQuantity(12.7))
+	assert.Equal(t, 0.123456, ForExchange("BINANCE").RoundQuantity(0.1234567))
+}
+
+func TestNetProfitLossPercent(t *testing.T) {
+	m := ForExchange("IDX")
+	// naik 2% kotor, setelah fee 0.15% + 0.25% tersisa ~1.59%
+	assert.InDelta(t, 1.5926, m.NetProfitLossPercent(1000, 1020), 0.001)
+	assert.Equal(t, 2.0, ForExchange("UNKNOWN").NetProfitLossPercent(100, 102))
+
+	// short: jual 100 (fee 0.1%), beli kembali 90 (fee 0.1%)
+	assert.InDelta(t, 99.9-90.09, ForExchange("BINANCE").PositionProfitLoss(100, 90, true), 1e-9)
+	assert.Equal(t, -2.0, ForExchange("UNKNOWN").PositionProfitLossPercent(100, 102, true))
+}
+
+func TestPositionSize(t *testing.T) {
+	// risk 1% dari 100jt, risk per lembar 1000*1.0015 - 950*0.9975 = 53.875
+	size := ForExchange("IDX").PositionSize(100_000_000, 1, 1000, 950)
+	assert.Equal(t, 18500.0, size.Quantity)
+	assert.Equal(t, 185.0, size.Lots)
+	assert.InDelta(t, 18_527_750, size.CapitalRequired, 1e-6)
+	assert.InDelta(t, 996_687.5, size.RiskAmount, 1e-6)
+
+	// dibatasi modal: risk 5% mengizinkan 50 unit tapi modal hanya cukup 10
+	assert.Equal(t, 10.0, ForExchange("NASDAQ").PositionSize(1000, 5, 100, 99).Quantity)
+
+	// short: SL di atas entry, risk per unit 105*1.001 - 100*0.999 = 5.205
+	short := ForExchange("BINANCE").PositionSize(10_000, 1, 100, 105)
+	assert.InDelta(t, 100/5.205, short.Quantity, 0.000001)
+	assert.InDelta(t, 100, short.RiskAmount, 0.01)
+
+	// akun terlalu kecil untuk 1 lot & SL sama dengan entry
+	assert.Zero(t, ForExchange("IDX").PositionSize(50_000, 1, 1000, 950).Quantity)
+	assert.Zero(t, ForExchange("IDX").PositionSize(100_000_000, 1, 1000, 1000).Quantity)
+}
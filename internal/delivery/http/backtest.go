@@ -1,7 +1,9 @@
 package http
 
 import (
+	"errors"
 	"golang-trading/internal/dto"
+	"golang-trading/internal/service"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -10,6 +12,8 @@ import (
 func (h *HttpAPIHandler) SetupBacktest(base *echo.Group) {
 	backtestGroup := base.Group("/backtest")
 	backtestGroup.POST("", h.runBacktest)
+	backtestGroup.POST("/portfolio", h.runPortfolioBacktest)
+	backtestGroup.POST("/walk-forward", h.runWalkForward)
 }
 
 func (h *HttpAPIHandler) runBacktest(c echo.Context) error {
@@ -31,3 +35,57 @@ func (h *HttpAPIHandler) runBacktest(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, result)
 }
+
+func (h *HttpAPIHandler) runPortfolioBacktest(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(dto.PortfolioBacktestRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if len(req.Symbols) == 0 && len(req.TradingViewBuyListParams) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "symbols or tradingview_buy_list_params is required"})
+	}
+
+	result, err := h.service.BacktestService.RunPortfolioBacktest(ctx, *req)
+	if errors.Is(err, service.ErrMixedCurrencyPortfolio) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to run portfolio backtest"})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *HttpAPIHandler) runWalkForward(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(dto.WalkForwardRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := req.ValidateSearchSpace(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.service.BacktestService.RunWalkForward(ctx, *req)
+	if errors.Is(err, service.ErrInvalidWalkForwardRequest) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to run walk-forward optimization"})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -26,4 +26,5 @@ func (h *HttpAPIHandler) SetupRoutes() {
 	base := h.echo.Group("/api")
 	h.SetupJobs(base)
 	h.SetupBacktest(base)
+	h.SetupMarketData(base)
 }
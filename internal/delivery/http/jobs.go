@@ -1,8 +1,11 @@
 package http
 
 import (
+	"errors"
 	"golang-trading/internal/dto"
+	"golang-trading/internal/service"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -11,6 +14,22 @@ func (h *HttpAPIHandler) SetupJobs(base *echo.Group) {
 	v1 := base.Group("/v1/jobs")
 	{
 		v1.POST("/run", h.RunJobs)
+		v1.GET("", h.getJobs)
+		v1.POST("", h.createJob)
+		v1.GET("/histories", h.getJobHistories)
+		v1.PUT("/schedules/:scheduleID", h.updateTaskSchedule)
+		v1.DELETE("/schedules/:scheduleID", h.deleteTaskSchedule)
+		v1.GET("/:id", h.getJob)
+		v1.PUT("/:id", h.updateJob)
+		v1.DELETE("/:id", h.deleteJob)
+		v1.POST("/:id/pause", h.pauseJob)
+		v1.POST("/:id/resume", h.resumeJob)
+		v1.POST("/:id/run", h.runJob)
+		v1.POST("/:id/schedules", h.createTaskSchedule)
+		v1.GET("/:id/histories", h.getJobHistories)
+		v1.GET("/:id/dependencies", h.getJobDependencies)
+		v1.POST("/:id/dependencies", h.addJobDependency)
+		v1.DELETE("/:id/dependencies/:dependsOnID", h.removeJobDependency)
 	}
 
 }
@@ -23,3 +42,257 @@ func (h *HttpAPIHandler) RunJobs(c echo.Context) error {
 	}
 	return c.JSON(response.Code, response)
 }
+
+func (h *HttpAPIHandler) getJobs(c echo.Context) error {
+	jobs, err := h.service.JobService.GetJobs(c.Request().Context())
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+
+	data := make([]dto.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, dto.NewJobResponse(job))
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("OK", data))
+}
+
+func (h *HttpAPIHandler) getJob(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	job, err := h.service.JobService.GetJob(c.Request().Context(), id)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("OK", dto.NewJobResponse(*job)))
+}
+
+func (h *HttpAPIHandler) createJob(c echo.Context) error {
+	req := new(dto.JobRequest)
+	if err := h.bindAndValidate(c, req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	job, err := h.service.JobService.CreateJob(c.Request().Context(), *req)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, dto.NewBaseResponse(http.StatusCreated, "Job created", dto.NewJobResponse(*job)))
+}
+
+func (h *HttpAPIHandler) updateJob(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+	req := new(dto.JobRequest)
+	if err := h.bindAndValidate(c, req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	job, err := h.service.JobService.UpdateJob(c.Request().Context(), id, *req)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Job updated", dto.NewJobResponse(*job)))
+}
+
+func (h *HttpAPIHandler) deleteJob(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.DeleteJob(c.Request().Context(), id); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Job deleted", nil))
+}
+
+func (h *HttpAPIHandler) pauseJob(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.PauseJob(c.Request().Context(), id); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Job paused", nil))
+}
+
+func (h *HttpAPIHandler) resumeJob(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.ResumeJob(c.Request().Context(), id); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Job resumed", nil))
+}
+
+func (h *HttpAPIHandler) runJob(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.RunJob(c.Request().Context(), id); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusAccepted, dto.NewBaseResponse(http.StatusAccepted, "Job started", nil))
+}
+
+func (h *HttpAPIHandler) createTaskSchedule(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+	req := new(dto.TaskScheduleRequest)
+	if err := h.bindAndValidate(c, req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	schedule, err := h.service.JobService.CreateTaskSchedule(c.Request().Context(), id, *req)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, dto.NewBaseResponse(http.StatusCreated, "Task schedule created", dto.NewTaskScheduleResponse(*schedule)))
+}
+
+func (h *HttpAPIHandler) updateTaskSchedule(c echo.Context) error {
+	id, err := pathID(c, "scheduleID")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+	req := new(dto.TaskScheduleRequest)
+	if err := h.bindAndValidate(c, req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	schedule, err := h.service.JobService.UpdateTaskSchedule(c.Request().Context(), id, *req)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Task schedule updated", dto.NewTaskScheduleResponse(*schedule)))
+}
+
+func (h *HttpAPIHandler) deleteTaskSchedule(c echo.Context) error {
+	id, err := pathID(c, "scheduleID")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.DeleteTaskSchedule(c.Request().Context(), id); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Task schedule deleted", nil))
+}
+
+func (h *HttpAPIHandler) getJobDependencies(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	dependencies, err := h.service.JobService.GetJobDependencies(c.Request().Context(), id)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+
+	data := make([]dto.JobDependencyResponse, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		data = append(data, dto.NewJobDependencyResponse(dependency))
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("OK", data))
+}
+
+// addJobDependency job :id dijalankan setelah depends_on_job_id selesai, 400 jika membentuk siklus
+func (h *HttpAPIHandler) addJobDependency(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+	req := new(dto.JobDependencyRequest)
+	if err := h.bindAndValidate(c, req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.AddJobDependency(c.Request().Context(), id, req.DependsOnJobID); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, dto.NewBaseResponse(http.StatusCreated, "Job dependency created", nil))
+}
+
+func (h *HttpAPIHandler) removeJobDependency(c echo.Context) error {
+	id, err := pathID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+	dependsOnID, err := pathID(c, "dependsOnID")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+
+	if err := h.service.JobService.RemoveJobDependency(c.Request().Context(), id, dependsOnID); err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("Job dependency deleted", nil))
+}
+
+// getJobHistories history eksekusi semua job, atau job :id jika dipanggil lewat /:id/histories
+func (h *HttpAPIHandler) getJobHistories(c echo.Context) error {
+	filter := new(dto.TaskExecutionHistoryFilter)
+	if err := h.bindAndValidate(c, filter); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+	}
+	if c.Param("id") != "" {
+		id, err := pathID(c, "id")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.NewBadRequestResponse(err.Error()))
+		}
+		filter.JobID = id
+	}
+
+	histories, err := h.service.JobService.GetTaskExecutionHistories(c.Request().Context(), *filter)
+	if err != nil {
+		return jobErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("OK", histories))
+}
+
+func (h *HttpAPIHandler) bindAndValidate(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return errors.New("invalid request body")
+	}
+	return h.validator.Struct(req)
+}
+
+func pathID(c echo.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("invalid " + name)
+	}
+	return uint(id), nil
+}
+
+// jobErrorResponse status HTTP sesuai error JobService
+func jobErrorResponse(c echo.Context, err error) error {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrJobNotFound), errors.Is(err, service.ErrTaskScheduleNotFound),
+		errors.Is(err, service.ErrJobDependencyNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidJobRequest):
+		code = http.StatusBadRequest
+	case errors.Is(err, service.ErrJobAlreadyRunning):
+		code = http.StatusConflict
+	case errors.Is(err, service.ErrSchedulerBusy):
+		code = http.StatusServiceUnavailable
+	}
+	return c.JSON(code, dto.NewBaseResponse(code, err.Error(), nil))
+}
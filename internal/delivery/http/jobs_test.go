@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang-trading/internal/model"
+	"golang-trading/internal/service"
+
+	goValidator "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJobService pipeline 1 -> 2, job 1-3 ada
+type fakeJobService struct {
+	service.JobService
+	dependencies []model.JobDependency
+}
+
+func (s *fakeJobService) GetJobDependencies(ctx context.Context, id uint) ([]model.JobDependency, error) {
+	if id > 3 {
+		return nil, service.ErrJobNotFound
+	}
+	var upstream []model.JobDependency
+	for _, dependency := range s.dependencies {
+		if dependency.JobID == id {
+			upstream = append(upstream, dependency)
+		}
+	}
+	return upstream, nil
+}
+
+func (s *fakeJobService) AddJobDependency(ctx context.Context, id uint, dependsOnJobID uint) error {
+	if id > 3 {
+		return service.ErrJobNotFound
+	}
+	if id == 1 && dependsOnJobID == 2 {
+		return fmt.Errorf("%w: dependency cycle", service.ErrInvalidJobRequest)
+	}
+	s.dependencies = append(s.dependencies, model.JobDependency{JobID: id, DependsOnJobID: dependsOnJobID})
+	return nil
+}
+
+func (s *fakeJobService) RemoveJobDependency(ctx context.Context, id uint, dependsOnJobID uint) error {
+	for i, dependency := range s.dependencies {
+		if dependency.JobID == id && dependency.DependsOnJobID == dependsOnJobID {
+			s.dependencies = append(s.dependencies[:i], s.dependencies[i+1:]...)
+			return nil
+		}
+	}
+	return service.ErrJobDependencyNotFound
+}
+
+func TestJobDependencyRoutes(t *testing.T) {
+	jobService := &fakeJobService{dependencies: []model.JobDependency{{JobID: 2, DependsOnJobID: 1}}}
+	e := echo.New()
+	h := NewHttpAPIHandler(context.Background(), e, goValidator.New(), &service.Service{JobService: jobService})
+	h.SetupRoutes()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{"add", http.MethodPost, "/api/v1/jobs/3/dependencies", `{"depends_on_job_id": 2}`, http.StatusCreated},
+		{"add cycle", http.MethodPost, "/api/v1/jobs/1/dependencies", `{"depends_on_job_id": 2}`, http.StatusBadRequest},
+		{"add without upstream", http.MethodPost, "/api/v1/jobs/3/dependencies", `{}`, http.StatusBadRequest},
+		{"add to unknown job", http.MethodPost, "/api/v1/jobs/9/dependencies", `{"depends_on_job_id": 1}`, http.StatusNotFound},
+		{"list", http.MethodGet, "/api/v1/jobs/3/dependencies", "", http.StatusOK},
+		{"remove", http.MethodDelete, "/api/v1/jobs/2/dependencies/1", "", http.StatusOK},
+		{"remove missing", http.MethodDelete, "/api/v1/jobs/2/dependencies/1", "", http.StatusNotFound},
+		{"remove invalid id", http.MethodDelete, "/api/v1/jobs/2/dependencies/x", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.code, rec.Code, rec.Body.String())
+		})
+	}
+	assert.Equal(t, []model.JobDependency{{JobID: 3, DependsOnJobID: 2}}, jobService.dependencies)
+}
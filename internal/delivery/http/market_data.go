@@ -0,0 +1,20 @@
+package http
+
+import (
+	"golang-trading/internal/dto"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *HttpAPIHandler) SetupMarketData(base *echo.Group) {
+	v1 := base.Group("/v1/market-data")
+	{
+		v1.GET("/health", h.GetMarketDataHealth)
+	}
+}
+
+func (h *HttpAPIHandler) GetMarketDataHealth(c echo.Context) error {
+	health := h.service.MarketDataService.GetProviderHealth(c.Request().Context())
+	return c.JSON(http.StatusOK, dto.NewSuccessResponse("OK", health))
+}
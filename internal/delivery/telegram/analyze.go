@@ -48,7 +48,7 @@ func (t *TelegramBotHandler) showAnalysisWithLoading(ctx context.Context, c tele
 		newCtx, cancel := context.WithTimeout(t.ctx, t.cfg.Telegram.TimeoutDuration)
 		defer cancel()
 
-		latestAnalyses, err := t.service.TelegramBotService.AnalyzeStock(newCtx, c, symbol)
+		latestAnalyses, err := t.service.TelegramBotService.AnalyzeStock(t.withTimeframeProfile(newCtx, c.Sender().ID), c, symbol)
 		if err != nil {
 			close(stopChan)
 			t.log.ErrorContext(ctx, "Failed to analyze stock", logger.ErrorField(err))
@@ -150,7 +150,7 @@ func (t *TelegramBotHandler) showAnalysis(ctx context.Context, c telebot.Context
 
 	sb.WriteString("\n📊 <b><i>Rangkuman Analisis (Multi-Timeframe)</i></b>\n")
 
-	tradePlanResult, err := t.service.TradingService.CreateTradePlan(ctx, latestAnalyses)
+	tradePlanResult, err := t.service.TradingService.CreateTradePlan(t.withPositionSizing(ctx, c.Sender().ID), latestAnalyses)
 	if err != nil {
 		return err
 	}
@@ -264,7 +264,10 @@ func (t *TelegramBotHandler) showAnalysis(ctx context.Context, c telebot.Context
 		sbHeader.WriteString(fmt.Sprintf("🎯 <b>Take Profit</b>: %s (%s)\n", utils.FormatPrice(tradePlanResult.TakeProfit, exchange), utils.FormatChange(marketPrice, tradePlanResult.TakeProfit)))
 		sbHeader.WriteString(fmt.Sprintf("🛡️ <b>Stop Loss</b>: %s (%s)\n", utils.FormatPrice(tradePlanResult.StopLoss, exchange), utils.FormatChange(marketPrice, tradePlanResult.StopLoss)))
 		sbHeader.WriteString(fmt.Sprintf("🔁 <b>Risk Reward</b>: %.2f\n", tradePlanResult.RiskReward))
-		sbHeader.WriteString(fmt.Sprintf("🪧 <b>Plan: </b>%s\n", tradePlanResult.PlanType.String()))
+		if sizeText := tradePlanResult.PositionSizeText(); sizeText != "" {
+			sbHeader.WriteString(fmt.Sprintf("⚖️ <b>Size</b>: %s\n", sizeText))
+		}
+		sbHeader.WriteString(fmt.Sprintf("🪧 <b>Plan: </b>%s <i>(config %s)</i>\n", tradePlanResult.PlanType.String(), tradePlanResult.ParamsVersion))
 		sbHeader.WriteString(fmt.Sprintf("🧠 <b>Score: </b>%.2f\n", tradePlanResult.Score))
 		sbHeader.WriteString("\n<b>📝 Penjelasan Entry,SL & TP</b>\n")
 		sbHeader.WriteString(fmt.Sprintf("<b>🚀 Entry</b> %s\n", tradePlanResult.EntryReason))
@@ -306,7 +309,7 @@ func (t *TelegramBotHandler) handleBtnRefreshAnalysis(ctx context.Context, c tel
 		newCtx, cancel := context.WithTimeout(t.ctx, t.cfg.Telegram.TimeoutDuration)
 		defer cancel()
 
-		latestAnalyses, err := t.service.TelegramBotService.ExecuteStockAnalyzer(newCtx, symbol)
+		latestAnalyses, err := t.service.TelegramBotService.ExecuteStockAnalyzer(t.withTimeframeProfile(newCtx, c.Sender().ID), symbol)
 		if err != nil {
 			close(stopChan)
 			t.log.ErrorContext(ctx, "Failed to analyze stock", logger.ErrorField(err))
@@ -38,7 +38,7 @@ func (t *TelegramBotHandler) handleAskAIAnalyzer(ctx context.Context, c telebot.
 		newCtx, cancel := context.WithTimeout(t.ctx, t.cfg.Telegram.TimeoutAsyncDuration)
 		defer cancel()
 
-		analysis, err := t.service.TelegramBotService.AnalyzeStockAI(newCtx, c, symbol)
+		analysis, err := t.service.TelegramBotService.AnalyzeStockAI(t.withTimeframeProfile(newCtx, c.Sender().ID), c, symbol)
 		if err != nil {
 			close(stopChan)
 			t.log.ErrorContext(ctx, "Failed to AI analyze stock", logger.ErrorField(err))
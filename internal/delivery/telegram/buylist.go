@@ -15,23 +15,21 @@ import (
 )
 
 func (t *TelegramBotHandler) handleBuyList(ctx context.Context, c telebot.Context) error {
-	msg := `📊 <b>Pilih Exchange untuk Daftar BUY Hari Ini:</b>
+	exchanges := t.marketData.Exchanges()
 
-Silakan pilih jenis pasar yang ingin Anda lihat sinyal BUY-nya:
-
-🇮🇩 IDX — Saham Indonesia  
-📈 NASDAQ — Saham Amerika Serikat  
-💰 BINANCE — Cryptocurrency
+	sb := strings.Builder{}
+	sb.WriteString("📊 <b>Pilih Exchange untuk Daftar BUY Hari Ini:</b>\n\n")
+	sb.WriteString("Silakan pilih jenis pasar yang ingin Anda lihat sinyal BUY-nya:\n\n")
+	for _, exchange := range exchanges {
+		sb.WriteString(exchangeLabel(exchange) + "\n")
+	}
+	sb.WriteString("\nPilih salah satu tombol di bawah untuk melihat daftar rekomendasi BUY dari masing-masing exchange 👇\n")
+	msg := sb.String()
 
-Pilih salah satu tombol di bawah untuk melihat daftar rekomendasi BUY dari masing-masing exchange 👇
-	
-`
 	menu := &telebot.ReplyMarkup{}
 	rows := []telebot.Row{}
 	var tempRow []telebot.Btn
 
-	exchanges := common.GetExchangeList()
-
 	for _, exchange := range exchanges {
 		tempRow = append(tempRow, menu.Data(exchange, btnShowBuyListAnalysis.Unique, exchange))
 		if len(tempRow) == 2 {
@@ -112,7 +110,7 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru atau /
 			mapSymbolExchangeAnalysis[symbolWithExchange] = append(mapSymbolExchangeAnalysis[symbolWithExchange], analisis)
 		}
 
-		buyListResult, err := t.service.TradingService.BuyListTradePlan(newCtx, mapSymbolExchangeAnalysis)
+		buyListResult, err := t.service.TradingService.BuyListTradePlan(t.withPositionSizing(newCtx, c.Sender().ID), mapSymbolExchangeAnalysis)
 
 		time.Sleep(300 * time.Millisecond)
 		close(stopChan)
@@ -161,6 +159,9 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru atau /
 			buyListResultMsg.WriteString(fmt.Sprintf("- <b>SL:</b> %s (%s)\n", utils.FormatPrice(tradePlan.StopLoss, tradePlan.Exchange), utils.FormatChange(tradePlan.Entry, tradePlan.StopLoss)))
 			buyListResultMsg.WriteString(fmt.Sprintf("- <b>Signal:</b> %s | %s\n", dto.TASignalText(tradePlan.TechnicalSignal), dto.PositionStatus(tradePlan.Status)))
 			buyListResultMsg.WriteString(fmt.Sprintf("- <b>Score:</b> %.2f | RR: %.2f\n", tradePlan.Score, tradePlan.RiskReward))
+			if sizeText := tradePlan.PositionSizeText(); sizeText != "" {
+				buyListResultMsg.WriteString(fmt.Sprintf("- <b>Size:</b> %s\n", sizeText))
+			}
 
 			if len(buySymbolMap) >= t.cfg.Trading.MaxBuyList {
 				break
@@ -213,3 +214,22 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 
 	return nil
 }
+
+// exchangeLabel keterangan exchange di pilihan daftar BUY, exchange tanpa keterangan ditampilkan apa adanya
+func exchangeLabel(exchange string) string {
+	switch exchange {
+	case common.EXCHANGE_IDX:
+		return "🇮🇩 IDX — Saham Indonesia"
+	case common.EXCHANGE_NASDAQ:
+		return "📈 NASDAQ — Saham Amerika Serikat"
+	case common.EXCHANGE_NYSE:
+		return "🗽 NYSE — Saham Amerika Serikat"
+	case common.EXCHANGE_BINANCE:
+		return "💰 BINANCE — Cryptocurrency"
+	case common.EXCHANGE_BINANCE_FUTURES:
+		return "⚡ BINANCE_FUTURES — Crypto Futures"
+	case common.EXCHANGE_BYBIT:
+		return "💰 BYBIT — Cryptocurrency"
+	}
+	return "• " + exchange
+}
@@ -23,12 +23,16 @@ func (t *TelegramBotHandler) handleConversation(ctx context.Context, c telebot.C
 	}
 
 	switch {
-	case state >= StateWaitingSetPositionSymbol && state <= StateWaitingSetPositionAlertMonitor:
+	case state >= StateWaitingSetPositionSymbol && state <= StateWaitingSetPositionQuantity:
 		return t.handleSetPositionConversation(ctx, c)
 	case state == StateWaitingAnalyzeSymbol:
 		return t.handleAnalyzeSymbol(ctx, c)
 	case state >= StateWaitingExitPositionInputExitPrice && state <= StateWaitingExitPositionConfirm:
 		return t.handleExitPositionConversation(ctx, c)
+	case state >= StateWaitingPositionTxQuantity && state <= StateWaitingPositionTxConfirm:
+		return t.handlePositionTransactionConversation(ctx, c)
+	case state == StateWaitingSetRiskAccountSize || state == StateWaitingSetRiskPercent:
+		return t.handleSetRiskConversation(ctx, c)
 	default:
 		// If no specific conversation is matched, maybe it's a dangling state.
 		t.ResetUserState(userID)
@@ -19,6 +19,8 @@ Saya di sini untuk membantu kamu memantau saham dan mencari peluang terbaik dari
 💰 /report Melihat ringkasan hasil trading kamu berdasarkan posisi yang sudah kamu entry dan exit.
 🔄 /scheduler	- Lihat status scheduler & jalankan job secara manual  
 📡 /alertsignal - Notifikasi sinyal BUY terbaik yang dikirim otomatis sesuai jadwal oleh sistem
+⚖️ /setrisk - Atur modal & risk per trade untuk menghitung jumlah lot
+🕒 /timeframe - Pilih profil timeframe analisa (scalp, swing, position)
 
 💡 Info & Bantuan:
 🆘 /help - Lihat panduan penggunaan lengkap  
@@ -47,12 +49,15 @@ Berikut daftar perintah yang bisa kamu gunakan:
 /report - Melihat ringkasan hasil trading kamu berdasarkan posisi yang sudah kamu entry dan exit.
 /scheduler	- Lihat status scheduler & jalankan job secara manual  
 /alertsignal - Notifikasi sinyal BUY terbaik yang dikirim otomatis sesuai jadwal oleh sistem
+/setrisk - Atur modal & risk per trade, trade plan akan menampilkan jumlah lot yang disarankan
+/timeframe - Pilih profil timeframe analisa, misalnya scalp (intraday) untuk crypto atau swing / position untuk saham
 
 💡 *Tips Penggunaan:*
 1. Gunakan /analyze untuk analisa cepat atau mendalam (bisa juga langsung kirim kode saham, misalnya: 'BBCA')  
 2. Jalankan /buylist setiap pagi untuk melihat peluang baru  
 3. Setelah beli saham, gunakan /setposition agar bot bisa bantu awasi harga  
 4. Pantau semua posisi aktif kamu lewat /myposition
+5. Ambil profit sebagian atau average down lewat detail posisi di /myposition (💰 Jual Sebagian / ➕ Tambah Posisi)
 
 
 📌 Gunakan sinyal ini sebagai referensi tambahan saja, ya.  
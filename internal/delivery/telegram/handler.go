@@ -41,6 +41,8 @@ func (t *TelegramBotHandler) RegisterHandlers() {
 	t.bot.Handle("/buylist", t.WithContext(t.handleBuyList))
 	t.bot.Handle("/scheduler", t.WithContext(t.handleScheduler))
 	t.bot.Handle("/alertsignal", t.WithContext(t.handleAlertSignal))
+	t.bot.Handle("/setrisk", t.WithContext(t.handleSetRisk), t.IsOnConversationMiddleware())
+	t.bot.Handle("/timeframe", t.WithContext(t.handleTimeframe))
 
 	t.bot.Handle(telebot.OnText, t.WithContext(t.handleConversation))
 
@@ -67,6 +69,9 @@ func (t *TelegramBotHandler) RegisterHandlers() {
 	// exit position
 	t.bot.Handle(&btnExitStockPosition, t.WithContext(t.handleBtnExitStockPosition))
 	t.bot.Handle(&btnSaveExitPosition, t.WithContext(t.handleBtnSaveExitPosition))
+	t.bot.Handle(&btnPartialSellStockPosition, t.WithContext(t.handleBtnPartialSellStockPosition))
+	t.bot.Handle(&btnAddStockPosition, t.WithContext(t.handleBtnAddStockPosition))
+	t.bot.Handle(&btnSavePositionTransaction, t.WithContext(t.handleBtnSavePositionTransaction))
 
 	//buylist
 	t.bot.Handle(&btnShowBuyListAnalysis, t.WithContext(t.handleBtnShowBuyListAnalysis))
@@ -79,4 +84,7 @@ func (t *TelegramBotHandler) RegisterHandlers() {
 	// alert signal
 	t.bot.Handle(&btnAlertSignal, t.WithContext(t.handleBtnAlertSignal))
 
+	// timeframe profile
+	t.bot.Handle(&btnTimeframeProfile, t.WithContext(t.handleBtnTimeframeProfile))
+
 }
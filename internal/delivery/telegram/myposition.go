@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/pkg/cache"
@@ -65,27 +66,39 @@ func (t *TelegramBotHandler) showMyPosition(ctx context.Context, c telebot.Conte
 			marketPrice = position.StockPositionMonitorings[0].MarketPrice
 		}
 
+		// profit mengikuti arah posisi, short untung saat harga turun
+		profitPercent := 0.0
 		if marketPrice > 0 {
 			pnl = utils.FormatChange(position.BuyPrice, marketPrice)
+			profitPercent = dto.ProfitPercent(position.Side, position.BuyPrice, marketPrice)
 
-			if marketPrice >= position.BuyPrice {
+			if profitPercent >= 0 {
 				countWin++
 			} else {
 				countLose++
 			}
 
-			countPnL += utils.CalculateChangePercent(position.BuyPrice, marketPrice)
+			countPnL += profitPercent
 
 		}
 
 		iconTitle := "⚪️"
-		if marketPrice > position.BuyPrice {
+		if profitPercent > 0 {
 			iconTitle = "🟢"
-		} else if marketPrice < position.BuyPrice {
+		} else if profitPercent < 0 {
 			iconTitle = "🔴"
 		}
 		sb.WriteString(fmt.Sprintf("<b>%d. %s %s</b>\n", idx+1, iconTitle, stockWithExchange))
+		if dto.IsShort(position.Side) {
+			sb.WriteString(fmt.Sprintf("  • Side: %s\n", dto.SideText(position.Side)))
+		}
 		sb.WriteString(fmt.Sprintf("  • Buy: %s ⮕ %s (%s)\n", utils.FormatPrice(position.BuyPrice, position.Exchange), utils.FormatPrice(marketPrice, position.Exchange), pnl))
+		if ledger := dto.BuildPositionLedger(position, costmodel.ForExchange(position.Exchange)); ledger.IsTracked() && marketPrice > 0 {
+			sb.WriteString(fmt.Sprintf("  • P/L: %s (%s)\n", formatAmount(ledger.UnrealizedProfitLoss(costmodel.ForExchange(position.Exchange), marketPrice)), formatQuantity(position.Exchange, ledger.RemainingQuantity)))
+			if ledger.ClosedQuantity > 0 {
+				sb.WriteString(fmt.Sprintf("  • Realized: %s\n", formatAmount(ledger.RealizedProfitLoss)))
+			}
+		}
 		if position.TrailingProfitPrice > 0 {
 			sb.WriteString(fmt.Sprintf("  • TP: %s ⮕ %s (%s)\n", utils.FormatPrice(position.TakeProfitPrice, position.Exchange), utils.FormatPrice(position.TrailingProfitPrice, position.Exchange), utils.FormatChange(position.BuyPrice, position.TrailingProfitPrice)))
 		} else {
@@ -122,6 +135,9 @@ func (t *TelegramBotHandler) showMyPosition(ctx context.Context, c telebot.Conte
 		sb.WriteString(fmt.Sprintf("  • Score (Pos): %s\n", techScore))
 		sb.WriteString(fmt.Sprintf("  • Score (Plan): %.2f\n", position.PlanScore))
 		sb.WriteString(fmt.Sprintf("  • Signal: %s\n", signal))
+		if position.MaxHoldingDays > 0 {
+			sb.WriteString(fmt.Sprintf("  • Hold: %s\n", formatRemainingHolding(&position)))
+		}
 		sb.WriteString(fmt.Sprintf("  • Status: %s\n", techStatus))
 
 		sb.WriteString("\n")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/pkg/cache"
@@ -59,10 +60,23 @@ func (t *TelegramBotHandler) showMyPositionDetail(ctx context.Context, c telebot
 	sb.WriteString(fmt.Sprintf("<b>📌 Detail Posisi Saham %s</b>\n", stockCodeWithExchange))
 	sb.WriteString("\n")
 	sb.WriteString("<b>🧾 Informasi Posisi:</b>\n")
+	if dto.IsShort(stockPosition.Side) {
+		sb.WriteString(fmt.Sprintf("  • Side: %s\n", dto.SideText(stockPosition.Side)))
+	}
 	sb.WriteString(fmt.Sprintf("  • Buy: %s (%d Hari)\n", stockPosition.BuyDate.Format("2006-01-02"), ageDays))
+	if stockPosition.MaxHoldingDays > 0 {
+		sb.WriteString(fmt.Sprintf("  • Max Hold: %d Hari (%s)\n", stockPosition.MaxHoldingDays, formatRemainingHolding(stockPosition)))
+	}
 	sb.WriteString(fmt.Sprintf("  • Entry: %s \n", utils.FormatPrice(stockPosition.BuyPrice, exchange)))
 	sb.WriteString(fmt.Sprintf("  • Last Price: %s\n", utils.FormatPrice(marketPrice, exchange)))
-	sb.WriteString(fmt.Sprintf("  • PnL: %s\n", utils.FormatChangeWithIcon(stockPosition.BuyPrice, marketPrice)))
+	sb.WriteString(fmt.Sprintf("  • PnL: %s\n", utils.FormatChgIcon(dto.ProfitPercent(stockPosition.Side, stockPosition.BuyPrice, marketPrice))))
+	if ledger := dto.BuildPositionLedger(*stockPosition, costmodel.ForExchange(exchange)); ledger.IsTracked() {
+		sb.WriteString(fmt.Sprintf("  • Qty: %s\n", formatQuantity(exchange, ledger.RemainingQuantity)))
+		sb.WriteString(fmt.Sprintf("  • Unrealized: %s\n", formatAmount(ledger.UnrealizedProfitLoss(costmodel.ForExchange(exchange), marketPrice))))
+		if ledger.ClosedQuantity > 0 {
+			sb.WriteString(fmt.Sprintf("  • Realized: %s (%s terjual)\n", formatAmount(ledger.RealizedProfitLoss), formatQuantity(exchange, ledger.ClosedQuantity)))
+		}
+	}
 	sb.WriteString(fmt.Sprintf("  • Score (Plan): %.2f\n", stockPosition.PlanScore))
 	if stockPosition.TrailingProfitPrice > 0 {
 		sb.WriteString(fmt.Sprintf("  • TP: %s ⮕ %s (%s)\n", utils.FormatPrice(stockPosition.TakeProfitPrice, exchange), utils.FormatPrice(stockPosition.TrailingProfitPrice, exchange), utils.FormatChange(stockPosition.BuyPrice, stockPosition.TrailingProfitPrice)))
@@ -82,7 +96,10 @@ func (t *TelegramBotHandler) showMyPositionDetail(ctx context.Context, c telebot
 	btnDelete := menu.Data("🗑 Hapus Posisi", btnConfirmDeleteStockPosition.Unique, fmt.Sprintf("%d", stockPosition.ID))
 	btnRefreshAnalysis := menu.Data("🔄 Refresh Analisis", btnRefreshAnalysisPosition.Unique, fmt.Sprintf("%d", stockPosition.ID))
 
-	menu.Inline(menu.Row(btnExit, btnDelete), menu.Row(btnRefreshAnalysis, btnBack))
+	btnPartialSell := menu.Data("💰 Jual Sebagian", btnPartialSellStockPosition.Unique, fmt.Sprintf("%s|%d", stockCodeWithExchange, stockPosition.ID))
+	btnAddPosition := menu.Data("➕ Tambah Posisi", btnAddStockPosition.Unique, fmt.Sprintf("%s|%d", stockCodeWithExchange, stockPosition.ID))
+
+	menu.Inline(menu.Row(btnExit, btnDelete), menu.Row(btnPartialSell, btnAddPosition), menu.Row(btnRefreshAnalysis, btnBack))
 
 	if !isHasMonitoring {
 		sb.WriteString("\n\n<i>⚠️ Belum ada monitoring</i>")
@@ -207,7 +224,7 @@ func (t *TelegramBotHandler) showMyPositionDetail(ctx context.Context, c telebot
 		sb.WriteString(fmt.Sprintf("\n<b>(%s) - %s %s</b>\n",
 			stockPositionMonitoring.Timestamp.Format("02/01 15:04"),
 			utils.FormatPrice(stockPositionMonitoring.MarketPrice, stockPosition.Exchange),
-			utils.FormatChangeWithIcon(stockPosition.BuyPrice, float64(stockPositionMonitoring.MarketPrice)),
+			utils.FormatChgIcon(dto.ProfitPercent(stockPosition.Side, stockPosition.BuyPrice, float64(stockPositionMonitoring.MarketPrice))),
 		))
 		sb.WriteString(fmt.Sprintf("Signal: %s | %s\n", dto.Signal(evalSummary.PositionSignal), dto.PositionStatus(evalSummary.TechnicalAnalysis.Status)))
 
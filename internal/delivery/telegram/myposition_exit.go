@@ -152,7 +152,7 @@ func (t *TelegramBotHandler) handleExitPositionConversation(ctx context.Context,
 • Kode Saham   : %s 
 • Harga Exit   : %s %s  
 • Tanggal Exit : %s  
-		`, data.Symbol, utils.FormatPrice(data.ExitPrice, stockPosition[0].Exchange), utils.FormatChangeWithIcon(stockPosition[0].BuyPrice, data.ExitPrice), data.ExitDate.Format("2006-01-02"))
+		`, data.Symbol, utils.FormatPrice(data.ExitPrice, stockPosition[0].Exchange), utils.FormatChgIcon(dto.ProfitPercent(stockPosition[0].Side, stockPosition[0].BuyPrice, data.ExitPrice)), data.ExitDate.Format("2006-01-02"))
 		menu := &telebot.ReplyMarkup{}
 		btnSave := menu.Data(btnSaveExitPosition.Text, btnSaveExitPosition.Unique)
 		btnCancel := menu.Data(btnCancelGeneral.Text, btnCancelGeneral.Unique)
@@ -229,6 +229,9 @@ func (t *TelegramBotHandler) msgCurrentPosition(stockPosition *model.StockPositi
 
 	sb := strings.Builder{}
 	sb.WriteString("\n<b>Informasi Posisi Saat Ini:</b>\n")
+	if dto.IsShort(stockPosition.Side) {
+		sb.WriteString(fmt.Sprintf("• Side: %s\n", dto.SideText(stockPosition.Side)))
+	}
 	sb.WriteString(fmt.Sprintf("• Entry: %s\n", utils.FormatPrice(stockPosition.BuyPrice, stockPosition.Exchange)))
 	if stockPosition.TrailingProfitPrice > 0 {
 		sb.WriteString(fmt.Sprintf("• TP: %s ⮕ %s (%s)\n", utils.FormatPrice(stockPosition.TakeProfitPrice, stockPosition.Exchange), utils.FormatPrice(stockPosition.TrailingProfitPrice, stockPosition.Exchange), utils.FormatChange(stockPosition.BuyPrice, stockPosition.TrailingProfitPrice)))
@@ -241,7 +244,7 @@ func (t *TelegramBotHandler) msgCurrentPosition(stockPosition *model.StockPositi
 		sb.WriteString(fmt.Sprintf("• SL: %s (%s)\n", utils.FormatPrice(stockPosition.StopLossPrice, stockPosition.Exchange), utils.FormatChange(stockPosition.BuyPrice, stockPosition.StopLossPrice)))
 	}
 	sb.WriteString(fmt.Sprintf("• Buy Date: %s\n", stockPosition.BuyDate.Format("2006-01-02")))
-	sb.WriteString(fmt.Sprintf("• PnL: %s\n", utils.FormatChangeWithIcon(stockPosition.BuyPrice, marketPrice)))
+	sb.WriteString(fmt.Sprintf("• PnL: %s\n", utils.FormatChgIcon(dto.ProfitPercent(stockPosition.Side, stockPosition.BuyPrice, marketPrice))))
 	return sb.String()
 
 }
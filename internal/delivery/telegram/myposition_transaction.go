@@ -0,0 +1,305 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-trading/internal/costmodel"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/service"
+	"golang-trading/pkg/cache"
+	"golang-trading/pkg/common"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+func (t *TelegramBotHandler) handleBtnPartialSellStockPosition(ctx context.Context, c telebot.Context) error {
+	return t.startPositionTransaction(ctx, c, true)
+}
+
+func (t *TelegramBotHandler) handleBtnAddStockPosition(ctx context.Context, c telebot.Context) error {
+	return t.startPositionTransaction(ctx, c, false)
+}
+
+// startPositionTransaction memulai percakapan jual sebagian / tambah posisi (average down).
+// Jenis transaksi mengikuti side posisi: tutup posisi short berarti BUY, tambah posisi short berarti SELL.
+func (t *TelegramBotHandler) startPositionTransaction(ctx context.Context, c telebot.Context, isClose bool) error {
+	userID := c.Sender().ID
+
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+
+	stockPositionID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+
+	position, ledger, marketPrice, err := t.getPositionLedger(ctx, userID, uint(stockPositionID))
+	if err != nil {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+
+	if !ledger.IsTracked() {
+		_, err := t.telegram.Send(ctx, c, "⚠️ Posisi ini belum mencatat jumlah lot / unit, sehingga tidak bisa jual sebagian atau tambah posisi.\n\nHapus lalu catat ulang posisi dengan /setposition beserta jumlahnya.")
+		return err
+	}
+
+	txType := dto.OpenTransactionType(position.Side)
+	if isClose {
+		txType = dto.CloseTransactionType(position.Side)
+	}
+
+	t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingPositionTxQuantity, t.cfg.Cache.TelegramStateExpDuration)
+	t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), &dto.RequestPositionTransactionData{
+		Symbol:          parts[0],
+		StockPositionID: position.ID,
+		Type:            txType,
+	}, t.cfg.Cache.TelegramStateExpDuration)
+
+	sb := strings.Builder{}
+	if isClose && dto.IsShort(position.Side) {
+		sb.WriteString(fmt.Sprintf("💰 Buy back sebagian <b>%s (1/3)</b>\n", parts[0]))
+	} else if isClose {
+		sb.WriteString(fmt.Sprintf("💰 Jual sebagian <b>%s (1/3)</b>\n", parts[0]))
+	} else {
+		sb.WriteString(fmt.Sprintf("➕ Tambah posisi <b>%s (1/3)</b>\n", parts[0]))
+	}
+	sb.WriteString(t.msgCurrentPosition(position, marketPrice))
+	sb.WriteString(fmt.Sprintf("• Qty: %s (avg %s)\n\n", formatQuantity(position.Exchange, ledger.RemainingQuantity), utils.FormatPrice(ledger.AveragePrice, position.Exchange)))
+	if isClose {
+		sb.WriteString(fmt.Sprintf("Berapa %s yang di%s? Bisa juga dalam persen, contoh: <b>50%%</b>", quantityUnit(position.Exchange), transactionVerb(txType)))
+	} else {
+		sb.WriteString(fmt.Sprintf("Berapa %s yang di%s?", quantityUnit(position.Exchange), transactionVerb(txType)))
+	}
+
+	_, err = t.telegram.Edit(ctx, c, c.Message(), sb.String(), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	return err
+}
+
+func (t *TelegramBotHandler) handlePositionTransactionConversation(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	text := strings.TrimSpace(c.Text())
+	state, ok := cache.GetFromCache[int](fmt.Sprintf(UserStateKey, userID))
+	if !ok {
+		t.ResetUserState(userID)
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+
+	data, ok := cache.GetFromCache[*dto.RequestPositionTransactionData](fmt.Sprintf(UserDataKey, userID))
+	if !ok {
+		t.ResetUserState(userID)
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+
+	position, ledger, marketPrice, err := t.getPositionLedger(ctx, userID, data.StockPositionID)
+	if err != nil {
+		t.ResetUserState(userID)
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+	cost := costmodel.ForExchange(position.Exchange)
+
+	switch state {
+	case StateWaitingPositionTxQuantity:
+		quantity, err := parseTransactionQuantity(text, cost, ledger.RemainingQuantity)
+		if err != nil {
+			_, err = t.telegram.Send(ctx, c, fmt.Sprintf("Jumlah tidak valid. Masukkan angka %s (contoh: 10) atau persen (contoh: 50%%).", quantityUnit(position.Exchange)))
+			return err
+		}
+		if data.Type == dto.CloseTransactionType(position.Side) && quantity > ledger.RemainingQuantity {
+			_, err = t.telegram.Send(ctx, c, fmt.Sprintf("Jumlah %s melebihi sisa posisi (%s).", transactionVerb(data.Type), formatQuantity(position.Exchange, ledger.RemainingQuantity)))
+			return err
+		}
+
+		data.Quantity = quantity
+		t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), data, t.cfg.Cache.TelegramStateExpDuration)
+		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingPositionTxPrice, t.cfg.Cache.TelegramStateExpDuration)
+
+		_, err = t.telegram.Send(ctx, c, fmt.Sprintf("💵 Di harga berapa %s? <i>(Last Price: %s)</i>", transactionVerb(data.Type), utils.FormatPrice(marketPrice, position.Exchange)), telebot.ModeHTML)
+		return err
+
+	case StateWaitingPositionTxPrice:
+		price, err := strconv.ParseFloat(text, 64)
+		if err != nil || price <= 0 {
+			_, err = t.telegram.Send(ctx, c, "Format harga tidak valid. Silakan masukkan angka (contoh: 150).")
+			return err
+		}
+
+		data.Price = price
+		t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), data, t.cfg.Cache.TelegramStateExpDuration)
+		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingPositionTxDate, t.cfg.Cache.TelegramStateExpDuration)
+
+		_, err = t.telegram.Send(ctx, c, fmt.Sprintf("📅 Tanggal transaksinya? (contoh: %s)", utils.TimeNowWIB().Format("2006-01-02")))
+		return err
+
+	case StateWaitingPositionTxDate:
+		date, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			_, err = t.telegram.Send(ctx, c, "Format tanggal tidak valid. Silakan gunakan format YYYY-MM-DD.")
+			return err
+		}
+
+		data.Date = date
+		t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), data, t.cfg.Cache.TelegramStateExpDuration)
+		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingPositionTxConfirm, t.cfg.Cache.TelegramStateExpDuration)
+
+		return t.showPositionTransactionConfirm(ctx, c, position, ledger, data)
+
+	case StateWaitingPositionTxConfirm:
+		_, err := t.telegram.Send(ctx, c, "👆 Silakan pilih salah satu opsi di atas, atau kirim /cancel untuk membatalkan.")
+		return err
+	}
+	return nil
+}
+
+func (t *TelegramBotHandler) showPositionTransactionConfirm(ctx context.Context, c telebot.Context, position *model.StockPosition, ledger dto.PositionLedger, data *dto.RequestPositionTransactionData) error {
+	cost := costmodel.ForExchange(position.Exchange)
+
+	// simulasi ledger setelah transaksi untuk ditampilkan sebelum disimpan
+	simulated := *position
+	simulated.Transactions = append(append([]model.StockPositionTransaction{}, position.Transactions...), dto.NewPositionTransaction(cost, data.Type, data.Quantity, data.Price, data.Date))
+	after := dto.BuildPositionLedger(simulated, cost)
+
+	sb := strings.Builder{}
+	sb.WriteString("📌 <b>Mohon cek kembali transaksi kamu:</b>\n\n")
+	sb.WriteString(fmt.Sprintf("• Saham : %s\n", data.Symbol))
+	sb.WriteString(fmt.Sprintf("• Tipe  : %s\n", data.Type))
+	sb.WriteString(fmt.Sprintf("• Qty   : %s\n", formatQuantity(position.Exchange, data.Quantity)))
+	sb.WriteString(fmt.Sprintf("• Harga : %s %s\n", utils.FormatPrice(data.Price, position.Exchange), utils.FormatChgIcon(dto.ProfitPercent(position.Side, ledger.AveragePrice, data.Price))))
+	sb.WriteString(fmt.Sprintf("• Tanggal: %s\n\n", data.Date.Format("2006-01-02")))
+	sb.WriteString("<b>Setelah transaksi:</b>\n")
+	sb.WriteString(fmt.Sprintf("• Sisa Qty: %s\n", formatQuantity(position.Exchange, after.RemainingQuantity)))
+	if after.RemainingQuantity > 0 {
+		sb.WriteString(fmt.Sprintf("• Avg Price: %s ⮕ %s\n", utils.FormatPrice(ledger.AveragePrice, position.Exchange), utils.FormatPrice(after.AveragePrice, position.Exchange)))
+	}
+	isClose := data.Type == dto.CloseTransactionType(position.Side)
+	if isClose {
+		sb.WriteString(fmt.Sprintf("• Realized: %s\n", formatAmount(after.RealizedProfitLoss-ledger.RealizedProfitLoss)))
+	}
+
+	menu := &telebot.ReplyMarkup{}
+	rows := []telebot.Row{menu.Row(menu.Data(btnSavePositionTransaction.Text, btnSavePositionTransaction.Unique, "false"))}
+	if isClose && after.RemainingQuantity > 0 {
+		// sell half at TP1, sisa posisi dilindungi di harga rata-rata dan dibiarkan trailing
+		sb.WriteString("\n💡 <i>Pilih \"SL ke BEP\" untuk memindahkan stop loss ke harga rata-rata agar sisa posisi tidak berubah jadi rugi.</i>")
+		rows = append(rows, menu.Row(menu.Data("💾 Simpan & SL ke BEP", btnSavePositionTransaction.Unique, "true")))
+	}
+	rows = append(rows, menu.Row(menu.Data(btnCancelGeneral.Text, btnCancelGeneral.Unique)))
+	menu.Inline(rows...)
+
+	_, err := t.telegram.Send(ctx, c, sb.String(), menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotHandler) handleBtnSavePositionTransaction(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	defer t.ResetUserState(userID)
+
+	state, _ := cache.GetFromCache[int](fmt.Sprintf(UserStateKey, userID))
+	data, ok := cache.GetFromCache[*dto.RequestPositionTransactionData](fmt.Sprintf(UserDataKey, userID))
+	if state != StateWaitingPositionTxConfirm || !ok {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
+		return err
+	}
+	data.MoveStopLossToBreakEven = c.Data() == "true"
+
+	ledger, err := t.service.TelegramBotService.AddPositionTransaction(ctx, userID, data)
+	if err != nil {
+		t.log.ErrorContext(ctx, "Failed to add position transaction", logger.ErrorField(err))
+		msg := commonErrorInternalMyPosition
+		if errors.Is(err, service.ErrPositionQuantityNotTracked) {
+			msg = "⚠️ Posisi ini belum mencatat jumlah lot / unit."
+		}
+		_, err = t.telegram.Edit(ctx, c, c.Message(), msg)
+		return err
+	}
+
+	exchange := strings.Split(data.Symbol, ":")[0]
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("✅ Transaksi %s %s berhasil disimpan.\n\n", data.Type, data.Symbol))
+	if ledger.RemainingQuantity > 0 {
+		sb.WriteString(fmt.Sprintf("• Sisa Qty: %s\n", formatQuantity(exchange, ledger.RemainingQuantity)))
+		sb.WriteString(fmt.Sprintf("• Avg Price: %s\n", utils.FormatPrice(ledger.AveragePrice, exchange)))
+	} else {
+		sb.WriteString("• Posisi sudah ditutup seluruhnya.\n")
+	}
+	if ledger.ClosedQuantity > 0 {
+		sb.WriteString(fmt.Sprintf("• Total Realized: %s\n", formatAmount(ledger.RealizedProfitLoss)))
+	}
+	if data.MoveStopLossToBreakEven {
+		sb.WriteString("• Stop loss dipindahkan ke harga rata-rata (BEP).\n")
+	}
+
+	_, err = t.telegram.Edit(ctx, c, c.Message(), sb.String())
+	return err
+}
+
+func (t *TelegramBotHandler) getPositionLedger(ctx context.Context, telegramID int64, stockPositionID uint) (*model.StockPosition, dto.PositionLedger, float64, error) {
+	positions, err := t.service.TelegramBotService.GetStockPositions(ctx, dto.GetStockPositionsParam{
+		TelegramID: &telegramID,
+		IDs:        []uint{stockPositionID},
+		IsActive:   utils.ToPointer(true),
+	})
+	if err != nil {
+		return nil, dto.PositionLedger{}, 0, err
+	}
+	if len(positions) == 0 {
+		return nil, dto.PositionLedger{}, 0, fmt.Errorf("position not found")
+	}
+
+	position := &positions[0]
+	marketPrice, _ := cache.GetFromCache[float64](fmt.Sprintf(common.KEY_LAST_PRICE, position.Exchange+":"+position.StockCode))
+	if marketPrice == 0 {
+		marketPrice = position.BuyPrice
+	}
+	return position, dto.BuildPositionLedger(*position, costmodel.ForExchange(position.Exchange)), marketPrice, nil
+}
+
+// parseTransactionQuantity membaca jumlah lot / unit, atau persen dari sisa posisi (mis. "50%")
+func parseTransactionQuantity(text string, cost costmodel.Model, remaining float64) (float64, error) {
+	if strings.HasSuffix(text, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return 0, fmt.Errorf("invalid percent")
+		}
+		quantity := cost.RoundQuantity(remaining * percent / 100)
+		if quantity <= 0 {
+			return 0, fmt.Errorf("quantity below lot size")
+		}
+		return quantity, nil
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid quantity")
+	}
+	if cost.LotSize > 0 {
+		value *= cost.LotSize
+	}
+	quantity := cost.RoundQuantity(value)
+	if quantity <= 0 {
+		return 0, fmt.Errorf("quantity below lot size")
+	}
+	return quantity, nil
+}
+
+// transactionVerb kata kerja transaksi untuk pesan: "jual" / "beli"
+func transactionVerb(txType string) string {
+	if txType == model.StockPositionTransactionTypeBuy {
+		return "beli"
+	}
+	return "jual"
+}
@@ -3,10 +3,12 @@ package telegram
 import (
 	"context"
 	"fmt"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/pkg/logger"
 	"golang-trading/pkg/utils"
+	"strconv"
 	"strings"
 
 	"gopkg.in/telebot.v3"
@@ -68,30 +70,52 @@ func (t *TelegramBotHandler) showReport(ctx context.Context, c telebot.Context,
 	countWin := 0
 	countLose := 0
 	countPnL := 0.0
+	// P/L nominal per exchange (mata uang berbeda), hanya posisi yang mencatat quantity
+	pnlAmounts := map[string]float64{}
+	exchanges := []string{}
 	for _, position := range positions {
 		if position.ExitPrice == nil {
 			continue
 		}
 
-		if *position.ExitPrice >= position.BuyPrice {
+		// P/L bersih setelah fee broker & pajak jual
+		cost := costmodel.ForExchange(position.Exchange)
+		netPnL := cost.PositionProfitLossPercent(position.BuyPrice, *position.ExitPrice, dto.IsShort(position.Side))
+		ledger := dto.BuildPositionLedger(position, cost)
+		if ledger.IsTracked() {
+			// termasuk partial exit & average down
+			netPnL = ledger.RealizedProfitLossPercent()
+		}
+		if netPnL > 0 {
 			countWin++
 		} else {
 			countLose++
 		}
 
-		countPnL += utils.CalculateChangePercent(position.BuyPrice, *position.ExitPrice)
+		countPnL += netPnL
 
 		symbolWithExchange := fmt.Sprintf("%s:%s", position.Exchange, position.StockCode)
 		sbBody.WriteString(fmt.Sprintf("\n<b>─ %s</b>\n", symbolWithExchange))
 		sbBody.WriteString(fmt.Sprintf("- Date: %s - %s\n", position.BuyDate.Format("01/02"), position.ExitDate.Format("01/02")))
-		sbBody.WriteString(fmt.Sprintf("- E/X: %d ⮕ %d %s\n", int(position.BuyPrice), int(*position.ExitPrice), utils.FormatChangeWithIcon(position.BuyPrice, *position.ExitPrice)))
+		sbBody.WriteString(fmt.Sprintf("- E/X: %d ⮕ %d %s\n", int(position.BuyPrice), int(*position.ExitPrice), utils.FormatChgIcon(dto.ProfitPercent(position.Side, position.BuyPrice, *position.ExitPrice))))
+		sbBody.WriteString(fmt.Sprintf("- Net (after fee): %s\n", utils.FormatChgIcon(netPnL)))
+		if ledger.IsTracked() {
+			if _, ok := pnlAmounts[position.Exchange]; !ok {
+				exchanges = append(exchanges, position.Exchange)
+			}
+			pnlAmounts[position.Exchange] += ledger.RealizedProfitLoss
+			sbBody.WriteString(fmt.Sprintf("- P/L: %s (%s)\n", formatAmount(ledger.RealizedProfitLoss), formatQuantity(position.Exchange, ledger.ClosedQuantity)))
+		}
 		sbBody.WriteString(fmt.Sprintf("- Score (Pos): %.2f ⮕ %.2f\n", position.InitialScore, position.FinalScore))
 		sbBody.WriteString(fmt.Sprintf("- Score (Plan): %.2f\n", position.PlanScore))
 	}
 
 	sbSummary := &strings.Builder{}
 	sbSummary.WriteString(fmt.Sprintf("\n🟢 <b>Win</b>: %d | 🔴 Lose: %d", countWin, countLose))
-	sbSummary.WriteString(fmt.Sprintf("\n📈 <b>Total PnL</b>: %s", utils.FormatChgIcon(countPnL)))
+	sbSummary.WriteString(fmt.Sprintf("\n📈 <b>Total PnL (net)</b>: %s", utils.FormatChgIcon(countPnL)))
+	for _, exchange := range exchanges {
+		sbSummary.WriteString(fmt.Sprintf("\n💵 <b>Total P/L %s</b>: %s", exchange, formatAmount(pnlAmounts[exchange])))
+	}
 	sbSummary.WriteString(fmt.Sprintf("\n🏆 <b>Win Rate</b>: %.2f%%", float64(countWin)/float64(len(positions))*100))
 
 	result := fmt.Sprintf("%s%s%s", sb.String(), sbSummary.String(), sbBody.String())
@@ -102,3 +126,36 @@ func (t *TelegramBotHandler) showReport(ctx context.Context, c telebot.Context,
 	}
 	return nil
 }
+
+// formatAmount nominal uang dengan tanda +/- (mis. +1.2M, -350.0K)
+func formatAmount(amount float64) string {
+	if amount < 0 {
+		return "🔴 -" + utils.FormatVolume(-amount)
+	}
+	return "🟢 +" + utils.FormatVolume(amount)
+}
+
+// formatRemainingHolding sisa hari sebelum batas max holding posisi
+func formatRemainingHolding(position *model.StockPosition) string {
+	remaining := utils.RemainingDays(position.MaxHoldingDays, position.BuyDate)
+	if remaining <= 0 {
+		return "⏰ lewat batas hold"
+	}
+	return fmt.Sprintf("sisa %d hari", remaining)
+}
+
+// quantityUnit satuan input quantity, lot untuk exchange yang memakai lot (IDX)
+func quantityUnit(exchange string) string {
+	if costmodel.ForExchange(exchange).LotSize > 1 {
+		return "lot"
+	}
+	return "unit"
+}
+
+// formatQuantity quantity dalam satuan input user (lot / unit)
+func formatQuantity(exchange string, quantity float64) string {
+	if lotSize := costmodel.ForExchange(exchange).LotSize; lotSize > 1 {
+		quantity /= lotSize
+	}
+	return strconv.FormatFloat(quantity, 'f', -1, 64) + " " + quantityUnit(exchange)
+}
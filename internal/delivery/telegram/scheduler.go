@@ -2,8 +2,10 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"golang-trading/internal/model"
+	"golang-trading/internal/service"
 	"golang-trading/pkg/logger"
 	"golang-trading/pkg/utils"
 	"strconv"
@@ -101,26 +103,26 @@ func (t *TelegramBotHandler) handleBtnDetailJob(ctx context.Context, c telebot.C
 	msg.WriteString("📜 Riwayat Eksekusi Terakhir:\n")
 	for idx, history := range job.Histories {
 
-		icon := "🟢"
-		if history.Status == model.StatusRunning {
-			icon = "🟡"
-		} else if history.Status == model.StatusCompleted {
-			icon = "🟢"
-		} else if history.Status == model.StatusFailed {
-			icon = "🔴"
-		} else if history.Status == model.StatusTimeout {
-			icon = "🟠"
+		icon := taskStatusIcon(history.Status)
+
+		status := strings.ToUpper(string(history.Status))
+		if history.Attempt > 1 {
+			status = fmt.Sprintf("%s (retry %d)", status, history.Attempt-1)
 		}
 
 		if history.CreatedAt.IsZero() {
 			continue
 		}
 		if !history.CompletedAt.Valid {
-			msg.WriteString(fmt.Sprintf("%d. %s %s - %s\n", idx+1, icon, utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), strings.ToUpper(string(history.Status))))
+			msg.WriteString(fmt.Sprintf("%d. %s %s - %s\n", idx+1, icon, utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), status))
 			continue
 		}
 		duration := history.CompletedAt.Time.Sub(history.StartedAt)
-		msg.WriteString(fmt.Sprintf("%d. %s %s - %d | %s (%.1fs)\n", idx+1, icon, utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), history.ExitCode.Int32, strings.ToUpper(string(history.Status)), duration.Seconds()))
+		msg.WriteString(fmt.Sprintf("%d. %s %s - %d | %s (%.1fs)\n", idx+1, icon, utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), history.ExitCode.Int32, status, duration.Seconds()))
+		// step pipeline yang dipicu eksekusi ini
+		for _, child := range history.Children {
+			msg.WriteString(fmt.Sprintf("    ↳ %s Job #%d - %s\n", taskStatusIcon(child.Status), child.JobID, strings.ToUpper(string(child.Status))))
+		}
 	}
 
 	menu := &telebot.ReplyMarkup{}
@@ -157,7 +159,15 @@ func (t *TelegramBotHandler) handleBtnActionRunJob(ctx context.Context, c telebo
 		return err
 	}
 
-	if err := t.service.SchedulerService.RunJobTask(ctx, uint(job[0].ID)); err != nil {
+	err = t.service.SchedulerService.RunJobTask(ctx, uint(job[0].ID))
+	switch {
+	case errors.Is(err, service.ErrJobAlreadyRunning):
+		_, err = t.telegram.Send(ctx, c, "Job sedang berjalan, coba lagi setelah selesai.")
+		return err
+	case errors.Is(err, service.ErrSchedulerBusy):
+		_, err = t.telegram.Send(ctx, c, "Semua slot scheduler sedang terpakai, coba lagi nanti.")
+		return err
+	case err != nil:
 		t.log.ErrorContext(ctx, "failed to run job task", logger.ErrorField(err))
 		_, err = t.telegram.Send(ctx, c, commonErrorInternalMyPosition)
 		return err
@@ -168,3 +178,20 @@ func (t *TelegramBotHandler) handleBtnActionRunJob(ctx context.Context, c telebo
 func (t *TelegramBotHandler) handleBtnActionBackToJobList(ctx context.Context, c telebot.Context) error {
 	return t.handleScheduler(ctx, c)
 }
+
+func taskStatusIcon(status model.TaskExecutionStatus) string {
+	switch status {
+	case model.StatusRunning:
+		return "🟡"
+	case model.StatusFailed:
+		return "🔴"
+	case model.StatusTimeout:
+		return "🟠"
+	case model.StatusDeadLetter:
+		return "⚫"
+	case model.StatusSkipped:
+		return "⚪"
+	default:
+		return "🟢"
+	}
+}
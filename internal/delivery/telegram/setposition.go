@@ -2,10 +2,14 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
+	"golang-trading/internal/service"
 	"golang-trading/pkg/cache"
+	"golang-trading/pkg/logger"
 	"golang-trading/pkg/utils"
 	"strconv"
 	"strings"
@@ -52,9 +56,11 @@ func (t *TelegramBotHandler) handleSetPositionConversation(ctx context.Context,
 		stockCode, exchange, err := utils.ParseStockSymbol(strings.ToUpper(text))
 		if err != nil {
 			_, err = t.telegram.Send(ctx, c, "Format kode saham tidak valid. Silakan masukkan kode saham dan exchange (contoh: IDX:ANTM, NASDAQ:TSLA).", telebot.ModeMarkdown)
-			if err != nil {
-				return err
-			}
+			return err
+		}
+		if _, err := t.marketData.Provider(exchange); err != nil {
+			_, err = t.telegram.Send(ctx, c, fmt.Sprintf("⚠️ Exchange %s belum didukung. Exchange yang tersedia: %s", exchange, strings.Join(t.marketData.Exchanges(), ", ")))
+			return err
 		}
 		data.StockCode = stockCode
 		data.Exchange = exchange
@@ -79,6 +85,25 @@ func (t *TelegramBotHandler) handleSetPositionConversation(ctx context.Context,
 			}
 		}
 		data.BuyPrice = price
+		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingSetPositionQuantity, t.cfg.Cache.TelegramStateExpDuration)
+		_, err = t.telegram.Send(ctx, c, fmt.Sprintf("📦 Berapa %s yang dibeli? (contoh: 10)\n\n📌 *Note:* Isi *0* jika tidak ingin mencatat jumlah, report akan ditampilkan dalam persen.", quantityUnit(data.Exchange)), telebot.ModeMarkdown)
+		if err != nil {
+			return err
+		}
+
+		t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), data, t.cfg.Cache.TelegramStateExpDuration)
+
+	case StateWaitingSetPositionQuantity:
+		quantity, err := strconv.ParseFloat(text, 64)
+		if err != nil || quantity < 0 {
+			_, err = t.telegram.Send(ctx, c, "Format jumlah tidak valid. Silakan masukkan angka (contoh: 10).", telebot.ModeMarkdown)
+			return err
+		}
+		cost := costmodel.ForExchange(data.Exchange)
+		if cost.LotSize > 0 {
+			quantity *= cost.LotSize
+		}
+		data.Quantity = cost.RoundQuantity(quantity)
 		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingSetPositionBuyDate, t.cfg.Cache.TelegramStateExpDuration)
 		_, err = t.telegram.Send(ctx, c, "📅 Kapan tanggal belinya? (format: YYYY-MM-DD)", telebot.ModeMarkdown)
 		if err != nil {
@@ -125,11 +150,16 @@ func (t *TelegramBotHandler) handleSetPositionConversation(ctx context.Context,
 		price, err := strconv.ParseFloat(text, 64)
 		if err != nil {
 			_, err = t.telegram.Send(ctx, c, "Format harga stop loss tidak valid. Silakan masukkan angka.", telebot.ModeMarkdown)
-			if err != nil {
-				return err
-			}
+			return err
+		}
+		// TP di bawah & SL di atas harga entry berarti posisi short
+		side := dto.InferSide(data.BuyPrice, data.TakeProfit, price)
+		if dto.IsShort(side) && !costmodel.ForExchange(data.Exchange).Shortable {
+			_, err = t.telegram.Send(ctx, c, msgShortNotSupported+" Silakan masukkan stop loss di bawah harga beli.")
+			return err
 		}
 		data.StopLoss = price
+		data.Side = side
 		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingSetPositionMaxHolding, t.cfg.Cache.TelegramStateExpDuration)
 		_, err = t.telegram.Send(ctx, c, "⏳ Berapa maksimal hari mau di-hold? (contoh: 1) \n\n📌 *Note:* Isi angka dari *1* sampai *14* hari.", &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 		if err != nil {
@@ -142,9 +172,7 @@ func (t *TelegramBotHandler) handleSetPositionConversation(ctx context.Context,
 		intVal, err := strconv.Atoi(text)
 		if err != nil || intVal <= 0 {
 			_, err = t.telegram.Send(ctx, c, "Format maksimal hari hold tidak valid. Silakan masukkan angka bulat positif.", telebot.ModeMarkdown)
-			if err != nil {
-				return err
-			}
+			return err
 		}
 		data.MaxHolding = intVal
 		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingSetPositionAlertPrice, t.cfg.Cache.TelegramStateExpDuration)
@@ -259,7 +287,13 @@ func (t *TelegramBotHandler) handleSetPositionFinish(ctx context.Context, c tele
 	}
 
 	if err := t.service.TelegramBotService.SetStockPosition(ctx, data); err != nil {
-		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetPosition)
+		msg := commonErrorInternalSetPosition
+		if errors.Is(err, service.ErrPositionAlreadyExists) {
+			msg = msgPositionAlreadyExists
+		} else if errors.Is(err, service.ErrShortNotSupported) {
+			msg = msgShortNotSupported
+		}
+		_, err := t.telegram.Send(ctx, c, msg)
 		return err
 	}
 
@@ -272,7 +306,13 @@ func (t *TelegramBotHandler) showSetPositionSuccess(ctx context.Context, c teleb
 	sb.WriteString("💾 Posisi saham berhasil disimpan!\n\n")
 	sb.WriteString("📊 Detail:\n")
 	sb.WriteString("— Saham: " + symbolWithExchange + "\n")
+	if dto.IsShort(data.Side) {
+		sb.WriteString("— Side: " + dto.SideText(data.Side) + "\n")
+	}
 	sb.WriteString("— Harga Beli: " + strconv.FormatFloat(data.BuyPrice, 'f', 0, 64) + "\n")
+	if data.Quantity > 0 {
+		sb.WriteString("— Jumlah: " + formatQuantity(data.Exchange, data.Quantity) + "\n")
+	}
 	sb.WriteString("— Tanggal Beli: " + data.BuyDate + "\n")
 	sb.WriteString("— Take Profit: " + strconv.FormatFloat(data.TakeProfit, 'f', 0, 64) + "\n")
 	sb.WriteString("— Stop Loss: " + strconv.FormatFloat(data.StopLoss, 'f', 0, 64) + "\n")
@@ -316,13 +356,13 @@ func (t *TelegramBotHandler) handleBtnSetPositionByTechnical(ctx context.Context
 	exchange := parts[0]
 	stockCode := parts[1]
 
-	latestAnalyses, err := t.service.TelegramBotService.AnalyzeStock(ctx, c, symbolWithExchange)
+	latestAnalyses, err := t.service.TelegramBotService.AnalyzeStock(t.withTimeframeProfile(ctx, c.Sender().ID), c, symbolWithExchange)
 	if err != nil {
 		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetPosition)
 		return err
 	}
 
-	tradePlanResult, err := t.service.TradingService.CreateTradePlan(ctx, latestAnalyses)
+	tradePlanResult, err := t.service.TradingService.CreateTradePlan(t.withPositionSizing(ctx, userTelegram.ID), latestAnalyses)
 	if err != nil {
 		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetPosition)
 		return err
@@ -333,8 +373,10 @@ func (t *TelegramBotHandler) handleBtnSetPositionByTechnical(ctx context.Context
 		StockCode:     stockCode,
 		Exchange:      exchange,
 		BuyPrice:      tradePlanResult.Entry,
+		Quantity:      tradePlanResult.Quantity,
 		TakeProfit:    tradePlanResult.TakeProfit,
 		StopLoss:      tradePlanResult.StopLoss,
+		Side:          tradePlanResult.Side,
 		BuyDate:       utils.TimeNowWIB().Format("2006-01-02"),
 		MaxHolding:    5,
 		AlertPrice:    true,
@@ -342,6 +384,7 @@ func (t *TelegramBotHandler) handleBtnSetPositionByTechnical(ctx context.Context
 		SourceType:    model.StockPositionSourceTypeTechnical,
 		IsMessageEdit: true,
 		PlanScore:     tradePlanResult.Score,
+		PlanVersion:   tradePlanResult.ParamsVersion,
 		PositionScore: tradePlanResult.PositionScore,
 	}
 
@@ -365,19 +408,27 @@ func (t *TelegramBotHandler) handleBtnSetPositionByAI(ctx context.Context, c tel
 	exchange := parts[0]
 	stockCode := parts[1]
 
-	analysis, err := t.service.TelegramBotService.AnalyzeStockAI(ctx, c, symbolWithExchange)
+	analysis, err := t.service.TelegramBotService.AnalyzeStockAI(t.withTimeframeProfile(ctx, c.Sender().ID), c, symbolWithExchange)
 	if err != nil {
 		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetPosition)
 		return err
 	}
 
+	sizing, err := t.service.TelegramBotService.GetPositionSizing(ctx, userTelegram.ID)
+	if err != nil {
+		t.log.WarnContext(ctx, "Failed to get position sizing, position saved without quantity", logger.ErrorField(err))
+	}
+	size := costmodel.ForExchange(exchange).PositionSize(sizing.AccountSize, sizing.RiskPerTradePercent, analysis.MarketPrice, analysis.StopLoss)
+
 	data := &dto.RequestSetPositionData{
 		UserTelegram:  userTelegram,
 		StockCode:     stockCode,
 		Exchange:      exchange,
 		BuyPrice:      analysis.MarketPrice,
+		Quantity:      size.Quantity,
 		TakeProfit:    analysis.TargetPrice,
 		StopLoss:      analysis.StopLoss,
+		Side:          model.PositionSideLong,
 		BuyDate:       utils.TimeNowWIB().Format("2006-01-02"),
 		MaxHolding:    5,
 		AlertPrice:    true,
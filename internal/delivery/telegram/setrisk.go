@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/service"
+	"golang-trading/pkg/cache"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"strconv"
+	"strings"
+
+	"gopkg.in/telebot.v3"
+)
+
+const maxRiskPerTradePercent = 10.0
+
+func (t *TelegramBotHandler) handleSetRisk(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+
+	sizing, err := t.service.TelegramBotService.GetPositionSizing(ctx, userID)
+	if err != nil {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetRisk)
+		return err
+	}
+
+	t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingSetRiskAccountSize, t.cfg.Cache.TelegramStateExpDuration)
+	t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), &dto.PositionSizing{}, t.cfg.Cache.TelegramStateExpDuration)
+
+	sb := strings.Builder{}
+	sb.WriteString("⚖️ <b>Pengaturan Position Sizing</b>\n\n")
+	if sizing.IsSet() {
+		sb.WriteString(fmt.Sprintf("Saat ini: modal <b>%s</b>, risk <b>%.2f%%</b> per trade.\n\n", utils.FormatVolume(sizing.AccountSize), sizing.RiskPerTradePercent))
+	}
+	sb.WriteString("💼 Berapa modal trading kamu? <i>(contoh: 100000000)</i>\n\n")
+	sb.WriteString("📌 <i>Note:</i> Kirim <b>0</b> untuk menonaktifkan perhitungan lot.")
+
+	_, err = t.telegram.Send(ctx, c, sb.String(), telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotHandler) handleSetRiskConversation(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	text := strings.TrimSpace(c.Text())
+	state, ok := cache.GetFromCache[int](fmt.Sprintf(UserStateKey, userID))
+	if !ok {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetRisk)
+		return err
+	}
+
+	data, ok := cache.GetFromCache[*dto.PositionSizing](fmt.Sprintf(UserDataKey, userID))
+	if !ok {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetRisk)
+		return err
+	}
+
+	switch state {
+	case StateWaitingSetRiskAccountSize:
+		accountSize, err := strconv.ParseFloat(text, 64)
+		if err != nil || accountSize < 0 {
+			_, err = t.telegram.Send(ctx, c, "Format modal tidak valid. Silakan masukkan angka (contoh: 100000000).")
+			return err
+		}
+
+		if accountSize == 0 {
+			return t.handleSetRiskFinish(ctx, c, dto.PositionSizing{})
+		}
+
+		data.AccountSize = accountSize
+		t.inmemoryCache.Set(fmt.Sprintf(UserDataKey, userID), data, t.cfg.Cache.TelegramStateExpDuration)
+		t.inmemoryCache.Set(fmt.Sprintf(UserStateKey, userID), StateWaitingSetRiskPercent, t.cfg.Cache.TelegramStateExpDuration)
+
+		_, err = t.telegram.Send(ctx, c, fmt.Sprintf("🎯 Berapa persen modal yang siap hilang per trade jika stop loss kena? (contoh: 1)\n\n📌 *Note:* Isi angka dari *0.1* sampai *%.0f*.", maxRiskPerTradePercent), telebot.ModeMarkdown)
+		return err
+
+	case StateWaitingSetRiskPercent:
+		riskPercent, err := strconv.ParseFloat(text, 64)
+		if err != nil || riskPercent < 0.1 || riskPercent > maxRiskPerTradePercent {
+			_, err = t.telegram.Send(ctx, c, fmt.Sprintf("Format risk tidak valid. Silakan masukkan angka 0.1 sampai %.0f.", maxRiskPerTradePercent))
+			return err
+		}
+
+		data.RiskPerTradePercent = riskPercent
+		return t.handleSetRiskFinish(ctx, c, *data)
+	}
+	return nil
+}
+
+func (t *TelegramBotHandler) handleSetRiskFinish(ctx context.Context, c telebot.Context, sizing dto.PositionSizing) error {
+	defer t.ResetUserState(c.Sender().ID)
+
+	if err := t.service.TelegramBotService.SetPositionSizing(ctx, dto.ToRequestUserTelegram(c.Sender()), sizing); err != nil {
+		t.log.ErrorContext(ctx, "Failed to set position sizing", logger.ErrorField(err))
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalSetRisk)
+		return err
+	}
+
+	if !sizing.IsSet() {
+		_, err := t.telegram.Send(ctx, c, "🔕 Perhitungan lot dinonaktifkan.")
+		return err
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("💾 Position sizing berhasil disimpan!\n\n")
+	sb.WriteString(fmt.Sprintf("— Modal: <b>%s</b>\n", utils.FormatVolume(sizing.AccountSize)))
+	sb.WriteString(fmt.Sprintf("— Risk per trade: <b>%.2f%%</b> (maks. rugi %s)\n\n", sizing.RiskPerTradePercent, utils.FormatVolume(sizing.AccountSize*sizing.RiskPerTradePercent/100)))
+	sb.WriteString("Trade plan dari /analyze, /buylist dan signal BUY akan menampilkan jumlah lot yang disarankan.")
+
+	_, err := t.telegram.Send(ctx, c, sb.String(), telebot.ModeHTML)
+	return err
+}
+
+// withPositionSizing menyertakan modal & risk user ke context supaya trade plan menghitung jumlah lot.
+// Gagal memuat pengaturan user tidak menggagalkan analisa, plan tetap dibuat tanpa sizing.
+func (t *TelegramBotHandler) withPositionSizing(ctx context.Context, telegramID int64) context.Context {
+	sizing, err := t.service.TelegramBotService.GetPositionSizing(ctx, telegramID)
+	if err != nil {
+		t.log.WarnContext(ctx, "Failed to get position sizing, trade plan without sizing", logger.ErrorField(err))
+		return ctx
+	}
+	return service.WithPositionSizing(ctx, sizing)
+}
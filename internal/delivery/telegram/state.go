@@ -17,6 +17,7 @@ const (
 	StateWaitingSetPositionMaxHolding   = 6
 	StateWaitingSetPositionAlertPrice   = 7
 	StateWaitingSetPositionAlertMonitor = 8
+	StateWaitingSetPositionQuantity     = 9
 
 	// /analyze position states
 	StateWaitingAnalysisPositionSymbol   = 10
@@ -42,4 +43,14 @@ const (
 	StateWaitingAdjustTargetPositionInputStopLossPrice = 51
 	StateWaitingAdjustTargetPositionMaxHoldingDays     = 52
 	StateWaitingAdjustTargetPositionConfirm            = 53
+
+	// partial sell / tambah posisi states
+	StateWaitingPositionTxQuantity = 70
+	StateWaitingPositionTxPrice    = 71
+	StateWaitingPositionTxDate     = 72
+	StateWaitingPositionTxConfirm  = 73
+
+	// /setrisk states
+	StateWaitingSetRiskAccountSize = 60
+	StateWaitingSetRiskPercent     = 61
 )
@@ -32,6 +32,7 @@ type TelegramBotHandler struct {
 	httpClient    httpclient.HTTPClient
 	inmemoryCache cache.Cache
 	sysParam      repository.SystemParamRepository
+	marketData    repository.MarketDataRegistry
 }
 
 func NewTelegramBotHandler(
@@ -44,7 +45,8 @@ func NewTelegramBotHandler(
 	validator *goValidator.Validate,
 	service *service.Service,
 	inmemoryCache cache.Cache,
-	sysParam repository.SystemParamRepository) *TelegramBotHandler {
+	sysParam repository.SystemParamRepository,
+	marketData repository.MarketDataRegistry) *TelegramBotHandler {
 	return &TelegramBotHandler{
 		ctx:           ctx,
 		mu:            sync.Mutex{},
@@ -58,6 +60,7 @@ func NewTelegramBotHandler(
 		httpClient:    httpclient.New(log, cfg.Telegram.WebhookURL, cfg.Telegram.TimeoutDuration, ""),
 		inmemoryCache: inmemoryCache,
 		sysParam:      sysParam,
+		marketData:    marketData,
 	}
 }
 
@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/repository"
+	"golang-trading/pkg/logger"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// timeframeProfileAuto data tombol untuk kembali ke profil symbol / exchange
+const timeframeProfileAuto = "auto"
+
+func (t *TelegramBotHandler) handleTimeframe(ctx context.Context, c telebot.Context) error {
+	telegramID := c.Sender().ID
+
+	profiles, err := t.service.TelegramBotService.GetTimeframeProfiles(ctx)
+	if err != nil {
+		t.log.ErrorContext(ctx, "Failed to get timeframe profiles", logger.ErrorField(err))
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalTimeframe)
+		return err
+	}
+
+	current, err := t.service.TelegramBotService.GetTimeframeProfile(ctx, telegramID)
+	if err != nil {
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalTimeframe)
+		return err
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("🕒 <b>Profil Timeframe Analisa</b>\n\n")
+	sb.WriteString("Profil menentukan timeframe yang dipakai /analyze, trade plan dan monitoring posisi kamu.\n\n")
+	for _, profile := range profiles {
+		sb.WriteString(fmt.Sprintf("— <b>%s</b>: %s\n", profile.Name, formatTimeframeProfile(profile)))
+	}
+	sb.WriteString("\n")
+	if current == "" {
+		sb.WriteString("Saat ini: <b>Otomatis</b> (mengikuti pengaturan saham / exchange)\n\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Saat ini: <b>%s</b>\n\n", current))
+	}
+	sb.WriteString("📌 <i>Note:</i> Profil yang tidak didukung data exchange (mis. intraday untuk sebagian saham) otomatis diganti profil exchange.")
+
+	menu := &telebot.ReplyMarkup{}
+	rows := []telebot.Row{}
+	var tempRow []telebot.Btn
+	for _, profile := range profiles {
+		text := profile.Name
+		if profile.Name == current {
+			text = "✅ " + text
+		}
+		tempRow = append(tempRow, menu.Data(text, btnTimeframeProfile.Unique, profile.Name))
+		if len(tempRow) == 3 {
+			rows = append(rows, menu.Row(tempRow...))
+			tempRow = []telebot.Btn{}
+		}
+	}
+	if len(tempRow) > 0 {
+		rows = append(rows, menu.Row(tempRow...))
+	}
+
+	autoText := "Otomatis"
+	if current == "" {
+		autoText = "✅ " + autoText
+	}
+	rows = append(rows, menu.Row(menu.Data(autoText, btnTimeframeProfile.Unique, timeframeProfileAuto), btnDeleteMessage))
+	menu.Inline(rows...)
+
+	_, err = t.telegram.Send(ctx, c, sb.String(), menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotHandler) handleBtnTimeframeProfile(ctx context.Context, c telebot.Context) error {
+	profile := c.Data()
+	if profile == timeframeProfileAuto {
+		profile = ""
+	}
+
+	if err := t.service.TelegramBotService.SetTimeframeProfile(ctx, dto.ToRequestUserTelegram(c.Sender()), profile); err != nil {
+		t.log.ErrorContext(ctx, "Failed to set timeframe profile", logger.ErrorField(err))
+		_, err := t.telegram.Send(ctx, c, commonErrorInternalTimeframe)
+		return err
+	}
+
+	text := "✅ Profil timeframe kembali <b>otomatis</b>"
+	if profile != "" {
+		text = fmt.Sprintf("✅ Profil timeframe diubah ke <b>%s</b>", profile)
+	}
+
+	t.telegram.Edit(ctx, c, c.Message(), text, telebot.ModeHTML)
+	time.Sleep(300 * time.Millisecond)
+	t.telegram.Delete(ctx, c, c.Message())
+	return t.handleTimeframe(ctx, c)
+}
+
+// withTimeframeProfile menyertakan profil timeframe pilihan user ke context analisa.
+// Gagal memuat pengaturan user tidak menggagalkan analisa, profil symbol / exchange tetap dipakai.
+func (t *TelegramBotHandler) withTimeframeProfile(ctx context.Context, telegramID int64) context.Context {
+	profile, err := t.service.TelegramBotService.GetTimeframeProfile(ctx, telegramID)
+	if err != nil {
+		t.log.WarnContext(ctx, "Failed to get timeframe profile, using default profile", logger.ErrorField(err))
+		return ctx
+	}
+	return repository.WithTimeframeProfile(ctx, profile)
+}
+
+// formatTimeframeProfile daftar interval profil, timeframe utama ditebalkan
+func formatTimeframeProfile(profile dto.TimeframeProfile) string {
+	intervals := make([]string, 0, len(profile.Timeframes))
+	for _, tf := range profile.Timeframes {
+		if tf.IsMain {
+			intervals = append(intervals, "<b>"+tf.Interval+"</b>")
+			continue
+		}
+		intervals = append(intervals, tf.Interval)
+	}
+	return strings.Join(intervals, " / ")
+}
@@ -24,6 +24,10 @@ var (
 	btnBackStockPosition       telebot.Btn = telebot.Btn{Text: "🔙 Kembali", Unique: "btn_back_stock_position"}
 	btnRefreshAnalysisPosition telebot.Btn = telebot.Btn{Text: "🔄 Refresh Analisis", Unique: "btn_refresh_analysis_position"}
 
+	btnPartialSellStockPosition telebot.Btn = telebot.Btn{Unique: "btn_partial_sell_stock_position"}
+	btnAddStockPosition         telebot.Btn = telebot.Btn{Unique: "btn_add_stock_position"}
+	btnSavePositionTransaction  telebot.Btn = telebot.Btn{Text: "💾 Simpan", Unique: "btn_save_position_transaction"}
+
 	//buylist
 	btnCancelBuyListAnalysis telebot.Btn = telebot.Btn{Text: "⛔ Hentikan Analisis", Unique: "btn_cancel_buy_list_analysis"}
 	btnShowBuyListAnalysis   telebot.Btn = telebot.Btn{Unique: "btn_show_buy_list_analysis"}
@@ -37,6 +41,9 @@ var (
 
 	//alert signal
 	btnAlertSignal telebot.Btn = telebot.Btn{Unique: "btn_alert_signal"}
+
+	// timeframe profile
+	btnTimeframeProfile telebot.Btn = telebot.Btn{Unique: "btn_timeframe_profile"}
 )
 
 const (
@@ -44,6 +51,11 @@ const (
 	commonErrorInternalSetPosition = commonErrorInternal + " dengan /setposition."
 	commonErrorInternalMyPosition  = commonErrorInternal + " dengan /myposition."
 	commonErrorInternalReport      = commonErrorInternal + " dengan /report."
+	commonErrorInternalSetRisk     = commonErrorInternal + " dengan /setrisk."
+	commonErrorInternalTimeframe   = commonErrorInternal + " dengan /timeframe."
+
+	msgPositionAlreadyExists = "⚠️ Posisi saham ini sudah ada. Untuk average down / menambah lot, buka /myposition lalu pilih ➕ Tambah Posisi."
+	msgShortNotSupported     = "⚠️ Posisi short (TP di bawah & SL di atas harga entry) tidak didukung untuk exchange ini."
 )
 
 const (
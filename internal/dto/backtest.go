@@ -2,40 +2,228 @@ package dto
 
 import "time"
 
+const (
+	// BacktestModeAnalysis replay dari data stock_analyses yang tersimpan (default)
+	BacktestModeAnalysis = "analysis"
+	// BacktestModeCandleReplay replay bar-by-bar dari candle OHLCV, analisis dibangun ulang per bar
+	BacktestModeCandleReplay = "candle_replay"
+
+	// FillPriorityStopLoss jika SL & TP kena di bar yang sama, anggap SL kena duluan (konservatif, default)
+	FillPriorityStopLoss = "stop_loss_first"
+	// FillPriorityTakeProfit jika SL & TP kena di bar yang sama, anggap TP kena duluan
+	FillPriorityTakeProfit = "take_profit_first"
+	// FillPriorityNearestOpen jika SL & TP kena di bar yang sama, level yang paling dekat dengan open kena duluan
+	FillPriorityNearestOpen = "nearest_open"
+)
+
 // BacktestRequest mendefinisikan parameter untuk menjalankan sebuah backtest.
 type BacktestRequest struct {
-	StockCode string    `json:"stock_code"`
-	Exchange  string    `json:"exchange"`
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
+	StockCode    string    `json:"stock_code"`
+	Exchange     string    `json:"exchange"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+	Mode         string    `json:"mode" validate:"omitempty,oneof=analysis candle_replay"`
+	FillPriority string    `json:"fill_priority" validate:"omitempty,oneof=stop_loss_first take_profit_first nearest_open"`
+	Side         string    `json:"side" validate:"omitempty,oneof=LONG SHORT"` // arah posisi, default LONG
+	// TradePlanParams menimpa parameter trade plan default (mis. hasil RobustParams walk-forward)
+	TradePlanParams *TradePlanParams `json:"trade_plan_params"`
+	// AdjustDividends candle replay memakai harga yang di-back-adjust dividen & rights issue
+	AdjustDividends bool `json:"adjust_dividends"`
 }
 
 // TradeLog mencatat setiap transaksi yang terjadi selama backtest.
 type TradeLog struct {
-	Symbol      string    `json:"symbol"`
-	EntryDate   time.Time `json:"entry_date"`
-	EntryPrice  float64   `json:"entry_price"`
-	ExitDate    time.Time `json:"exit_date"`
-	ExitPrice   float64   `json:"exit_price"`
-	ExitReason  string    `json:"exit_reason"`
-	ProfitLoss  float64   `json:"profit_loss"`
-	HoldingPeriod int     `json:"holding_period"`
+	Symbol     string    `json:"symbol"`
+	Exchange   string    `json:"exchange,omitempty"`
+	Side       string    `json:"side,omitempty"`
+	EntryDate  time.Time `json:"entry_date"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitDate   time.Time `json:"exit_date"`
+	ExitPrice  float64   `json:"exit_price"`
+	ExitReason string    `json:"exit_reason"`
+	ProfitLoss float64   `json:"profit_loss"` // per lembar / unit, bersih setelah fee
+	// ProfitLossPercent return trade terhadap harga entry
+	ProfitLossPercent float64 `json:"profit_loss_percent"`
+	Fees              float64 `json:"fees"` // fee beli + jual per lembar / unit
+	Quantity          float64 `json:"quantity,omitempty"`
+	// ProfitLossAmount P/L total posisi (ProfitLoss * Quantity)
+	ProfitLossAmount float64 `json:"profit_loss_amount,omitempty"`
+	HoldingPeriod    int     `json:"holding_period"`
 }
 
 // BacktestResult merangkum hasil dari sebuah sesi backtest.
+// Metrik persen dihitung dari return setiap trade (compounding), sehingga bisa dibandingkan antar harga saham.
 type BacktestResult struct {
-	StockCode         string     `json:"stock_code"`
-	StartDate         time.Time  `json:"start_date"`
-	EndDate           time.Time  `json:"end_date"`
-	TotalTrades       int        `json:"total_trades"`
-	WinningTrades     int        `json:"winning_trades"`
-	LosingTrades      int        `json:"losing_trades"`
-	WinRate           float64    `json:"win_rate"`
-	TotalProfitLoss   float64    `json:"total_profit_loss"`
-	TotalProfit       float64    `json:"total_profit"`
-	TotalLoss         float64    `json:"total_loss"`
-	ProfitFactor      float64    `json:"profit_factor"` // Total Profit / Total Loss
-	MaxDrawdown       float64    `json:"max_drawdown"`
-	AvgHoldingPeriod  float64    `json:"avg_holding_period"`
-	Trades            []TradeLog `json:"trades"`
+	StockCode        string    `json:"stock_code"`
+	StartDate        time.Time `json:"start_date"`
+	EndDate          time.Time `json:"end_date"`
+	TotalTrades      int       `json:"total_trades"`
+	WinningTrades    int       `json:"winning_trades"`
+	LosingTrades     int       `json:"losing_trades"`
+	WinRate          float64   `json:"win_rate"`
+	TotalProfitLoss  float64   `json:"total_profit_loss"` // selisih harga, hanya bermakna untuk satu simbol
+	TotalProfit      float64   `json:"total_profit"`
+	TotalLoss        float64   `json:"total_loss"`
+	ProfitFactor     float64   `json:"profit_factor"` // Total Profit % / Total Loss %
+	AvgHoldingPeriod float64   `json:"avg_holding_period"`
+
+	TotalReturnPercent    float64       `json:"total_return_percent"`
+	AvgWinPercent         float64       `json:"avg_win_percent"`
+	AvgLossPercent        float64       `json:"avg_loss_percent"`
+	Expectancy            float64       `json:"expectancy"`   // rata-rata return (%) yang diharapkan per trade
+	MaxDrawdown           float64       `json:"max_drawdown"` // persen dari puncak equity
+	MaxDrawdownPeakDate   time.Time     `json:"max_drawdown_peak_date"`
+	MaxDrawdownTroughDate time.Time     `json:"max_drawdown_trough_date"`
+	SharpeRatio           float64       `json:"sharpe_ratio"`
+	SortinoRatio          float64       `json:"sortino_ratio"`
+	LongestLosingStreak   int           `json:"longest_losing_streak"`
+	ExposurePercent       float64       `json:"exposure_percent"` // persen hari dengan posisi terbuka
+	EquityCurve           []EquityPoint `json:"equity_curve"`     // equity harian, dimulai dari 100
+
+	Trades []TradeLog `json:"trades"`
+}
+
+const (
+	// SizingEqualWeight modal per posisi = equity / MaxConcurrentPositions (default)
+	SizingEqualWeight = "equal_weight"
+	// SizingPercentEquity modal per posisi = equity * SizingValue / 100
+	SizingPercentEquity = "percent_equity"
+	// SizingFixedAmount modal per posisi = SizingValue
+	SizingFixedAmount = "fixed_amount"
+)
+
+// PortfolioBacktestRequest parameter backtest portfolio multi simbol dengan alokasi modal.
+type PortfolioBacktestRequest struct {
+	Symbols                  []StockInfo              `json:"symbols"`
+	TradingViewBuyListParams []map[string]interface{} `json:"trading_view_buy_list_params"`
+	StartDate                time.Time                `json:"start_date"`
+	EndDate                  time.Time                `json:"end_date"`
+	InitialCapital           float64                  `json:"initial_capital" validate:"gt=0"`
+	MaxConcurrentPositions   int                      `json:"max_concurrent_positions" validate:"gte=0"`
+	Sizing                   string                   `json:"sizing" validate:"omitempty,oneof=equal_weight percent_equity fixed_amount"`
+	SizingValue              float64                  `json:"sizing_value" validate:"gte=0"`
+	FillPriority             string                   `json:"fill_priority" validate:"omitempty,oneof=stop_loss_first take_profit_first nearest_open"`
+	Side                     string                   `json:"side" validate:"omitempty,oneof=LONG SHORT"` // arah posisi, default LONG
+	AdjustDividends          bool                     `json:"adjust_dividends"`                           // harga di-back-adjust dividen & rights issue
+}
+
+// EquityPoint nilai portfolio di akhir setiap hari.
+type EquityPoint struct {
+	Date          time.Time `json:"date"`
+	Equity        float64   `json:"equity"`
+	Cash          float64   `json:"cash"`
+	Exposure      float64   `json:"exposure"` // persen equity yang sedang di posisi
+	OpenPositions int       `json:"open_positions"`
+}
+
+// SymbolContribution kontribusi P/L setiap simbol terhadap portfolio.
+type SymbolContribution struct {
+	Symbol              string  `json:"symbol"`
+	Exchange            string  `json:"exchange"`
+	TotalTrades         int     `json:"total_trades"`
+	WinningTrades       int     `json:"winning_trades"`
+	ProfitLoss          float64 `json:"profit_loss"`
+	ContributionPercent float64 `json:"contribution_percent"` // persen dari modal awal
+}
+
+// PortfolioBacktestResult merangkum hasil backtest portfolio.
+type PortfolioBacktestResult struct {
+	StartDate             time.Time            `json:"start_date"`
+	EndDate               time.Time            `json:"end_date"`
+	Currency              string               `json:"currency"` // mata uang modal & equity
+	InitialCapital        float64              `json:"initial_capital"`
+	FinalEquity           float64              `json:"final_equity"`
+	TotalReturnPercent    float64              `json:"total_return_percent"`
+	TotalTrades           int                  `json:"total_trades"`
+	WinningTrades         int                  `json:"winning_trades"`
+	LosingTrades          int                  `json:"losing_trades"`
+	WinRate               float64              `json:"win_rate"`
+	AvgExposure           float64              `json:"avg_exposure"`
+	MaxDrawdown           float64              `json:"max_drawdown"`
+	MaxDrawdownPeakDate   time.Time            `json:"max_drawdown_peak_date"`
+	MaxDrawdownTroughDate time.Time            `json:"max_drawdown_trough_date"`
+	SharpeRatio           float64              `json:"sharpe_ratio"`
+	SortinoRatio          float64              `json:"sortino_ratio"`
+	SkippedSignals        int                  `json:"skipped_signals"` // sinyal yang tidak dieksekusi karena slot / modal habis
+	Symbols               []StockInfo          `json:"symbols"`
+	FailedSymbols         []string             `json:"failed_symbols,omitempty"`
+	EquityCurve           []EquityPoint        `json:"equity_curve"`
+	SymbolContributions   []SymbolContribution `json:"symbol_contributions"`
+	Trades                []TradeLog           `json:"trades"`
+}
+
+const (
+	OptimizeMethodGrid   = "grid"
+	OptimizeMethodRandom = "random"
+
+	OptimizeObjectiveSharpe       = "sharpe"
+	OptimizeObjectiveExpectancy   = "expectancy"
+	OptimizeObjectiveTotalReturn  = "total_return"
+	OptimizeObjectiveProfitFactor = "profit_factor"
+)
+
+// ParamRange rentang nilai satu parameter trade plan (nama sesuai json tag TradePlanParams).
+// Jika Values diisi, nilai tersebut yang dipakai dan Min/Max/Step diabaikan.
+type ParamRange struct {
+	Name   string    `json:"name" validate:"required"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max" validate:"gtefield=Min"`
+	Step   float64   `json:"step" validate:"gte=0"`
+	Values []float64 `json:"values"`
+}
+
+// WalkForwardRequest parameter optimasi walk-forward: parameter dicari di window train,
+// lalu divalidasi di window test berikutnya, kemudian window digeser sejauh TestDays.
+type WalkForwardRequest struct {
+	StockCode    string       `json:"stock_code" validate:"required"`
+	Exchange     string       `json:"exchange" validate:"required"`
+	StartDate    time.Time    `json:"start_date"`
+	EndDate      time.Time    `json:"end_date" validate:"gtfield=StartDate"`
+	TrainDays    int          `json:"train_days" validate:"gt=0,lte=3650"` // maksimal 10 tahun
+	TestDays     int          `json:"test_days" validate:"gt=0,lte=3650"`
+	Method       string       `json:"method" validate:"omitempty,oneof=grid random"`
+	Iterations   int          `json:"iterations" validate:"gte=0"` // jumlah sampel random search
+	Seed         int64        `json:"seed"`
+	Objective    string       `json:"objective" validate:"omitempty,oneof=sharpe expectancy total_return profit_factor"`
+	MinTrades    int          `json:"min_trades" validate:"gte=0"` // trade minimal di window train agar skor dihitung
+	FillPriority string       `json:"fill_priority" validate:"omitempty,oneof=stop_loss_first take_profit_first nearest_open"`
+	Side         string       `json:"side" validate:"omitempty,oneof=LONG SHORT"` // arah posisi, default LONG
+	SearchSpace  []ParamRange `json:"search_space" validate:"dive"`
+}
+
+// ValidateSearchSpace memastikan semua nama parameter di search space dikenal
+func (r WalkForwardRequest) ValidateSearchSpace() error {
+	params := DefaultTradePlanParams()
+	for _, pr := range r.SearchSpace {
+		if err := params.Set(pr.Name, pr.Min); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkForwardWindow hasil satu langkah walk-forward
+type WalkForwardWindow struct {
+	TrainStart  time.Time       `json:"train_start"`
+	TrainEnd    time.Time       `json:"train_end"`
+	TestStart   time.Time       `json:"test_start"`
+	TestEnd     time.Time       `json:"test_end"`
+	Params      TradePlanParams `json:"params"`
+	TrainScore  float64         `json:"train_score"`
+	TrainTrades int             `json:"train_trades"`
+	TestResult  *BacktestResult `json:"test_result"`
+}
+
+// WalkForwardResult merangkum optimasi walk-forward.
+// RobustParams adalah kandidat dengan median skor train tertinggi di semua window,
+// OutOfSample gabungan hasil semua window test (hanya data yang tidak dipakai saat mencari parameter).
+type WalkForwardResult struct {
+	StockCode    string              `json:"stock_code"`
+	Exchange     string              `json:"exchange"`
+	Objective    string              `json:"objective"`
+	Candidates   int                 `json:"candidates"`
+	RobustParams TradePlanParams     `json:"robust_params"`
+	RobustScore  float64             `json:"robust_score"`
+	Windows      []WalkForwardWindow `json:"windows"`
+	OutOfSample  *BacktestResult     `json:"out_of_sample"`
 }
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // BinanceKlines represents a single kline/candlestick from Binance.
 type BinanceKlines struct {
 	OpenTime                 int64   `json:"openTime"`
@@ -20,3 +22,44 @@ type BinancePrice struct {
 	Symbol string  `json:"symbol"`
 	Price  float64 `json:"price"`
 }
+
+// BinancePremiumIndex mark price & funding rate terakhir dari /fapi/v1/premiumIndex.
+type BinancePremiumIndex struct {
+	Symbol          string  `json:"symbol"`
+	MarkPrice       float64 `json:"markPrice,string"`
+	IndexPrice      float64 `json:"indexPrice,string"`
+	LastFundingRate float64 `json:"lastFundingRate,string"`
+	NextFundingTime int64   `json:"nextFundingTime"`
+}
+
+// BinanceFundingRate histori funding rate dari /fapi/v1/fundingRate.
+type BinanceFundingRate struct {
+	Symbol      string  `json:"symbol"`
+	FundingRate float64 `json:"fundingRate,string"`
+	FundingTime int64   `json:"fundingTime"`
+}
+
+// BinanceOpenInterestHist histori open interest dari /futures/data/openInterestHist.
+type BinanceOpenInterestHist struct {
+	Symbol               string  `json:"symbol"`
+	SumOpenInterest      float64 `json:"sumOpenInterest,string"`
+	SumOpenInterestValue float64 `json:"sumOpenInterestValue,string"`
+	Timestamp            int64   `json:"timestamp"`
+}
+
+// BinanceAggTrade event <symbol>@aggTrade dari websocket stream binance.
+type BinanceAggTrade struct {
+	EventType string  `json:"e"`
+	Symbol    string  `json:"s"`
+	Price     float64 `json:"p,string"`
+	Quantity  float64 `json:"q,string"`
+	TradeTime int64   `json:"T"`
+}
+
+// PriceTick harga transaksi terakhir dari price stream.
+type PriceTick struct {
+	Exchange  string    `json:"exchange"`
+	Symbol    string    `json:"symbol"`
+	Price     float64   `json:"price"`
+	Timestamp time.Time `json:"timestamp"`
+}
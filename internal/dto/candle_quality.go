@@ -0,0 +1,34 @@
+package dto
+
+// Jenis masalah candle yang dideteksi validasi kualitas data
+const (
+	CandleIssueInvalid          = "INVALID"           // NaN / Inf / harga negatif, bar dibuang
+	CandleIssueZeroValue        = "ZERO_VALUE"        // OHLC bernilai 0 (null dari provider)
+	CandleIssueDuplicate        = "DUPLICATE"         // timestamp dobel, bar terakhir dipakai
+	CandleIssueOutOfOrder       = "OUT_OF_ORDER"      // timestamp tidak urut, series diurutkan ulang
+	CandleIssueOHLCInconsistent = "OHLC_INCONSISTENT" // high / low tidak mencakup open & close
+	CandleIssueSpike            = "SPIKE"             // harga melonjak sendirian dibanding bar tetangga
+	CandleIssueGap              = "GAP"               // bar hilang di hari / jam bursa buka
+)
+
+// CandleIssue satu temuan pada series candle, Timestamp mengikuti satuan candle exchange
+type CandleIssue struct {
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+	Detail    string `json:"detail,omitempty"`
+	Repaired  bool   `json:"repaired"`
+}
+
+// CandleQualityReport ringkasan kualitas series candle, Score 0-100 (100 berarti tidak ada masalah)
+type CandleQualityReport struct {
+	Score          float64       `json:"score"`
+	TotalBars      int           `json:"total_bars"`
+	DroppedBars    int           `json:"dropped_bars"`
+	RepairedBars   int           `json:"repaired_bars"`
+	DuplicateBars  int           `json:"duplicate_bars"`
+	OutOfOrderBars int           `json:"out_of_order_bars"`
+	SpikeBars      int           `json:"spike_bars"`
+	MissingBars    int           `json:"missing_bars"`
+	FilledBars     int           `json:"filled_bars"`
+	Issues         []CandleIssue `json:"issues,omitempty"` // dibatasi beberapa temuan pertama
+}
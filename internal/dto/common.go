@@ -1,5 +1,7 @@
 package dto
 
+import "fmt"
+
 type DataTimeframe struct {
 	Interval string `json:"interval"`
 	Range    string `json:"range"`
@@ -7,35 +9,56 @@ type DataTimeframe struct {
 	IsMain   bool   `json:"is_main"`
 }
 
-func (d *DataTimeframe) ToTradingViewScreenersInterval() string {
+// ToTradingViewScreenersInterval interval TradingView untuk timeframe, error jika interval tidak punya padanan
+func (d *DataTimeframe) ToTradingViewScreenersInterval() (string, error) {
 	switch d.Interval {
+	case Interval1Min:
+		return TradingViewInterval1Min, nil
+	case Interval5Min:
+		return TradingViewInterval5Min, nil
+	case Interval15Min:
+		return TradingViewInterval15Min, nil
 	case Interval30Min:
-		return TradingViewInterval30Min
-	case Interval1Hour:
-		return TradingViewInterval1Hour
+		return TradingViewInterval30Min, nil
+	case Interval1Hour, "60m":
+		return TradingViewInterval1Hour, nil
+	case Interval2Hour:
+		return TradingViewInterval2Hour, nil
 	case Interval4Hour:
-		return TradingViewInterval4Hour
+		return TradingViewInterval4Hour, nil
 	case Interval1Day:
-		return TradingViewInterval1Day
-	case Interval1Week:
-		return TradingViewInterval1Week
+		return TradingViewInterval1Day, nil
+	case Interval1Week, "1wk":
+		return TradingViewInterval1Week, nil
+	case Interval1Month, "1mo":
+		return TradingViewInterval1Month, nil
 	default:
-		return TradingViewInterval1Day
+		return "", fmt.Errorf("interval %q has no TradingView equivalent", d.Interval)
 	}
 }
 
 func TradingViewIntervalToDataTimeframe(interval string) string {
 	switch interval {
+	case TradingViewInterval1Min:
+		return Interval1Min
+	case TradingViewInterval5Min:
+		return Interval5Min
+	case TradingViewInterval15Min:
+		return Interval15Min
 	case TradingViewInterval30Min:
 		return Interval30Min
 	case TradingViewInterval1Hour:
 		return Interval1Hour
+	case TradingViewInterval2Hour:
+		return Interval2Hour
 	case TradingViewInterval4Hour:
 		return Interval4Hour
 	case TradingViewInterval1Day:
 		return Interval1Day
 	case TradingViewInterval1Week:
 		return Interval1Week
+	case TradingViewInterval1Month:
+		return Interval1Month
 	default:
 		return "UNKNOWN"
 	}
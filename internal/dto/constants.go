@@ -28,11 +28,16 @@ const (
 	TradingViewSignalSell       int = -1 // SELL
 	TradingViewSignalStrongSell int = -2 // STRONG_SELL
 
-	Interval30Min string = "30m"
-	Interval1Hour string = "1h"
-	Interval4Hour string = "4h"
-	Interval1Day  string = "1d"
-	Interval1Week string = "1w"
+	Interval1Min   string = "1m"
+	Interval5Min   string = "5m"
+	Interval15Min  string = "15m"
+	Interval30Min  string = "30m"
+	Interval1Hour  string = "1h"
+	Interval2Hour  string = "2h"
+	Interval4Hour  string = "4h"
+	Interval1Day   string = "1d"
+	Interval1Week  string = "1w"
+	Interval1Month string = "1M"
 
 	SignalStrongBuy  = "STRONG_BUY"
 	SignalBuy        = "BUY"
@@ -82,6 +87,7 @@ const (
 	TrailingStop   Signal = "trailing_stop"
 	TrailingProfit Signal = "trailing_profit"
 	Hold           Signal = "hold"
+	TimeStop       Signal = "time_stop"
 )
 
 func (s Signal) String() string {
@@ -96,6 +102,8 @@ func (s Signal) String() string {
 		return "🟠 Trailing Profit"
 	case Hold:
 		return "🟡 Hold"
+	case TimeStop:
+		return "⏰ Time Stop"
 	default:
 		return "Unknown"
 	}
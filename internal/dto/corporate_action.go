@@ -0,0 +1,181 @@
+package dto
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-trading/internal/model"
+)
+
+// Jenis corporate action yang mempengaruhi skala harga
+const (
+	CorporateActionSplit    = "SPLIT"    // stock split / reverse split
+	CorporateActionDividend = "DIVIDEND" // dividen tunai
+	CorporateActionRights   = "RIGHTS"   // rights issue, PriceFactor diisi manual (TERP / harga cum)
+)
+
+// CorporateAction satu corporate action emiten, ExDate adalah hari pertama harga diperdagangkan dengan skala baru
+type CorporateAction struct {
+	Type        string    `json:"type"`
+	ExDate      time.Time `json:"ex_date"`
+	Numerator   float64   `json:"numerator,omitempty"`    // split 2:1 -> numerator 2, denominator 1
+	Denominator float64   `json:"denominator,omitempty"`  // pembagi rasio split
+	Amount      float64   `json:"amount,omitempty"`       // dividen per lembar
+	PriceFactor float64   `json:"price_factor,omitempty"` // pengali harga sebelum ExDate (split 2:1 -> 0.5)
+}
+
+// SplitPriceFactor pengali harga split, 0 jika rasio tidak valid
+func SplitPriceFactor(numerator, denominator float64) float64 {
+	if numerator <= 0 || denominator <= 0 {
+		return 0
+	}
+	return denominator / numerator
+}
+
+// YahooFinanceEvents events=div,split pada response chart yahoo finance, key map berupa unix timestamp ex-date
+type YahooFinanceEvents struct {
+	Dividends map[string]struct {
+		Amount float64 `json:"amount"`
+		Date   int64   `json:"date"`
+	} `json:"dividends"`
+	Splits map[string]struct {
+		Date        int64   `json:"date"`
+		Numerator   float64 `json:"numerator"`
+		Denominator float64 `json:"denominator"`
+		SplitRatio  string  `json:"splitRatio"`
+	} `json:"splits"`
+}
+
+// CorporateActions events yahoo sebagai corporate action urut ex-date
+func (e *YahooFinanceEvents) CorporateActions() []CorporateAction {
+	if e == nil {
+		return nil
+	}
+
+	actions := make([]CorporateAction, 0, len(e.Dividends)+len(e.Splits))
+	for _, div := range e.Dividends {
+		if div.Amount <= 0 {
+			continue
+		}
+		actions = append(actions, CorporateAction{
+			Type:   CorporateActionDividend,
+			ExDate: time.Unix(div.Date, 0).UTC(),
+			Amount: div.Amount,
+		})
+	}
+	for _, split := range e.Splits {
+		factor := SplitPriceFactor(split.Numerator, split.Denominator)
+		if factor == 0 || factor == 1 {
+			continue
+		}
+		actions = append(actions, CorporateAction{
+			Type:        CorporateActionSplit,
+			ExDate:      time.Unix(split.Date, 0).UTC(),
+			Numerator:   split.Numerator,
+			Denominator: split.Denominator,
+			PriceFactor: factor,
+		})
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].ExDate.Before(actions[j].ExDate)
+	})
+	return actions
+}
+
+// BackAdjust menyesuaikan harga bar sebelum ex-date dengan corporate action yang belum tercermin di candle provider
+// (dividen & rights issue), volume tidak diubah. barTime mengubah timestamp candle menjadi waktu.
+func BackAdjust(ohlcv []StockOHLCV, actions []CorporateAction, barTime func(ts int64) time.Time) []StockOHLCV {
+	if len(ohlcv) == 0 || len(actions) == 0 {
+		return ohlcv
+	}
+
+	adjusted := make([]StockOHLCV, len(ohlcv))
+	copy(adjusted, ohlcv)
+
+	for _, action := range actions {
+		if action.Type == CorporateActionSplit {
+			continue
+		}
+
+		// bar terakhir sebelum ex-date
+		cum := -1
+		for i, bar := range ohlcv {
+			if !barTime(bar.Timestamp).Before(action.ExDate) {
+				break
+			}
+			cum = i
+		}
+		if cum < 0 {
+			continue
+		}
+
+		factor := action.PriceFactor
+		if action.Type == CorporateActionDividend && factor == 0 {
+			if ohlcv[cum].Close <= action.Amount {
+				continue
+			}
+			factor = 1 - action.Amount/ohlcv[cum].Close
+		}
+		if factor <= 0 {
+			continue
+		}
+
+		for i := 0; i <= cum; i++ {
+			adjusted[i].Open *= factor
+			adjusted[i].High *= factor
+			adjusted[i].Low *= factor
+			adjusted[i].Close *= factor
+		}
+	}
+	return adjusted
+}
+
+// ApplyPriceFactor menyesuaikan posisi yang dibuka sebelum ex-date ke skala harga baru: harga entry, TP/SL & trailing
+// dikali factor, quantity dibagi factor. Transaksi sebelum ex-date ikut disesuaikan dan dikembalikan untuk disimpan.
+func ApplyPriceFactor(position *model.StockPosition, factor float64, exDate time.Time) []model.StockPositionTransaction {
+	if factor <= 0 || factor == 1 || !position.BuyDate.Before(exDate) {
+		return nil
+	}
+
+	position.BuyPrice *= factor
+	position.TakeProfitPrice *= factor
+	position.StopLossPrice *= factor
+	position.TrailingProfitPrice *= factor
+	position.TrailingStopPrice *= factor
+	position.HighestPriceSinceTTP *= factor
+	position.Quantity /= factor
+
+	var adjusted []model.StockPositionTransaction
+	for i := range position.Transactions {
+		tx := &position.Transactions[i]
+		if !tx.TransactionDate.Before(exDate) {
+			continue
+		}
+		tx.Price *= factor
+		tx.Quantity /= factor
+		adjusted = append(adjusted, *tx)
+	}
+	return adjusted
+}
+
+// CorporateActionLabel deskripsi singkat corporate action untuk notifikasi, mis. "stock split 5:1"
+func CorporateActionLabel(actionType string, numerator, denominator, factor float64) string {
+	switch actionType {
+	case CorporateActionSplit:
+		if numerator < denominator {
+			return fmt.Sprintf("reverse split %s:%s", formatRatio(numerator), formatRatio(denominator))
+		}
+		return fmt.Sprintf("stock split %s:%s", formatRatio(numerator), formatRatio(denominator))
+	case CorporateActionRights:
+		return fmt.Sprintf("rights issue (faktor harga %.4f)", factor)
+	default:
+		return strings.ToLower(actionType)
+	}
+}
+
+func formatRatio(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
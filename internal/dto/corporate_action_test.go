@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"golang-trading/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYahooFinanceEventsCorporateActions(t *testing.T) {
+	raw := `{
+		"dividends": {"1717372800": {"amount": 25, "date": 1717372800}, "1700000000": {"amount": 0, "date": 1700000000}},
+		"splits": {"1712016000": {"date": 1712016000, "numerator": 5, "denominator": 1, "splitRatio": "5:1"},
+		           "1710000000": {"date": 1710000000, "numerator": 0, "denominator": 1, "splitRatio": "0:1"}}
+	}`
+	var events YahooFinanceEvents
+	assert.NoError(t, json.Unmarshal([]byte(raw), &events))
+
+	actions := events.CorporateActions()
+	// dividen 0 & rasio split tidak valid dibuang, urut ex-date
+	assert.Len(t, actions, 2)
+	assert.Equal(t, CorporateActionSplit, actions[0].Type)
+	assert.InDelta(t, 0.2, actions[0].PriceFactor, 1e-9)
+	assert.Equal(t, CorporateActionDividend, actions[1].Type)
+	assert.Equal(t, 25.0, actions[1].Amount)
+
+	var empty *YahooFinanceEvents
+	assert.Nil(t, empty.CorporateActions())
+}
+
+func TestBackAdjust(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 2, 0, 0, 0, time.UTC) }
+	barTime := func(ts int64) time.Time { return time.Unix(ts, 0).UTC() }
+	ohlcv := []StockOHLCV{
+		{Timestamp: day(2).Unix(), Open: 1000, High: 1000, Low: 1000, Close: 1000, Volume: 10},
+		{Timestamp: day(3).Unix(), Open: 1000, High: 1000, Low: 1000, Close: 1000, Volume: 10},
+		{Timestamp: day(4).Unix(), Open: 950, High: 950, Low: 950, Close: 950, Volume: 10},
+	}
+
+	adjusted := BackAdjust(ohlcv, []CorporateAction{
+		{Type: CorporateActionDividend, ExDate: day(4), Amount: 50},
+		// split sudah tercermin di candle provider
+		{Type: CorporateActionSplit, ExDate: day(4), PriceFactor: 0.5},
+	}, barTime)
+
+	assert.InDelta(t, 950, adjusted[0].Close, 1e-9)
+	assert.InDelta(t, 950, adjusted[1].Open, 1e-9)
+	assert.InDelta(t, 950, adjusted[2].Close, 1e-9)
+	assert.Equal(t, 10.0, adjusted[0].Volume)
+	// series asli tidak berubah
+	assert.Equal(t, 1000.0, ohlcv[0].Close)
+
+	adjusted = BackAdjust(ohlcv, []CorporateAction{{Type: CorporateActionRights, ExDate: day(3), PriceFactor: 0.8}}, barTime)
+	assert.InDelta(t, 800, adjusted[0].Close, 1e-9)
+	assert.InDelta(t, 1000, adjusted[1].Close, 1e-9)
+}
+
+func TestApplyPriceFactor(t *testing.T) {
+	exDate := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	position := model.StockPosition{
+		BuyPrice:        5000,
+		Quantity:        100,
+		TakeProfitPrice: 6000,
+		StopLossPrice:   4500,
+		BuyDate:         exDate.AddDate(0, 0, -10),
+		Transactions: []model.StockPositionTransaction{
+			{ID: 1, Type: model.StockPositionTransactionTypeBuy, Price: 5000, Quantity: 100, TransactionDate: exDate.AddDate(0, 0, -10)},
+			{ID: 2, Type: model.StockPositionTransactionTypeSell, Price: 1100, Quantity: 100, TransactionDate: exDate.AddDate(0, 0, 1)},
+		},
+	}
+
+	// split 5:1
+	transactions := ApplyPriceFactor(&position, SplitPriceFactor(5, 1), exDate)
+	assert.InDelta(t, 1000, position.BuyPrice, 1e-9)
+	assert.InDelta(t, 500, position.Quantity, 1e-9)
+	assert.InDelta(t, 1200, position.TakeProfitPrice, 1e-9)
+	assert.InDelta(t, 900, position.StopLossPrice, 1e-9)
+	// hanya transaksi sebelum ex-date yang disesuaikan
+	assert.Len(t, transactions, 1)
+	assert.Equal(t, uint(1), transactions[0].ID)
+	assert.InDelta(t, 500, transactions[0].Quantity, 1e-9)
+	assert.InDelta(t, 1100, position.Transactions[1].Price, 1e-9)
+
+	// posisi dibuka setelah ex-date tidak disesuaikan
+	position.BuyDate = exDate
+	assert.Nil(t, ApplyPriceFactor(&position, 0.5, exDate))
+	assert.InDelta(t, 1000, position.BuyPrice, 1e-9)
+}
+
+func TestCorporateActionLabel(t *testing.T) {
+	assert.Equal(t, "stock split 5:1", CorporateActionLabel(CorporateActionSplit, 5, 1, 0.2))
+	assert.Equal(t, "reverse split 1:10", CorporateActionLabel(CorporateActionSplit, 1, 10, 10))
+}
@@ -0,0 +1,63 @@
+package dto
+
+import "math"
+
+// FundingIntervalHours periode funding perpetual futures binance
+const FundingIntervalHours = 8
+
+// Hasil pembacaan kombinasi arah harga & open interest
+const (
+	OpenInterestNewLongs        = "NEW_LONGS"        // harga naik, OI naik: kenaikan didukung posisi baru
+	OpenInterestShortCovering   = "SHORT_COVERING"   // harga naik, OI turun: kenaikan dari short yang ditutup, rapuh
+	OpenInterestNewShorts       = "NEW_SHORTS"       // harga turun, OI naik: penurunan didukung posisi baru
+	OpenInterestLongLiquidation = "LONG_LIQUIDATION" // harga turun, OI turun: long keluar / likuidasi
+	OpenInterestNeutral         = "NEUTRAL"
+)
+
+// perubahan minimal (persen) supaya arah harga / OI dianggap signifikan
+const (
+	minOpenInterestChangePercent = 1.0
+	minPriceChangePercent        = 0.5
+)
+
+// DerivativesData data pasar futures perpetual sebagai input indikator tambahan di analisa.
+// Funding rate dalam persen per periode funding (0.01 = 0.01% per 8 jam).
+type DerivativesData struct {
+	MarkPrice                 float64 `json:"mark_price"`
+	IndexPrice                float64 `json:"index_price"`
+	FundingRate               float64 `json:"funding_rate"`
+	AvgFundingRate            float64 `json:"avg_funding_rate"`  // rata-rata funding beberapa periode terakhir
+	NextFundingTime           int64   `json:"next_funding_time"` // unix ms
+	OpenInterest              float64 `json:"open_interest"`
+	OpenInterestValue         float64 `json:"open_interest_value"`
+	OpenInterestChangePercent float64 `json:"open_interest_change_percent"` // perubahan OI selama window histori
+	PriceChangePercent        float64 `json:"price_change_percent"`         // perubahan harga pada window yang sama
+}
+
+// FundingCostPercent estimasi funding yang dibayar (positif) atau diterima (negatif) posisi selama hours,
+// dalam persen notional. Funding positif dibayar LONG ke SHORT.
+func (d DerivativesData) FundingCostPercent(side string, hours float64) float64 {
+	rate := d.AvgFundingRate
+	if rate == 0 {
+		rate = d.FundingRate
+	}
+	return SideDirection(side) * rate * hours / FundingIntervalHours
+}
+
+// OpenInterestSignal membaca arah harga & open interest pada window histori
+func (d DerivativesData) OpenInterestSignal() string {
+	if math.Abs(d.OpenInterestChangePercent) < minOpenInterestChangePercent || math.Abs(d.PriceChangePercent) < minPriceChangePercent {
+		return OpenInterestNeutral
+	}
+
+	switch {
+	case d.PriceChangePercent > 0 && d.OpenInterestChangePercent > 0:
+		return OpenInterestNewLongs
+	case d.PriceChangePercent > 0:
+		return OpenInterestShortCovering
+	case d.OpenInterestChangePercent > 0:
+		return OpenInterestNewShorts
+	default:
+		return OpenInterestLongLiquidation
+	}
+}
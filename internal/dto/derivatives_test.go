@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"testing"
+
+	"golang-trading/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerivativesFundingCostPercent(t *testing.T) {
+	d := DerivativesData{FundingRate: 0.03, AvgFundingRate: 0.01}
+
+	// rata-rata funding dipakai jika ada, 3 periode per hari
+	assert.InDelta(t, 0.03, d.FundingCostPercent(model.PositionSideLong, 24), 1e-9)
+	assert.InDelta(t, -0.03, d.FundingCostPercent(model.PositionSideShort, 24), 1e-9)
+
+	d.AvgFundingRate = 0
+	assert.InDelta(t, 0.09, d.FundingCostPercent(model.PositionSideLong, 24), 1e-9)
+}
+
+func TestDerivativesOpenInterestSignal(t *testing.T) {
+	assert.Equal(t, OpenInterestNewLongs, DerivativesData{OpenInterestChangePercent: 5, PriceChangePercent: 2}.OpenInterestSignal())
+	assert.Equal(t, OpenInterestShortCovering, DerivativesData{OpenInterestChangePercent: -5, PriceChangePercent: 2}.OpenInterestSignal())
+	assert.Equal(t, OpenInterestNewShorts, DerivativesData{OpenInterestChangePercent: 5, PriceChangePercent: -2}.OpenInterestSignal())
+	assert.Equal(t, OpenInterestLongLiquidation, DerivativesData{OpenInterestChangePercent: -5, PriceChangePercent: -2}.OpenInterestSignal())
+	// perubahan kecil dianggap netral
+	assert.Equal(t, OpenInterestNeutral, DerivativesData{OpenInterestChangePercent: 0.5, PriceChangePercent: 2}.OpenInterestSignal())
+}
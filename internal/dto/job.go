@@ -0,0 +1,215 @@
+package dto
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-trading/internal/model"
+	"golang-trading/pkg/utils"
+)
+
+const (
+	DefaultHistoryPageSize = 20
+	MaxHistoryPageSize     = 100
+)
+
+// JobRequest body create / update job, payload divalidasi terhadap payload struct strategy Type
+type JobRequest struct {
+	Name        string          `json:"name" validate:"required,max=255"`
+	Description string          `json:"description"`
+	Type        string          `json:"type" validate:"required,max=50"`
+	Payload     json.RawMessage `json:"payload" validate:"required"`
+	RetryPolicy json.RawMessage `json:"retry_policy"`
+	Timeout     int             `json:"timeout" validate:"gte=0"` // detik, 0 memakai default 60
+	// DependsOn job upstream pipeline, mengganti seluruh upstream job. Tidak dikirim = tidak diubah, [] = dihapus semua.
+	DependsOn []uint `json:"depends_on" validate:"omitempty,dive,gt=0"`
+}
+
+// JobDependencyRequest body menambah upstream job
+type JobDependencyRequest struct {
+	DependsOnJobID uint `json:"depends_on_job_id" validate:"required"`
+}
+
+// TaskScheduleRequest body create / update schedule job
+type TaskScheduleRequest struct {
+	CronExpression string `json:"cron_expression" validate:"required,max=100"`
+	IsActive       *bool  `json:"is_active"` // default true
+	Exchange       string `json:"exchange" validate:"required_with=MarketHours,max=50"`
+	MarketHours    string `json:"market_hours" validate:"omitempty,oneof=trading_day session extended"`
+}
+
+// TaskExecutionHistoryFilter query history eksekusi job, from & to tanggal WIB (2006-01-02) inklusif
+type TaskExecutionHistoryFilter struct {
+	JobID    uint   `query:"job_id"`
+	Status   string `query:"status" validate:"omitempty,oneof=running completed failed timeout skipped dead_letter"`
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Page     int    `query:"page" validate:"gte=0"`
+	PageSize int    `query:"page_size" validate:"gte=0,lte=100"`
+}
+
+// ToParam konversi filter ke parameter repository, page default 1 & page size default DefaultHistoryPageSize
+func (f TaskExecutionHistoryFilter) ToParam() (model.GetTaskExecutionHistoriesParam, error) {
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+	if f.PageSize <= 0 {
+		f.PageSize = DefaultHistoryPageSize
+	}
+	param := model.GetTaskExecutionHistoriesParam{
+		JobID:  f.JobID,
+		Status: model.TaskExecutionStatus(f.Status),
+		Limit:  min(f.PageSize, MaxHistoryPageSize),
+	}
+	param.Offset = (f.Page - 1) * param.Limit
+
+	loc := utils.TimeNowWIB().Location()
+	if f.From != "" {
+		from, err := time.ParseInLocation(time.DateOnly, f.From, loc)
+		if err != nil {
+			return param, fmt.Errorf("invalid from date: %w", err)
+		}
+		param.From = from
+	}
+	if f.To != "" {
+		to, err := time.ParseInLocation(time.DateOnly, f.To, loc)
+		if err != nil {
+			return param, fmt.Errorf("invalid to date: %w", err)
+		}
+		param.To = to.AddDate(0, 0, 1)
+	}
+	if !param.From.IsZero() && !param.To.IsZero() && !param.From.Before(param.To) {
+		return param, fmt.Errorf("from date must not be after to date")
+	}
+	return param, nil
+}
+
+// PaginatedResponse data satu halaman beserta total seluruh data yang cocok dengan filter
+type PaginatedResponse struct {
+	Items    interface{} `json:"items"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int64       `json:"total"`
+}
+
+type JobResponse struct {
+	ID          uint                   `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Type        string                 `json:"type"`
+	Payload     json.RawMessage        `json:"payload"`
+	RetryPolicy json.RawMessage        `json:"retry_policy,omitempty"`
+	Timeout     int                    `json:"timeout"`
+	Schedules   []TaskScheduleResponse `json:"schedules"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+type TaskScheduleResponse struct {
+	ID             uint       `json:"id"`
+	JobID          uint       `json:"job_id"`
+	CronExpression string     `json:"cron_expression"`
+	NextExecution  *time.Time `json:"next_execution"`
+	LastExecution  *time.Time `json:"last_execution"`
+	IsActive       bool       `json:"is_active"`
+	Exchange       string     `json:"exchange,omitempty"`
+	MarketHours    string     `json:"market_hours,omitempty"`
+	LockedBy       string     `json:"locked_by,omitempty"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type TaskExecutionHistoryResponse struct {
+	ID           uint       `json:"id"`
+	JobID        uint       `json:"job_id"`
+	ScheduleID   uint       `json:"schedule_id"`
+	ParentID     *uint      `json:"parent_id,omitempty"`
+	Attempt      int        `json:"attempt"`
+	InstanceID   string     `json:"instance_id,omitempty"`
+	Status       string     `json:"status"`
+	StartedAt    time.Time  `json:"started_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	ExitCode     *int32     `json:"exit_code"`
+	Output       string     `json:"output,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+}
+
+type JobDependencyResponse struct {
+	JobID          uint      `json:"job_id"`
+	DependsOnJobID uint      `json:"depends_on_job_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func NewJobResponse(job model.Job) JobResponse {
+	response := JobResponse{
+		ID:          job.ID,
+		Name:        job.Name,
+		Description: job.Description,
+		Type:        job.Type,
+		Payload:     json.RawMessage(job.Payload),
+		RetryPolicy: json.RawMessage(job.RetryPolicy),
+		Timeout:     job.Timeout,
+		Schedules:   make([]TaskScheduleResponse, 0, len(job.Schedules)),
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+	for _, schedule := range job.Schedules {
+		response.Schedules = append(response.Schedules, NewTaskScheduleResponse(schedule))
+	}
+	return response
+}
+
+func NewTaskScheduleResponse(schedule model.TaskSchedule) TaskScheduleResponse {
+	return TaskScheduleResponse{
+		ID:             schedule.ID,
+		JobID:          schedule.JobID,
+		CronExpression: schedule.CronExpression,
+		NextExecution:  nullTime(schedule.NextExecution),
+		LastExecution:  nullTime(schedule.LastExecution),
+		IsActive:       schedule.IsActive,
+		Exchange:       schedule.Exchange,
+		MarketHours:    schedule.MarketHours,
+		LockedBy:       schedule.LockedBy,
+		LockedUntil:    nullTime(schedule.LockedUntil),
+		CreatedAt:      schedule.CreatedAt,
+		UpdatedAt:      schedule.UpdatedAt,
+	}
+}
+
+func NewTaskExecutionHistoryResponse(history model.TaskExecutionHistory) TaskExecutionHistoryResponse {
+	response := TaskExecutionHistoryResponse{
+		ID:           history.ID,
+		JobID:        history.JobID,
+		ScheduleID:   history.ScheduleID,
+		ParentID:     history.ParentID,
+		Attempt:      history.Attempt,
+		InstanceID:   history.InstanceID,
+		Status:       string(history.Status),
+		StartedAt:    history.StartedAt,
+		CompletedAt:  nullTime(history.CompletedAt),
+		Output:       history.Output.String,
+		ErrorMessage: history.ErrorMessage.String,
+	}
+	if history.ExitCode.Valid {
+		response.ExitCode = &history.ExitCode.Int32
+	}
+	return response
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func NewJobDependencyResponse(dependency model.JobDependency) JobDependencyResponse {
+	return JobDependencyResponse{
+		JobID:          dependency.JobID,
+		DependsOnJobID: dependency.DependsOnJobID,
+		CreatedAt:      dependency.CreatedAt,
+	}
+}
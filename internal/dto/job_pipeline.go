@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+
+	"golang-trading/internal/model"
+)
+
+// JobPipelineStep job hilir pipeline beserta upstream-nya yang ikut dijalankan di pipeline yang sama
+type JobPipelineStep struct {
+	JobID    uint
+	Upstream []uint
+}
+
+// BuildJobPipeline urutan topologis semua job hilir rootJobID (root tidak termasuk).
+// Upstream di luar pipeline diabaikan karena dijalankan schedule-nya sendiri. Error jika dependency membentuk siklus.
+func BuildJobPipeline(rootJobID uint, dependencies []model.JobDependency) ([]JobPipelineStep, error) {
+	downstream := make(map[uint][]uint)
+	upstream := make(map[uint][]uint)
+	for _, dep := range dependencies {
+		downstream[dep.DependsOnJobID] = append(downstream[dep.DependsOnJobID], dep.JobID)
+		upstream[dep.JobID] = append(upstream[dep.JobID], dep.DependsOnJobID)
+	}
+
+	// job yang bisa dicapai dari root
+	inPipeline := map[uint]bool{}
+	queue := []uint{rootJobID}
+	for len(queue) > 0 {
+		jobID := queue[0]
+		queue = queue[1:]
+		for _, next := range downstream[jobID] {
+			if next == rootJobID {
+				return nil, fmt.Errorf("job %d is part of a dependency cycle", rootJobID)
+			}
+			if !inPipeline[next] {
+				inPipeline[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	steps := make(map[uint]*JobPipelineStep, len(inPipeline))
+	pending := make(map[uint]int, len(inPipeline))
+	for jobID := range inPipeline {
+		step := &JobPipelineStep{JobID: jobID}
+		for _, up := range upstream[jobID] {
+			if up == rootJobID || inPipeline[up] {
+				step.Upstream = append(step.Upstream, up)
+			}
+			if inPipeline[up] {
+				pending[jobID]++
+			}
+		}
+		slices.Sort(step.Upstream)
+		steps[jobID] = step
+	}
+
+	// Kahn, job dengan ID terkecil didahulukan supaya urutan stabil
+	ordered := make([]JobPipelineStep, 0, len(steps))
+	ready := []uint{}
+	for jobID := range steps {
+		if pending[jobID] == 0 {
+			ready = append(ready, jobID)
+		}
+	}
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		jobID := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, *steps[jobID])
+		for _, next := range downstream[jobID] {
+			if !inPipeline[next] {
+				continue
+			}
+			pending[next]--
+			if pending[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(ordered) != len(steps) {
+		return nil, fmt.Errorf("job pipeline of job %d contains a dependency cycle", rootJobID)
+	}
+	return ordered, nil
+}
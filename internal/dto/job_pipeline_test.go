@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"testing"
+
+	"golang-trading/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJobPipeline(t *testing.T) {
+	// 1 analyze -> 2 signal -> 3 monitor -> 4 digest, 4 juga menunggu 2, 3 juga menunggu job 9 di luar pipeline
+	deps := []model.JobDependency{
+		{JobID: 2, DependsOnJobID: 1},
+		{JobID: 3, DependsOnJobID: 2},
+		{JobID: 3, DependsOnJobID: 9},
+		{JobID: 4, DependsOnJobID: 3},
+		{JobID: 4, DependsOnJobID: 2},
+	}
+
+	steps, err := BuildJobPipeline(1, deps)
+	assert.NoError(t, err)
+	assert.Equal(t, []JobPipelineStep{
+		{JobID: 2, Upstream: []uint{1}},
+		{JobID: 3, Upstream: []uint{2}},
+		{JobID: 4, Upstream: []uint{2, 3}},
+	}, steps)
+
+	// pipeline dari tengah hanya berisi job hilirnya
+	steps, err = BuildJobPipeline(3, deps)
+	assert.NoError(t, err)
+	assert.Equal(t, []JobPipelineStep{{JobID: 4, Upstream: []uint{3}}}, steps)
+
+	steps, err = BuildJobPipeline(4, deps)
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+}
+
+func TestBuildJobPipelineCycle(t *testing.T) {
+	_, err := BuildJobPipeline(1, []model.JobDependency{{JobID: 2, DependsOnJobID: 1}, {JobID: 1, DependsOnJobID: 2}})
+	assert.Error(t, err)
+
+	// siklus di hilir root
+	_, err = BuildJobPipeline(1, []model.JobDependency{
+		{JobID: 2, DependsOnJobID: 1},
+		{JobID: 3, DependsOnJobID: 2},
+		{JobID: 2, DependsOnJobID: 3},
+	})
+	assert.Error(t, err)
+
+	_, err = BuildJobPipeline(1, []model.JobDependency{{JobID: 1, DependsOnJobID: 1}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	BackoffStrategyFixed       = "fixed"
+	BackoffStrategyExponential = "exponential"
+)
+
+// RetryPolicy isi kolom jobs.retry_policy. Attempt pertama bukan retry, total eksekusi paling banyak MaxRetries+1.
+type RetryPolicy struct {
+	MaxRetries       int     `json:"max_retries"`
+	BackoffStrategy  string  `json:"backoff_strategy"`              // fixed / exponential, default fixed
+	InitialInterval  string  `json:"initial_interval"`              // jeda sebelum retry pertama, format time.ParseDuration
+	MaxInterval      string  `json:"max_interval,omitempty"`        // batas jeda exponential, kosong = tanpa batas
+	Multiplier       float64 `json:"multiplier,omitempty"`          // pengali exponential, default 2
+	RetryOnExitCodes []int32 `json:"retry_on_exit_codes,omitempty"` // exit code yang di-retry, kosong = default scheduler
+
+	initialInterval time.Duration
+	maxInterval     time.Duration
+}
+
+// ParseRetryPolicy membaca retry_policy job, kosong / null berarti tanpa retry
+func ParseRetryPolicy(raw []byte) (RetryPolicy, error) {
+	var policy RetryPolicy
+	if len(raw) == 0 || string(raw) == "null" {
+		return policy, nil
+	}
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return RetryPolicy{}, fmt.Errorf("failed to unmarshal retry policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return RetryPolicy{}, err
+	}
+	return policy, nil
+}
+
+// Validate memastikan strategi & interval bisa dipakai, policy tanpa retry tidak divalidasi lebih lanjut
+func (p *RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must not be negative")
+	}
+	if p.MaxRetries == 0 {
+		return nil
+	}
+
+	switch p.BackoffStrategy {
+	case "":
+		p.BackoffStrategy = BackoffStrategyFixed
+	case BackoffStrategyFixed, BackoffStrategyExponential:
+	default:
+		return fmt.Errorf("backoff_strategy %q is not supported", p.BackoffStrategy)
+	}
+
+	initial, err := time.ParseDuration(p.InitialInterval)
+	if err != nil || initial <= 0 {
+		return fmt.Errorf("initial_interval %q must be a positive duration", p.InitialInterval)
+	}
+	p.initialInterval = initial
+
+	if p.MaxInterval != "" {
+		maxInterval, err := time.ParseDuration(p.MaxInterval)
+		if err != nil || maxInterval < initial {
+			return fmt.Errorf("max_interval %q must be a duration not less than initial_interval", p.MaxInterval)
+		}
+		p.maxInterval = maxInterval
+	}
+
+	if p.Multiplier == 0 {
+		p.Multiplier = 2
+	}
+	if p.Multiplier < 1 {
+		return fmt.Errorf("multiplier must be at least 1")
+	}
+	return nil
+}
+
+// ShouldRetry true jika attempt (mulai 1) yang berakhir dengan exitCode masih boleh diulang
+func (p RetryPolicy) ShouldRetry(attempt int, exitCode int32) bool {
+	return attempt <= p.MaxRetries && slices.Contains(p.RetryOnExitCodes, exitCode)
+}
+
+// Backoff jeda sebelum attempt berikutnya setelah attempt (mulai 1) gagal
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if p.BackoffStrategy != BackoffStrategyExponential || attempt <= 1 {
+		return p.initialInterval
+	}
+
+	backoff := float64(p.initialInterval) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.maxInterval > 0 && backoff > float64(p.maxInterval) {
+		return p.maxInterval
+	}
+	if backoff > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(backoff)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	policy, err := ParseRetryPolicy(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, policy.MaxRetries)
+
+	// placeholder seed lama tanpa retry tetap valid
+	policy, err = ParseRetryPolicy([]byte(`{"max_retries": 0, "backoff_strategy": "string", "initial_interval": "string"}`))
+	assert.NoError(t, err)
+	assert.False(t, policy.ShouldRetry(1, 500))
+
+	_, err = ParseRetryPolicy([]byte(`{"max_retries": 2, "backoff_strategy": "string", "initial_interval": "1m"}`))
+	assert.Error(t, err)
+	_, err = ParseRetryPolicy([]byte(`{"max_retries": 2, "initial_interval": "string"}`))
+	assert.Error(t, err)
+	_, err = ParseRetryPolicy([]byte(`{"max_retries": 2, "initial_interval": "1m", "max_interval": "30s"}`))
+	assert.Error(t, err)
+	_, err = ParseRetryPolicy([]byte(`{"max_retries": -1}`))
+	assert.Error(t, err)
+
+	policy, err = ParseRetryPolicy([]byte(`{"max_retries": 2, "initial_interval": "30s", "retry_on_exit_codes": [500, 206]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, BackoffStrategyFixed, policy.BackoffStrategy)
+	assert.True(t, policy.ShouldRetry(1, 500))
+	assert.True(t, policy.ShouldRetry(2, 206))
+	assert.False(t, policy.ShouldRetry(3, 500))
+	assert.False(t, policy.ShouldRetry(1, 200))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy, err := ParseRetryPolicy([]byte(`{"max_retries": 5, "backoff_strategy": "fixed", "initial_interval": "30s"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, policy.Backoff(1))
+	assert.Equal(t, 30*time.Second, policy.Backoff(4))
+
+	policy, err = ParseRetryPolicy([]byte(`{"max_retries": 5, "backoff_strategy": "exponential", "initial_interval": "1m", "max_interval": "5m"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, policy.Backoff(1))
+	assert.Equal(t, 2*time.Minute, policy.Backoff(2))
+	assert.Equal(t, 4*time.Minute, policy.Backoff(3))
+	assert.Equal(t, 5*time.Minute, policy.Backoff(4))
+
+	policy, err = ParseRetryPolicy([]byte(`{"max_retries": 3, "backoff_strategy": "exponential", "initial_interval": "10s", "multiplier": 3}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, policy.Backoff(3))
+}
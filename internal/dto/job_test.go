@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"golang-trading/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskExecutionHistoryFilterToParam(t *testing.T) {
+	param, err := TaskExecutionHistoryFilter{}.ToParam()
+	assert.NoError(t, err)
+	assert.Equal(t, model.GetTaskExecutionHistoriesParam{Limit: DefaultHistoryPageSize}, param)
+
+	param, err = TaskExecutionHistoryFilter{JobID: 4, Status: "failed", From: "2025-07-01", To: "2025-07-03", Page: 3, PageSize: 500}.ToParam()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), param.JobID)
+	assert.Equal(t, model.StatusFailed, param.Status)
+	assert.Equal(t, MaxHistoryPageSize, param.Limit)
+	assert.Equal(t, 2*MaxHistoryPageSize, param.Offset)
+	// to inklusif sampai akhir hari WIB
+	assert.Equal(t, "2025-07-01T00:00:00+07:00", param.From.Format(time.RFC3339))
+	assert.Equal(t, "2025-07-04T00:00:00+07:00", param.To.Format(time.RFC3339))
+
+	_, err = TaskExecutionHistoryFilter{From: "2025-07-03", To: "2025-07-01"}.ToParam()
+	assert.Error(t, err)
+	_, err = TaskExecutionHistoryFilter{From: "03-07-2025"}.ToParam()
+	assert.Error(t, err)
+}
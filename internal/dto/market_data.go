@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// MarketDataProviderHealth statistik request provider market data sejak aplikasi berjalan
+type MarketDataProviderHealth struct {
+	Provider            string     `json:"provider"`
+	Exchanges           []string   `json:"exchanges"`
+	SuccessCount        int64      `json:"success_count"`
+	FailureCount        int64      `json:"failure_count"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DivergenceCount     int64      `json:"divergence_count"`
+	AvgLatencyMs        float64    `json:"avg_latency_ms"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	CoolingDownUntil    *time.Time `json:"cooling_down_until,omitempty"`
+}
@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"math"
+	"time"
+
+	"golang-trading/internal/costmodel"
+	"golang-trading/internal/model"
+)
+
+// PositionLedger ringkasan transaksi sebuah posisi dengan metode average cost.
+// Nominal P/L sudah termasuk fee buka & tutup posisi. Untuk posisi SHORT transaksi SELL membuka
+// posisi dan BUY menutupnya, CostBasis berisi hasil jual bersih fee.
+type PositionLedger struct {
+	Side               string
+	OpenQuantity       float64
+	ClosedQuantity     float64
+	RemainingQuantity  float64
+	AveragePrice       float64 // harga entry rata-rata sisa posisi tanpa fee, acuan % perubahan harga
+	AverageCost        float64 // nilai entry rata-rata per unit sisa posisi termasuk fee (harga break even)
+	AverageExitPrice   float64
+	CostBasis          float64 // nilai entry untuk sisa quantity
+	RealizedProfitLoss float64
+	RealizedCostBasis  float64 // nilai entry dari quantity yang sudah ditutup
+	TotalFee           float64
+	LastExitDate       time.Time
+}
+
+// BuildPositionLedger menghitung ledger dari transaksi posisi. Posisi lama tanpa transaksi
+// dianggap satu kali entry (dan satu kali exit jika sudah exit) sesuai quantity yang tercatat.
+func BuildPositionLedger(position model.StockPosition, cost costmodel.Model) PositionLedger {
+	openType, closeType := OpenTransactionType(position.Side), CloseTransactionType(position.Side)
+	transactions := position.Transactions
+	if len(transactions) == 0 && position.Quantity > 0 {
+		transactions = append(transactions, NewPositionTransaction(cost, openType, position.Quantity, position.BuyPrice, position.BuyDate))
+		if position.ExitPrice != nil && position.ExitDate != nil {
+			transactions = append(transactions, NewPositionTransaction(cost, closeType, position.Quantity, *position.ExitPrice, *position.ExitDate))
+		}
+	}
+
+	var (
+		ledger     = PositionLedger{Side: NormalizeSide(position.Side)}
+		direction  = SideDirection(position.Side)
+		priceBasis float64
+		exitValue  float64
+	)
+	for _, tx := range transactions {
+		ledger.TotalFee += tx.Fee
+		switch tx.Type {
+		case openType:
+			ledger.OpenQuantity += tx.Quantity
+			ledger.RemainingQuantity += tx.Quantity
+			ledger.CostBasis += tx.Price*tx.Quantity + direction*tx.Fee
+			priceBasis += tx.Price * tx.Quantity
+
+		case closeType:
+			quantity := math.Min(tx.Quantity, ledger.RemainingQuantity)
+			if quantity <= 0 {
+				continue
+			}
+			portion := quantity / ledger.RemainingQuantity
+			closedCost := ledger.CostBasis * portion
+
+			ledger.RealizedProfitLoss += direction * (tx.Price*quantity - direction*tx.Fee - closedCost)
+			ledger.RealizedCostBasis += closedCost
+			ledger.CostBasis -= closedCost
+			priceBasis -= priceBasis * portion
+			ledger.RemainingQuantity -= quantity
+			ledger.ClosedQuantity += quantity
+			exitValue += tx.Price * quantity
+			ledger.LastExitDate = tx.TransactionDate
+		}
+	}
+
+	if ledger.RemainingQuantity > 0 {
+		ledger.AverageCost = ledger.CostBasis / ledger.RemainingQuantity
+		ledger.AveragePrice = priceBasis / ledger.RemainingQuantity
+	}
+	if ledger.ClosedQuantity > 0 {
+		ledger.AverageExitPrice = exitValue / ledger.ClosedQuantity
+	}
+	return ledger
+}
+
+// NewPositionTransaction membuat transaksi dengan fee sesuai model biaya exchange
+func NewPositionTransaction(cost costmodel.Model, txType string, quantity, price float64, date time.Time) model.StockPositionTransaction {
+	fee := cost.BuyCost(price, quantity) - price*quantity
+	if txType == model.StockPositionTransactionTypeSell {
+		fee = price*quantity - cost.SellProceeds(price, quantity)
+	}
+	return model.StockPositionTransaction{
+		Type:            txType,
+		Quantity:        quantity,
+		Price:           price,
+		Fee:             fee,
+		TransactionDate: date,
+	}
+}
+
+// IsTracked true jika posisi mencatat quantity sehingga P/L nominal bisa dihitung
+func (l PositionLedger) IsTracked() bool {
+	return l.OpenQuantity > 0
+}
+
+// UnrealizedProfitLoss P/L sisa posisi jika ditutup di markPrice, setelah fee
+func (l PositionLedger) UnrealizedProfitLoss(cost costmodel.Model, markPrice float64) float64 {
+	if l.RemainingQuantity <= 0 {
+		return 0
+	}
+	if IsShort(l.Side) {
+		return l.CostBasis - cost.BuyCost(markPrice, l.RemainingQuantity)
+	}
+	return cost.SellProceeds(markPrice, l.RemainingQuantity) - l.CostBasis
+}
+
+// RealizedProfitLossPercent P/L yang sudah direalisasikan terhadap nilai entry bagian yang ditutup
+func (l PositionLedger) RealizedProfitLossPercent() float64 {
+	if l.RealizedCostBasis == 0 {
+		return 0
+	}
+	return l.RealizedProfitLoss / l.RealizedCostBasis * 100
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"golang-trading/internal/costmodel"
+	"golang-trading/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPositionLedger(t *testing.T) {
+	cost := costmodel.ForExchange("UNKNOWN") // tanpa fee supaya angka mudah dicek
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	position := model.StockPosition{Transactions: []model.StockPositionTransaction{
+		NewPositionTransaction(cost, model.StockPositionTransactionTypeBuy, 100, 10, day(1)),
+		NewPositionTransaction(cost, model.StockPositionTransactionTypeBuy, 100, 8, day(2)), // average down
+		NewPositionTransaction(cost, model.StockPositionTransactionTypeSell, 100, 12, day(3)),
+	}}
+
+	ledger := BuildPositionLedger(position, cost)
+	assert.Equal(t, 100.0, ledger.RemainingQuantity)
+	assert.InDelta(t, 9, ledger.AveragePrice, 1e-9)
+	assert.InDelta(t, 300, ledger.RealizedProfitLoss, 1e-9)
+	assert.InDelta(t, 33.333, ledger.RealizedProfitLossPercent(), 1e-3)
+	assert.InDelta(t, 100, ledger.UnrealizedProfitLoss(cost, 10), 1e-9)
+	assert.Equal(t, day(3), ledger.LastExitDate)
+}
+
+func TestBuildPositionLedgerShort(t *testing.T) {
+	cost := costmodel.ForExchange("BINANCE")
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	position := model.StockPosition{Side: model.PositionSideShort, Transactions: []model.StockPositionTransaction{
+		NewPositionTransaction(cost, model.StockPositionTransactionTypeSell, 2, 100, day(1)),
+		NewPositionTransaction(cost, model.StockPositionTransactionTypeBuy, 1, 90, day(2)), // tutup sebagian
+	}}
+
+	ledger := BuildPositionLedger(position, cost)
+	assert.Equal(t, 2.0, ledger.OpenQuantity)
+	assert.Equal(t, 1.0, ledger.RemainingQuantity)
+	assert.InDelta(t, cost.PositionProfitLoss(100, 90, true), ledger.RealizedProfitLoss, 1e-9)
+	assert.InDelta(t, 99.9, ledger.AverageCost, 1e-9)
+	assert.InDelta(t, cost.PositionProfitLoss(100, 110, true), ledger.UnrealizedProfitLoss(cost, 110), 1e-9)
+}
+
+func TestBuildPositionLedgerLegacyPosition(t *testing.T) {
+	cost := costmodel.ForExchange("IDX")
+	exitPrice, exitDate := 1100.0, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	ledger := BuildPositionLedger(model.StockPosition{
+		BuyPrice: 1000, Quantity: 1000, BuyDate: exitDate.AddDate(0, 0, -3),
+		ExitPrice: &exitPrice, ExitDate: &exitDate,
+	}, cost)
+	assert.True(t, ledger.IsTracked())
+	assert.Zero(t, ledger.RemainingQuantity)
+	assert.InDelta(t, cost.SellProceeds(1100, 1000)-cost.BuyCost(1000, 1000), ledger.RealizedProfitLoss, 1e-6)
+
+	assert.False(t, BuildPositionLedger(model.StockPosition{BuyPrice: 1000}, cost).IsTracked())
+}
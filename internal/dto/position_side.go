@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"strings"
+
+	"golang-trading/internal/model"
+)
+
+// NormalizeSide side dalam huruf besar, kosong / tidak dikenal dianggap LONG (posisi lama sebelum ada side)
+func NormalizeSide(side string) string {
+	if strings.ToUpper(strings.TrimSpace(side)) == model.PositionSideShort {
+		return model.PositionSideShort
+	}
+	return model.PositionSideLong
+}
+
+// IsShort true jika side SHORT
+func IsShort(side string) bool {
+	return NormalizeSide(side) == model.PositionSideShort
+}
+
+// SideDirection 1 untuk LONG dan -1 untuk SHORT. (harga - entry) * arah positif berarti posisi profit
+func SideDirection(side string) float64 {
+	if IsShort(side) {
+		return -1
+	}
+	return 1
+}
+
+// ProfitPercent persen pergerakan harga dari entry yang menguntungkan posisi (negatif jika merugi)
+func ProfitPercent(side string, entry, price float64) float64 {
+	if entry == 0 {
+		return 0
+	}
+	return (price - entry) / entry * 100 * SideDirection(side)
+}
+
+// IsTargetReached true jika harga sudah mencapai target profit: di atas target untuk LONG, di bawah untuk SHORT
+func IsTargetReached(side string, price, target float64) bool {
+	return target > 0 && (price-target)*SideDirection(side) >= 0
+}
+
+// IsStopReached true jika harga sudah menyentuh level stop: di bawah stop untuk LONG, di atas untuk SHORT
+func IsStopReached(side string, price, stop float64) bool {
+	return stop > 0 && (price-stop)*SideDirection(side) <= 0
+}
+
+// IsMoreFavorable true jika price lebih menguntungkan posisi dibanding than: lebih tinggi untuk LONG,
+// lebih rendah untuk SHORT. Dipakai untuk trailing stop & harga ekstrem, than 0 berarti belum ada nilai.
+func IsMoreFavorable(side string, price, than float64) bool {
+	if price <= 0 {
+		return false
+	}
+	return than <= 0 || (price-than)*SideDirection(side) > 0
+}
+
+// OpenTransactionType jenis transaksi yang membuka / menambah posisi: BUY untuk LONG, SELL untuk SHORT
+func OpenTransactionType(side string) string {
+	if IsShort(side) {
+		return model.StockPositionTransactionTypeSell
+	}
+	return model.StockPositionTransactionTypeBuy
+}
+
+// CloseTransactionType jenis transaksi yang menutup / mengurangi posisi: SELL untuk LONG, BUY untuk SHORT
+func CloseTransactionType(side string) string {
+	if IsShort(side) {
+		return model.StockPositionTransactionTypeBuy
+	}
+	return model.StockPositionTransactionTypeSell
+}
+
+// InferSide menentukan side dari posisi TP & SL terhadap harga entry, TP di bawah dan SL di atas entry berarti SHORT
+func InferSide(entry, takeProfit, stopLoss float64) string {
+	if takeProfit > 0 && takeProfit < entry && stopLoss > entry {
+		return model.PositionSideShort
+	}
+	return model.PositionSideLong
+}
+
+// SideText label side untuk ditampilkan ke user
+func SideText(side string) string {
+	if IsShort(side) {
+		return "📉 SHORT"
+	}
+	return "📈 LONG"
+}
+
+// IsSignalWithSide true jika sinyal teknikal searah posisi: Buy / Strong Buy untuk LONG, Sell / Strong Sell untuk SHORT
+func IsSignalWithSide(side, signal string) bool {
+	if IsShort(side) {
+		return signal == SignalSell || signal == SignalStrongSell
+	}
+	return signal == SignalBuy || signal == SignalStrongBuy
+}
+
+// IsSignalAgainstSide true jika sinyal teknikal berlawanan dengan posisi
+func IsSignalAgainstSide(side, signal string) bool {
+	return IsSignalWithSide(OppositeSide(side), signal)
+}
+
+// OppositeSide arah kebalikan posisi
+func OppositeSide(side string) string {
+	if IsShort(side) {
+		return model.PositionSideLong
+	}
+	return model.PositionSideShort
+}
@@ -14,6 +14,16 @@ type StockData struct {
 	Range       string       `json:"range"`
 	Interval    string       `json:"interval"`
 	OHLCV       []StockOHLCV `json:"ohlc"`
+	// Derivatives hanya terisi untuk sumber futures (funding rate & open interest)
+	Derivatives *DerivativesData `json:"derivatives,omitempty"`
+	// Source nama provider market data yang melayani request (bisa provider fallback / candle_cache)
+	Source string `json:"source,omitempty"`
+	// PriceDivergencePercent selisih market price dengan provider pembanding jika melewati batas
+	PriceDivergencePercent float64 `json:"price_divergence_percent,omitempty"`
+	// Quality hasil validasi kualitas candle (OHLCV sudah diperbaiki)
+	Quality *CandleQualityReport `json:"quality,omitempty"`
+	// CorporateActions dividen & split pada periode data yang dilaporkan provider
+	CorporateActions []CorporateAction `json:"corporate_actions,omitempty"`
 }
 
 type GetStockDataParam struct {
@@ -21,6 +31,11 @@ type GetStockDataParam struct {
 	Range     string `json:"range"`
 	Interval  string `json:"interval"`
 	Exchange  string `json:"exchange"`
+	// StartTime & EndTime (unix second) opsional, jika diisi akan override Range
+	StartTime int64 `json:"start_time,omitempty"`
+	EndTime   int64 `json:"end_time,omitempty"`
+	// Adjusted back-adjust harga sebelum ex-date dividen & rights issue, split selalu disesuaikan
+	Adjusted bool `json:"adjusted,omitempty"`
 }
 
 // Yahoo Finance API Response
@@ -41,6 +56,7 @@ type YahooFinanceResponse struct {
 					Volume []int64   `json:"volume"`
 				} `json:"quote"`
 			} `json:"indicators"`
+			Events *YahooFinanceEvents `json:"events"`
 		} `json:"result"`
 		Error interface{} `json:"error"`
 	} `json:"chart"`
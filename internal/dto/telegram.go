@@ -11,7 +11,9 @@ import (
 type RequestSetPositionData struct {
 	StockCode     string
 	Exchange      string
+	Side          string // model.PositionSideLong / Short, kosong = LONG
 	BuyPrice      float64
+	Quantity      float64
 	BuyDate       string
 	TakeProfit    float64
 	StopLoss      float64
@@ -22,6 +24,7 @@ type RequestSetPositionData struct {
 	SourceType    string
 	IsMessageEdit bool
 	PlanScore     float64
+	PlanVersion   string
 	PositionScore float64
 }
 
@@ -29,14 +32,18 @@ func (r *RequestSetPositionData) ToStockPositionEntity() *model.StockPosition {
 	return &model.StockPosition{
 		StockCode:       r.StockCode,
 		BuyPrice:        r.BuyPrice,
+		Quantity:        r.Quantity,
 		BuyDate:         utils.MustParseDate(r.BuyDate),
+		MaxHoldingDays:  r.MaxHolding,
 		TakeProfitPrice: r.TakeProfit,
 		StopLossPrice:   r.StopLoss,
 		PriceAlert:      utils.ToPointer(r.AlertPrice),
 		MonitorPosition: utils.ToPointer(r.AlertMonitor),
 		Exchange:        r.Exchange,
+		Side:            NormalizeSide(r.Side),
 		SourceType:      r.SourceType,
 		PlanScore:       r.PlanScore,
+		PlanVersion:     r.PlanVersion,
 		InitialScore:    r.PositionScore,
 	}
 }
@@ -80,6 +87,16 @@ type GetStockPositionsUserTelegramParam struct {
 	IsActive   *bool
 }
 
+type RequestPositionTransactionData struct {
+	Symbol                  string
+	StockPositionID         uint
+	Type                    string // model.StockPositionTransactionTypeBuy / Sell, lihat OpenTransactionType / CloseTransactionType
+	Quantity                float64
+	Price                   float64
+	Date                    time.Time
+	MoveStopLossToBreakEven bool // geser SL ke harga rata-rata setelah jual sebagian, sisa posisi dibiarkan trailing
+}
+
 type RequestExitPositionData struct {
 	Symbol          string
 	ExitPrice       float64
@@ -0,0 +1,182 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang-trading/pkg/utils"
+)
+
+const (
+	TimeframeProfileScalp    = "scalp"
+	TimeframeProfileSwing    = "swing"
+	TimeframeProfilePosition = "position"
+)
+
+// TimeframeProfile kumpulan timeframe yang dianalisa bersama untuk satu saham
+type TimeframeProfile struct {
+	Name       string          `json:"name"`
+	Timeframes []DataTimeframe `json:"timeframes"`
+}
+
+// Intervals interval setiap timeframe sesuai urutan profil
+func (p TimeframeProfile) Intervals() []string {
+	intervals := make([]string, 0, len(p.Timeframes))
+	for _, tf := range p.Timeframes {
+		intervals = append(intervals, tf.Interval)
+	}
+	return intervals
+}
+
+// Covers true jika setiap interval ada di profil
+func (p TimeframeProfile) Covers(intervals []string) bool {
+	for _, interval := range intervals {
+		if !slices.Contains(p.Intervals(), interval) {
+			return false
+		}
+	}
+	return true
+}
+
+// TimeframeProfileConfig isi system parameter ANALYSIS_TIMEFRAME_PROFILES.
+// Profil dipilih dari preferensi user, lalu symbol (EXCHANGE:SYMBOL), lalu exchange, lalu Default.
+// Default kosong berarti memakai DEFAULT_ANALYSIS_TIMEFRAMES.
+type TimeframeProfileConfig struct {
+	Default   string                     `json:"default"`
+	Profiles  map[string][]DataTimeframe `json:"profiles"`
+	Exchanges map[string]string          `json:"exchanges"`
+	Symbols   map[string]string          `json:"symbols"`
+}
+
+// DefaultTimeframeProfileConfig dipakai jika system parameter belum ada, profil di system parameter
+// menambah / menimpa profil bawaan
+func DefaultTimeframeProfileConfig() TimeframeProfileConfig {
+	return TimeframeProfileConfig{
+		Profiles: map[string][]DataTimeframe{
+			TimeframeProfileScalp: {
+				{Interval: Interval1Hour, Range: "1m", Weight: 3, IsMain: true},
+				{Interval: Interval4Hour, Range: "3m", Weight: 2},
+				{Interval: Interval15Min, Range: "14d", Weight: 1},
+			},
+			TimeframeProfileSwing: {
+				{Interval: Interval1Day, Range: "6m", Weight: 3, IsMain: true},
+				{Interval: Interval1Week, Range: "1y", Weight: 2},
+				{Interval: Interval4Hour, Range: "1m", Weight: 1},
+			},
+			TimeframeProfilePosition: {
+				{Interval: Interval1Week, Range: "1y", Weight: 3, IsMain: true},
+				{Interval: Interval1Day, Range: "6m", Weight: 2},
+				{Interval: Interval1Month, Range: "1y", Weight: 1},
+			},
+		},
+	}
+}
+
+// ProfileNames nama profil terurut, untuk pilihan preferensi user
+func (c TimeframeProfileConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile profil berdasarkan nama (case-insensitive)
+func (c TimeframeProfileConfig) Profile(name string) (TimeframeProfile, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	timeframes, ok := c.Profiles[name]
+	if !ok {
+		return TimeframeProfile{}, false
+	}
+	return TimeframeProfile{Name: name, Timeframes: timeframes}, true
+}
+
+// Candidates urutan nama profil untuk saham: preferensi user, symbol, exchange, default. Nama kosong dilewati.
+func (c TimeframeProfileConfig) Candidates(userProfile, exchange, stockCode string) []string {
+	exchange = strings.ToUpper(exchange)
+	names := []string{
+		userProfile,
+		c.Symbols[exchange+":"+strings.ToUpper(stockCode)],
+		c.Exchanges[exchange],
+		c.Default,
+	}
+
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates
+}
+
+// Validate memastikan setiap profil valid dan profil yang dirujuk default / exchange / symbol tersedia
+func (c TimeframeProfileConfig) Validate() error {
+	for name, timeframes := range c.Profiles {
+		if name != strings.ToLower(name) {
+			return fmt.Errorf("profile %q: name must be lowercase", name)
+		}
+		if err := ValidateTimeframes(timeframes); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+
+	if c.Default != "" {
+		if _, ok := c.Profile(c.Default); !ok {
+			return fmt.Errorf("default profile %q not found", c.Default)
+		}
+	}
+	for exchange, name := range c.Exchanges {
+		if _, ok := c.Profile(name); !ok {
+			return fmt.Errorf("exchange %s: profile %q not found", exchange, name)
+		}
+	}
+	for symbol, name := range c.Symbols {
+		if !strings.Contains(symbol, ":") {
+			return fmt.Errorf("symbol %s: must be in EXCHANGE:SYMBOL format", symbol)
+		}
+		if _, ok := c.Profile(name); !ok {
+			return fmt.Errorf("symbol %s: profile %q not found", symbol, name)
+		}
+	}
+	return nil
+}
+
+// ValidateTimeframes memastikan setiap timeframe bisa diproses analyzer: interval punya durasi candle & padanan
+// TradingView, range dikenal, weight positif, interval tidak duplikat dan paling banyak satu timeframe utama
+func ValidateTimeframes(timeframes []DataTimeframe) error {
+	if len(timeframes) == 0 {
+		return fmt.Errorf("timeframes must not be empty")
+	}
+
+	seen := make(map[string]bool, len(timeframes))
+	mainCount := 0
+	for _, tf := range timeframes {
+		if utils.IntervalToDuration(tf.Interval) == 0 {
+			return fmt.Errorf("interval %q is not supported", tf.Interval)
+		}
+		if _, err := tf.ToTradingViewScreenersInterval(); err != nil {
+			return err
+		}
+		if from, _ := utils.MapPeriodeStringToUnix(tf.Range); from == 0 {
+			return fmt.Errorf("interval %s: range %q is not supported", tf.Interval, tf.Range)
+		}
+		if tf.Weight <= 0 {
+			return fmt.Errorf("interval %s: weight must be positive", tf.Interval)
+		}
+		if seen[tf.Interval] {
+			return fmt.Errorf("interval %s is duplicated", tf.Interval)
+		}
+		seen[tf.Interval] = true
+		if tf.IsMain {
+			mainCount++
+		}
+	}
+	if mainCount > 1 {
+		return fmt.Errorf("only one main timeframe is allowed")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeframeProfileConfigCandidates(t *testing.T) {
+	cfg := DefaultTimeframeProfileConfig()
+	cfg.Default = TimeframeProfileSwing
+	cfg.Exchanges = map[string]string{"BINANCE": TimeframeProfileScalp}
+	cfg.Symbols = map[string]string{"IDX:BBCA": TimeframeProfilePosition}
+	assert.NoError(t, cfg.Validate())
+
+	assert.Equal(t, []string{"scalp", "position", "swing"}, cfg.Candidates(" Scalp ", "idx", "bbca"))
+	assert.Equal(t, []string{"scalp", "swing"}, cfg.Candidates("", "BINANCE", "BTCUSDT"))
+	assert.Equal(t, []string{"swing"}, cfg.Candidates("", "NASDAQ", "AAPL"))
+
+	profile, ok := cfg.Profile("SCALP")
+	assert.True(t, ok)
+	assert.Equal(t, TimeframeProfileScalp, profile.Name)
+	assert.Equal(t, []string{"1h", "4h", "15m"}, profile.Intervals())
+
+	assert.Equal(t, []string{"position", "scalp", "swing"}, cfg.ProfileNames())
+}
+
+func TestTimeframeProfileConfigValidate(t *testing.T) {
+	cfg := DefaultTimeframeProfileConfig()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Exchanges = map[string]string{"IDX": "intraday"}
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultTimeframeProfileConfig()
+	cfg.Symbols = map[string]string{"BBCA": TimeframeProfileSwing}
+	assert.Error(t, cfg.Validate())
+
+	// interval tanpa padanan TradingView tidak boleh diam-diam jadi 1D
+	cfg = DefaultTimeframeProfileConfig()
+	cfg.Profiles["custom"] = []DataTimeframe{{Interval: "3d", Range: "1y", Weight: 1}}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateTimeframes(t *testing.T) {
+	assert.NoError(t, ValidateTimeframes([]DataTimeframe{{Interval: "1h", Range: "14d", Weight: 1}}))
+	assert.Error(t, ValidateTimeframes(nil))
+	assert.Error(t, ValidateTimeframes([]DataTimeframe{{Interval: "1h", Range: "5y", Weight: 1}}))
+	assert.Error(t, ValidateTimeframes([]DataTimeframe{{Interval: "1h", Range: "14d"}}))
+	assert.Error(t, ValidateTimeframes([]DataTimeframe{
+		{Interval: "1h", Range: "14d", Weight: 1},
+		{Interval: "1h", Range: "1m", Weight: 2},
+	}))
+	assert.Error(t, ValidateTimeframes([]DataTimeframe{
+		{Interval: "1h", Range: "14d", Weight: 1, IsMain: true},
+		{Interval: "4h", Range: "1m", Weight: 2, IsMain: true},
+	}))
+}
+
+func TestToTradingViewScreenersInterval(t *testing.T) {
+	for interval, expected := range map[string]string{
+		"15m": TradingViewInterval15Min,
+		"60m": TradingViewInterval1Hour,
+		"4h":  TradingViewInterval4Hour,
+		"1w":  TradingViewInterval1Week,
+		"1wk": TradingViewInterval1Week,
+		"1M":  TradingViewInterval1Month,
+	} {
+		tf := DataTimeframe{Interval: interval}
+		got, err := tf.ToTradingViewScreenersInterval()
+		assert.NoError(t, err, interval)
+		assert.Equal(t, expected, got, interval)
+	}
+
+	tf := DataTimeframe{Interval: "3d"}
+	_, err := tf.ToTradingViewScreenersInterval()
+	assert.Error(t, err)
+
+	assert.Equal(t, Interval1Month, TradingViewIntervalToDataTimeframe(TradingViewInterval1Month))
+	assert.Equal(t, Interval15Min, TradingViewIntervalToDataTimeframe(TradingViewInterval15Min))
+}
@@ -1,6 +1,12 @@
 package dto
 
-import "golang-trading/internal/model"
+import (
+	"fmt"
+	"strconv"
+
+	"golang-trading/internal/model"
+	"golang-trading/pkg/utils"
+)
 
 type TradePlanResult struct {
 	CurrentMarketPrice float64
@@ -11,7 +17,8 @@ type TradePlanResult struct {
 	Score              float64
 	Confidence         float64
 	Status             string
-	IsBuySignal        bool
+	IsBuySignal        bool   // sinyal teknikal searah plan: Buy untuk LONG, Sell untuk SHORT
+	Side               string // LONG / SHORT
 	Symbol             string
 	TechnicalSignal    string
 	Exchange           string
@@ -27,6 +34,37 @@ type TradePlanResult struct {
 	EntryReason      string // alasan entry price
 	IndicatorSummary model.IndicatorSummary
 	Insights         []Insight
+
+	Params        TradePlanParams // parameter yang dipakai membentuk plan
+	ParamsVersion string          // versi TradingPlanConfig (atau "override" saat optimasi backtest)
+
+	// ukuran posisi sesuai modal & risk per trade user, kosong jika user belum mengatur /setrisk
+	Quantity        float64
+	Lots            float64
+	CapitalRequired float64
+	RiskAmount      float64
+}
+
+// PositionSizing modal dan risk per trade user untuk menghitung ukuran posisi
+type PositionSizing struct {
+	AccountSize         float64
+	RiskPerTradePercent float64
+}
+
+func (p PositionSizing) IsSet() bool {
+	return p.AccountSize > 0 && p.RiskPerTradePercent > 0
+}
+
+// PositionSizeText ringkasan ukuran posisi untuk pesan telegram, kosong jika belum dihitung
+func (r TradePlanResult) PositionSizeText() string {
+	if r.Quantity == 0 {
+		return ""
+	}
+	size := fmt.Sprintf("%s unit", strconv.FormatFloat(r.Quantity, 'f', -1, 64))
+	if r.Lots != r.Quantity {
+		size = fmt.Sprintf("%s lot", strconv.FormatFloat(r.Lots, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s | Modal %s | Risk %s", size, utils.FormatVolume(r.CapitalRequired), utils.FormatVolume(r.RiskAmount))
 }
 
 type TradePlan struct {
@@ -39,6 +77,7 @@ type TradePlan struct {
 	RiskReward         float64
 	Score              float64
 	PlanType           PlanType
+	Side               string // LONG / SHORT
 
 	SLType   string // jenis SL: support / ema-adjust
 	SLReason string // alasan SL
@@ -119,6 +158,186 @@ type TradeConfig struct {
 	Score                float64
 }
 
+// TradePlanParams parameter pembentukan trade plan & ambang sinyal beli yang bisa dioptimasi.
+// Persen dalam bentuk rasio (0.05 = 5%).
+type TradePlanParams struct {
+	TargetRiskReward     float64 `json:"target_risk_reward"`
+	MaxStopLossPercent   float64 `json:"max_stop_loss_percent"`
+	MinStopLossPercent   float64 `json:"min_stop_loss_percent"`
+	MaxTakeProfitPercent float64 `json:"max_take_profit_percent"`
+	MinTakeProfitPercent float64 `json:"min_take_profit_percent"`
+
+	FallbackMaxTakeProfitPercent float64 `json:"fallback_max_take_profit_percent"`
+	FallbackMinTakeProfitPercent float64 `json:"fallback_min_take_profit_percent"`
+	FallbackMaxStopLossPercent   float64 `json:"fallback_max_stop_loss_percent"`
+	FallbackMinStopLossPercent   float64 `json:"fallback_min_stop_loss_percent"`
+
+	SLFromEMAAdj float64 `json:"sl_from_ema_adj"` // SL dari EMA = EMA * SLFromEMAAdj
+
+	SLATRMultiplierMin float64 `json:"sl_atr_multiplier_min"` // jarak SL (x ATR) saat tren kuat
+	SLATRMultiplierMax float64 `json:"sl_atr_multiplier_max"` // jarak SL (x ATR) saat pasar lemah / ekstrem
+	TPATRMultiplier    float64 `json:"tp_atr_multiplier"`     // jarak TP (x ATR) untuk plan fallback ATR
+
+	BuySignalScore      float64 `json:"buy_signal_score"`       // skor minimal trade plan untuk entry
+	BuySignalRiskReward float64 `json:"buy_signal_risk_reward"` // risk reward minimal untuk entry
+}
+
+// DefaultTradePlanParams nilai default parameter trade plan
+func DefaultTradePlanParams() TradePlanParams {
+	return TradePlanParams{
+		TargetRiskReward:     1.0,
+		MaxStopLossPercent:   0.05,
+		MinStopLossPercent:   0.02,
+		MaxTakeProfitPercent: 0.07,
+		MinTakeProfitPercent: 0.02,
+
+		FallbackMaxTakeProfitPercent: 0.14,
+		FallbackMinTakeProfitPercent: 0.01,
+		FallbackMaxStopLossPercent:   0.10,
+		FallbackMinStopLossPercent:   0.01,
+
+		SLFromEMAAdj: 0.995,
+
+		SLATRMultiplierMin: 1.25,
+		SLATRMultiplierMax: 2.25,
+		TPATRMultiplier:    3,
+
+		BuySignalScore: 50,
+	}
+}
+
+// fields nama parameter (sesuai json tag) untuk search space optimasi
+func (p *TradePlanParams) fields() map[string]*float64 {
+	return map[string]*float64{
+		"target_risk_reward":               &p.TargetRiskReward,
+		"max_stop_loss_percent":            &p.MaxStopLossPercent,
+		"min_stop_loss_percent":            &p.MinStopLossPercent,
+		"max_take_profit_percent":          &p.MaxTakeProfitPercent,
+		"min_take_profit_percent":          &p.MinTakeProfitPercent,
+		"fallback_max_take_profit_percent": &p.FallbackMaxTakeProfitPercent,
+		"fallback_min_take_profit_percent": &p.FallbackMinTakeProfitPercent,
+		"fallback_max_stop_loss_percent":   &p.FallbackMaxStopLossPercent,
+		"fallback_min_stop_loss_percent":   &p.FallbackMinStopLossPercent,
+		"sl_from_ema_adj":                  &p.SLFromEMAAdj,
+		"sl_atr_multiplier_min":            &p.SLATRMultiplierMin,
+		"sl_atr_multiplier_max":            &p.SLATRMultiplierMax,
+		"tp_atr_multiplier":                &p.TPATRMultiplier,
+		"buy_signal_score":                 &p.BuySignalScore,
+		"buy_signal_risk_reward":           &p.BuySignalRiskReward,
+	}
+}
+
+// Set mengubah satu parameter berdasarkan nama json-nya
+func (p *TradePlanParams) Set(name string, value float64) error {
+	field, ok := p.fields()[name]
+	if !ok {
+		return fmt.Errorf("unknown trade plan param: %s", name)
+	}
+	*field = value
+	return nil
+}
+
+// Validate memastikan batas SL / TP konsisten
+func (p TradePlanParams) Validate() error {
+	switch {
+	case p.TargetRiskReward <= 0:
+		return fmt.Errorf("target_risk_reward must be greater than 0")
+	case p.MinStopLossPercent < 0 || p.MinStopLossPercent > p.MaxStopLossPercent || p.MaxStopLossPercent >= 1:
+		return fmt.Errorf("stop loss percent must satisfy 0 <= min <= max < 1")
+	case p.MinTakeProfitPercent < 0 || p.MinTakeProfitPercent > p.MaxTakeProfitPercent:
+		return fmt.Errorf("take profit percent must satisfy 0 <= min <= max")
+	case p.FallbackMinStopLossPercent < 0 || p.FallbackMinStopLossPercent > p.FallbackMaxStopLossPercent || p.FallbackMaxStopLossPercent >= 1:
+		return fmt.Errorf("fallback stop loss percent must satisfy 0 <= min <= max < 1")
+	case p.FallbackMinTakeProfitPercent < 0 || p.FallbackMinTakeProfitPercent > p.FallbackMaxTakeProfitPercent:
+		return fmt.Errorf("fallback take profit percent must satisfy 0 <= min <= max")
+	case p.SLFromEMAAdj <= 0 || p.SLFromEMAAdj > 1:
+		return fmt.Errorf("sl_from_ema_adj must be in (0, 1]")
+	case p.SLATRMultiplierMin <= 0 || p.SLATRMultiplierMin > p.SLATRMultiplierMax:
+		return fmt.Errorf("sl atr multiplier must satisfy 0 < min <= max")
+	case p.TPATRMultiplier <= 0:
+		return fmt.Errorf("tp_atr_multiplier must be greater than 0")
+	case p.BuySignalScore < 0 || p.BuySignalRiskReward < 0:
+		return fmt.Errorf("buy signal thresholds must not be negative")
+	}
+	return nil
+}
+
+// TradingPlanConfig isi system parameter TRADING_PLAN_CONFIG.
+// Default berlaku untuk semua exchange, lalu ditimpa override exchange, lalu override timeframe di exchange tersebut.
+// Override berisi nama parameter (json tag TradePlanParams) dan nilainya.
+type TradingPlanConfig struct {
+	Version   string                         `json:"version"`
+	Default   TradePlanParams                `json:"default"`
+	Exchanges map[string]TradingPlanOverride `json:"exchanges"`
+}
+
+type TradingPlanOverride struct {
+	Params     map[string]float64            `json:"params"`
+	Timeframes map[string]map[string]float64 `json:"timeframes"`
+}
+
+// DefaultTradingPlanConfig dipakai jika system parameter belum ada, field yang tidak diisi di system parameter
+// juga jatuh ke nilai ini
+func DefaultTradingPlanConfig() TradingPlanConfig {
+	return TradingPlanConfig{
+		Version: TradingPlanConfigDefaultVersion,
+		Default: DefaultTradePlanParams(),
+	}
+}
+
+const (
+	TradingPlanConfigDefaultVersion  = "default"
+	TradingPlanConfigOverrideVersion = "override"
+)
+
+// Resolve menggabungkan default dengan override exchange & timeframe
+func (c TradingPlanConfig) Resolve(exchange, timeframe string) (TradePlanParams, error) {
+	params := c.Default
+	override, ok := c.Exchanges[exchange]
+	if !ok {
+		return params, nil
+	}
+
+	for name, value := range override.Params {
+		if err := params.Set(name, value); err != nil {
+			return params, fmt.Errorf("exchange %s: %w", exchange, err)
+		}
+	}
+	for name, value := range override.Timeframes[timeframe] {
+		if err := params.Set(name, value); err != nil {
+			return params, fmt.Errorf("exchange %s timeframe %s: %w", exchange, timeframe, err)
+		}
+	}
+	return params, nil
+}
+
+// Validate memastikan default dan setiap kombinasi override menghasilkan parameter yang valid
+func (c TradingPlanConfig) Validate() error {
+	if c.Version == "" {
+		return fmt.Errorf("version is required")
+	}
+	if err := c.Default.Validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+
+	for exchange, override := range c.Exchanges {
+		timeframes := []string{""}
+		for timeframe := range override.Timeframes {
+			timeframes = append(timeframes, timeframe)
+		}
+		for _, timeframe := range timeframes {
+			params, err := c.Resolve(exchange, timeframe)
+			if err != nil {
+				return err
+			}
+			if err := params.Validate(); err != nil {
+				return fmt.Errorf("exchange %s timeframe %q: %w", exchange, timeframe, err)
+			}
+		}
+	}
+	return nil
+}
+
 type PlanType string
 
 const (
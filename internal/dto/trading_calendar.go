@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// Mode market hours task schedule, menentukan kapan job dilewati karena market exchange tutup
+const (
+	MarketHoursAny        = ""            // selalu jalan
+	MarketHoursTradingDay = "trading_day" // hanya di hari bursa (bukan akhir pekan / libur)
+	MarketHoursSession    = "session"     // hanya saat sesi perdagangan reguler buka
+	MarketHoursExtended   = "extended"    // sesi reguler + pre-opening / pre-market
+)
+
+// TradingSession jam sesi bursa dalam format HH:MM di zona waktu exchange
+type TradingSession struct {
+	Name  string `json:"name"`
+	Open  string `json:"open"`
+	Close string `json:"close"`
+	// Weekdays kosong berarti berlaku di semua hari bursa
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+	// PreMarket sesi pre-opening / pre-market, tidak dihitung sebagai market buka untuk sesi reguler
+	PreMarket bool `json:"pre_market,omitempty"`
+}
+
+// ExchangeCalendar konfigurasi kalender bursa (system parameter EXCHANGE_CALENDARS), field kosong memakai default exchange
+type ExchangeCalendar struct {
+	TimeZone    string           `json:"time_zone,omitempty"`
+	Is24x7      bool             `json:"is_24x7,omitempty"`
+	TradingDays []time.Weekday   `json:"trading_days,omitempty"`
+	Sessions    []TradingSession `json:"sessions,omitempty"`
+	// Holidays tanggal libur bursa (YYYY-MM-DD)
+	Holidays []string `json:"holidays,omitempty"`
+	// EarlyCloses tanggal (YYYY-MM-DD) -> jam tutup lebih awal (HH:MM)
+	EarlyCloses map[string]string `json:"early_closes,omitempty"`
+}
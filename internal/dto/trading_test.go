@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTradingPlanConfigResolve(t *testing.T) {
+	cfg := DefaultTradingPlanConfig()
+	cfg.Exchanges = map[string]TradingPlanOverride{
+		"BINANCE": {
+			Params:     map[string]float64{"max_stop_loss_percent": 0.08},
+			Timeframes: map[string]map[string]float64{"4h": {"max_stop_loss_percent": 0.1}},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	params, err := cfg.Resolve("IDX", "1d")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.05, params.MaxStopLossPercent)
+
+	params, err = cfg.Resolve("BINANCE", "1d")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.08, params.MaxStopLossPercent)
+
+	params, err = cfg.Resolve("BINANCE", "4h")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, params.MaxStopLossPercent)
+
+	// override yang membuat min SL > max SL ditolak
+	cfg.Exchanges["IDX"] = TradingPlanOverride{Params: map[string]float64{"min_stop_loss_percent": 0.2}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Exchanges["IDX"] = TradingPlanOverride{Params: map[string]float64{"unknown": 1}}
+	assert.Error(t, cfg.Validate())
+}
@@ -138,6 +138,7 @@ type TradingViewScanner struct {
 			Low   float64 // Lowest price
 		}
 	}
+	Derivatives *DerivativesData `json:"derivatives,omitempty"` // funding & open interest, khusus exchange futures
 }
 
 func (s *TradingViewScanner) GetTrendMACD() string {
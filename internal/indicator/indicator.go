@@ -0,0 +1,538 @@
+package indicator
+
+import (
+	"errors"
+	"math"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/utils"
+)
+
+// MinCandles jumlah candle minimum agar semua indikator (termasuk EMA200 / SMA200) terisi
+const MinCandles = 200
+
+var ErrNotEnoughCandles = errors.New("not enough candles to compute indicator")
+
+// Compute menghitung dto.TradingViewScanner dari candle OHLCV (terurut dari yang paling lama).
+// Rumus & rating mengikuti "Technical Ratings" tradingview, indikator yang datanya belum cukup bernilai 0
+// dan tidak ikut dihitung di summary.
+func Compute(candles []dto.StockOHLCV, interval string) (*dto.TradingViewScanner, error) {
+	if len(candles) < 2 {
+		return nil, ErrNotEnoughCandles
+	}
+
+	n := len(candles)
+	open := make([]float64, n)
+	high := make([]float64, n)
+	low := make([]float64, n)
+	closes := make([]float64, n)
+	volume := make([]float64, n)
+	hl2 := make([]float64, n)
+	hlc3 := make([]float64, n)
+	for i, c := range candles {
+		open[i] = c.Open
+		high[i] = c.High
+		low[i] = c.Low
+		closes[i] = c.Close
+		volume[i] = c.Volume
+		hl2[i] = (c.High + c.Low) / 2
+		hlc3[i] = (c.High + c.Low + c.Close) / 3
+	}
+
+	ta := &dto.TradingViewScanner{Timeframe: interval}
+	lastClose := closes[n-1]
+
+	sma50 := sma(closes, 50)
+	upTrend := !math.IsNaN(at(sma50, 0)) && lastClose > at(sma50, 0)
+	downTrend := !math.IsNaN(at(sma50, 0)) && lastClose < at(sma50, 0)
+
+	oscRatings := []float64{}
+	maRatings := []float64{}
+	addRating := func(ratings *[]float64, rating int, available bool) int {
+		if !available {
+			return dto.TradingViewSignalNeutral
+		}
+		*ratings = append(*ratings, float64(rating))
+		return rating
+	}
+
+	// Oscillators
+	// Relative Strength Index (14)
+	rsi := rsiSeries(closes, 14)
+	ta.Value.Oscillators.RSI = value(at(rsi, 0))
+	ta.Recommend.Oscillators.RSI = addRating(&oscRatings, RecommendRSI(at(rsi, 0), at(rsi, 1)), !isNaN(at(rsi, 0), at(rsi, 1)))
+
+	// Stochastic %K (14, 3, 3)
+	stochK, stochD := stoch(closes, high, low, 14, 3, 3)
+	ta.Value.Oscillators.StochK = value(at(stochK, 0))
+	ta.Recommend.Oscillators.StochK = addRating(&oscRatings,
+		RecommendStoch(at(stochK, 0), at(stochD, 0), at(stochK, 1), at(stochD, 1)),
+		!isNaN(at(stochK, 0), at(stochD, 0), at(stochK, 1), at(stochD, 1)))
+
+	// Commodity Channel Index (20)
+	cci := cciSeries(hlc3, 20)
+	ta.Value.Oscillators.CCI = value(at(cci, 0))
+	ta.Recommend.Oscillators.CCI = addRating(&oscRatings, RecommendCCI20(at(cci, 0), at(cci, 1)), !isNaN(at(cci, 0), at(cci, 1)))
+
+	// Average Directional Index (14)
+	adx, plusDI, minusDI := dmi(high, low, closes, 14, 14)
+	ta.Value.Oscillators.ADX.Value = value(at(adx, 0))
+	ta.Value.Oscillators.ADX.PlusDI = value(at(plusDI, 0))
+	ta.Value.Oscillators.ADX.MinusDI = value(at(minusDI, 0))
+	ta.Value.Oscillators.ADX.PlusDI1 = value(at(plusDI, 1))
+	ta.Value.Oscillators.ADX.MinusDI1 = value(at(minusDI, 1))
+	ta.Recommend.Oscillators.ADX = addRating(&oscRatings,
+		RecommendADX(at(adx, 0), at(plusDI, 0), at(minusDI, 0), at(plusDI, 1), at(minusDI, 1)),
+		!isNaN(at(adx, 0), at(plusDI, 1), at(minusDI, 1)))
+
+	// Awesome Oscillator
+	ao := subtract(sma(hl2, 5), sma(hl2, 34))
+	ta.Value.Oscillators.AO.Value = value(at(ao, 0))
+	ta.Value.Oscillators.AO.Prev1 = value(at(ao, 1))
+	ta.Value.Oscillators.AO.Prev2 = value(at(ao, 2))
+	ta.Recommend.Oscillators.AO = addRating(&oscRatings, RecommendAO(at(ao, 0), at(ao, 1), at(ao, 2)), !isNaN(at(ao, 0), at(ao, 1), at(ao, 2)))
+
+	// Momentum (10)
+	mom := momentum(closes, 10)
+	ta.Value.Oscillators.Mom = value(at(mom, 0))
+	ta.Recommend.Oscillators.Mom = addRating(&oscRatings, RecommendMom(at(mom, 0), at(mom, 1)), !isNaN(at(mom, 0), at(mom, 1)))
+
+	// MACD Level (12, 26)
+	macd := subtract(ema(closes, 12), ema(closes, 26))
+	macdSignal := ema(macd, 9)
+	ta.Value.Oscillators.MACD.Macd = value(at(macd, 0))
+	ta.Value.Oscillators.MACD.Signal = value(at(macdSignal, 0))
+	ta.Recommend.Oscillators.MACD = addRating(&oscRatings, RecommendMACD(at(macd, 0), at(macdSignal, 0)), !isNaN(at(macd, 0), at(macdSignal, 0)))
+
+	// Stochastic RSI Fast (3, 3, 14, 14)
+	stochRsiK, stochRsiD := stoch(rsi, rsi, rsi, 14, 3, 3)
+	ta.Value.Oscillators.StochRSI = value(at(stochRsiK, 0))
+	ta.Recommend.Oscillators.StochRSI = addRating(&oscRatings,
+		recommendStochRSI(at(stochRsiK, 0), at(stochRsiD, 0), at(stochRsiK, 1), at(stochRsiD, 1), upTrend, downTrend),
+		!isNaN(at(stochRsiK, 0), at(stochRsiD, 0), at(stochRsiK, 1), at(stochRsiD, 1)))
+
+	// Williams Percent Range (14)
+	wr := williamsR(closes, high, low, 14)
+	ta.Value.Oscillators.WR = value(at(wr, 0))
+	ta.Recommend.Oscillators.WR = addRating(&oscRatings, recommendWR(at(wr, 0), at(wr, 1)), !isNaN(at(wr, 0), at(wr, 1)))
+
+	// Bull Bear Power
+	ema13 := ema(closes, 13)
+	bullPower := subtract(high, ema13)
+	bearPower := subtract(low, ema13)
+	ta.Value.Oscillators.BBP = value(at(bullPower, 0) + at(bearPower, 0))
+	ta.Recommend.Oscillators.BBP = addRating(&oscRatings,
+		recommendBBP(at(bullPower, 0), at(bullPower, 1), at(bearPower, 0), at(bearPower, 1), upTrend, downTrend),
+		!isNaN(at(bullPower, 0), at(bullPower, 1)))
+
+	// Ultimate Oscillator (7, 14, 28)
+	uo := ultimateOscillator(closes, high, low, 7, 14, 28)
+	ta.Value.Oscillators.UO = value(at(uo, 0))
+	ta.Recommend.Oscillators.UO = addRating(&oscRatings, recommendUO(at(uo, 0)), !isNaN(at(uo, 0)))
+
+	// Moving Averages
+	maValues := []struct {
+		val *float64
+		rec *int
+		src []float64
+	}{
+		{&ta.Value.MovingAverages.EMA10, &ta.Recommend.MovingAverages.EMA10, ema(closes, 10)},
+		{&ta.Value.MovingAverages.SMA10, &ta.Recommend.MovingAverages.SMA10, sma(closes, 10)},
+		{&ta.Value.MovingAverages.EMA20, &ta.Recommend.MovingAverages.EMA20, ema(closes, 20)},
+		{&ta.Value.MovingAverages.SMA20, &ta.Recommend.MovingAverages.SMA20, sma(closes, 20)},
+		{&ta.Value.MovingAverages.EMA30, &ta.Recommend.MovingAverages.EMA30, ema(closes, 30)},
+		{&ta.Value.MovingAverages.SMA30, &ta.Recommend.MovingAverages.SMA30, sma(closes, 30)},
+		{&ta.Value.MovingAverages.EMA50, &ta.Recommend.MovingAverages.EMA50, ema(closes, 50)},
+		{&ta.Value.MovingAverages.SMA50, &ta.Recommend.MovingAverages.SMA50, sma50},
+		{&ta.Value.MovingAverages.EMA100, &ta.Recommend.MovingAverages.EMA100, ema(closes, 100)},
+		{&ta.Value.MovingAverages.SMA100, &ta.Recommend.MovingAverages.SMA100, sma(closes, 100)},
+		{&ta.Value.MovingAverages.EMA200, &ta.Recommend.MovingAverages.EMA200, ema(closes, 200)},
+		{&ta.Value.MovingAverages.SMA200, &ta.Recommend.MovingAverages.SMA200, sma(closes, 200)},
+		// Volume Weighted Moving Average (20)
+		{&ta.Value.MovingAverages.VWMA, &ta.Recommend.MovingAverages.VWMA, vwma(closes, volume, 20)},
+		// Hull Moving Average (9)
+		{&ta.Value.MovingAverages.HullMA, &ta.Recommend.MovingAverages.HullMA, hma(closes, 9)},
+	}
+	for _, ma := range maValues {
+		last := at(ma.src, 0)
+		*ma.val = value(last)
+		*ma.rec = addRating(&maRatings, RecommendMA(last, lastClose), !isNaN(last))
+	}
+
+	// Ichimoku Base Line (9, 26, 52, 26)
+	conversion, base, lead1, lead2 := ichimoku(high, low, 9, 26, 52, 26)
+	ta.Value.MovingAverages.Ichimoku = value(at(base, 0))
+	ta.Recommend.MovingAverages.Ichimoku = addRating(&maRatings,
+		recommendIchimoku(at(conversion, 0), at(base, 0), at(lead1, 0), at(lead2, 0), lastClose),
+		!isNaN(at(conversion, 0), at(base, 0), at(lead1, 0), at(lead2, 0)))
+
+	// Summary
+	ta.Value.Global.Oscillators = average(oscRatings)
+	ta.Value.Global.MA = average(maRatings)
+	ta.Value.Global.Summary = (ta.Value.Global.Oscillators + ta.Value.Global.MA) / 2
+	ta.Recommend.Global.Oscillators = ComputeRecommend(ta.Value.Global.Oscillators)
+	ta.Recommend.Global.MA = ComputeRecommend(ta.Value.Global.MA)
+	ta.Recommend.Global.Summary = ComputeRecommend(ta.Value.Global.Summary)
+
+	// Pivots
+	computePivots(ta, candles, open, interval)
+
+	// Prices
+	ta.Value.Prices.Close = lastClose
+	ta.Value.Prices.High = high[n-1]
+	ta.Value.Prices.Low = low[n-1]
+
+	return ta, nil
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+func subtract(a, b []float64) []float64 {
+	out := nanSeries(len(a))
+	for i := range a {
+		out[i] = a[i] - b[i]
+	}
+	return out
+}
+
+func rsiSeries(closes []float64, length int) []float64 {
+	n := len(closes)
+	gain := nanSeries(n)
+	loss := nanSeries(n)
+	for i := 1; i < n; i++ {
+		change := closes[i] - closes[i-1]
+		gain[i] = math.Max(change, 0)
+		loss[i] = math.Max(-change, 0)
+	}
+
+	avgGain := rma(gain, length)
+	avgLoss := rma(loss, length)
+	out := nanSeries(n)
+	for i := range out {
+		if isNaN(avgGain[i], avgLoss[i]) {
+			continue
+		}
+		switch {
+		case avgLoss[i] == 0:
+			out[i] = 100
+		case avgGain[i] == 0:
+			out[i] = 0
+		default:
+			out[i] = 100 - 100/(1+avgGain[i]/avgLoss[i])
+		}
+	}
+	return out
+}
+
+// stoch mengembalikan %K (smoothK) dan %D (smoothD)
+func stoch(src, high, low []float64, length, smoothK, smoothD int) ([]float64, []float64) {
+	hh := highest(high, length)
+	ll := lowest(low, length)
+	raw := nanSeries(len(src))
+	for i := range src {
+		if isNaN(hh[i], ll[i], src[i]) {
+			continue
+		}
+		if hh[i] == ll[i] {
+			raw[i] = 0
+			continue
+		}
+		raw[i] = 100 * (src[i] - ll[i]) / (hh[i] - ll[i])
+	}
+	k := smaSkipNaN(raw, smoothK)
+	d := smaSkipNaN(k, smoothD)
+	return k, d
+}
+
+// smaSkipNaN SMA untuk series yang diawali NaN (hasil indikator lain)
+func smaSkipNaN(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	start := firstValid(src)
+	if start < 0 {
+		return out
+	}
+	copy(out[start:], sma(src[start:], length))
+	return out
+}
+
+func cciSeries(src []float64, length int) []float64 {
+	mean := sma(src, length)
+	out := nanSeries(len(src))
+	for i := range src {
+		if i+1 < length || math.IsNaN(mean[i]) {
+			continue
+		}
+		dev := 0.0
+		for j := i + 1 - length; j <= i; j++ {
+			dev += math.Abs(src[j] - mean[i])
+		}
+		dev /= float64(length)
+		if dev == 0 {
+			out[i] = 0
+			continue
+		}
+		out[i] = (src[i] - mean[i]) / (0.015 * dev)
+	}
+	return out
+}
+
+// dmi mengembalikan ADX, +DI dan -DI
+func dmi(high, low, closes []float64, diLength, adxSmoothing int) ([]float64, []float64, []float64) {
+	n := len(closes)
+	tr := nanSeries(n)
+	plusDM := nanSeries(n)
+	minusDM := nanSeries(n)
+	for i := 1; i < n; i++ {
+		up := high[i] - high[i-1]
+		down := low[i-1] - low[i]
+		plusDM[i] = 0
+		if up > down && up > 0 {
+			plusDM[i] = up
+		}
+		minusDM[i] = 0
+		if down > up && down > 0 {
+			minusDM[i] = down
+		}
+		tr[i] = math.Max(high[i]-low[i], math.Max(math.Abs(high[i]-closes[i-1]), math.Abs(low[i]-closes[i-1])))
+	}
+
+	trur := rma(tr, diLength)
+	plusSmooth := rma(plusDM, diLength)
+	minusSmooth := rma(minusDM, diLength)
+
+	plus := nanSeries(n)
+	minus := nanSeries(n)
+	dx := nanSeries(n)
+	for i := 0; i < n; i++ {
+		if isNaN(trur[i]) || trur[i] == 0 {
+			continue
+		}
+		plus[i] = 100 * plusSmooth[i] / trur[i]
+		minus[i] = 100 * minusSmooth[i] / trur[i]
+		if plus[i]+minus[i] == 0 {
+			dx[i] = 0
+			continue
+		}
+		dx[i] = math.Abs(plus[i]-minus[i]) / (plus[i] + minus[i])
+	}
+
+	adx := rma(dx, adxSmoothing)
+	for i := range adx {
+		adx[i] *= 100
+	}
+	return adx, plus, minus
+}
+
+func momentum(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	for i := length; i < len(src); i++ {
+		out[i] = src[i] - src[i-length]
+	}
+	return out
+}
+
+func williamsR(closes, high, low []float64, length int) []float64 {
+	hh := highest(high, length)
+	ll := lowest(low, length)
+	out := nanSeries(len(closes))
+	for i := range closes {
+		if isNaN(hh[i], ll[i]) || hh[i] == ll[i] {
+			continue
+		}
+		out[i] = 100 * (closes[i] - hh[i]) / (hh[i] - ll[i])
+	}
+	return out
+}
+
+func ultimateOscillator(closes, high, low []float64, fast, middle, slow int) []float64 {
+	n := len(closes)
+	if n < 2 {
+		return nanSeries(n)
+	}
+
+	bp := nanSeries(n)
+	tr := nanSeries(n)
+	for i := 1; i < n; i++ {
+		minLow := math.Min(low[i], closes[i-1])
+		maxHigh := math.Max(high[i], closes[i-1])
+		bp[i] = closes[i] - minLow
+		tr[i] = maxHigh - minLow
+	}
+
+	avg := func(length int) []float64 {
+		bpSum := rollingSum(bp[1:], length)
+		trSum := rollingSum(tr[1:], length)
+		out := nanSeries(n)
+		for i := range bpSum {
+			if isNaN(bpSum[i], trSum[i]) || trSum[i] == 0 {
+				continue
+			}
+			out[i+1] = bpSum[i] / trSum[i]
+		}
+		return out
+	}
+
+	fastAvg, middleAvg, slowAvg := avg(fast), avg(middle), avg(slow)
+	out := nanSeries(n)
+	for i := range out {
+		if isNaN(fastAvg[i], middleAvg[i], slowAvg[i]) {
+			continue
+		}
+		out[i] = 100 * (4*fastAvg[i] + 2*middleAvg[i] + slowAvg[i]) / 7
+	}
+	return out
+}
+
+func vwma(closes, volume []float64, length int) []float64 {
+	cv := make([]float64, len(closes))
+	for i := range closes {
+		cv[i] = closes[i] * volume[i]
+	}
+	cvSum := rollingSum(cv, length)
+	vSum := rollingSum(volume, length)
+	out := nanSeries(len(closes))
+	for i := range out {
+		if isNaN(cvSum[i], vSum[i]) || vSum[i] == 0 {
+			continue
+		}
+		out[i] = cvSum[i] / vSum[i]
+	}
+	return out
+}
+
+// hma - Hull Moving Average
+func hma(src []float64, length int) []float64 {
+	half := wma(src, length/2)
+	full := wma(src, length)
+	diff := nanSeries(len(src))
+	for i := range src {
+		diff[i] = 2*half[i] - full[i]
+	}
+	out := nanSeries(len(src))
+	start := firstValid(diff)
+	if start < 0 {
+		return out
+	}
+	copy(out[start:], wma(diff[start:], int(math.Floor(math.Sqrt(float64(length))))))
+	return out
+}
+
+// ichimoku mengembalikan conversion line, base line, leading span A & B (sudah di-shift displacement)
+func ichimoku(high, low []float64, conversionPeriods, basePeriods, laggingSpan2Periods, displacement int) ([]float64, []float64, []float64, []float64) {
+	donchian := func(length int) []float64 {
+		hh := highest(high, length)
+		ll := lowest(low, length)
+		out := nanSeries(len(high))
+		for i := range out {
+			out[i] = (hh[i] + ll[i]) / 2
+		}
+		return out
+	}
+
+	conversion := donchian(conversionPeriods)
+	base := donchian(basePeriods)
+	span2 := donchian(laggingSpan2Periods)
+
+	n := len(high)
+	lead1 := nanSeries(n)
+	lead2 := nanSeries(n)
+	for i := range lead1 {
+		j := i - displacement + 1
+		if j < 0 {
+			continue
+		}
+		lead1[i] = (conversion[j] + base[j]) / 2
+		lead2[i] = span2[j]
+	}
+	return conversion, base, lead1, lead2
+}
+
+func recommendStochRSI(k, d, k1, d1 float64, upTrend, downTrend bool) int {
+	switch {
+	case downTrend && k < 20 && d < 20 && k > d && k1 < d1:
+		return dto.TradingViewSignalBuy
+	case upTrend && k > 80 && d > 80 && k < d && k1 > d1:
+		return dto.TradingViewSignalSell
+	default:
+		return dto.TradingViewSignalNeutral
+	}
+}
+
+func recommendWR(wr, wr1 float64) int {
+	switch {
+	case wr < -80 && wr > wr1:
+		return dto.TradingViewSignalBuy
+	case wr > -20 && wr < wr1:
+		return dto.TradingViewSignalSell
+	default:
+		return dto.TradingViewSignalNeutral
+	}
+}
+
+func recommendBBP(bull, bull1, bear, bear1 float64, upTrend, downTrend bool) int {
+	switch {
+	case upTrend && bear < 0 && bear > bear1:
+		return dto.TradingViewSignalBuy
+	case downTrend && bull > 0 && bull < bull1:
+		return dto.TradingViewSignalSell
+	default:
+		return dto.TradingViewSignalNeutral
+	}
+}
+
+func recommendUO(uo float64) int {
+	switch {
+	case uo > 70:
+		return dto.TradingViewSignalBuy
+	case uo < 30:
+		return dto.TradingViewSignalSell
+	default:
+		return dto.TradingViewSignalNeutral
+	}
+}
+
+func recommendIchimoku(conversion, base, lead1, lead2, close float64) int {
+	switch {
+	case lead1 > lead2 && close > lead1 && close > base && conversion > base:
+		return dto.TradingViewSignalBuy
+	case lead1 < lead2 && close < lead1 && close < base && conversion < base:
+		return dto.TradingViewSignalSell
+	default:
+		return dto.TradingViewSignalNeutral
+	}
+}
+
+// lookback range minimum per interval supaya Compute punya cukup bar (EMA200, Ichimoku 52, dst)
+var lookbackRanges = map[string]string{
+	dto.Interval15Min:  "1m",
+	dto.Interval30Min:  "1m",
+	dto.Interval1Hour:  "3m",
+	dto.Interval4Hour:  "6m",
+	dto.Interval1Day:   "1y",
+	dto.Interval1Week:  "1y",
+	dto.Interval1Month: "1y",
+}
+
+// LookbackRange mengembalikan range yang lebih panjang antara dataRange dan lookback minimum interval
+func LookbackRange(interval, dataRange string) string {
+	lookback, ok := lookbackRanges[interval]
+	if !ok {
+		return dataRange
+	}
+
+	lookbackStart, _ := utils.MapPeriodeStringToUnix(lookback)
+	dataStart, _ := utils.MapPeriodeStringToUnix(dataRange)
+	if dataStart > 0 && dataStart <= lookbackStart {
+		return dataRange
+	}
+	return lookback
+}
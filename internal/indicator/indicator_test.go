@@ -0,0 +1,76 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generateCandles(n int, start time.Time, step func(i int) float64) []dto.StockOHLCV {
+	candles := make([]dto.StockOHLCV, 0, n)
+	for i := 0; i < n; i++ {
+		c := step(i)
+		candles = append(candles, dto.StockOHLCV{
+			Timestamp: start.AddDate(0, 0, i).Unix(),
+			Open:      c - 1,
+			High:      c + 2,
+			Low:       c - 2,
+			Close:     c,
+			Volume:    1000,
+		})
+	}
+	return candles
+}
+
+func TestSeries(t *testing.T) {
+	src := []float64{1, 2, 3, 4, 5}
+
+	got := sma(src, 3)
+	assert.True(t, math.IsNaN(got[1]))
+	assert.InDelta(t, 2, got[2], 1e-9)
+	assert.InDelta(t, 4, got[4], 1e-9)
+
+	got = ema(src, 3)
+	assert.InDelta(t, 2, got[2], 1e-9) // seed SMA
+	assert.InDelta(t, 3, got[3], 1e-9) // 0.5*4 + 0.5*2
+	assert.InDelta(t, 4, got[4], 1e-9) // 0.5*5 + 0.5*3
+	assert.InDelta(t, 4, at(got, 0), 1e-9)
+	assert.InDelta(t, 3, at(got, 1), 1e-9)
+}
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+
+	t.Run("not enough candles", func(t *testing.T) {
+		_, err := Compute(generateCandles(1, start, func(i int) float64 { return 100 }), dto.Interval1Day)
+		assert.ErrorIs(t, err, ErrNotEnoughCandles)
+	})
+
+	t.Run("uptrend", func(t *testing.T) {
+		candles := generateCandles(250, start, func(i int) float64 { return 100 + float64(i) })
+		ta, err := Compute(candles, dto.Interval1Day)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.Interval1Day, ta.Timeframe)
+		assert.Equal(t, 100, int(ta.Value.Oscillators.RSI))
+		assert.Greater(t, ta.Value.MovingAverages.EMA10, ta.Value.MovingAverages.EMA200)
+		assert.NotZero(t, ta.Value.MovingAverages.SMA200)
+		assert.Equal(t, dto.TradingViewSignalBuy, ta.Recommend.MovingAverages.EMA200)
+		assert.Equal(t, dto.TradingViewSignalStrongBuy, ta.Recommend.Global.MA)
+		assert.Equal(t, 349.0, ta.Value.Prices.Close)
+	})
+
+	t.Run("pivots from previous month", func(t *testing.T) {
+		candles := generateCandles(45, start, func(i int) float64 { return 100 })
+		ta, err := Compute(candles, dto.Interval1Day)
+		assert.NoError(t, err)
+		// bulan sebelumnya (januari): high 102, low 98, close 100
+		assert.InDelta(t, 100, ta.Value.Pivots.Classic.Middle, 1e-9)
+		assert.InDelta(t, 102, ta.Value.Pivots.Classic.R1, 1e-9)
+		assert.InDelta(t, 98, ta.Value.Pivots.Classic.S1, 1e-9)
+		assert.Zero(t, ta.Value.MovingAverages.EMA200)
+	})
+}
@@ -0,0 +1,147 @@
+package indicator
+
+import (
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/utils"
+)
+
+// timestamp di atas nilai ini dianggap ms (binance), selain itu second (yahoo finance)
+const msTimestampThreshold = 1e12
+
+type pivotPeriod int
+
+const (
+	pivotPeriodDay pivotPeriod = iota
+	pivotPeriodWeek
+	pivotPeriodMonth
+	pivotPeriodYear
+)
+
+// pivotPeriodForInterval mengikuti mode "Auto" pivot points tradingview:
+// <= 15m pakai daily, intraday lain weekly, daily monthly, weekly ke atas yearly
+func pivotPeriodForInterval(interval string) pivotPeriod {
+	d := utils.IntervalToDuration(interval)
+	switch {
+	case d > 0 && d <= 15*time.Minute:
+		return pivotPeriodDay
+	case d > 0 && d < 24*time.Hour:
+		return pivotPeriodWeek
+	case d >= 7*24*time.Hour:
+		return pivotPeriodYear
+	default:
+		return pivotPeriodMonth
+	}
+}
+
+func candleTime(ts int64) time.Time {
+	if ts > msTimestampThreshold {
+		return time.UnixMilli(ts).UTC()
+	}
+	return time.Unix(ts, 0).UTC()
+}
+
+func periodKey(t time.Time, period pivotPeriod) int {
+	switch period {
+	case pivotPeriodDay:
+		return t.Year()*1000 + t.YearDay()
+	case pivotPeriodWeek:
+		year, week := t.ISOWeek()
+		return year*100 + week
+	case pivotPeriodYear:
+		return t.Year()
+	default:
+		return t.Year()*100 + int(t.Month())
+	}
+}
+
+// computePivots menghitung pivot dari periode terakhir yang sudah selesai (sebelum periode bar terakhir)
+func computePivots(ta *dto.TradingViewScanner, candles []dto.StockOHLCV, open []float64, interval string) {
+	period := pivotPeriodForInterval(interval)
+	n := len(candles)
+	currentKey := periodKey(candleTime(candles[n-1].Timestamp), period)
+
+	end := -1
+	for i := n - 1; i >= 0; i-- {
+		if periodKey(candleTime(candles[i].Timestamp), period) != currentKey {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return
+	}
+
+	prevKey := periodKey(candleTime(candles[end].Timestamp), period)
+	start := end
+	for start > 0 && periodKey(candleTime(candles[start-1].Timestamp), period) == prevKey {
+		start--
+	}
+
+	o := open[start]
+	h := candles[start].High
+	l := candles[start].Low
+	c := candles[end].Close
+	for i := start; i <= end; i++ {
+		if candles[i].High > h {
+			h = candles[i].High
+		}
+		if candles[i].Low < l {
+			l = candles[i].Low
+		}
+	}
+	r := h - l
+
+	// Classic
+	p := (h + l + c) / 3
+	ta.Value.Pivots.Classic.Middle = p
+	ta.Value.Pivots.Classic.R1 = 2*p - l
+	ta.Value.Pivots.Classic.S1 = 2*p - h
+	ta.Value.Pivots.Classic.R2 = p + r
+	ta.Value.Pivots.Classic.S2 = p - r
+	ta.Value.Pivots.Classic.R3 = h + 2*(p-l)
+	ta.Value.Pivots.Classic.S3 = l - 2*(h-p)
+
+	// Fibonacci
+	ta.Value.Pivots.Fibonacci.Middle = p
+	ta.Value.Pivots.Fibonacci.R1 = p + 0.382*r
+	ta.Value.Pivots.Fibonacci.S1 = p - 0.382*r
+	ta.Value.Pivots.Fibonacci.R2 = p + 0.618*r
+	ta.Value.Pivots.Fibonacci.S2 = p - 0.618*r
+	ta.Value.Pivots.Fibonacci.R3 = p + r
+	ta.Value.Pivots.Fibonacci.S3 = p - r
+
+	// Camarilla
+	ta.Value.Pivots.Camarilla.Middle = p
+	ta.Value.Pivots.Camarilla.R1 = c + 1.1*r/12
+	ta.Value.Pivots.Camarilla.S1 = c - 1.1*r/12
+	ta.Value.Pivots.Camarilla.R2 = c + 1.1*r/6
+	ta.Value.Pivots.Camarilla.S2 = c - 1.1*r/6
+	ta.Value.Pivots.Camarilla.R3 = c + 1.1*r/4
+	ta.Value.Pivots.Camarilla.S3 = c - 1.1*r/4
+
+	// Woodie
+	wp := (h + l + 2*c) / 4
+	ta.Value.Pivots.Woodie.Middle = wp
+	ta.Value.Pivots.Woodie.R1 = 2*wp - l
+	ta.Value.Pivots.Woodie.S1 = 2*wp - h
+	ta.Value.Pivots.Woodie.R2 = wp + r
+	ta.Value.Pivots.Woodie.S2 = wp - r
+	ta.Value.Pivots.Woodie.R3 = h + 2*(wp-l)
+	ta.Value.Pivots.Woodie.S3 = l - 2*(h-wp)
+
+	// Demark
+	var x float64
+	switch {
+	case c < o:
+		x = h + 2*l + c
+	case c > o:
+		x = 2*h + l + c
+	default:
+		x = h + l + 2*c
+	}
+	ta.Value.Pivots.Demark.Middle = x / 4
+	ta.Value.Pivots.Demark.R1 = x/2 - l
+	ta.Value.Pivots.Demark.S1 = x/2 - h
+}
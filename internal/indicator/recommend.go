@@ -0,0 +1,129 @@
+package indicator
+
+import "golang-trading/internal/dto"
+
+// ComputeRecommend - Compute Recommend
+func ComputeRecommend(v float64) int {
+	switch {
+	case v > 0.1 && v <= 0.5:
+		return dto.TradingViewSignalBuy // BUY
+	case v > 0.5 && v <= 1:
+		return dto.TradingViewSignalStrongBuy // STRONG_BUY
+	case v >= -0.1 && v <= 0.1:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	case v >= -1 && v < -0.5:
+		return dto.TradingViewSignalStrongSell // STRONG_SELL
+	case v >= -0.5 && v < -0.1:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendRSI - Compute Relative Strength Index
+func RecommendRSI(rsi, rsi1 float64) int {
+	switch {
+	case rsi < 30 && rsi1 < rsi:
+		return dto.TradingViewSignalBuy // BUY
+	case rsi > 70 && rsi1 > rsi:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendStoch - Compute Stochastic
+func RecommendStoch(k, d, k1, d1 float64) int {
+	switch {
+	case k < 20 && d < 20 && k > d && k1 < d1:
+		return dto.TradingViewSignalBuy // BUY
+	case k > 80 && d > 80 && k < d && k1 > d1:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendCCI20 - Compute Commodity Channel Index 20
+func RecommendCCI20(cci20, cci201 float64) int {
+	switch {
+	case cci20 < -100 && cci20 > cci201:
+		return dto.TradingViewSignalBuy // BUY
+	case cci20 > 100 && cci20 < cci201:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendADX - Compute Average Directional Index
+func RecommendADX(adx, adxpdi, adxndi, adxpdi1, adxndi1 float64) int {
+	switch {
+	case adx > 20 && adxpdi1 < adxndi1 && adxpdi > adxndi:
+		return dto.TradingViewSignalBuy // BUY
+	case adx > 20 && adxpdi1 > adxndi1 && adxpdi < adxndi:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendAO - Compute Awesome Oscillator
+func RecommendAO(ao, ao1, ao2 float64) int {
+	switch {
+	case (ao > 0 && ao1 < 0) || (ao > 0 && ao1 > 0 && ao > ao1 && ao2 > ao1):
+		return dto.TradingViewSignalBuy // BUY
+	case (ao < 0 && ao1 > 0) || (ao < 0 && ao1 < 0 && ao < ao1 && ao2 < ao1):
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendMom - Compute Momentum
+func RecommendMom(mom, mom1 float64) int {
+	switch {
+	case mom > mom1:
+		return dto.TradingViewSignalBuy // BUY
+	case mom < mom1:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendMACD - Compute Moving Average Convergence/Divergence
+func RecommendMACD(macd, s float64) int {
+	switch {
+	case macd > s:
+		return dto.TradingViewSignalBuy // BUY
+	case macd < s:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendSimple - Compute Simple
+func RecommendSimple(v float64) int {
+	switch {
+	case v == 1:
+		return dto.TradingViewSignalBuy // BUY
+	case v == -1:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
+
+// RecommendMA - Compute Moving Average
+func RecommendMA(ma, close float64) int {
+	switch {
+	case ma < close:
+		return dto.TradingViewSignalBuy // BUY
+	case ma > close:
+		return dto.TradingViewSignalSell // SELL
+	default:
+		return dto.TradingViewSignalNeutral // NEUTRAL
+	}
+}
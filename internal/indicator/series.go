@@ -0,0 +1,173 @@
+package indicator
+
+import "math"
+
+// semua fungsi series mengembalikan slice dengan panjang sama dengan input,
+// index yang belum punya cukup data diisi NaN (setara dengan `na` di pine script)
+
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// sma - Simple Moving Average
+func sma(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	if length <= 0 {
+		return out
+	}
+	for i := range src {
+		if i+1 < length {
+			continue
+		}
+		sum := 0.0
+		valid := true
+		for j := i + 1 - length; j <= i; j++ {
+			if math.IsNaN(src[j]) {
+				valid = false
+				break
+			}
+			sum += src[j]
+		}
+		if valid {
+			out[i] = sum / float64(length)
+		}
+	}
+	return out
+}
+
+// ema - Exponential Moving Average, nilai awal memakai SMA seperti ta.ema
+func ema(src []float64, length int) []float64 {
+	return smoothed(src, length, 2/float64(length+1))
+}
+
+// rma - Wilder's Moving Average (dipakai RSI & ADX)
+func rma(src []float64, length int) []float64 {
+	return smoothed(src, length, 1/float64(length))
+}
+
+func smoothed(src []float64, length int, alpha float64) []float64 {
+	out := nanSeries(len(src))
+	if length <= 0 {
+		return out
+	}
+
+	start := firstValid(src)
+	if start < 0 || len(src)-start < length {
+		return out
+	}
+
+	seed := 0.0
+	for j := start; j < start+length; j++ {
+		seed += src[j]
+	}
+	prev := seed / float64(length)
+	out[start+length-1] = prev
+
+	for i := start + length; i < len(src); i++ {
+		if math.IsNaN(src[i]) {
+			out[i] = prev
+			continue
+		}
+		prev = alpha*src[i] + (1-alpha)*prev
+		out[i] = prev
+	}
+	return out
+}
+
+// wma - Weighted Moving Average
+func wma(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	if length <= 0 {
+		return out
+	}
+	norm := float64(length*(length+1)) / 2
+	for i := range src {
+		if i+1 < length {
+			continue
+		}
+		sum := 0.0
+		valid := true
+		for j := 0; j < length; j++ {
+			v := src[i-j]
+			if math.IsNaN(v) {
+				valid = false
+				break
+			}
+			sum += v * float64(length-j)
+		}
+		if valid {
+			out[i] = sum / norm
+		}
+	}
+	return out
+}
+
+func highest(src []float64, length int) []float64 {
+	return window(src, length, math.Max)
+}
+
+func lowest(src []float64, length int) []float64 {
+	return window(src, length, math.Min)
+}
+
+func window(src []float64, length int, pick func(a, b float64) float64) []float64 {
+	out := nanSeries(len(src))
+	for i := range src {
+		if i+1 < length {
+			continue
+		}
+		v := src[i]
+		for j := i + 1 - length; j < i; j++ {
+			v = pick(v, src[j])
+		}
+		out[i] = v
+	}
+	return out
+}
+
+func rollingSum(src []float64, length int) []float64 {
+	out := sma(src, length)
+	for i := range out {
+		out[i] *= float64(length)
+	}
+	return out
+}
+
+func firstValid(src []float64) int {
+	for i, v := range src {
+		if !math.IsNaN(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// at mengambil nilai ke-n dari belakang (at(src, 0) = bar terakhir, at(src, 1) = src[1] di pine)
+func at(src []float64, n int) float64 {
+	i := len(src) - 1 - n
+	if i < 0 {
+		return math.NaN()
+	}
+	return src[i]
+}
+
+// value mengubah NaN menjadi 0, sama seperti field null dari tradingview scanner
+func value(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+func isNaN(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
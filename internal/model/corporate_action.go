@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// CorporateAction dividen / split / rights issue per emiten, AppliedAt terisi setelah posisi terbuka disesuaikan
+type CorporateAction struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Exchange    string     `gorm:"not null" json:"exchange"`
+	StockCode   string     `gorm:"not null" json:"stock_code"`
+	Type        string     `gorm:"not null" json:"type"` // dto.CorporateAction*
+	ExDate      time.Time  `gorm:"not null" json:"ex_date"`
+	Numerator   float64    `gorm:"default:0" json:"numerator"`
+	Denominator float64    `gorm:"default:0" json:"denominator"`
+	Amount      float64    `gorm:"default:0" json:"amount"`
+	PriceFactor float64    `gorm:"default:0" json:"price_factor"` // pengali harga sebelum ex-date, dividen dihitung saat adjust
+	Source      string     `json:"source"`
+	AppliedAt   *time.Time `json:"applied_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (CorporateAction) TableName() string {
+	return "corporate_actions"
+}
+
+type GetCorporateActionsParam struct {
+	Exchange  string
+	StockCode string
+	Types     []string
+	Pending   *bool // true = belum diterapkan ke posisi
+	ExDateTo  time.Time
+}
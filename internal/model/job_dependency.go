@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// JobDependency JobID dijalankan setelah DependsOnJobID selesai, dalam satu pipeline yang dipicu schedule upstream
+type JobDependency struct {
+	JobID          uint      `gorm:"primaryKey"`
+	DependsOnJobID uint      `gorm:"primaryKey"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func (JobDependency) TableName() string {
+	return "job_dependencies"
+}
@@ -18,6 +18,8 @@ type StockAnalysis struct {
 	OHLCV             datatypes.JSON `gorm:"type:jsonb"`
 	TechnicalData     datatypes.JSON `gorm:"type:jsonb"`
 	Recommendation    string         `gorm:"not null"`
+	DataQualityScore  *float64       `gorm:"null"` // 0-100, nil untuk analisa sebelum ada validasi candle
+	DataQuality       datatypes.JSON `gorm:"type:jsonb"`
 	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -32,6 +34,7 @@ type GetLatestAnalysisParam struct {
 	StockCode       string
 	Exchange        string
 	TimestampAfter  time.Time
+	Timeframes      []string // hanya analisa dengan timeframe ini, kosong = semua timeframe
 	ExpectedTFCount int
 }
 
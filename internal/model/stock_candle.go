@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// StockCandle menyimpan satu bar OHLCV per (exchange, stock_code, interval, open_time)
+type StockCandle struct {
+	ID        uint      `gorm:"primarykey"`
+	Exchange  string    `gorm:"not null"`
+	StockCode string    `gorm:"not null"`
+	Interval  string    `gorm:"not null"`
+	OpenTime  time.Time `gorm:"not null"`
+	Open      float64   `gorm:"not null"`
+	High      float64   `gorm:"not null"`
+	Low       float64   `gorm:"not null"`
+	Close     float64   `gorm:"not null"`
+	Volume    float64   `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (StockCandle) TableName() string {
+	return "stock_candles"
+}
+
+type GetStockCandlesParam struct {
+	Exchange      string
+	StockCode     string
+	Interval      string
+	OpenTimeAfter time.Time // inclusive
+	OpenTimeUntil time.Time // inclusive
+	Limit         int
+}
@@ -8,18 +8,27 @@ const (
 	StockPositionSourceTypeTechnical = "TECHNICAL"
 )
 
+const (
+	PositionSideLong  = "LONG"
+	PositionSideShort = "SHORT"
+)
+
 type StockPosition struct {
 	ID                    uint       `gorm:"primaryKey" json:"id"`
 	UserID                uint       `gorm:"not null" json:"user_id"`
 	StockCode             string     `gorm:"not null" json:"stock_code"`
 	Exchange              string     `gorm:"not null" json:"exchange"`
+	Side                  string     `gorm:"default:'LONG'" json:"side"` // LONG / SHORT, BuyPrice = harga entry untuk kedua arah
 	BuyPrice              float64    `gorm:"not null" json:"buy_price"`
+	Quantity              float64    `gorm:"default:0" json:"quantity"` // sisa lembar / unit posisi, 0 = tidak dicatat. Riwayat ada di Transactions
 	TakeProfitPrice       float64    `gorm:"not null" json:"take_profit_price"`
 	StopLossPrice         float64    `gorm:"not null" json:"stop_loss_price"`
-	HighestPriceSinceTTP  float64    `gorm:"default:0" json:"highest_price_since_ttp"`
+	HighestPriceSinceTTP  float64    `gorm:"default:0" json:"highest_price_since_ttp"` // harga terendah untuk SHORT
 	TrailingProfitPrice   float64    `gorm:"not null" json:"trailing_profit_price"`
 	TrailingStopPrice     float64    `gorm:"not null" json:"trailing_stop_price"`
 	BuyDate               time.Time  `gorm:"not null" json:"buy_date"`
+	MaxHoldingDays        int        `gorm:"default:0" json:"max_holding_days"` // 0 = tanpa batas waktu hold
+	LastHoldingAlertAt    *time.Time `json:"last_holding_alert_at"`
 	IsActive              *bool      `gorm:"not null" json:"is_active"`
 	ExitPrice             *float64   `json:"exit_price"`
 	ExitDate              *time.Time `json:"exit_date"`
@@ -34,8 +43,10 @@ type StockPosition struct {
 	InitialScore          float64    `json:"initial_score"`
 	FinalScore            float64    `json:"final_score"`
 	PlanScore             float64    `json:"plan_score"`
+	PlanVersion           string     `json:"plan_version"` // versi TradingPlanConfig saat plan dibuat
 
 	StockPositionMonitorings []StockPositionMonitoring
+	Transactions             []StockPositionTransaction `gorm:"foreignKey:StockPositionID"`
 }
 
 func (StockPosition) TableName() string {
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	StockPositionTransactionTypeBuy  = "BUY"
+	StockPositionTransactionTypeSell = "SELL"
+)
+
+// StockPositionTransaction transaksi beli / jual pada satu posisi, dipakai untuk partial exit & average down
+type StockPositionTransaction struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	StockPositionID uint      `gorm:"not null" json:"stock_position_id"`
+	Type            string    `gorm:"not null" json:"type"`
+	Quantity        float64   `gorm:"not null" json:"quantity"`
+	Price           float64   `gorm:"not null" json:"price"`
+	Fee             float64   `gorm:"default:0" json:"fee"` // fee broker + pajak dalam nominal
+	TransactionDate time.Time `gorm:"not null" json:"transaction_date"`
+	Note            string    `json:"note"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (StockPositionTransaction) TableName() string {
+	return "stock_position_transactions"
+}
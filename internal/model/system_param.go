@@ -9,6 +9,9 @@ import (
 
 const (
 	SysParamDefaultAnalysisTimeframes = "DEFAULT_ANALYSIS_TIMEFRAMES"
+	SysParamTradingPlanConfig         = "TRADING_PLAN_CONFIG"
+	SysParamExchangeCalendars         = "EXCHANGE_CALENDARS"
+	SysParamAnalysisTimeframeProfiles = "ANALYSIS_TIMEFRAME_PROFILES"
 )
 
 type SystemParameter struct {
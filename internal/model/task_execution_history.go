@@ -8,16 +8,21 @@ import (
 type TaskExecutionStatus string
 
 const (
-	StatusRunning   TaskExecutionStatus = "running"
-	StatusCompleted TaskExecutionStatus = "completed"
-	StatusFailed    TaskExecutionStatus = "failed"
-	StatusTimeout   TaskExecutionStatus = "timeout"
+	StatusRunning    TaskExecutionStatus = "running"
+	StatusCompleted  TaskExecutionStatus = "completed"
+	StatusFailed     TaskExecutionStatus = "failed"
+	StatusTimeout    TaskExecutionStatus = "timeout"
+	StatusSkipped    TaskExecutionStatus = "skipped"     // market exchange tutup
+	StatusDeadLetter TaskExecutionStatus = "dead_letter" // gagal setelah retry habis
 )
 
 type TaskExecutionHistory struct {
 	ID           uint      `gorm:"primaryKey"`
 	JobID        uint      `gorm:"not null"`
 	ScheduleID   uint      `gorm:"not null"`
+	Attempt      int       `gorm:"not null;default:1"` // attempt ke-n eksekusi jadwal yang sama, >1 berarti retry
+	InstanceID   string    `gorm:"type:varchar(100)"`  // instance scheduler yang menjalankan job
+	ParentID     *uint     `gorm:"null"`               // history job upstream yang memicu pipeline
 	StartedAt    time.Time `gorm:"not null"`
 	CompletedAt  sql.NullTime
 	Status       TaskExecutionStatus `gorm:"type:varchar(50);not null"`
@@ -25,8 +30,20 @@ type TaskExecutionHistory struct {
 	Output       sql.NullString `gorm:"type:text"`
 	ErrorMessage sql.NullString `gorm:"type:text"`
 	CreatedAt    time.Time      `gorm:"autoCreateTime"`
+
+	Children []TaskExecutionHistory `gorm:"foreignKey:ParentID"` // step hilir pipeline
 }
 
 func (TaskExecutionHistory) TableName() string {
 	return "task_execution_history"
 }
+
+// GetTaskExecutionHistoriesParam filter history, nilai kosong berarti tidak difilter. To eksklusif.
+type GetTaskExecutionHistoriesParam struct {
+	JobID  uint
+	Status TaskExecutionStatus
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
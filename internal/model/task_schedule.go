@@ -11,9 +11,13 @@ type TaskSchedule struct {
 	CronExpression string `gorm:"type:varchar(100)"`
 	NextExecution  sql.NullTime
 	LastExecution  sql.NullTime
-	IsActive       bool      `gorm:"default:true"`
-	CreatedAt      time.Time `gorm:"autoCreateTime"`
-	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+	IsActive       bool         `gorm:"default:true"`
+	Exchange       string       `gorm:"type:varchar(50)"`  // cron dievaluasi di zona waktu exchange jika diisi
+	MarketHours    string       `gorm:"type:varchar(20)"`  // dto.MarketHours*, eksekusi dilewati saat market exchange tutup
+	LockedBy       string       `gorm:"type:varchar(100)"` // instance yang sedang memproses schedule
+	LockedUntil    sql.NullTime // lease LockedBy, lewat dari ini dianggap crash & bisa diklaim instance lain
+	CreatedAt      time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time    `gorm:"autoUpdateTime"`
 
 	Job Job `gorm:"foreignKey:JobID;references:ID"`
 }
@@ -17,6 +17,10 @@ type User struct {
 	LastActiveAt time.Time `gorm:"not null" json:"last_active_at"`
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	AccountSize         float64 `gorm:"default:0" json:"account_size"`           // modal trading, 0 = belum diisi
+	RiskPerTradePercent float64 `gorm:"default:0" json:"risk_per_trade_percent"` // maksimal kerugian per posisi dalam persen modal
+	TimeframeProfile    string  `gorm:"default:''" json:"timeframe_profile"`     // profil timeframe analisa, kosong = ikut symbol / exchange
 }
 
 func (User) TableName() string {
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/common"
+	"golang-trading/pkg/httpclient"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// jumlah histori funding (8 jam) untuk rata-rata funding, 21 periode = 7 hari
+	binanceFundingHistoryLimit = 21
+	// jumlah titik histori open interest untuk menghitung perubahan OI
+	binanceOpenInterestHistoryLimit = 30
+)
+
+// period yang didukung /futures/data/openInterestHist, urut dari terkecil
+var binanceOpenInterestPeriods = []string{"5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d"}
+
+// BinanceFuturesRepository sumber data USDⓈ-M perpetual futures (klines, mark price, funding rate, open interest)
+type BinanceFuturesRepository interface {
+	MarketDataProvider
+	GetKlines(ctx context.Context, symbol string, interval string, limit int, startTime, endTime int64) ([]dto.BinanceKlines, error)
+	GetPremiumIndex(ctx context.Context, symbol string) (*dto.BinancePremiumIndex, error)
+	GetFundingRateHistory(ctx context.Context, symbol string, limit int) ([]dto.BinanceFundingRate, error)
+	GetOpenInterestHistory(ctx context.Context, symbol string, period string, limit int) ([]dto.BinanceOpenInterestHist, error)
+}
+
+type binanceFuturesRepository struct {
+	httpClient     httpclient.HTTPClient
+	cfg            *config.Config
+	logger         *logger.Logger
+	requestLimiter *rate.Limiter
+	mu             sync.Mutex
+}
+
+func NewBinanceFuturesRepository(cfg *config.Config, log *logger.Logger) BinanceFuturesRepository {
+	secondsPerRequest := time.Minute / time.Duration(cfg.Binance.MaxRequestPerMinute)
+	requestLimiter := rate.NewLimiter(rate.Every(secondsPerRequest), 1)
+
+	return &binanceFuturesRepository{
+		httpClient:     httpclient.New(log, cfg.Binance.FuturesBaseURL, cfg.Binance.Timeout, ""),
+		cfg:            cfg,
+		logger:         log,
+		requestLimiter: requestLimiter,
+		mu:             sync.Mutex{},
+	}
+}
+
+func (r *binanceFuturesRepository) GetKlines(ctx context.Context, symbol string, interval string, limit int, startTime, endTime int64) ([]dto.BinanceKlines, error) {
+	queryParams := map[string]string{
+		"symbol":    symbol,
+		"interval":  interval,
+		"limit":     strconv.Itoa(limit),
+		"startTime": strconv.FormatInt(startTime, 10),
+		"endTime":   strconv.FormatInt(endTime, 10),
+	}
+
+	var klines [][]interface{}
+	if err := r.get(ctx, "/fapi/v1/klines", queryParams, &klines); err != nil {
+		return nil, fmt.Errorf("failed to fetch futures klines from binance: %w", err)
+	}
+
+	return parseBinanceKlines(klines), nil
+}
+
+func (r *binanceFuturesRepository) GetPremiumIndex(ctx context.Context, symbol string) (*dto.BinancePremiumIndex, error) {
+	var result dto.BinancePremiumIndex
+	if err := r.get(ctx, "/fapi/v1/premiumIndex", map[string]string{"symbol": symbol}, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch premium index from binance: %w", err)
+	}
+	return &result, nil
+}
+
+func (r *binanceFuturesRepository) GetFundingRateHistory(ctx context.Context, symbol string, limit int) ([]dto.BinanceFundingRate, error) {
+	queryParams := map[string]string{
+		"symbol": symbol,
+		"limit":  strconv.Itoa(limit),
+	}
+
+	var result []dto.BinanceFundingRate
+	if err := r.get(ctx, "/fapi/v1/fundingRate", queryParams, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch funding rate from binance: %w", err)
+	}
+	return result, nil
+}
+
+func (r *binanceFuturesRepository) GetOpenInterestHistory(ctx context.Context, symbol string, period string, limit int) ([]dto.BinanceOpenInterestHist, error) {
+	queryParams := map[string]string{
+		"symbol": symbol,
+		"period": period,
+		"limit":  strconv.Itoa(limit),
+	}
+
+	var result []dto.BinanceOpenInterestHist
+	if err := r.get(ctx, "/futures/data/openInterestHist", queryParams, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch open interest from binance: %w", err)
+	}
+	return result, nil
+}
+
+func (r *binanceFuturesRepository) Name() string {
+	return "binance_futures"
+}
+
+func (r *binanceFuturesRepository) Exchanges() []string {
+	return []string{common.EXCHANGE_BINANCE_FUTURES}
+}
+
+func (r *binanceFuturesRepository) SupportedIntervals() []string {
+	return binanceIntervals
+}
+
+func (r *binanceFuturesRepository) SupportedRanges() []string {
+	return marketDataRanges
+}
+
+func (r *binanceFuturesRepository) NormalizeSymbol(exchange, symbol string) string {
+	return normalizeBinanceSymbol(symbol)
+}
+
+// LastPrice mark price, dipakai juga untuk perhitungan PnL & likuidasi di binance futures
+func (r *binanceFuturesRepository) LastPrice(ctx context.Context, exchange, symbol string) (float64, error) {
+	premiumIndex, err := r.GetPremiumIndex(ctx, r.NormalizeSymbol(exchange, symbol))
+	if err != nil {
+		return 0, err
+	}
+	return premiumIndex.MarkPrice, nil
+}
+
+// Get candle futures dengan mark price sebagai market price, ditambah funding rate & open interest.
+// Data derivatif bersifat pelengkap, jika gagal diambil candle tetap dikembalikan tanpa data tersebut.
+func (r *binanceFuturesRepository) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	param.StockCode = r.NormalizeSymbol(param.Exchange, param.StockCode)
+
+	startTime, endTime := utils.MapPeriodeStringToUnixMs(param.Range)
+	if param.StartTime > 0 && param.EndTime > 0 {
+		startTime, endTime = param.StartTime*1000, param.EndTime*1000
+	}
+
+	klines, err := paginateBinanceKlines(startTime, endTime, func(start int64) ([]dto.BinanceKlines, error) {
+		return r.GetKlines(ctx, param.StockCode, param.Interval, binanceMaxKlinesLimit, start, endTime)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	premiumIndex, err := r.GetPremiumIndex(ctx, param.StockCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StockData{
+		OHLCV:       binanceKlinesToOHLCV(klines),
+		Range:       param.Range,
+		Interval:    param.Interval,
+		MarketPrice: premiumIndex.MarkPrice,
+		Derivatives: r.buildDerivatives(ctx, param, premiumIndex),
+	}, nil
+}
+
+func (r *binanceFuturesRepository) buildDerivatives(ctx context.Context, param dto.GetStockDataParam, premiumIndex *dto.BinancePremiumIndex) *dto.DerivativesData {
+	// funding dari binance dalam pecahan (0.0001), disimpan dalam persen
+	derivatives := &dto.DerivativesData{
+		MarkPrice:       premiumIndex.MarkPrice,
+		IndexPrice:      premiumIndex.IndexPrice,
+		FundingRate:     premiumIndex.LastFundingRate * 100,
+		NextFundingTime: premiumIndex.NextFundingTime,
+	}
+
+	fundingHistory, err := r.GetFundingRateHistory(ctx, param.StockCode, binanceFundingHistoryLimit)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to get funding rate history", logger.ErrorField(err), logger.StringField("symbol", param.StockCode))
+	} else if len(fundingHistory) > 0 {
+		var total float64
+		for _, f := range fundingHistory {
+			total += f.FundingRate
+		}
+		derivatives.AvgFundingRate = total / float64(len(fundingHistory)) * 100
+	}
+
+	openInterest, err := r.GetOpenInterestHistory(ctx, param.StockCode, openInterestPeriod(param.Interval), binanceOpenInterestHistoryLimit)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to get open interest history", logger.ErrorField(err), logger.StringField("symbol", param.StockCode))
+		return derivatives
+	}
+	if len(openInterest) == 0 {
+		return derivatives
+	}
+
+	first, last := openInterest[0], openInterest[len(openInterest)-1]
+	derivatives.OpenInterest = last.SumOpenInterest
+	derivatives.OpenInterestValue = last.SumOpenInterestValue
+	if first.SumOpenInterest > 0 {
+		derivatives.OpenInterestChangePercent = (last.SumOpenInterest - first.SumOpenInterest) / first.SumOpenInterest * 100
+	}
+
+	// harga pada titik histori = nilai OI / jumlah OI, sehingga perubahan harga pada window yang sama
+	// tidak bergantung pada candle yang di-fetch (bisa hanya tail saat candle sudah tersimpan)
+	if first.SumOpenInterest > 0 && last.SumOpenInterest > 0 {
+		firstPrice := first.SumOpenInterestValue / first.SumOpenInterest
+		lastPrice := last.SumOpenInterestValue / last.SumOpenInterest
+		if firstPrice > 0 {
+			derivatives.PriceChangePercent = (lastPrice - firstPrice) / firstPrice * 100
+		}
+	}
+
+	return derivatives
+}
+
+func (r *binanceFuturesRepository) get(ctx context.Context, endpoint string, queryParams map[string]string, result interface{}) error {
+	if err := r.requestLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	resp, err := r.httpClient.Get(ctx, endpoint, queryParams, nil, result)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		r.logger.Error("Binance Futures API returned Non-OK status",
+			logger.StringField("endpoint", endpoint),
+			logger.IntField("status_code", resp.StatusCode),
+			logger.StringField("body", string(resp.Body)))
+		return fmt.Errorf("binance futures api returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// openInterestPeriod period histori OI terbesar yang tidak melebihi interval candle
+func openInterestPeriod(interval string) string {
+	duration := utils.IntervalToDuration(interval)
+	if duration == 0 {
+		return "1d"
+	}
+
+	period := binanceOpenInterestPeriods[0]
+	for _, p := range binanceOpenInterestPeriods {
+		if utils.IntervalToDuration(p) > duration {
+			break
+		}
+		period = p
+	}
+	return period
+}
@@ -5,21 +5,28 @@ import (
 	"fmt"
 	"golang-trading/config"
 	"golang-trading/internal/dto"
+	"golang-trading/pkg/common"
 	"golang-trading/pkg/httpclient"
 	"golang-trading/pkg/logger"
 	"golang-trading/pkg/utils"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
+const binanceMaxKlinesLimit = 1000
+
+// interval kline yang didukung binance spot & futures
+var binanceIntervals = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w", "1M"}
+
 type BinanceRepository interface {
+	MarketDataProvider
 	GetKlines(ctx context.Context, symbol string, interval string, limit int, startTime, endTime int64) ([]dto.BinanceKlines, error)
 	GetLastPrice(ctx context.Context, symbol string) (*dto.BinancePrice, error)
-	Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error)
 }
 
 type binanceRepository struct {
@@ -70,65 +77,58 @@ func (r *binanceRepository) GetKlines(ctx context.Context, symbol string, interv
 		return nil, fmt.Errorf("binance api returned status: %d", resp.StatusCode)
 	}
 
-	var result []dto.BinanceKlines
-	for _, k := range klines {
-		openTime, _ := k[0].(float64)
-		open, _ := strconv.ParseFloat(k[1].(string), 64)
-		high, _ := strconv.ParseFloat(k[2].(string), 64)
-		low, _ := strconv.ParseFloat(k[3].(string), 64)
-		closePrice, _ := strconv.ParseFloat(k[4].(string), 64)
-		volume, _ := strconv.ParseFloat(k[5].(string), 64)
-		closeTime, _ := k[6].(float64)
-		quoteAssetVolume, _ := strconv.ParseFloat(k[7].(string), 64)
-		trades, _ := k[8].(float64)
-		takerBuyBaseAssetVolume, _ := strconv.ParseFloat(k[9].(string), 64)
-		takerBuyQuoteAssetVolume, _ := strconv.ParseFloat(k[10].(string), 64)
+	return parseBinanceKlines(klines), nil
+}
 
-		result = append(result, dto.BinanceKlines{
-			OpenTime:                 int64(openTime),
-			Open:                     open,
-			High:                     high,
-			Low:                      low,
-			Close:                    closePrice,
-			Volume:                   volume,
-			CloseTime:                int64(closeTime),
-			QuoteAssetVolume:         quoteAssetVolume,
-			NumberOfTrades:           int64(trades),
-			TakerBuyBaseAssetVolume:  takerBuyBaseAssetVolume,
-			TakerBuyQuoteAssetVolume: takerBuyQuoteAssetVolume,
-		})
-	}
+func (r *binanceRepository) Name() string {
+	return "binance"
+}
+
+func (r *binanceRepository) Exchanges() []string {
+	return []string{common.EXCHANGE_BINANCE}
+}
+
+func (r *binanceRepository) SupportedIntervals() []string {
+	return binanceIntervals
+}
+
+func (r *binanceRepository) SupportedRanges() []string {
+	return marketDataRanges
+}
+
+func (r *binanceRepository) NormalizeSymbol(exchange, symbol string) string {
+	return normalizeBinanceSymbol(symbol)
+}
 
-	return result, nil
+func (r *binanceRepository) LastPrice(ctx context.Context, exchange, symbol string) (float64, error) {
+	lastPrice, err := r.GetLastPrice(ctx, r.NormalizeSymbol(exchange, symbol))
+	if err != nil {
+		return 0, err
+	}
+	return lastPrice.Price, nil
 }
 
 func (r *binanceRepository) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	param.StockCode = r.NormalizeSymbol(param.Exchange, param.StockCode)
 	startTime, endTime := utils.MapPeriodeStringToUnixMs(param.Range)
+	if param.StartTime > 0 && param.EndTime > 0 {
+		startTime, endTime = param.StartTime*1000, param.EndTime*1000
+	}
 
-	klines, err := r.GetKlines(ctx, param.StockCode, param.Interval, 1000, startTime, endTime)
+	klines, err := paginateBinanceKlines(startTime, endTime, func(start int64) ([]dto.BinanceKlines, error) {
+		return r.GetKlines(ctx, param.StockCode, param.Interval, binanceMaxKlinesLimit, start, endTime)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var ohlcvData []dto.StockOHLCV
-	for _, k := range klines {
-		ohlcvData = append(ohlcvData, dto.StockOHLCV{
-			Timestamp: k.OpenTime, // dalam ms tpi di yahoo finance second
-			Open:      k.Open,
-			High:      k.High,
-			Low:       k.Low,
-			Close:     k.Close,
-			Volume:    k.Volume,
-		})
-	}
-
 	lastPrice, err := r.GetLastPrice(ctx, param.StockCode)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.StockData{
-		OHLCV:       ohlcvData,
+		OHLCV:       binanceKlinesToOHLCV(klines),
 		Range:       param.Range,
 		Interval:    param.Interval,
 		MarketPrice: lastPrice.Price,
@@ -168,3 +168,75 @@ func (r *binanceRepository) GetLastPrice(ctx context.Context, symbol string) (*d
 		Price:  price,
 	}, nil
 }
+
+// normalizeBinanceSymbol symbol binance tanpa pemisah, contoh btc/usdt atau BTC-USDT menjadi BTCUSDT
+func normalizeBinanceSymbol(symbol string) string {
+	return strings.NewReplacer("/", "", "-", "", "_", "").Replace(strings.ToUpper(strings.TrimSpace(symbol)))
+}
+
+// paginateBinanceKlines binance max 1000 kline per request (dihitung dari startTime), paginate sampai endTime
+func paginateBinanceKlines(startTime, endTime int64, fetch func(start int64) ([]dto.BinanceKlines, error)) ([]dto.BinanceKlines, error) {
+	var klines []dto.BinanceKlines
+	for startTime < endTime {
+		page, err := fetch(startTime)
+		if err != nil {
+			return nil, err
+		}
+
+		klines = append(klines, page...)
+		if len(page) < binanceMaxKlinesLimit {
+			break
+		}
+		startTime = page[len(page)-1].OpenTime + 1
+	}
+	return klines, nil
+}
+
+func binanceKlinesToOHLCV(klines []dto.BinanceKlines) []dto.StockOHLCV {
+	var ohlcvData []dto.StockOHLCV
+	for _, k := range klines {
+		ohlcvData = append(ohlcvData, dto.StockOHLCV{
+			Timestamp: k.OpenTime, // dalam ms tpi di yahoo finance second
+			Open:      k.Open,
+			High:      k.High,
+			Low:       k.Low,
+			Close:     k.Close,
+			Volume:    k.Volume,
+		})
+	}
+	return ohlcvData
+}
+
+// parseBinanceKlines format kline spot & futures sama (array campuran angka dan string)
+func parseBinanceKlines(klines [][]interface{}) []dto.BinanceKlines {
+	var result []dto.BinanceKlines
+	for _, k := range klines {
+		openTime, _ := k[0].(float64)
+		open, _ := strconv.ParseFloat(k[1].(string), 64)
+		high, _ := strconv.ParseFloat(k[2].(string), 64)
+		low, _ := strconv.ParseFloat(k[3].(string), 64)
+		closePrice, _ := strconv.ParseFloat(k[4].(string), 64)
+		volume, _ := strconv.ParseFloat(k[5].(string), 64)
+		closeTime, _ := k[6].(float64)
+		quoteAssetVolume, _ := strconv.ParseFloat(k[7].(string), 64)
+		trades, _ := k[8].(float64)
+		takerBuyBaseAssetVolume, _ := strconv.ParseFloat(k[9].(string), 64)
+		takerBuyQuoteAssetVolume, _ := strconv.ParseFloat(k[10].(string), 64)
+
+		result = append(result, dto.BinanceKlines{
+			OpenTime:                 int64(openTime),
+			Open:                     open,
+			High:                     high,
+			Low:                      low,
+			Close:                    closePrice,
+			Volume:                   volume,
+			CloseTime:                int64(closeTime),
+			QuoteAssetVolume:         quoteAssetVolume,
+			NumberOfTrades:           int64(trades),
+			TakerBuyBaseAssetVolume:  takerBuyBaseAssetVolume,
+			TakerBuyQuoteAssetVolume: takerBuyQuoteAssetVolume,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// koneksi dianggap putus jika tidak ada pesan / ping selama durasi ini, binance mengirim ping tiap beberapa menit
+	binanceStreamReadTimeout  = 10 * time.Minute
+	binanceStreamWriteTimeout = 10 * time.Second
+)
+
+// PriceTickHandler dipanggil untuk setiap transaksi dari price stream
+type PriceTickHandler func(ctx context.Context, tick dto.PriceTick)
+
+// PriceStream langganan harga real-time lewat websocket
+type PriceStream interface {
+	Exchange() string
+	// NormalizeSymbol symbol posisi dalam format symbol event stream
+	NormalizeSymbol(symbol string) string
+	// SetSymbols mengganti daftar symbol yang di-subscribe, koneksi aktif langsung subscribe / unsubscribe selisihnya
+	SetSymbols(symbols []string)
+	// Run menjaga koneksi sampai ctx selesai: reconnect otomatis dan subscribe ulang semua symbol,
+	// koneksi ditutup selama tidak ada symbol
+	Run(ctx context.Context, handler PriceTickHandler)
+}
+
+type binanceStream struct {
+	logger         *logger.Logger
+	exchange       string
+	url            string
+	reconnectDelay time.Duration
+	dialer         *websocket.Dialer
+
+	mu        sync.Mutex
+	symbols   map[string]bool
+	changed   chan struct{}
+	requestID int64
+}
+
+// binanceStreamMessage pesan combined stream (/stream) atau balasan SUBSCRIBE / UNSUBSCRIBE
+type binanceStreamMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+	ID     int64           `json:"id"`
+	Error  *struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
+
+type binanceStreamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int64    `json:"id"`
+}
+
+// NewBinanceStream price stream aggTrade binance, url berupa endpoint combined stream spot / futures
+func NewBinanceStream(log *logger.Logger, exchange, url string, reconnectDelay time.Duration) PriceStream {
+	if reconnectDelay <= 0 {
+		reconnectDelay = 5 * time.Second
+	}
+	return &binanceStream{
+		logger:         log,
+		exchange:       exchange,
+		url:            url,
+		reconnectDelay: reconnectDelay,
+		dialer:         websocket.DefaultDialer,
+		symbols:        make(map[string]bool),
+		changed:        make(chan struct{}, 1),
+	}
+}
+
+func (r *binanceStream) Exchange() string {
+	return r.exchange
+}
+
+func (r *binanceStream) NormalizeSymbol(symbol string) string {
+	return normalizeBinanceSymbol(symbol)
+}
+
+func (r *binanceStream) SetSymbols(symbols []string) {
+	desired := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		if symbol = normalizeBinanceSymbol(symbol); symbol != "" {
+			desired[symbol] = true
+		}
+	}
+
+	r.mu.Lock()
+	r.symbols = desired
+	r.mu.Unlock()
+
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (r *binanceStream) Run(ctx context.Context, handler PriceTickHandler) {
+	for {
+		if len(r.desiredSymbols()) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.changed:
+				continue
+			}
+		}
+
+		err := r.session(ctx, handler)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			// ditutup karena tidak ada symbol yang perlu di-subscribe
+			continue
+		}
+
+		r.logger.WarnContext(ctx, "Price stream disconnected, reconnecting",
+			logger.ErrorField(err),
+			logger.StringField("exchange", r.exchange),
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.reconnectDelay):
+		}
+	}
+}
+
+// session satu koneksi websocket, selesai saat koneksi putus, ctx selesai, atau symbol kosong (nil)
+func (r *binanceStream) session(ctx context.Context, handler PriceTickHandler) error {
+	conn, _, err := r.dialer.DialContext(ctx, r.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", r.url, err)
+	}
+	defer conn.Close()
+
+	r.logger.InfoContext(ctx, "Price stream connected", logger.StringField("exchange", r.exchange))
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- r.readLoop(ctx, conn, handler)
+	}()
+
+	subscribed := make(map[string]bool)
+	if err := r.syncSubscriptions(conn, subscribed); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(binanceStreamWriteTimeout))
+			return ctx.Err()
+		case err := <-readErr:
+			return err
+		case <-r.changed:
+			if len(r.desiredSymbols()) == 0 {
+				return nil
+			}
+			if err := r.syncSubscriptions(conn, subscribed); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// syncSubscriptions mengirim UNSUBSCRIBE / SUBSCRIBE untuk selisih symbol, subscribed diperbarui
+func (r *binanceStream) syncSubscriptions(conn *websocket.Conn, subscribed map[string]bool) error {
+	desired := r.desiredSymbols()
+
+	var subscribe, unsubscribe []string
+	for symbol := range desired {
+		if !subscribed[symbol] {
+			subscribe = append(subscribe, binanceTradeStreamName(symbol))
+		}
+	}
+	for symbol := range subscribed {
+		if !desired[symbol] {
+			unsubscribe = append(unsubscribe, binanceTradeStreamName(symbol))
+		}
+	}
+
+	if err := r.sendRequest(conn, "UNSUBSCRIBE", unsubscribe); err != nil {
+		return err
+	}
+	if err := r.sendRequest(conn, "SUBSCRIBE", subscribe); err != nil {
+		return err
+	}
+
+	clear(subscribed)
+	for symbol := range desired {
+		subscribed[symbol] = true
+	}
+	return nil
+}
+
+func (r *binanceStream) sendRequest(conn *websocket.Conn, method string, params []string) error {
+	if len(params) == 0 {
+		return nil
+	}
+	sort.Strings(params)
+
+	r.mu.Lock()
+	r.requestID++
+	id := r.requestID
+	r.mu.Unlock()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(binanceStreamWriteTimeout)); err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(binanceStreamRequest{Method: method, Params: params, ID: id}); err != nil {
+		return fmt.Errorf("failed to %s %s: %w", strings.ToLower(method), strings.Join(params, ","), err)
+	}
+	return nil
+}
+
+func (r *binanceStream) readLoop(ctx context.Context, conn *websocket.Conn, handler PriceTickHandler) error {
+	_ = conn.SetReadDeadline(time.Now().Add(binanceStreamReadTimeout))
+	conn.SetPingHandler(func(appData string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(binanceStreamReadTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(binanceStreamWriteTimeout))
+	})
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(binanceStreamReadTimeout))
+
+		var msg binanceStreamMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			r.logger.WarnContext(ctx, "Failed to decode price stream message", logger.ErrorField(err), logger.StringField("exchange", r.exchange))
+			continue
+		}
+		if msg.Error != nil {
+			r.logger.WarnContext(ctx, "Price stream request rejected",
+				logger.StringField("exchange", r.exchange),
+				logger.IntField("request_id", int(msg.ID)),
+				logger.StringField("error", msg.Error.Msg),
+			)
+			continue
+		}
+		// balasan subscribe tanpa data
+		if len(msg.Data) == 0 {
+			continue
+		}
+
+		var trade dto.BinanceAggTrade
+		if err := json.Unmarshal(msg.Data, &trade); err != nil {
+			r.logger.WarnContext(ctx, "Failed to decode price stream trade", logger.ErrorField(err), logger.StringField("stream", msg.Stream))
+			continue
+		}
+		if trade.Symbol == "" || trade.Price <= 0 {
+			continue
+		}
+
+		handler(ctx, dto.PriceTick{
+			Exchange:  r.exchange,
+			Symbol:    trade.Symbol,
+			Price:     trade.Price,
+			Timestamp: time.UnixMilli(trade.TradeTime),
+		})
+	}
+}
+
+func (r *binanceStream) desiredSymbols() map[string]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.symbols
+}
+
+// binanceTradeStreamName nama stream aggTrade, binance memakai symbol huruf kecil
+func binanceTradeStreamName(symbol string) string {
+	return strings.ToLower(symbol) + "@aggTrade"
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeBinanceStreamServer pengganti websocket combined stream binance: mencatat request SUBSCRIBE / UNSUBSCRIBE
+// dan bisa mengirim event aggTrade atau memutus koneksi aktif
+type fakeBinanceStreamServer struct {
+	*httptest.Server
+	requests    chan binanceStreamRequest
+	connections chan struct{}
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func newFakeBinanceStreamServer(t *testing.T) *fakeBinanceStreamServer {
+	s := &fakeBinanceStreamServer{
+		requests:    make(chan binanceStreamRequest, 16),
+		connections: make(chan struct{}, 4),
+	}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conn = conn
+		s.mu.Unlock()
+		s.connections <- struct{}{}
+
+		for {
+			var req binanceStreamRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			s.requests <- req
+			s.write(`{"result":null,"id":` + strconv.FormatInt(req.ID, 10) + `}`)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeBinanceStreamServer) url() string {
+	return "ws" + strings.TrimPrefix(s.Server.URL, "http") + "/stream"
+}
+
+func (s *fakeBinanceStreamServer) write(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.conn.WriteMessage(websocket.TextMessage, []byte(msg))
+}
+
+func (s *fakeBinanceStreamServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.conn.Close()
+}
+
+func waitRequest(t *testing.T, requests chan binanceStreamRequest) binanceStreamRequest {
+	t.Helper()
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for stream request")
+		return binanceStreamRequest{}
+	}
+}
+
+func waitConnection(t *testing.T, connections chan struct{}) {
+	t.Helper()
+	select {
+	case <-connections:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for stream connection")
+	}
+}
+
+func TestBinanceStream(t *testing.T) {
+	server := newFakeBinanceStreamServer(t)
+	stream := NewBinanceStream(&logger.Logger{Logger: zap.NewNop()}, "BINANCE", server.url(), 10*time.Millisecond)
+
+	ticks := make(chan dto.PriceTick, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stream.Run(ctx, func(ctx context.Context, tick dto.PriceTick) { ticks <- tick })
+	}()
+
+	stream.SetSymbols([]string{"btc/usdt", "ETHUSDT"})
+	waitConnection(t, server.connections)
+	req := waitRequest(t, server.requests)
+	assert.Equal(t, "SUBSCRIBE", req.Method)
+	assert.Equal(t, []string{"btcusdt@aggTrade", "ethusdt@aggTrade"}, req.Params)
+
+	server.write(`{"stream":"btcusdt@aggTrade","data":{"e":"aggTrade","s":"BTCUSDT","p":"65000.50","q":"0.1","T":1717000000000}}`)
+	select {
+	case tick := <-ticks:
+		assert.Equal(t, "BINANCE", tick.Exchange)
+		assert.Equal(t, "BTCUSDT", tick.Symbol)
+		assert.Equal(t, 65000.50, tick.Price)
+		assert.Equal(t, int64(1717000000000), tick.Timestamp.UnixMilli())
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for price tick")
+	}
+
+	// perubahan posisi hanya mengirim selisih symbol
+	stream.SetSymbols([]string{"BTCUSDT", "SOLUSDT"})
+	req = waitRequest(t, server.requests)
+	assert.Equal(t, "UNSUBSCRIBE", req.Method)
+	assert.Equal(t, []string{"ethusdt@aggTrade"}, req.Params)
+	req = waitRequest(t, server.requests)
+	assert.Equal(t, "SUBSCRIBE", req.Method)
+	assert.Equal(t, []string{"solusdt@aggTrade"}, req.Params)
+
+	// koneksi putus: reconnect lalu subscribe ulang semua symbol
+	server.drop()
+	waitConnection(t, server.connections)
+	req = waitRequest(t, server.requests)
+	assert.Equal(t, "SUBSCRIBE", req.Method)
+	assert.Equal(t, []string{"btcusdt@aggTrade", "solusdt@aggTrade"}, req.Params)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream did not stop after context cancel")
+	}
+}
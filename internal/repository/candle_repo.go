@@ -2,30 +2,283 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"golang-trading/internal/candlequality"
 	"golang-trading/internal/dto"
-	"golang-trading/pkg/common"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"sort"
+	"time"
 )
 
+// toleransi selisih antara awal range dengan candle pertama yang tersimpan (weekend / libur bursa)
+const candleHeadTolerance = 4 * 24 * time.Hour
+
 type CandleRepository interface {
 	Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error)
 }
 
 type candleRepository struct {
-	binanceRepo BinanceRepository
-	yahooRepo   YahooFinanceRepository
+	logger          *logger.Logger
+	stockCandleRepo StockCandleRepository
+	marketData      MarketDataRegistry
+	calendarRepo    TradingCalendarRepository
+	corporateAction CorporateActionRepository
 }
 
-func NewCandleRepository(binanceRepo BinanceRepository, yahooRepo YahooFinanceRepository) CandleRepository {
+func NewCandleRepository(log *logger.Logger, stockCandleRepo StockCandleRepository, marketData MarketDataRegistry, calendarRepo TradingCalendarRepository, corporateAction CorporateActionRepository) CandleRepository {
 	return &candleRepository{
-		binanceRepo: binanceRepo,
-		yahooRepo:   yahooRepo,
+		logger:          log,
+		stockCandleRepo: stockCandleRepo,
+		marketData:      marketData,
+		calendarRepo:    calendarRepo,
+		corporateAction: corporateAction,
 	}
 }
 
+// Get candle exchange yang sudah divalidasi & diperbaiki (bar nol / NaN, timestamp dobel, spike, gap),
+// report kualitasnya dikembalikan di StockData.Quality.
 func (r *candleRepository) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
-	if param.Exchange == common.EXCHANGE_BINANCE {
-		return r.binanceRepo.Get(ctx, param)
+	data, err := r.load(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	if param.Adjusted {
+		data.OHLCV = r.backAdjust(ctx, param, data.OHLCV)
+	}
+
+	ohlcv, report := candlequality.Check(param.Exchange, param.Interval, data.OHLCV, r.calendarRepo.Get(ctx, param.Exchange))
+	if len(ohlcv) == 0 {
+		return nil, fmt.Errorf("no valid OHLCV data after quality check for symbol: %s", param.StockCode)
+	}
+	if report.Score < 100 {
+		r.logger.DebugContext(ctx, "Candle quality issues repaired",
+			logger.StringField("stock_code", param.StockCode),
+			logger.StringField("exchange", param.Exchange),
+			logger.StringField("interval", param.Interval),
+			logger.Field("score", report.Score),
+			logger.IntField("dropped_bars", report.DroppedBars),
+			logger.IntField("spike_bars", report.SpikeBars),
+			logger.IntField("missing_bars", report.MissingBars),
+		)
+	}
+
+	data.OHLCV = ohlcv
+	data.Quality = &report
+	return data, nil
+}
+
+// load membaca candle dari stock_candles, lalu hanya mengambil tail yang belum ada dari provider market data exchange.
+// Bar terakhir yang tersimpan selalu di-fetch ulang karena bisa saja belum close.
+func (r *candleRepository) load(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	periode := param.Range
+	if param.StartTime > 0 && param.EndTime > 0 {
+		periode = ""
+	}
+	if err := r.marketData.Validate(param.Exchange, param.Interval, periode); err != nil {
+		return nil, err
+	}
+
+	from, to := utils.MapPeriodeStringToUnix(param.Range)
+	if param.StartTime > 0 && param.EndTime > 0 {
+		from, to = param.StartTime, param.EndTime
 	}
 
-	return r.yahooRepo.Get(ctx, param)
+	if from == 0 || to == 0 {
+		return r.marketData.Get(ctx, param)
+	}
+
+	stored, err := r.stockCandleRepo.Get(ctx, model.GetStockCandlesParam{
+		Exchange:      param.Exchange,
+		StockCode:     param.StockCode,
+		Interval:      param.Interval,
+		OpenTimeAfter: time.Unix(from, 0),
+		OpenTimeUntil: time.Unix(to, 0),
+	})
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to read stored candles, fallback to full fetch",
+			logger.ErrorField(err),
+			logger.StringField("stock_code", param.StockCode),
+			logger.StringField("exchange", param.Exchange),
+		)
+		stored = nil
+	}
+
+	fetchParam := param
+	if r.isHeadCovered(stored, from, param.Interval) {
+		last := stored[len(stored)-1]
+		fetchParam.StartTime = last.OpenTime.Unix()
+		fetchParam.EndTime = to
+		stored = stored[:len(stored)-1]
+	} else {
+		stored = nil
+	}
+
+	fetched, err := r.marketData.Get(ctx, fetchParam)
+	if err != nil {
+		return nil, err
+	}
+
+	// candle provider sudah disesuaikan split, candle tersimpan sebelum ex-date split baru masih skala lama
+	if split := r.saveCorporateActions(ctx, param, fetched); split != nil {
+		deleted, err := r.stockCandleRepo.DeleteBefore(ctx, param.Exchange, param.StockCode, split.ExDate)
+		if err != nil {
+			r.logger.WarnContext(ctx, "Failed to delete candles before stock split",
+				logger.ErrorField(err),
+				logger.StringField("stock_code", param.StockCode),
+				logger.StringField("exchange", param.Exchange),
+			)
+		} else if len(stored) > 0 {
+			r.logger.InfoContext(ctx, "Stock split detected, stored candles refetched",
+				logger.StringField("stock_code", param.StockCode),
+				logger.StringField("exchange", param.Exchange),
+				logger.Field("ex_date", split.ExDate),
+				logger.Field("deleted_candles", deleted),
+			)
+			return r.load(ctx, param)
+		}
+	}
+
+	// candle dari candle_cache sudah tersimpan, tidak perlu di-upsert ulang
+	if fetched.Source != candleCacheProviderName {
+		if err := r.stockCandleRepo.Upsert(ctx, r.toStockCandles(param, fetched.OHLCV)); err != nil {
+			r.logger.WarnContext(ctx, "Failed to store candles",
+				logger.ErrorField(err),
+				logger.StringField("stock_code", param.StockCode),
+				logger.StringField("exchange", param.Exchange),
+			)
+		}
+	}
+
+	return &dto.StockData{
+		MarketPrice:            fetched.MarketPrice,
+		Range:                  param.Range,
+		Interval:               param.Interval,
+		OHLCV:                  r.merge(param.Exchange, stored, fetched.OHLCV),
+		Derivatives:            fetched.Derivatives,
+		Source:                 fetched.Source,
+		PriceDivergencePercent: fetched.PriceDivergencePercent,
+		CorporateActions:       fetched.CorporateActions,
+	}, nil
+}
+
+// saveCorporateActions menyimpan dividen & split dari provider, mengembalikan split baru dengan ex-date paling akhir
+func (r *candleRepository) saveCorporateActions(ctx context.Context, param dto.GetStockDataParam, data *dto.StockData) *model.CorporateAction {
+	if len(data.CorporateActions) == 0 {
+		return nil
+	}
+
+	actions := make([]model.CorporateAction, 0, len(data.CorporateActions))
+	for _, action := range data.CorporateActions {
+		actions = append(actions, model.CorporateAction{
+			Exchange:    param.Exchange,
+			StockCode:   param.StockCode,
+			Type:        action.Type,
+			ExDate:      action.ExDate,
+			Numerator:   action.Numerator,
+			Denominator: action.Denominator,
+			Amount:      action.Amount,
+			PriceFactor: action.PriceFactor,
+			Source:      data.Source,
+		})
+	}
+
+	created, err := r.corporateAction.Save(ctx, actions)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to store corporate actions",
+			logger.ErrorField(err),
+			logger.StringField("stock_code", param.StockCode),
+			logger.StringField("exchange", param.Exchange),
+		)
+	}
+
+	var split *model.CorporateAction
+	for i, action := range created {
+		if action.Type == dto.CorporateActionSplit && (split == nil || action.ExDate.After(split.ExDate)) {
+			split = &created[i]
+		}
+	}
+	return split
+}
+
+// backAdjust menerapkan dividen & rights issue tersimpan ke candle sebelum ex-date
+func (r *candleRepository) backAdjust(ctx context.Context, param dto.GetStockDataParam, ohlcv []dto.StockOHLCV) []dto.StockOHLCV {
+	actions, err := r.corporateAction.Get(ctx, model.GetCorporateActionsParam{
+		Exchange:  param.Exchange,
+		StockCode: param.StockCode,
+		Types:     []string{dto.CorporateActionDividend, dto.CorporateActionRights},
+	})
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to get corporate actions, candles are not adjusted",
+			logger.ErrorField(err),
+			logger.StringField("stock_code", param.StockCode),
+			logger.StringField("exchange", param.Exchange),
+		)
+		return ohlcv
+	}
+
+	return dto.BackAdjust(ohlcv, toCorporateActionDTO(actions), func(ts int64) time.Time {
+		return utils.CandleTimestampToTime(param.Exchange, ts)
+	})
+}
+
+func (r *candleRepository) isHeadCovered(stored []model.StockCandle, from int64, interval string) bool {
+	if len(stored) == 0 {
+		return false
+	}
+
+	tolerance := utils.IntervalToDuration(interval) + candleHeadTolerance
+	return !stored[0].OpenTime.After(time.Unix(from, 0).Add(tolerance))
+}
+
+func (r *candleRepository) toStockCandles(param dto.GetStockDataParam, ohlcv []dto.StockOHLCV) []model.StockCandle {
+	candles := make([]model.StockCandle, 0, len(ohlcv))
+	for _, c := range ohlcv {
+		// bar null dari provider tidak disimpan, validasi kualitas tetap mendeteksinya saat fetch
+		if c.Close <= 0 {
+			continue
+		}
+		candles = append(candles, model.StockCandle{
+			Exchange:  param.Exchange,
+			StockCode: param.StockCode,
+			Interval:  param.Interval,
+			OpenTime:  utils.CandleTimestampToTime(param.Exchange, c.Timestamp),
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		})
+	}
+	return candles
+}
+
+// merge menggabungkan candle tersimpan dengan hasil fetch, data fetch menang jika timestamp sama
+func (r *candleRepository) merge(exchange string, stored []model.StockCandle, fetched []dto.StockOHLCV) []dto.StockOHLCV {
+	byTimestamp := make(map[int64]dto.StockOHLCV, len(stored)+len(fetched))
+	for _, c := range stored {
+		ts := utils.TimeToCandleTimestamp(exchange, c.OpenTime)
+		byTimestamp[ts] = dto.StockOHLCV{
+			Timestamp: ts,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	for _, c := range fetched {
+		byTimestamp[c.Timestamp] = c
+	}
+
+	result := make([]dto.StockOHLCV, 0, len(byTimestamp))
+	for _, c := range byTimestamp {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result
 }
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/tradingcalendar"
+	"golang-trading/pkg/common"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var candleTestStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testCandle bar harian ke-day, volume menandai asal candle (1 tersimpan, 2 provider)
+func testCandle(day int, volume float64) dto.StockOHLCV {
+	price := 100 + float64(day)
+	return dto.StockOHLCV{
+		Timestamp: candleTestStart.AddDate(0, 0, day).UnixMilli(),
+		Open:      price,
+		High:      price + 1,
+		Low:       price - 1,
+		Close:     price,
+		Volume:    volume,
+	}
+}
+
+// fakeCandleMarketData provider remote dengan bar harian hari 0 s/d days-1, overlap bar sebelum StartTime ikut dikirim
+type fakeCandleMarketData struct {
+	MarketDataRegistry
+	days    int
+	overlap int
+	params  []dto.GetStockDataParam
+}
+
+func (m *fakeCandleMarketData) Validate(exchange, interval, periode string) error {
+	return nil
+}
+
+func (m *fakeCandleMarketData) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	m.params = append(m.params, param)
+	start := time.Unix(param.StartTime, 0).AddDate(0, 0, -m.overlap)
+	var ohlcv []dto.StockOHLCV
+	for day := 0; day < m.days; day++ {
+		if openTime := candleTestStart.AddDate(0, 0, day); !openTime.Before(start) {
+			ohlcv = append(ohlcv, testCandle(day, 2))
+		}
+	}
+	return &dto.StockData{OHLCV: ohlcv, Source: "fake"}, nil
+}
+
+type fakeStockCandleRepository struct {
+	StockCandleRepository
+	stored   []model.StockCandle
+	upserted []model.StockCandle
+}
+
+func (r *fakeStockCandleRepository) Get(ctx context.Context, param model.GetStockCandlesParam, opts ...utils.DBOption) ([]model.StockCandle, error) {
+	var candles []model.StockCandle
+	for _, candle := range r.stored {
+		if !candle.OpenTime.Before(param.OpenTimeAfter) && !candle.OpenTime.After(param.OpenTimeUntil) {
+			candles = append(candles, candle)
+		}
+	}
+	return candles, nil
+}
+
+func (r *fakeStockCandleRepository) Upsert(ctx context.Context, candles []model.StockCandle, opts ...utils.DBOption) error {
+	r.upserted = append(r.upserted, candles...)
+	return nil
+}
+
+type fakeCandleCalendarRepository struct{}
+
+func (fakeCandleCalendarRepository) Get(ctx context.Context, exchange string) tradingcalendar.Calendar {
+	return tradingcalendar.DefaultCalendar(exchange)
+}
+
+func TestCandleRepositoryGet(t *testing.T) {
+	tests := []struct {
+		name       string
+		storedDays []int
+		overlap    int
+		fetchFrom  int   // hari awal yang diminta ke provider
+		volumes    []int // asal tiap bar hasil Get, 1 tersimpan 2 provider
+		upserted   int
+	}{
+		{
+			name:      "empty store",
+			fetchFrom: 0,
+			volumes:   []int{2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+			upserted:  10,
+		},
+		{
+			name:       "stored head missing",
+			storedDays: []int{6, 7, 8},
+			fetchFrom:  0,
+			volumes:    []int{2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+			upserted:   10,
+		},
+		{
+			name:       "partial tail",
+			storedDays: []int{0, 1, 2, 3, 4, 5},
+			fetchFrom:  5,
+			volumes:    []int{1, 1, 1, 1, 1, 2, 2, 2, 2, 2},
+			upserted:   5,
+		},
+		{
+			name:       "full coverage",
+			storedDays: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			fetchFrom:  9,
+			volumes:    []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 2},
+			upserted:   1,
+		},
+		{
+			name:       "overlapping bars",
+			storedDays: []int{0, 1, 2, 3, 4, 5, 6},
+			overlap:    3,
+			fetchFrom:  6,
+			volumes:    []int{1, 1, 1, 2, 2, 2, 2, 2, 2, 2},
+			upserted:   7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marketData := &fakeCandleMarketData{days: 10, overlap: tt.overlap}
+			stockCandleRepo := &fakeStockCandleRepository{}
+			for _, day := range tt.storedDays {
+				c := testCandle(day, 1)
+				stockCandleRepo.stored = append(stockCandleRepo.stored, model.StockCandle{
+					Exchange:  common.EXCHANGE_BINANCE,
+					StockCode: "BTCUSDT",
+					Interval:  "1d",
+					OpenTime:  utils.CandleTimestampToTime(common.EXCHANGE_BINANCE, c.Timestamp),
+					Open:      c.Open,
+					High:      c.High,
+					Low:       c.Low,
+					Close:     c.Close,
+					Volume:    c.Volume,
+				})
+			}
+			repo := NewCandleRepository(&logger.Logger{Logger: zap.NewNop()}, stockCandleRepo, marketData, fakeCandleCalendarRepository{}, nil)
+
+			data, err := repo.Get(context.Background(), dto.GetStockDataParam{
+				Exchange:  common.EXCHANGE_BINANCE,
+				StockCode: "BTCUSDT",
+				Interval:  "1d",
+				StartTime: candleTestStart.Unix(),
+				EndTime:   candleTestStart.AddDate(0, 0, 9).Unix(),
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if assert.Len(t, marketData.params, 1) {
+				assert.Equal(t, candleTestStart.AddDate(0, 0, tt.fetchFrom).Unix(), marketData.params[0].StartTime)
+			}
+			if assert.Len(t, data.OHLCV, len(tt.volumes)) {
+				for day, volume := range tt.volumes {
+					assert.Equal(t, testCandle(day, float64(volume)), data.OHLCV[day], "day %d", day)
+				}
+			}
+			assert.Len(t, stockCandleRepo.upserted, tt.upserted)
+			assert.Equal(t, 100.0, data.Quality.Score)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CorporateActionRepository interface {
+	Get(ctx context.Context, param model.GetCorporateActionsParam, opts ...utils.DBOption) ([]model.CorporateAction, error)
+	// Save menyimpan corporate action yang belum ada, mengembalikan yang baru tersimpan
+	Save(ctx context.Context, actions []model.CorporateAction, opts ...utils.DBOption) ([]model.CorporateAction, error)
+	MarkApplied(ctx context.Context, id uint, appliedAt time.Time, opts ...utils.DBOption) error
+}
+
+type corporateActionRepository struct {
+	db *gorm.DB
+}
+
+func NewCorporateActionRepository(db *gorm.DB) CorporateActionRepository {
+	return &corporateActionRepository{
+		db: db,
+	}
+}
+
+// Get mengembalikan corporate action terurut dari ex-date paling lama
+func (r *corporateActionRepository) Get(ctx context.Context, param model.GetCorporateActionsParam, opts ...utils.DBOption) ([]model.CorporateAction, error) {
+	var actions []model.CorporateAction
+
+	qFilter := []string{"1 = 1"}
+	qFilterParam := []interface{}{}
+
+	if param.Exchange != "" {
+		qFilter = append(qFilter, "exchange = ?")
+		qFilterParam = append(qFilterParam, param.Exchange)
+	}
+
+	if param.StockCode != "" {
+		qFilter = append(qFilter, "stock_code = ?")
+		qFilterParam = append(qFilterParam, param.StockCode)
+	}
+
+	if len(param.Types) > 0 {
+		qFilter = append(qFilter, "type IN (?)")
+		qFilterParam = append(qFilterParam, param.Types)
+	}
+
+	if param.Pending != nil {
+		if *param.Pending {
+			qFilter = append(qFilter, "applied_at IS NULL")
+		} else {
+			qFilter = append(qFilter, "applied_at IS NOT NULL")
+		}
+	}
+
+	if !param.ExDateTo.IsZero() {
+		qFilter = append(qFilter, "ex_date <= ?")
+		qFilterParam = append(qFilterParam, param.ExDateTo.UTC())
+	}
+
+	err := utils.ApplyOptions(r.db.WithContext(ctx), opts...).
+		Where(strings.Join(qFilter, " AND "), qFilterParam...).
+		Order("ex_date ASC, id ASC").
+		Find(&actions).Error
+	return actions, err
+}
+
+func (r *corporateActionRepository) Save(ctx context.Context, actions []model.CorporateAction, opts ...utils.DBOption) ([]model.CorporateAction, error) {
+	var created []model.CorporateAction
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	for _, action := range actions {
+		result := db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "exchange"},
+				{Name: "stock_code"},
+				{Name: "type"},
+				{Name: "ex_date"},
+			},
+			DoNothing: true,
+		}).Create(&action)
+		if result.Error != nil {
+			return created, result.Error
+		}
+		if result.RowsAffected > 0 {
+			created = append(created, action)
+		}
+	}
+	return created, nil
+}
+
+func (r *corporateActionRepository) MarkApplied(ctx context.Context, id uint, appliedAt time.Time, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).
+		Model(&model.CorporateAction{}).
+		Where("id = ?", id).
+		Update("applied_at", appliedAt).Error
+}
+
+// toCorporateActionDTO corporate action tersimpan sebagai input dto.BackAdjust
+func toCorporateActionDTO(actions []model.CorporateAction) []dto.CorporateAction {
+	result := make([]dto.CorporateAction, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, dto.CorporateAction{
+			Type:        action.Type,
+			ExDate:      action.ExDate,
+			Numerator:   action.Numerator,
+			Denominator: action.Denominator,
+			Amount:      action.Amount,
+			PriceFactor: action.PriceFactor,
+		})
+	}
+	return result
+}
@@ -2,21 +2,49 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"golang-trading/internal/model"
 	"golang-trading/pkg/utils"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type JobRepository interface {
-	FindJobsToSchedule(ctx context.Context, opts ...utils.DBOption) ([]model.TaskSchedule, error)
+	// ClaimJobsToSchedule mengklaim schedule yang jatuh tempo & schedule dengan lease kedaluwarsa (instance crash)
+	// untuk instanceID dengan FOR UPDATE SKIP LOCKED, sehingga satu schedule hanya diproses satu replika.
+	// limit > 0 membatasi jumlah schedule yang diklaim, yang paling lama jatuh tempo didahulukan.
+	ClaimJobsToSchedule(ctx context.Context, instanceID string, lease time.Duration, limit int, opts ...utils.DBOption) ([]model.TaskSchedule, error)
+	// ClaimTaskSchedule mengklaim lease satu schedule untuk run manual walau belum jatuh tempo,
+	// false jika lease masih dipegang instance lain / run lain
+	ClaimTaskSchedule(ctx context.Context, scheduleID uint, instanceID string, lease time.Duration) (bool, error)
+	// ExtendTaskScheduleLease memperpanjang lease, false jika lease sudah bukan milik instanceID
+	ExtendTaskScheduleLease(ctx context.Context, scheduleID uint, instanceID string, lease time.Duration) (bool, error)
+	ReleaseTaskSchedule(ctx context.Context, scheduleID uint, instanceID string) error
 	CreateTaskExecutionHistory(ctx context.Context, history *model.TaskExecutionHistory, opts ...utils.DBOption) error
 	UpdateTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error
 	FindByID(ctx context.Context, id uint) (*model.Job, error)
 	UpdateTaskExecutionHistory(ctx context.Context, history *model.TaskExecutionHistory, opts ...utils.DBOption) error
 	Get(ctx context.Context, param *model.GetJobParam, opts ...utils.DBOption) ([]model.Job, error)
 	DeleteTaskHistoryOlderThan(ctx context.Context, date time.Time, opts ...utils.DBOption) (int64, error)
+	GetJobDependencies(ctx context.Context, opts ...utils.DBOption) ([]model.JobDependency, error)
+	// LockJobDependencies mengunci tabel dependency sampai transaksi selesai supaya validasi siklus tidak balapan
+	LockJobDependencies(ctx context.Context, opts ...utils.DBOption) error
+	CreateJobDependencies(ctx context.Context, dependencies []model.JobDependency, opts ...utils.DBOption) error
+	// DeleteJobDependencies menghapus upstream dependsOnJobIDs milik jobID, dependsOnJobIDs kosong menghapus semua upstream jobID
+	DeleteJobDependencies(ctx context.Context, jobID uint, dependsOnJobIDs []uint, opts ...utils.DBOption) (int64, error)
+	CreateJob(ctx context.Context, job *model.Job, opts ...utils.DBOption) error
+	UpdateJob(ctx context.Context, job *model.Job, opts ...utils.DBOption) error
+	DeleteJob(ctx context.Context, id uint, opts ...utils.DBOption) error
+	// FindTaskScheduleByID nil jika schedule tidak ada
+	FindTaskScheduleByID(ctx context.Context, id uint) (*model.TaskSchedule, error)
+	CreateTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error
+	// SaveTaskSchedule menyimpan semua kolom schedule kecuali lease, termasuk nilai kosong (mis. is_active false)
+	SaveTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error
+	DeleteTaskSchedule(ctx context.Context, id uint, opts ...utils.DBOption) error
+	// GetTaskExecutionHistories history terbaru sesuai filter beserta total seluruh history yang cocok
+	GetTaskExecutionHistories(ctx context.Context, param model.GetTaskExecutionHistoriesParam, opts ...utils.DBOption) ([]model.TaskExecutionHistory, int64, error)
 }
 
 type jobRepository struct {
@@ -27,25 +55,109 @@ func NewJobRepository(db *gorm.DB) JobRepository {
 	return &jobRepository{db: db}
 }
 
-// FindJobsToSchedule finds all active jobs with schedules that need to be run.
-func (r *jobRepository) FindJobsToSchedule(ctx context.Context, opts ...utils.DBOption) ([]model.TaskSchedule, error) {
+func (r *jobRepository) ClaimJobsToSchedule(ctx context.Context, instanceID string, lease time.Duration, limit int, opts ...utils.DBOption) ([]model.TaskSchedule, error) {
 	var schedules []model.TaskSchedule
-	// Find jobs with active schedules that are due
-	err := utils.ApplyOptions(r.db.WithContext(ctx), opts...).
-		Where("is_active = ? AND (next_execution IS NULL OR next_execution <= ?)", true, utils.TimeNowWIB()).
-		Find(&schedules).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := utils.TimeNowWIB()
+
+		var claimed []model.TaskSchedule
+		// jatuh tempo & tidak dipegang instance lain, atau lease kedaluwarsa walau belum jatuh tempo (job instance crash)
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id", "locked_by").
+			Where("is_active = ?", true).
+			Where("(COALESCE(locked_by, '') = '' AND (next_execution IS NULL OR next_execution <= ?)) OR (COALESCE(locked_by, '') <> '' AND locked_until < ?)", now, now).
+			Order("next_execution ASC NULLS FIRST, id ASC")
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+		err := query.Find(&claimed).Error
+		if err != nil || len(claimed) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(claimed))
+		for _, schedule := range claimed {
+			ids = append(ids, schedule.ID)
+			if err := failExpiredLeaseHistories(tx, schedule, now); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&model.TaskSchedule{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"locked_by": instanceID, "locked_until": now.Add(lease)}).Error
+		if err != nil {
+			return err
+		}
+		return utils.ApplyOptions(tx, opts...).Where("id IN ?", ids).Find(&schedules).Error
+	})
 	if err != nil {
 		return nil, err
 	}
 	return schedules, nil
 }
 
+func (r *jobRepository) ClaimTaskSchedule(ctx context.Context, scheduleID uint, instanceID string, lease time.Duration) (bool, error) {
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := utils.TimeNowWIB()
+
+		var schedules []model.TaskSchedule
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "locked_by").
+			Where("id = ?", scheduleID).
+			Where("COALESCE(locked_by, '') = '' OR locked_until < ?", now).
+			Find(&schedules).Error
+		if err != nil || len(schedules) == 0 {
+			return err
+		}
+		if err := failExpiredLeaseHistories(tx, schedules[0], now); err != nil {
+			return err
+		}
+
+		claimed = true
+		return tx.Model(&model.TaskSchedule{}).Where("id = ?", scheduleID).
+			Updates(map[string]interface{}{"locked_by": instanceID, "locked_until": now.Add(lease)}).Error
+	})
+	return claimed, err
+}
+
+// failExpiredLeaseHistories history running milik instance yang lease-nya kedaluwarsa tidak akan pernah selesai
+func failExpiredLeaseHistories(tx *gorm.DB, schedule model.TaskSchedule, now time.Time) error {
+	if schedule.LockedBy == "" {
+		return nil
+	}
+	return tx.Model(&model.TaskExecutionHistory{}).
+		Where("schedule_id = ? AND instance_id = ? AND status = ?", schedule.ID, schedule.LockedBy, model.StatusRunning).
+		Updates(map[string]interface{}{
+			"status":        model.StatusFailed,
+			"error_message": fmt.Sprintf("lease of instance %s expired", schedule.LockedBy),
+			"completed_at":  now,
+		}).Error
+}
+
+func (r *jobRepository) ExtendTaskScheduleLease(ctx context.Context, scheduleID uint, instanceID string, lease time.Duration) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.TaskSchedule{}).
+		Where("id = ? AND locked_by = ?", scheduleID, instanceID).
+		Update("locked_until", utils.TimeNowWIB().Add(lease))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *jobRepository) ReleaseTaskSchedule(ctx context.Context, scheduleID uint, instanceID string) error {
+	return r.db.WithContext(ctx).Model(&model.TaskSchedule{}).
+		Where("id = ? AND locked_by = ?", scheduleID, instanceID).
+		Updates(map[string]interface{}{"locked_by": nil, "locked_until": nil}).Error
+}
+
 func (r *jobRepository) CreateTaskExecutionHistory(ctx context.Context, history *model.TaskExecutionHistory, opts ...utils.DBOption) error {
 	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Create(history).Error
 }
 
+// UpdateTaskSchedule hanya menyimpan waktu eksekusi schedule. Konfigurasi (cron, is_active, exchange) diubah lewat
+// SaveTaskSchedule dan lease lewat Claim / Extend / Release, supaya scheduler tidak menimpa perubahan dari API.
 func (r *jobRepository) UpdateTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error {
-	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Updates(schedule).Error
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Model(schedule).
+		Select("next_execution", "last_execution", "updated_at").
+		Updates(schedule).Error
 }
 
 func (r *jobRepository) FindByID(ctx context.Context, id uint) (*model.Job, error) {
@@ -74,6 +186,9 @@ func (r *jobRepository) Get(ctx context.Context, param *model.GetJobParam, opts
 		db = db.Limit(*param.Limit)
 	}
 	if param.WithTaskHistory != nil {
+		db = db.Preload("Histories.Children", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		})
 		db = db.Preload("Histories", func(db *gorm.DB) *gorm.DB {
 			db = db.Order("created_at DESC")
 			if param.WithTaskHistory.Limit != nil {
@@ -95,3 +210,100 @@ func (r *jobRepository) Get(ctx context.Context, param *model.GetJobParam, opts
 func (r *jobRepository) DeleteTaskHistoryOlderThan(ctx context.Context, date time.Time, opts ...utils.DBOption) (int64, error) {
 	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Where("created_at < ?", date).Delete(&model.TaskExecutionHistory{}).RowsAffected, nil
 }
+
+func (r *jobRepository) GetJobDependencies(ctx context.Context, opts ...utils.DBOption) ([]model.JobDependency, error) {
+	var dependencies []model.JobDependency
+	if err := utils.ApplyOptions(r.db.WithContext(ctx), opts...).Find(&dependencies).Error; err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+func (r *jobRepository) CreateJob(ctx context.Context, job *model.Job, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Omit("Schedules", "Histories").Create(job).Error
+}
+
+func (r *jobRepository) UpdateJob(ctx context.Context, job *model.Job, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Model(job).
+		Select("name", "description", "type", "payload", "retry_policy", "timeout", "updated_at").
+		Updates(job).Error
+}
+
+func (r *jobRepository) DeleteJob(ctx context.Context, id uint, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Delete(&model.Job{}, id).Error
+}
+
+func (r *jobRepository) FindTaskScheduleByID(ctx context.Context, id uint) (*model.TaskSchedule, error) {
+	var schedule model.TaskSchedule
+	if err := r.db.WithContext(ctx).Preload("Job").First(&schedule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *jobRepository) CreateTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Omit("Job", "locked_by", "locked_until").Create(schedule).Error
+}
+
+func (r *jobRepository) SaveTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Model(schedule).
+		Select("cron_expression", "next_execution", "is_active", "exchange", "market_hours", "updated_at").
+		Updates(schedule).Error
+}
+
+func (r *jobRepository) DeleteTaskSchedule(ctx context.Context, id uint, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Delete(&model.TaskSchedule{}, id).Error
+}
+
+func (r *jobRepository) GetTaskExecutionHistories(ctx context.Context, param model.GetTaskExecutionHistoriesParam, opts ...utils.DBOption) ([]model.TaskExecutionHistory, int64, error) {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...).Model(&model.TaskExecutionHistory{})
+	if param.JobID != 0 {
+		db = db.Where("job_id = ?", param.JobID)
+	}
+	if param.Status != "" {
+		db = db.Where("status = ?", param.Status)
+	}
+	if !param.From.IsZero() {
+		db = db.Where("started_at >= ?", param.From)
+	}
+	if !param.To.IsZero() {
+		db = db.Where("started_at < ?", param.To)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var histories []model.TaskExecutionHistory
+	if param.Limit > 0 {
+		db = db.Limit(param.Limit)
+	}
+	if err := db.Offset(param.Offset).Order("started_at DESC, id DESC").Find(&histories).Error; err != nil {
+		return nil, 0, err
+	}
+	return histories, total, nil
+}
+
+func (r *jobRepository) LockJobDependencies(ctx context.Context, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Exec("LOCK TABLE job_dependencies IN SHARE ROW EXCLUSIVE MODE").Error
+}
+
+func (r *jobRepository) CreateJobDependencies(ctx context.Context, dependencies []model.JobDependency, opts ...utils.DBOption) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Clauses(clause.OnConflict{DoNothing: true}).Create(&dependencies).Error
+}
+
+func (r *jobRepository) DeleteJobDependencies(ctx context.Context, jobID uint, dependsOnJobIDs []uint, opts ...utils.DBOption) (int64, error) {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...).Where("job_id = ?", jobID)
+	if len(dependsOnJobIDs) > 0 {
+		db = db.Where("depends_on_job_id IN ?", dependsOnJobIDs)
+	}
+	result := db.Delete(&model.JobDependency{})
+	return result.RowsAffected, result.Error
+}
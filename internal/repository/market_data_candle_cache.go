@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/utils"
+	"time"
+)
+
+const candleCacheProviderName = "candle_cache"
+
+// candleCacheProvider candle yang sudah tersimpan di stock_candles sebagai fallback terakhir saat provider live gagal.
+// Tidak melayani exchange apapun secara default, hanya dipakai jika disebut di MARKET_DATA_FALLBACK_<EXCHANGE>.
+type candleCacheProvider struct {
+	stockCandleRepo StockCandleRepository
+}
+
+func NewCandleCacheProvider(stockCandleRepo StockCandleRepository) MarketDataProvider {
+	return &candleCacheProvider{
+		stockCandleRepo: stockCandleRepo,
+	}
+}
+
+func (p *candleCacheProvider) Name() string {
+	return candleCacheProviderName
+}
+
+func (p *candleCacheProvider) Exchanges() []string {
+	return nil
+}
+
+func (p *candleCacheProvider) SupportedIntervals() []string {
+	return nil
+}
+
+func (p *candleCacheProvider) SupportedRanges() []string {
+	return nil
+}
+
+func (p *candleCacheProvider) NormalizeSymbol(exchange, symbol string) string {
+	return symbol
+}
+
+// Get candle tersimpan pada range request, market price diambil dari close candle terakhir (bisa sudah basi)
+func (p *candleCacheProvider) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	from, to := utils.MapPeriodeStringToUnix(param.Range)
+	if param.StartTime > 0 && param.EndTime > 0 {
+		from, to = param.StartTime, param.EndTime
+	}
+	if from == 0 || to == 0 {
+		return nil, fmt.Errorf("invalid period")
+	}
+
+	candles, err := p.stockCandleRepo.Get(ctx, model.GetStockCandlesParam{
+		Exchange:      param.Exchange,
+		StockCode:     param.StockCode,
+		Interval:      param.Interval,
+		OpenTimeAfter: time.Unix(from, 0),
+		OpenTimeUntil: time.Unix(to, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no stored candles for symbol: %s", param.StockCode)
+	}
+
+	ohlcv := make([]dto.StockOHLCV, 0, len(candles))
+	for _, c := range candles {
+		ohlcv = append(ohlcv, dto.StockOHLCV{
+			Timestamp: utils.TimeToCandleTimestamp(param.Exchange, c.OpenTime),
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		})
+	}
+
+	return &dto.StockData{
+		MarketPrice: ohlcv[len(ohlcv)-1].Close,
+		Range:       param.Range,
+		Interval:    param.Interval,
+		OHLCV:       ohlcv,
+	}, nil
+}
+
+func (p *candleCacheProvider) LastPrice(ctx context.Context, exchange, symbol string) (float64, error) {
+	return 0, fmt.Errorf("last price is not available from %s", candleCacheProviderName)
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+	"math"
+	"time"
+)
+
+// request dengan EndTime lebih lama dari ini dianggap historis (backtest), market price tidak di cross-check
+const marketDataHistoricalAfter = 24 * time.Hour
+
+type marketDataHealth struct {
+	successCount        int64
+	failureCount        int64
+	consecutiveFailures int
+	divergenceCount     int64
+	totalLatency        time.Duration
+	lastError           string
+	lastSuccessAt       time.Time
+	lastFailureAt       time.Time
+}
+
+// Get mengambil candle dari provider sesuai urutan chain exchange. Provider yang gagal (error / data kosong)
+// dilewati ke provider berikutnya, provider yang sedang cooldown dicoba paling akhir.
+func (r *marketDataRegistry) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	chain, err := r.Chain(param.Exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	periode := param.Range
+	if param.StartTime > 0 && param.EndTime > 0 {
+		periode = ""
+	}
+
+	var errs []error
+	for _, provider := range r.orderByHealth(chain) {
+		if !marketDataSupports(provider, param.Interval, periode) {
+			continue
+		}
+
+		start := time.Now()
+		data, err := provider.Get(ctx, param)
+		if err == nil && (data == nil || len(data.OHLCV) == 0) {
+			err = fmt.Errorf("no data returned for symbol: %s", param.StockCode)
+		}
+		r.record(provider.Name(), time.Since(start), err)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			if ctx.Err() != nil {
+				break
+			}
+			r.logger.WarnContext(ctx, "Market data provider failed, trying next provider",
+				logger.ErrorField(err),
+				logger.StringField("provider", provider.Name()),
+				logger.StringField("exchange", param.Exchange),
+				logger.StringField("stock_code", param.StockCode),
+			)
+			continue
+		}
+
+		data.Source = provider.Name()
+		if provider.Name() != chain[0].Name() {
+			r.logger.WarnContext(ctx, "Market data served by fallback provider",
+				logger.StringField("provider", provider.Name()),
+				logger.StringField("primary", chain[0].Name()),
+				logger.StringField("exchange", param.Exchange),
+				logger.StringField("stock_code", param.StockCode),
+			)
+		}
+		r.crossCheck(ctx, param, provider, chain, data)
+		return data, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no market data provider supports interval %s range %s for exchange %s", param.Interval, param.Range, param.Exchange)
+	}
+	return nil, fmt.Errorf("all market data providers failed for %s:%s: %w", param.Exchange, param.StockCode, errors.Join(errs...))
+}
+
+// crossCheck membandingkan market price dengan provider live lain di chain, selisih di atas batas ditandai di data & health
+func (r *marketDataRegistry) crossCheck(ctx context.Context, param dto.GetStockDataParam, source MarketDataProvider, chain []MarketDataProvider, data *dto.StockData) {
+	if r.cfg.PriceDivergencePercent <= 0 || data.MarketPrice <= 0 || source.Name() == candleCacheProviderName {
+		return
+	}
+	if param.EndTime > 0 && time.Since(time.Unix(param.EndTime, 0)) > marketDataHistoricalAfter {
+		return
+	}
+
+	for _, provider := range chain {
+		if provider.Name() == source.Name() || provider.Name() == candleCacheProviderName || r.isCoolingDown(provider.Name()) {
+			continue
+		}
+
+		start := time.Now()
+		price, err := provider.LastPrice(ctx, param.Exchange, param.StockCode)
+		r.record(provider.Name(), time.Since(start), err)
+		if err != nil || price <= 0 {
+			r.logger.DebugContext(ctx, "Skip market price cross-check",
+				logger.ErrorField(err),
+				logger.StringField("provider", provider.Name()),
+				logger.StringField("stock_code", param.StockCode),
+			)
+			return
+		}
+
+		divergence := math.Abs(data.MarketPrice-price) / price * 100
+		if divergence <= r.cfg.PriceDivergencePercent {
+			return
+		}
+
+		data.PriceDivergencePercent = divergence
+		r.recordDivergence(source.Name())
+		r.logger.WarnContext(ctx, "Market price diverges between providers",
+			logger.StringField("exchange", param.Exchange),
+			logger.StringField("stock_code", param.StockCode),
+			logger.StringField("source", source.Name()),
+			logger.StringField("reference", provider.Name()),
+			logger.Field("source_price", data.MarketPrice),
+			logger.Field("reference_price", price),
+			logger.Field("divergence_percent", divergence),
+		)
+		return
+	}
+}
+
+// orderByHealth provider yang sedang cooldown dipindah ke akhir tanpa mengubah urutan lainnya
+func (r *marketDataRegistry) orderByHealth(chain []MarketDataProvider) []MarketDataProvider {
+	ordered := make([]MarketDataProvider, 0, len(chain))
+	var coolingDown []MarketDataProvider
+	for _, provider := range chain {
+		if r.isCoolingDown(provider.Name()) {
+			coolingDown = append(coolingDown, provider)
+			continue
+		}
+		ordered = append(ordered, provider)
+	}
+	return append(ordered, coolingDown...)
+}
+
+func (r *marketDataRegistry) record(name string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health, ok := r.health[name]
+	if !ok {
+		return
+	}
+
+	health.totalLatency += latency
+	if err != nil {
+		health.failureCount++
+		health.consecutiveFailures++
+		health.lastError = err.Error()
+		health.lastFailureAt = time.Now()
+		return
+	}
+	health.successCount++
+	health.consecutiveFailures = 0
+	health.lastSuccessAt = time.Now()
+}
+
+func (r *marketDataRegistry) recordDivergence(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if health, ok := r.health[name]; ok {
+		health.divergenceCount++
+	}
+}
+
+func (r *marketDataRegistry) isCoolingDown(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	health, ok := r.health[name]
+	return ok && !r.cooldownUntil(health).IsZero()
+}
+
+// cooldownUntil waktu berakhirnya cooldown provider, zero jika provider tidak sedang cooldown
+func (r *marketDataRegistry) cooldownUntil(health *marketDataHealth) time.Time {
+	if r.cfg.FailureThreshold <= 0 || health.consecutiveFailures < r.cfg.FailureThreshold {
+		return time.Time{}
+	}
+
+	until := health.lastFailureAt.Add(r.cfg.FailureCooldown)
+	if time.Now().After(until) {
+		return time.Time{}
+	}
+	return until
+}
+
+// Health statistik setiap provider sesuai urutan pendaftaran
+func (r *marketDataRegistry) Health() []dto.MarketDataProviderHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]dto.MarketDataProviderHealth, 0, len(r.names))
+	for _, name := range r.names {
+		health := r.health[name]
+		item := dto.MarketDataProviderHealth{
+			Provider:            name,
+			Exchanges:           r.providers[name].Exchanges(),
+			SuccessCount:        health.successCount,
+			FailureCount:        health.failureCount,
+			ConsecutiveFailures: health.consecutiveFailures,
+			DivergenceCount:     health.divergenceCount,
+			LastError:           health.lastError,
+		}
+		if total := health.successCount + health.failureCount; total > 0 {
+			item.AvgLatencyMs = float64(health.totalLatency.Milliseconds()) / float64(total)
+		}
+		if lastSuccessAt := health.lastSuccessAt; !lastSuccessAt.IsZero() {
+			item.LastSuccessAt = &lastSuccessAt
+		}
+		if lastFailureAt := health.lastFailureAt; !lastFailureAt.IsZero() {
+			item.LastFailureAt = &lastFailureAt
+		}
+		if until := r.cooldownUntil(health); !until.IsZero() {
+			item.CoolingDownUntil = &until
+		}
+		result = append(result, item)
+	}
+	return result
+}
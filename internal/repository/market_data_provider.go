@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// range data (dto.GetStockDataParam.Range) yang dikenal utils.MapPeriodeStringToUnix
+var marketDataRanges = []string{"1d", "14d", "1w", "1m", "2m", "3m", "6m", "1y"}
+
+// MarketDataProvider sumber candle & harga terakhir untuk satu atau beberapa exchange.
+// Setiap provider mendaftarkan exchange yang dilayani ke MarketDataRegistry.
+type MarketDataProvider interface {
+	Name() string
+	Exchanges() []string
+	// SupportedIntervals & SupportedRanges kosong berarti semua interval / range didukung
+	SupportedIntervals() []string
+	SupportedRanges() []string
+	// NormalizeSymbol mengubah kode saham / pair dari user ke format symbol provider
+	NormalizeSymbol(exchange, symbol string) string
+	Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error)
+	LastPrice(ctx context.Context, exchange, symbol string) (float64, error)
+}
+
+// MarketDataRegistry routing exchange ke MarketDataProvider, exchange baru cukup didaftarkan tanpa mengubah routing.
+// Get mencoba provider sesuai urutan fallback exchange dan mencatat kesehatan tiap provider.
+type MarketDataRegistry interface {
+	Register(provider MarketDataProvider) error
+	SetFallbacks(exchange string, providerNames []string) error
+	Provider(exchange string) (MarketDataProvider, error)
+	Chain(exchange string) ([]MarketDataProvider, error)
+	Exchanges() []string
+	Validate(exchange, interval, periode string) error
+	Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error)
+	Health() []dto.MarketDataProviderHealth
+}
+
+type marketDataRegistry struct {
+	mu        sync.RWMutex
+	cfg       config.MarketData
+	logger    *logger.Logger
+	providers map[string]MarketDataProvider // nama provider -> provider
+	names     []string                      // urutan pendaftaran provider
+	routes    map[string][]string           // exchange -> nama provider, provider pertama adalah primary
+	fallbacks map[string][]string           // exchange -> urutan provider dari konfigurasi
+	exchanges []string                      // urutan pendaftaran, dipakai untuk pilihan exchange di telegram
+	health    map[string]*marketDataHealth
+}
+
+func NewMarketDataRegistry(cfg config.MarketData, log *logger.Logger) MarketDataRegistry {
+	return &marketDataRegistry{
+		cfg:       cfg,
+		logger:    log,
+		providers: make(map[string]MarketDataProvider),
+		routes:    make(map[string][]string),
+		fallbacks: make(map[string][]string),
+		health:    make(map[string]*marketDataHealth),
+	}
+}
+
+// Register mendaftarkan provider, provider yang mendaftar exchange yang sudah ada menjadi alternatif (fallback) exchange tersebut
+func (r *marketDataRegistry) Register(provider MarketDataProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := provider.Name()
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("market data provider %s already registered", name)
+	}
+	r.providers[name] = provider
+	r.names = append(r.names, name)
+	r.health[name] = &marketDataHealth{}
+
+	for _, exchange := range provider.Exchanges() {
+		exchange = strings.ToUpper(exchange)
+		if _, ok := r.routes[exchange]; !ok {
+			r.exchanges = append(r.exchanges, exchange)
+		}
+		r.routes[exchange] = append(r.routes[exchange], name)
+	}
+	return nil
+}
+
+// SetFallbacks override urutan provider exchange, semua nama provider harus sudah terdaftar
+func (r *marketDataRegistry) SetFallbacks(exchange string, providerNames []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(providerNames) == 0 {
+		return fmt.Errorf("fallback chain for exchange %s is empty", exchange)
+	}
+	for _, name := range providerNames {
+		if _, ok := r.providers[name]; !ok {
+			return fmt.Errorf("fallback provider %s for exchange %s is not registered", name, exchange)
+		}
+	}
+
+	exchange = strings.ToUpper(exchange)
+	if _, ok := r.routes[exchange]; !ok {
+		r.exchanges = append(r.exchanges, exchange)
+		r.routes[exchange] = nil
+	}
+	r.fallbacks[exchange] = slices.Clone(providerNames)
+	return nil
+}
+
+func (r *marketDataRegistry) Provider(exchange string) (MarketDataProvider, error) {
+	chain, err := r.Chain(exchange)
+	if err != nil {
+		return nil, err
+	}
+	return chain[0], nil
+}
+
+// Chain urutan provider yang dicoba untuk exchange: konfigurasi fallback jika ada, selain itu urutan pendaftaran
+func (r *marketDataRegistry) Chain(exchange string) ([]MarketDataProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exchange = strings.ToUpper(exchange)
+	names, ok := r.fallbacks[exchange]
+	if !ok {
+		names = r.routes[exchange]
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("exchange %s is not supported, available: %s", exchange, strings.Join(r.exchanges, ", "))
+	}
+
+	chain := make([]MarketDataProvider, 0, len(names))
+	for _, name := range names {
+		chain = append(chain, r.providers[name])
+	}
+	return chain, nil
+}
+
+func (r *marketDataRegistry) Exchanges() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.exchanges)
+}
+
+// Validate memastikan exchange terdaftar serta interval & range didukung minimal satu provider di chain-nya.
+// periode kosong (request dengan StartTime / EndTime eksplisit) tidak divalidasi.
+func (r *marketDataRegistry) Validate(exchange, interval, periode string) error {
+	chain, err := r.Chain(exchange)
+	if err != nil {
+		return err
+	}
+
+	for _, provider := range chain {
+		if marketDataSupports(provider, interval, periode) {
+			return nil
+		}
+	}
+
+	primary := chain[0]
+	if !marketDataSupports(primary, interval, "") {
+		return fmt.Errorf("interval %s is not supported by %s for exchange %s", interval, primary.Name(), exchange)
+	}
+	return fmt.Errorf("range %s is not supported by %s for exchange %s", periode, primary.Name(), exchange)
+}
+
+func marketDataSupports(provider MarketDataProvider, interval, periode string) bool {
+	intervals, ranges := provider.SupportedIntervals(), provider.SupportedRanges()
+	if len(intervals) > 0 && !slices.Contains(intervals, interval) {
+		return false
+	}
+	return periode == "" || len(ranges) == 0 || slices.Contains(ranges, periode)
+}
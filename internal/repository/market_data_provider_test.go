@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeMarketDataProvider struct {
+	name      string
+	exchanges []string
+	price     float64
+	err       error
+	calls     int
+}
+
+func (p *fakeMarketDataProvider) Name() string                 { return p.name }
+func (p *fakeMarketDataProvider) Exchanges() []string          { return p.exchanges }
+func (p *fakeMarketDataProvider) SupportedIntervals() []string { return []string{"1h", "1d"} }
+func (p *fakeMarketDataProvider) SupportedRanges() []string    { return []string{"1m", "1y"} }
+func (p *fakeMarketDataProvider) NormalizeSymbol(exchange, symbol string) string {
+	return symbol
+}
+func (p *fakeMarketDataProvider) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &dto.StockData{MarketPrice: p.price, OHLCV: []dto.StockOHLCV{{Timestamp: 1, Close: p.price}}}, nil
+}
+func (p *fakeMarketDataProvider) LastPrice(ctx context.Context, exchange, symbol string) (float64, error) {
+	return p.price, p.err
+}
+
+func newTestMarketDataRegistry(cfg config.MarketData) MarketDataRegistry {
+	return NewMarketDataRegistry(cfg, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestMarketDataRegistry(t *testing.T) {
+	registry := newTestMarketDataRegistry(config.MarketData{})
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "stocks", exchanges: []string{"IDX", "NYSE"}}))
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "crypto", exchanges: []string{"BYBIT"}}))
+	assert.Equal(t, []string{"IDX", "NYSE", "BYBIT"}, registry.Exchanges())
+
+	// nama provider harus unik, provider kedua untuk exchange yang sama menjadi alternatif
+	assert.Error(t, registry.Register(&fakeMarketDataProvider{name: "stocks"}))
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "other", exchanges: []string{"NYSE"}}))
+	assert.Equal(t, []string{"IDX", "NYSE", "BYBIT"}, registry.Exchanges())
+
+	provider, err := registry.Provider("nyse")
+	assert.NoError(t, err)
+	assert.Equal(t, "stocks", provider.Name())
+
+	chain, err := registry.Chain("NYSE")
+	assert.NoError(t, err)
+	assert.Len(t, chain, 2)
+
+	_, err = registry.Provider("OKX")
+	assert.Error(t, err)
+
+	assert.NoError(t, registry.Validate("IDX", "1d", "1y"))
+	assert.NoError(t, registry.Validate("IDX", "1d", ""))
+	assert.Error(t, registry.Validate("IDX", "4h", "1y"))
+	assert.Error(t, registry.Validate("IDX", "1d", "14d"))
+	assert.Error(t, registry.Validate("OKX", "1d", "1y"))
+
+	assert.Error(t, registry.SetFallbacks("IDX", []string{"stocks", "unknown"}))
+	assert.NoError(t, registry.SetFallbacks("IDX", []string{"other", "stocks"}))
+	provider, err = registry.Provider("IDX")
+	assert.NoError(t, err)
+	assert.Equal(t, "other", provider.Name())
+}
+
+func TestMarketDataRegistryFailover(t *testing.T) {
+	registry := newTestMarketDataRegistry(config.MarketData{FailureThreshold: 2, FailureCooldown: time.Minute})
+	primary := &fakeMarketDataProvider{name: "primary", exchanges: []string{"IDX"}, err: errors.New("status 429")}
+	backup := &fakeMarketDataProvider{name: "backup", exchanges: []string{"IDX"}, price: 100}
+	assert.NoError(t, registry.Register(primary))
+	assert.NoError(t, registry.Register(backup))
+
+	param := dto.GetStockDataParam{StockCode: "BBCA", Exchange: "IDX", Interval: "1d", Range: "1y"}
+	for i := 0; i < 3; i++ {
+		data, err := registry.Get(context.Background(), param)
+		assert.NoError(t, err)
+		assert.Equal(t, "backup", data.Source)
+	}
+	// setelah gagal 2x berturut-turut primary cooldown dan tidak dicoba lagi selama provider lain sehat
+	assert.Equal(t, 2, primary.calls)
+
+	health := registry.Health()
+	assert.Equal(t, "primary", health[0].Provider)
+	assert.Equal(t, int64(2), health[0].FailureCount)
+	assert.NotNil(t, health[0].CoolingDownUntil)
+	assert.Equal(t, int64(3), health[1].SuccessCount)
+
+	backup.err = errors.New("timeout")
+	_, err := registry.Get(context.Background(), param)
+	assert.Error(t, err)
+	assert.Equal(t, 3, primary.calls)
+}
+
+func TestMarketDataRegistryPriceDivergence(t *testing.T) {
+	registry := newTestMarketDataRegistry(config.MarketData{PriceDivergencePercent: 2})
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "primary", exchanges: []string{"NYSE"}, price: 110}))
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "reference", exchanges: []string{"NYSE"}, price: 100}))
+
+	data, err := registry.Get(context.Background(), dto.GetStockDataParam{StockCode: "IBM", Exchange: "NYSE", Interval: "1d", Range: "1y"})
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", data.Source)
+	assert.InDelta(t, 10, data.PriceDivergencePercent, 1e-9)
+	assert.Equal(t, int64(1), registry.Health()[0].DivergenceCount)
+}
+
+func TestMarketDataNormalizeSymbol(t *testing.T) {
+	yahoo := &yahooFinanceRepository{cfg: &config.Config{}}
+	assert.Equal(t, "BBCA.JK", yahoo.NormalizeSymbol("IDX", "bbca"))
+	assert.Equal(t, "BBCA.JK", yahoo.NormalizeSymbol("IDX", "BBCA.JK"))
+	assert.Equal(t, "IBM", yahoo.NormalizeSymbol("NYSE", "ibm"))
+
+	assert.Equal(t, "BTCUSDT", normalizeBinanceSymbol("btc/usdt"))
+	assert.Equal(t, "BTCUSDT", normalizeBinanceSymbol("BTC-USDT"))
+
+	rest := &restMarketDataRepository{cfg: config.MarketDataREST{Exchange: "BYBIT"}}
+	assert.Equal(t, "BTC/USDT", rest.NormalizeSymbol("BYBIT", "btcusdt"))
+	assert.Equal(t, "ETH/BTC", rest.NormalizeSymbol("BYBIT", "ETH-BTC"))
+	assert.Equal(t, "SOL/USDC", rest.NormalizeSymbol("BYBIT", "SOL/USDC"))
+	assert.Equal(t, "1w", restMarketDataTimeframe("1wk"))
+	assert.Equal(t, "4h", restMarketDataTimeframe("4h"))
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/httpclient"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const restMarketDataMaxOHLCVLimit = 1000
+
+// timeframe unified CCXT bawaan jika MARKET_DATA_REST_<EXCHANGE>_INTERVALS kosong
+var restMarketDataDefaultIntervals = []string{"1m", "5m", "15m", "30m", "1h", "4h", "1d", "1w"}
+
+// quote asset yang dikenali untuk memecah symbol tanpa pemisah (BTCUSDT -> BTC/USDT), urut dari yang terpanjang
+var restMarketDataQuoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "USD", "BTC", "ETH", "EUR"}
+
+// restMarketDataRepository adapter REST generik dengan kontrak unified CCXT:
+//
+//	GET {base}/ohlcv?symbol=BTC/USDT&timeframe=1h&since=<ms>&limit=<n> -> [[timestamp_ms, open, high, low, close, volume], ...]
+//	GET {base}/ticker?symbol=BTC/USDT                                  -> {"symbol": "BTC/USDT", "last": 65000.5}
+//
+// sehingga exchange baru (Bybit, OKX, ...) cukup dilayani service ccxt di base URL tersebut.
+type restMarketDataRepository struct {
+	httpClient     httpclient.HTTPClient
+	cfg            config.MarketDataREST
+	logger         *logger.Logger
+	requestLimiter *rate.Limiter
+}
+
+func NewRESTMarketDataRepository(cfg config.MarketDataREST, log *logger.Logger) MarketDataProvider {
+	maxRequestPerMinute := cfg.MaxRequestPerMinute
+	if maxRequestPerMinute <= 0 {
+		maxRequestPerMinute = 60
+	}
+	requestLimiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(maxRequestPerMinute)), 1)
+
+	return &restMarketDataRepository{
+		httpClient:     httpclient.New(log, cfg.BaseURL, cfg.Timeout, ""),
+		cfg:            cfg,
+		logger:         log,
+		requestLimiter: requestLimiter,
+	}
+}
+
+func (r *restMarketDataRepository) Name() string {
+	return "rest:" + strings.ToLower(r.cfg.Exchange)
+}
+
+func (r *restMarketDataRepository) Exchanges() []string {
+	return []string{r.cfg.Exchange}
+}
+
+func (r *restMarketDataRepository) SupportedIntervals() []string {
+	if len(r.cfg.Intervals) > 0 {
+		return r.cfg.Intervals
+	}
+	return restMarketDataDefaultIntervals
+}
+
+func (r *restMarketDataRepository) SupportedRanges() []string {
+	return marketDataRanges
+}
+
+// NormalizeSymbol format unified CCXT BASE/QUOTE
+func (r *restMarketDataRepository) NormalizeSymbol(exchange, symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.Contains(symbol, "/") {
+		return symbol
+	}
+	if base, quote, ok := strings.Cut(symbol, "-"); ok {
+		return base + "/" + quote
+	}
+
+	for _, quote := range restMarketDataQuoteAssets {
+		if base, ok := strings.CutSuffix(symbol, quote); ok && base != "" {
+			return base + "/" + quote
+		}
+	}
+	return symbol
+}
+
+func (r *restMarketDataRepository) LastPrice(ctx context.Context, exchange, symbol string) (float64, error) {
+	var ticker struct {
+		Symbol string  `json:"symbol"`
+		Last   float64 `json:"last"`
+	}
+	queryParams := map[string]string{"symbol": r.NormalizeSymbol(exchange, symbol)}
+	if err := r.get(ctx, "/ticker", queryParams, &ticker); err != nil {
+		return 0, fmt.Errorf("failed to fetch ticker from %s: %w", r.Name(), err)
+	}
+	return ticker.Last, nil
+}
+
+// Get timestamp candle dikonversi ke second (seperti yahoo finance) karena hanya binance yang disimpan dalam ms
+func (r *restMarketDataRepository) Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error) {
+	startTime, endTime := utils.MapPeriodeStringToUnixMs(param.Range)
+	if param.StartTime > 0 && param.EndTime > 0 {
+		startTime, endTime = param.StartTime*1000, param.EndTime*1000
+	}
+	if startTime == 0 || endTime == 0 {
+		return nil, fmt.Errorf("invalid period")
+	}
+
+	symbol := r.NormalizeSymbol(param.Exchange, param.StockCode)
+	timeframe := restMarketDataTimeframe(param.Interval)
+
+	var ohlcvData []dto.StockOHLCV
+	for since := startTime; since < endTime; {
+		var rows [][]float64
+		queryParams := map[string]string{
+			"symbol":    symbol,
+			"timeframe": timeframe,
+			"since":     strconv.FormatInt(since, 10),
+			"limit":     strconv.Itoa(restMarketDataMaxOHLCVLimit),
+		}
+		if err := r.get(ctx, "/ohlcv", queryParams, &rows); err != nil {
+			return nil, fmt.Errorf("failed to fetch ohlcv from %s: %w", r.Name(), err)
+		}
+
+		lastTimestamp := since
+		for _, row := range rows {
+			if len(row) < 6 || int64(row[0]) < since || int64(row[0]) > endTime {
+				continue
+			}
+			lastTimestamp = int64(row[0])
+			ohlcvData = append(ohlcvData, dto.StockOHLCV{
+				Timestamp: lastTimestamp / 1000,
+				Open:      row[1],
+				High:      row[2],
+				Low:       row[3],
+				Close:     row[4],
+				Volume:    row[5],
+			})
+		}
+
+		if len(rows) < restMarketDataMaxOHLCVLimit || lastTimestamp == since {
+			break
+		}
+		since = lastTimestamp + 1
+	}
+
+	if len(ohlcvData) == 0 {
+		return nil, fmt.Errorf("no valid OHLCV data found for symbol: %s", symbol)
+	}
+
+	marketPrice, err := r.LastPrice(ctx, param.Exchange, symbol)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to get last price, fallback to last close",
+			logger.ErrorField(err),
+			logger.StringField("symbol", symbol),
+		)
+		marketPrice = ohlcvData[len(ohlcvData)-1].Close
+	}
+
+	return &dto.StockData{
+		MarketPrice: marketPrice,
+		OHLCV:       ohlcvData,
+		Range:       param.Range,
+		Interval:    param.Interval,
+	}, nil
+}
+
+func (r *restMarketDataRepository) get(ctx context.Context, endpoint string, queryParams map[string]string, result interface{}) error {
+	if err := r.requestLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	resp, err := r.httpClient.Get(ctx, endpoint, queryParams, nil, result)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		r.logger.Error("Market data REST API returned Non-OK status",
+			logger.StringField("provider", r.Name()),
+			logger.StringField("endpoint", endpoint),
+			logger.IntField("status_code", resp.StatusCode),
+			logger.StringField("body", string(resp.Body)))
+		return fmt.Errorf("%s api returned status: %d", r.Name(), resp.StatusCode)
+	}
+	return nil
+}
+
+// restMarketDataTimeframe interval gaya yahoo (60m, 1wk, 1mo) ke timeframe unified CCXT
+func restMarketDataTimeframe(interval string) string {
+	switch interval {
+	case "60m":
+		return "1h"
+	case "1wk":
+		return "1w"
+	case "1mo":
+		return "1M"
+	}
+	return interval
+}
@@ -3,25 +3,34 @@ package repository
 import (
 	"golang-trading/config"
 	"golang-trading/pkg/cache"
+	"golang-trading/pkg/common"
 	"golang-trading/pkg/logger"
 
 	"gorm.io/gorm"
 )
 
 type Repository struct {
-	JobRepo                     JobRepository
-	StockPositionsRepo          StockPositionsRepository
-	TradingViewScreenersRepo    TradingViewScreenersRepository
-	YahooFinanceRepo            YahooFinanceRepository
-	StockAnalysisRepo           StockAnalysisRepository
-	SystemParamRepo             SystemParamRepository
-	GeminiAIRepo                AIRepository
-	UnitOfWork                  UnitOfWork
-	UserRepo                    UserRepository
-	StockPositionMonitoringRepo StockPositionMonitoringRepository
-	BinanceRepo                 BinanceRepository
-	CandleRepo                  CandleRepository
-	UserSignalAlertRepo         UserSignalAlertRepository
+	JobRepo                      JobRepository
+	StockPositionsRepo           StockPositionsRepository
+	TradingViewScreenersRepo     TradingViewScreenersRepository
+	YahooFinanceRepo             YahooFinanceRepository
+	StockAnalysisRepo            StockAnalysisRepository
+	SystemParamRepo              SystemParamRepository
+	GeminiAIRepo                 AIRepository
+	UnitOfWork                   UnitOfWork
+	UserRepo                     UserRepository
+	StockPositionMonitoringRepo  StockPositionMonitoringRepository
+	BinanceRepo                  BinanceRepository
+	BinanceFuturesRepo           BinanceFuturesRepository
+	PriceStreams                 []PriceStream
+	MarketDataRegistry           MarketDataRegistry
+	CandleRepo                   CandleRepository
+	TradingCalendarRepo          TradingCalendarRepository
+	TimeframeProfileRepo         TimeframeProfileRepository
+	CorporateActionRepo          CorporateActionRepository
+	StockCandleRepo              StockCandleRepository
+	UserSignalAlertRepo          UserSignalAlertRepository
+	StockPositionTransactionRepo StockPositionTransactionRepository
 }
 
 func NewRepository(cfg *config.Config, inmemoryCache cache.Cache, db *gorm.DB, log *logger.Logger) (*Repository, error) {
@@ -33,22 +42,75 @@ func NewRepository(cfg *config.Config, inmemoryCache cache.Cache, db *gorm.DB, l
 	userRepo := NewUserRepository(db)
 	stockPositionMonitoringRepo := NewStockPositionMonitoringRepository(db)
 	binanceRepo := NewBinanceRepository(cfg, log)
+	binanceFuturesRepo := NewBinanceFuturesRepository(cfg, log)
 	yahooFinanceRepo := NewYahooFinanceRepository(cfg, log)
-	candleRepo := NewCandleRepository(binanceRepo, yahooFinanceRepo)
+	stockCandleRepo := NewStockCandleRepository(db)
+	marketDataRegistry, err := newMarketDataRegistry(cfg, log, yahooFinanceRepo, binanceRepo, binanceFuturesRepo, NewCandleCacheProvider(stockCandleRepo))
+	if err != nil {
+		return nil, err
+	}
+	systemParamRepo := NewSystemParamRepository(cfg, inmemoryCache, db)
+	tradingCalendarRepo := NewTradingCalendarRepository(log, systemParamRepo)
+	corporateActionRepo := NewCorporateActionRepository(db)
+	candleRepo := NewCandleRepository(log, stockCandleRepo, marketDataRegistry, tradingCalendarRepo, corporateActionRepo)
 	userSignalAlertRepo := NewUserSignalAlertRepository(db)
 	return &Repository{
-		JobRepo:                     NewJobRepository(db),
-		StockPositionsRepo:          NewStockPositionsRepository(db),
-		TradingViewScreenersRepo:    NewTradingViewScreenersRepository(cfg, log),
-		YahooFinanceRepo:            yahooFinanceRepo,
-		StockAnalysisRepo:           NewStockAnalysisRepository(db),
-		SystemParamRepo:             NewSystemParamRepository(cfg, inmemoryCache, db),
-		GeminiAIRepo:                geminiAIRepo,
-		UnitOfWork:                  uow,
-		UserRepo:                    userRepo,
-		StockPositionMonitoringRepo: stockPositionMonitoringRepo,
-		BinanceRepo:                 binanceRepo,
-		CandleRepo:                  candleRepo,
-		UserSignalAlertRepo:         userSignalAlertRepo,
+		JobRepo:                      NewJobRepository(db),
+		StockPositionsRepo:           NewStockPositionsRepository(db),
+		TradingViewScreenersRepo:     NewTradingViewScreenersRepository(cfg, log),
+		YahooFinanceRepo:             yahooFinanceRepo,
+		StockAnalysisRepo:            NewStockAnalysisRepository(db),
+		SystemParamRepo:              systemParamRepo,
+		GeminiAIRepo:                 geminiAIRepo,
+		UnitOfWork:                   uow,
+		UserRepo:                     userRepo,
+		StockPositionMonitoringRepo:  stockPositionMonitoringRepo,
+		BinanceRepo:                  binanceRepo,
+		BinanceFuturesRepo:           binanceFuturesRepo,
+		PriceStreams:                 newPriceStreams(cfg, log),
+		MarketDataRegistry:           marketDataRegistry,
+		CandleRepo:                   candleRepo,
+		TradingCalendarRepo:          tradingCalendarRepo,
+		TimeframeProfileRepo:         NewTimeframeProfileRepository(log, systemParamRepo, marketDataRegistry),
+		CorporateActionRepo:          corporateActionRepo,
+		StockCandleRepo:              stockCandleRepo,
+		UserSignalAlertRepo:          userSignalAlertRepo,
+		StockPositionTransactionRepo: NewStockPositionTransactionRepository(db),
 	}, nil
 }
+
+// newPriceStreams price stream websocket per exchange yang url-nya dikonfigurasi
+func newPriceStreams(cfg *config.Config, log *logger.Logger) []PriceStream {
+	var streams []PriceStream
+	if cfg.Binance.WSURL != "" {
+		streams = append(streams, NewBinanceStream(log, common.EXCHANGE_BINANCE, cfg.Binance.WSURL, cfg.PriceStream.ReconnectDelay))
+	}
+	if cfg.Binance.FuturesWSURL != "" {
+		streams = append(streams, NewBinanceStream(log, common.EXCHANGE_BINANCE_FUTURES, cfg.Binance.FuturesWSURL, cfg.PriceStream.ReconnectDelay))
+	}
+	return streams
+}
+
+// newMarketDataRegistry mendaftarkan provider bawaan, adapter REST dari MARKET_DATA_REST_EXCHANGES,
+// lalu urutan fallback per exchange dari MARKET_DATA_FALLBACK_<EXCHANGE>
+func newMarketDataRegistry(cfg *config.Config, log *logger.Logger, providers ...MarketDataProvider) (MarketDataRegistry, error) {
+	registry := NewMarketDataRegistry(cfg.MarketData, log)
+	for _, provider := range providers {
+		if err := registry.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, restCfg := range cfg.MarketData.REST {
+		if err := registry.Register(NewRESTMarketDataRepository(restCfg, log)); err != nil {
+			return nil, err
+		}
+	}
+
+	for exchange, providerNames := range cfg.MarketData.Fallbacks {
+		if err := registry.SetFallbacks(exchange, providerNames); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
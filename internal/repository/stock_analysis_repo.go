@@ -55,6 +55,10 @@ func (s *stockAnalysisRepository) GetLatestAnalyses(ctx context.Context, param m
 		whereClauses = append(whereClauses, "timestamp >= ?")
 		args = append(args, param.TimestampAfter)
 	}
+	if len(param.Timeframes) > 0 {
+		whereClauses = append(whereClauses, "timeframe IN ?")
+		args = append(args, param.Timeframes)
+	}
 
 	if len(whereClauses) > 0 {
 		queryBuilder.WriteString(" WHERE " + strings.Join(whereClauses, " AND "))
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockCandleRepository interface {
+	Get(ctx context.Context, param model.GetStockCandlesParam, opts ...utils.DBOption) ([]model.StockCandle, error)
+	Upsert(ctx context.Context, candles []model.StockCandle, opts ...utils.DBOption) error
+	// DeleteBefore menghapus candle semua interval sebelum openTime, dipakai saat skala harga berubah (split)
+	DeleteBefore(ctx context.Context, exchange, stockCode string, openTime time.Time, opts ...utils.DBOption) (int64, error)
+}
+
+type stockCandleRepository struct {
+	db *gorm.DB
+}
+
+func NewStockCandleRepository(db *gorm.DB) StockCandleRepository {
+	return &stockCandleRepository{
+		db: db,
+	}
+}
+
+// Get mengembalikan candle terurut dari open_time paling lama
+func (r *stockCandleRepository) Get(ctx context.Context, param model.GetStockCandlesParam, opts ...utils.DBOption) ([]model.StockCandle, error) {
+	var candles []model.StockCandle
+
+	qFilter := []string{
+		"exchange = ?",
+		"stock_code = ?",
+		"interval = ?",
+	}
+	qFilterParam := []interface{}{
+		param.Exchange,
+		param.StockCode,
+		param.Interval,
+	}
+
+	if !param.OpenTimeAfter.IsZero() {
+		qFilter = append(qFilter, "open_time >= ?")
+		qFilterParam = append(qFilterParam, param.OpenTimeAfter.UTC())
+	}
+
+	if !param.OpenTimeUntil.IsZero() {
+		qFilter = append(qFilter, "open_time <= ?")
+		qFilterParam = append(qFilterParam, param.OpenTimeUntil.UTC())
+	}
+
+	query := utils.ApplyOptions(r.db.WithContext(ctx), opts...).
+		Where(strings.Join(qFilter, " AND "), qFilterParam...).
+		Order("open_time ASC")
+
+	if param.Limit > 0 {
+		query = query.Limit(param.Limit)
+	}
+
+	if err := query.Find(&candles).Error; err != nil {
+		return nil, err
+	}
+
+	return candles, nil
+}
+
+// Upsert insert candle baru, candle yang sudah ada (bar terakhir yang belum close) akan di-update
+func (r *stockCandleRepository) Upsert(ctx context.Context, candles []model.StockCandle, opts ...utils.DBOption) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "exchange"},
+				{Name: "stock_code"},
+				{Name: "interval"},
+				{Name: "open_time"},
+			},
+			DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "volume", "updated_at"}),
+		}).
+		CreateInBatches(&candles, 500).Error
+}
+
+func (r *stockCandleRepository) DeleteBefore(ctx context.Context, exchange, stockCode string, openTime time.Time, opts ...utils.DBOption) (int64, error) {
+	result := utils.ApplyOptions(r.db.WithContext(ctx), opts...).
+		Where("exchange = ? AND stock_code = ? AND open_time < ?", exchange, stockCode, openTime.UTC()).
+		Delete(&model.StockCandle{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+type StockPositionTransactionRepository interface {
+	Create(ctx context.Context, transaction *model.StockPositionTransaction, opts ...utils.DBOption) error
+	GetByStockPositionID(ctx context.Context, stockPositionID uint, opts ...utils.DBOption) ([]model.StockPositionTransaction, error)
+	Update(ctx context.Context, transaction *model.StockPositionTransaction, opts ...utils.DBOption) error
+}
+
+type stockPositionTransactionRepository struct {
+	db *gorm.DB
+}
+
+func NewStockPositionTransactionRepository(db *gorm.DB) StockPositionTransactionRepository {
+	return &stockPositionTransactionRepository{
+		db: db,
+	}
+}
+
+func (r *stockPositionTransactionRepository) Create(ctx context.Context, transaction *model.StockPositionTransaction, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Create(transaction).Error
+}
+
+func (r *stockPositionTransactionRepository) GetByStockPositionID(ctx context.Context, stockPositionID uint, opts ...utils.DBOption) ([]model.StockPositionTransaction, error) {
+	var transactions []model.StockPositionTransaction
+	err := utils.ApplyOptions(r.db.WithContext(ctx), opts...).
+		Where("stock_position_id = ?", stockPositionID).
+		Order("transaction_date ASC, id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *stockPositionTransactionRepository) Update(ctx context.Context, transaction *model.StockPositionTransaction, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Save(transaction).Error
+}
@@ -7,6 +7,7 @@ import (
 	"golang-trading/internal/model"
 	"golang-trading/pkg/utils"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,6 +15,8 @@ import (
 type StockPositionsRepository interface {
 	Get(ctx context.Context, param dto.GetStockPositionsParam, opts ...utils.DBOption) ([]model.StockPosition, error)
 	Update(ctx context.Context, stockPosition model.StockPosition, opts ...utils.DBOption) error
+	// UpdateLastPriceAlertAt hanya menulis last_price_alert_at, aman dipakai dengan salinan posisi yang mungkin sudah usang
+	UpdateLastPriceAlertAt(ctx context.Context, id uint, at time.Time, opts ...utils.DBOption) error
 	Create(ctx context.Context, stockPosition *model.StockPosition, opts ...utils.DBOption) error
 	Delete(ctx context.Context, stockPosition *model.StockPosition, opts ...utils.DBOption) error
 }
@@ -106,6 +109,10 @@ func (r *stockPositionsRepository) Get(ctx context.Context, param dto.GetStockPo
 		}
 	}
 
+	db = db.Preload("Transactions", func(pDb *gorm.DB) *gorm.DB {
+		return pDb.Order("transaction_date ASC, id ASC")
+	})
+
 	if err := db.Preload("User").Debug().Where(strings.Join(qFilter, " AND "), qFilterParam...).Find(&stockPositions).Error; err != nil {
 		return nil, err
 	}
@@ -114,7 +121,13 @@ func (r *stockPositionsRepository) Get(ctx context.Context, param dto.GetStockPo
 }
 
 func (r *stockPositionsRepository) Update(ctx context.Context, stockPosition model.StockPosition, opts ...utils.DBOption) error {
-	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Updates(&stockPosition).Error
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Omit("Transactions").Updates(&stockPosition).Error
+}
+
+func (r *stockPositionsRepository) UpdateLastPriceAlertAt(ctx context.Context, id uint, at time.Time, opts ...utils.DBOption) error {
+	return utils.ApplyOptions(r.db.WithContext(ctx), opts...).Model(&model.StockPosition{}).
+		Where("id = ?", id).
+		UpdateColumn("last_price_alert_at", at).Error
 }
 
 func (r *stockPositionsRepository) Create(ctx context.Context, stockPosition *model.StockPosition, opts ...utils.DBOption) error {
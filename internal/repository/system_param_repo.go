@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"golang-trading/config"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
@@ -14,6 +16,9 @@ import (
 type SystemParamRepository interface {
 	Get(ctx context.Context, name string, destValue interface{}) error
 	GetDefaultAnalysisTimeframes(ctx context.Context) ([]dto.DataTimeframe, error)
+	GetTradingPlanConfig(ctx context.Context) (*dto.TradingPlanConfig, error)
+	GetExchangeCalendars(ctx context.Context) (map[string]dto.ExchangeCalendar, error)
+	GetTimeframeProfileConfig(ctx context.Context) (*dto.TimeframeProfileConfig, error)
 }
 
 type systemParamRepository struct {
@@ -46,3 +51,58 @@ func (s *systemParamRepository) GetDefaultAnalysisTimeframes(ctx context.Context
 	s.inmemoryCache.Set(model.SysParamDefaultAnalysisTimeframes, destValue, s.cfg.Cache.SysParamExpDuration)
 	return destValue, nil
 }
+
+// GetTradingPlanConfig mengambil & memvalidasi TRADING_PLAN_CONFIG. Hasil di-cache selama SysParamExpDuration,
+// sehingga perubahan di database berlaku setelah cache expired tanpa restart. Jika belum ada, pakai default.
+func (s *systemParamRepository) GetTradingPlanConfig(ctx context.Context) (*dto.TradingPlanConfig, error) {
+	if val, found := cache.GetFromCache[*dto.TradingPlanConfig](model.SysParamTradingPlanConfig); found {
+		return val, nil
+	}
+
+	destValue := dto.DefaultTradingPlanConfig()
+	if err := s.Get(ctx, model.SysParamTradingPlanConfig, &destValue); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := destValue.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", model.SysParamTradingPlanConfig, err)
+	}
+
+	s.inmemoryCache.Set(model.SysParamTradingPlanConfig, &destValue, s.cfg.Cache.SysParamExpDuration)
+	return &destValue, nil
+}
+
+// GetExchangeCalendars mengambil EXCHANGE_CALENDARS (exchange -> hari libur & sesi), kosong jika belum ada
+func (s *systemParamRepository) GetExchangeCalendars(ctx context.Context) (map[string]dto.ExchangeCalendar, error) {
+	if val, found := cache.GetFromCache[map[string]dto.ExchangeCalendar](model.SysParamExchangeCalendars); found {
+		return val, nil
+	}
+
+	destValue := map[string]dto.ExchangeCalendar{}
+	if err := s.Get(ctx, model.SysParamExchangeCalendars, &destValue); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	s.inmemoryCache.Set(model.SysParamExchangeCalendars, destValue, s.cfg.Cache.SysParamExpDuration)
+	return destValue, nil
+}
+
+// GetTimeframeProfileConfig mengambil & memvalidasi ANALYSIS_TIMEFRAME_PROFILES, profil bawaan tetap tersedia
+// jika belum ada atau tidak ditimpa
+func (s *systemParamRepository) GetTimeframeProfileConfig(ctx context.Context) (*dto.TimeframeProfileConfig, error) {
+	if val, found := cache.GetFromCache[*dto.TimeframeProfileConfig](model.SysParamAnalysisTimeframeProfiles); found {
+		return val, nil
+	}
+
+	destValue := dto.DefaultTimeframeProfileConfig()
+	if err := s.Get(ctx, model.SysParamAnalysisTimeframeProfiles, &destValue); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := destValue.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", model.SysParamAnalysisTimeframeProfiles, err)
+	}
+
+	s.inmemoryCache.Set(model.SysParamAnalysisTimeframeProfiles, &destValue, s.cfg.Cache.SysParamExpDuration)
+	return &destValue, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+)
+
+// DefaultTimeframeProfileName nama profil untuk DEFAULT_ANALYSIS_TIMEFRAMES
+const DefaultTimeframeProfileName = "default"
+
+type timeframeProfileCtxKey struct{}
+
+// WithTimeframeProfile preferensi profil timeframe user untuk context ini, kosong = ikut symbol / exchange
+func WithTimeframeProfile(ctx context.Context, profile string) context.Context {
+	return context.WithValue(ctx, timeframeProfileCtxKey{}, profile)
+}
+
+func timeframeProfileFromContext(ctx context.Context) string {
+	profile, _ := ctx.Value(timeframeProfileCtxKey{}).(string)
+	return profile
+}
+
+// TimeframeProfileRepository memilih timeframe analisa per saham dari ANALYSIS_TIMEFRAME_PROFILES:
+// preferensi user (context), lalu symbol, lalu exchange, lalu default
+type TimeframeProfileRepository interface {
+	// Resolve profil pertama yang didukung market data exchange, DEFAULT_ANALYSIS_TIMEFRAMES jika tidak ada
+	Resolve(ctx context.Context, exchange, stockCode string) (dto.TimeframeProfile, error)
+	// Match profil yang mencakup interval analisa yang sudah tersimpan: hasil Resolve jika cocok,
+	// lalu profil lain / DEFAULT_ANALYSIS_TIMEFRAMES (analisa dibuat sebelum profil diubah), terakhir hasil Resolve
+	Match(ctx context.Context, exchange, stockCode string, intervals []string) (dto.TimeframeProfile, error)
+	// Profiles profil yang bisa dipilih user, urut nama
+	Profiles(ctx context.Context) ([]dto.TimeframeProfile, error)
+	// Validate memastikan profil ada dan setiap interval & range didukung market data exchange
+	Validate(ctx context.Context, name, exchange string) error
+}
+
+type timeframeProfileRepository struct {
+	logger             *logger.Logger
+	systemParamRepo    SystemParamRepository
+	marketDataRegistry MarketDataRegistry
+}
+
+func NewTimeframeProfileRepository(log *logger.Logger, systemParamRepo SystemParamRepository, marketDataRegistry MarketDataRegistry) TimeframeProfileRepository {
+	return &timeframeProfileRepository{logger: log, systemParamRepo: systemParamRepo, marketDataRegistry: marketDataRegistry}
+}
+
+func (r *timeframeProfileRepository) Resolve(ctx context.Context, exchange, stockCode string) (dto.TimeframeProfile, error) {
+	exchange = strings.ToUpper(exchange)
+
+	cfg, err := r.systemParamRepo.GetTimeframeProfileConfig(ctx)
+	if err != nil {
+		// konfigurasi profil rusak tidak boleh menghentikan analisa
+		r.logger.ErrorContext(ctx, "Failed to get timeframe profiles, using default analysis timeframes", logger.ErrorField(err))
+		return r.defaultProfile(ctx)
+	}
+
+	for _, name := range cfg.Candidates(timeframeProfileFromContext(ctx), exchange, stockCode) {
+		profile, ok := cfg.Profile(name)
+		if !ok {
+			r.logger.WarnContext(ctx, "Timeframe profile not found, trying next profile",
+				logger.StringField("profile", name),
+				logger.StringField("exchange", exchange),
+			)
+			continue
+		}
+		if err := r.validateExchange(profile, exchange); err != nil {
+			r.logger.WarnContext(ctx, "Timeframe profile not supported by exchange, trying next profile",
+				logger.ErrorField(err),
+				logger.StringField("profile", name),
+				logger.StringField("exchange", exchange),
+			)
+			continue
+		}
+		return profile, nil
+	}
+
+	return r.defaultProfile(ctx)
+}
+
+func (r *timeframeProfileRepository) Match(ctx context.Context, exchange, stockCode string, intervals []string) (dto.TimeframeProfile, error) {
+	resolved, err := r.Resolve(ctx, exchange, stockCode)
+	if err != nil || resolved.Covers(intervals) {
+		return resolved, err
+	}
+
+	if cfg, err := r.systemParamRepo.GetTimeframeProfileConfig(ctx); err == nil {
+		for _, name := range cfg.ProfileNames() {
+			if profile, _ := cfg.Profile(name); profile.Covers(intervals) {
+				return profile, nil
+			}
+		}
+	}
+	if profile, err := r.defaultProfile(ctx); err == nil && profile.Covers(intervals) {
+		return profile, nil
+	}
+
+	r.logger.WarnContext(ctx, "No timeframe profile covers analysis timeframes",
+		logger.StringField("exchange", exchange),
+		logger.StringField("stock_code", stockCode),
+		logger.StringField("profile", resolved.Name),
+		logger.StringField("intervals", strings.Join(intervals, ",")),
+	)
+	return resolved, nil
+}
+
+func (r *timeframeProfileRepository) Profiles(ctx context.Context) ([]dto.TimeframeProfile, error) {
+	cfg, err := r.systemParamRepo.GetTimeframeProfileConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]dto.TimeframeProfile, 0, len(cfg.Profiles))
+	for _, name := range cfg.ProfileNames() {
+		profile, _ := cfg.Profile(name)
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (r *timeframeProfileRepository) Validate(ctx context.Context, name, exchange string) error {
+	cfg, err := r.systemParamRepo.GetTimeframeProfileConfig(ctx)
+	if err != nil {
+		return err
+	}
+	profile, ok := cfg.Profile(name)
+	if !ok {
+		return fmt.Errorf("timeframe profile %q not found", name)
+	}
+	return r.validateExchange(profile, strings.ToUpper(exchange))
+}
+
+func (r *timeframeProfileRepository) validateExchange(profile dto.TimeframeProfile, exchange string) error {
+	for _, tf := range profile.Timeframes {
+		if err := r.marketDataRegistry.Validate(exchange, tf.Interval, tf.Range); err != nil {
+			return fmt.Errorf("profile %s: %w", profile.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *timeframeProfileRepository) defaultProfile(ctx context.Context) (dto.TimeframeProfile, error) {
+	timeframes, err := r.systemParamRepo.GetDefaultAnalysisTimeframes(ctx)
+	if err != nil {
+		return dto.TimeframeProfile{}, err
+	}
+	return dto.TimeframeProfile{Name: DefaultTimeframeProfileName, Timeframes: timeframes}, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeSystemParamRepository struct {
+	SystemParamRepository
+	profiles dto.TimeframeProfileConfig
+}
+
+func (r *fakeSystemParamRepository) GetDefaultAnalysisTimeframes(ctx context.Context) ([]dto.DataTimeframe, error) {
+	return []dto.DataTimeframe{{Interval: "1d", Range: "3m", Weight: 1}}, nil
+}
+
+func (r *fakeSystemParamRepository) GetTimeframeProfileConfig(ctx context.Context) (*dto.TimeframeProfileConfig, error) {
+	return &r.profiles, nil
+}
+
+func TestTimeframeProfileRepositoryResolve(t *testing.T) {
+	registry := newTestMarketDataRegistry(config.MarketData{})
+	// provider hanya mendukung interval 1h & 1d serta range 1m & 1y
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "stocks", exchanges: []string{"IDX"}}))
+
+	profiles := dto.DefaultTimeframeProfileConfig()
+	profiles.Profiles["daily"] = []dto.DataTimeframe{{Interval: "1d", Range: "1y", Weight: 2, IsMain: true}, {Interval: "1h", Range: "1m", Weight: 1}}
+	profiles.Exchanges = map[string]string{"IDX": "daily"}
+	repo := NewTimeframeProfileRepository(&logger.Logger{Logger: zap.NewNop()}, &fakeSystemParamRepository{profiles: profiles}, registry)
+	ctx := context.Background()
+
+	profile, err := repo.Resolve(ctx, "idx", "BBCA")
+	assert.NoError(t, err)
+	assert.Equal(t, "daily", profile.Name)
+
+	// preferensi user yang interval-nya tidak didukung exchange jatuh ke profil exchange
+	profile, err = repo.Resolve(WithTimeframeProfile(ctx, dto.TimeframeProfileScalp), "IDX", "BBCA")
+	assert.NoError(t, err)
+	assert.Equal(t, "daily", profile.Name)
+	assert.Error(t, repo.Validate(ctx, dto.TimeframeProfileScalp, "IDX"))
+	assert.NoError(t, repo.Validate(ctx, "daily", "IDX"))
+	assert.Error(t, repo.Validate(ctx, "unknown", "IDX"))
+
+	// exchange tanpa profil memakai DEFAULT_ANALYSIS_TIMEFRAMES
+	profile, err = repo.Resolve(ctx, "NASDAQ", "AAPL")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTimeframeProfileName, profile.Name)
+	assert.Equal(t, []string{"1d"}, profile.Intervals())
+}
+
+func TestTimeframeProfileRepositoryMatch(t *testing.T) {
+	registry := newTestMarketDataRegistry(config.MarketData{})
+	assert.NoError(t, registry.Register(&fakeMarketDataProvider{name: "stocks", exchanges: []string{"IDX"}}))
+
+	profiles := dto.DefaultTimeframeProfileConfig()
+	profiles.Profiles["daily"] = []dto.DataTimeframe{{Interval: "1d", Range: "1y", Weight: 2, IsMain: true}, {Interval: "1h", Range: "1m", Weight: 1}}
+	profiles.Exchanges = map[string]string{"IDX": "daily"}
+	repo := NewTimeframeProfileRepository(&logger.Logger{Logger: zap.NewNop()}, &fakeSystemParamRepository{profiles: profiles}, registry)
+	ctx := context.Background()
+
+	profile, err := repo.Match(ctx, "IDX", "BBCA", []string{"1h", "1d"})
+	assert.NoError(t, err)
+	assert.Equal(t, "daily", profile.Name)
+
+	// analisa lama dengan profil lain tetap mendapat weight profil tersebut
+	profile, err = repo.Match(ctx, "IDX", "BBCA", []string{"1w", "4h", "1d"})
+	assert.NoError(t, err)
+	assert.Equal(t, dto.TimeframeProfileSwing, profile.Name)
+
+	profile, err = repo.Match(ctx, "IDX", "BBCA", []string{"3d"})
+	assert.NoError(t, err)
+	assert.Equal(t, "daily", profile.Name)
+}
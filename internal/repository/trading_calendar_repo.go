@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"golang-trading/internal/tradingcalendar"
+	"golang-trading/pkg/logger"
+)
+
+// TradingCalendarRepository kalender bursa per exchange: default jam bursa ditimpa system parameter EXCHANGE_CALENDARS
+type TradingCalendarRepository interface {
+	Get(ctx context.Context, exchange string) tradingcalendar.Calendar
+}
+
+type tradingCalendarRepository struct {
+	logger          *logger.Logger
+	systemParamRepo SystemParamRepository
+}
+
+func NewTradingCalendarRepository(log *logger.Logger, systemParamRepo SystemParamRepository) TradingCalendarRepository {
+	return &tradingCalendarRepository{logger: log, systemParamRepo: systemParamRepo}
+}
+
+// Get selalu mengembalikan kalender, konfigurasi yang gagal dibaca / tidak valid jatuh ke kalender bawaan exchange
+func (r *tradingCalendarRepository) Get(ctx context.Context, exchange string) tradingcalendar.Calendar {
+	exchange = strings.ToUpper(exchange)
+
+	calendars, err := r.systemParamRepo.GetExchangeCalendars(ctx)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to get exchange calendars, using default calendar",
+			logger.ErrorField(err),
+			logger.StringField("exchange", exchange),
+		)
+		return tradingcalendar.DefaultCalendar(exchange)
+	}
+
+	override, ok := calendars[exchange]
+	if !ok {
+		return tradingcalendar.DefaultCalendar(exchange)
+	}
+
+	calendar, err := tradingcalendar.New(exchange, tradingcalendar.Merge(tradingcalendar.Default(exchange), override))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Invalid exchange calendar, using default calendar",
+			logger.ErrorField(err),
+			logger.StringField("exchange", exchange),
+		)
+		return tradingcalendar.DefaultCalendar(exchange)
+	}
+	return calendar
+}
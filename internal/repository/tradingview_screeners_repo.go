@@ -10,6 +10,7 @@ import (
 
 	"golang-trading/config"
 	"golang-trading/internal/dto"
+	"golang-trading/internal/indicator"
 	"golang-trading/pkg/httpclient"
 	"golang-trading/pkg/logger"
 
@@ -228,32 +229,32 @@ func (t *tradingViewScreenersRepository) Get(ctx context.Context, symbol string,
 
 	// Recommendations
 	// Summary recommendation
-	ta.Recommend.Global.Summary = tvComputeRecommend(responseMap[key("Recommend.All%s", dataInterval)])
+	ta.Recommend.Global.Summary = indicator.ComputeRecommend(responseMap[key("Recommend.All%s", dataInterval)])
 	ta.Value.Global.Summary = responseMap[key("Recommend.All%s", dataInterval)]
 
 	// Oscillators recommendation
-	ta.Recommend.Global.Oscillators = tvComputeRecommend(responseMap[key("Recommend.Other%s", dataInterval)])
+	ta.Recommend.Global.Oscillators = indicator.ComputeRecommend(responseMap[key("Recommend.Other%s", dataInterval)])
 	ta.Value.Global.Oscillators = responseMap[key("Recommend.Other%s", dataInterval)]
 
 	// Moving Averages recommendation
-	ta.Recommend.Global.MA = tvComputeRecommend(responseMap[key("Recommend.MA%s", dataInterval)])
+	ta.Recommend.Global.MA = indicator.ComputeRecommend(responseMap[key("Recommend.MA%s", dataInterval)])
 	ta.Value.Global.MA = responseMap[key("Recommend.MA%s", dataInterval)]
 
 	// Oscillators
 	// Relative Strength Index (14)
-	ta.Recommend.Oscillators.RSI = tvRsi(responseMap[key("RSI%s", dataInterval)], responseMap[key("RSI[1]%s", dataInterval)])
+	ta.Recommend.Oscillators.RSI = indicator.RecommendRSI(responseMap[key("RSI%s", dataInterval)], responseMap[key("RSI[1]%s", dataInterval)])
 	ta.Value.Oscillators.RSI = responseMap[key("RSI%s", dataInterval)]
 
 	// Stochastic %K (14, 3, 3)
-	ta.Recommend.Oscillators.StochK = tvStoch(responseMap[key("Stoch.K%s", dataInterval)], responseMap[key("Stoch.D%s", dataInterval)], responseMap[key("Stoch.K[1]%s", dataInterval)], responseMap[key("Stoch.D[1]%s", dataInterval)])
+	ta.Recommend.Oscillators.StochK = indicator.RecommendStoch(responseMap[key("Stoch.K%s", dataInterval)], responseMap[key("Stoch.D%s", dataInterval)], responseMap[key("Stoch.K[1]%s", dataInterval)], responseMap[key("Stoch.D[1]%s", dataInterval)])
 	ta.Value.Oscillators.StochK = responseMap[key("Stoch.K%s", dataInterval)]
 
 	// Commodity Channel Index (20)
-	ta.Recommend.Oscillators.CCI = tvCci20(responseMap[key("CCI20%s", dataInterval)], responseMap[key("CCI20[1]%s", dataInterval)])
+	ta.Recommend.Oscillators.CCI = indicator.RecommendCCI20(responseMap[key("CCI20%s", dataInterval)], responseMap[key("CCI20[1]%s", dataInterval)])
 	ta.Value.Oscillators.CCI = responseMap[key("CCI20%s", dataInterval)]
 
 	// Average Directional Index (14)
-	ta.Recommend.Oscillators.ADX = tvAdx(responseMap[key("ADX%s", dataInterval)], responseMap[key("ADX+DI%s", dataInterval)], responseMap[key("ADX+DI%s", dataInterval)], responseMap[key("ADX+DI[1]%s", dataInterval)], responseMap[key("ADX-DI[1]%s", dataInterval)])
+	ta.Recommend.Oscillators.ADX = indicator.RecommendADX(responseMap[key("ADX%s", dataInterval)], responseMap[key("ADX+DI%s", dataInterval)], responseMap[key("ADX+DI%s", dataInterval)], responseMap[key("ADX+DI[1]%s", dataInterval)], responseMap[key("ADX-DI[1]%s", dataInterval)])
 	ta.Value.Oscillators.ADX.Value = responseMap[key("ADX%s", dataInterval)]          // ADX Value
 	ta.Value.Oscillators.ADX.PlusDI = responseMap[key("ADX+DI%s", dataInterval)]      // ADX +DI
 	ta.Value.Oscillators.ADX.MinusDI = responseMap[key("ADX-DI%s", dataInterval)]     // ADX -DI
@@ -261,85 +262,85 @@ func (t *tradingViewScreenersRepository) Get(ctx context.Context, symbol string,
 	ta.Value.Oscillators.ADX.MinusDI1 = responseMap[key("ADX-DI[1]%s", dataInterval)] // ADX -DI[1]
 
 	// Awesome Oscillator
-	ta.Recommend.Oscillators.AO = tvAo(responseMap[key("AO%s", dataInterval)], responseMap[key("AO[1]%s", dataInterval)], responseMap[key("AO[2]%s", dataInterval)])
+	ta.Recommend.Oscillators.AO = indicator.RecommendAO(responseMap[key("AO%s", dataInterval)], responseMap[key("AO[1]%s", dataInterval)], responseMap[key("AO[2]%s", dataInterval)])
 	ta.Value.Oscillators.AO.Value = responseMap[key("AO%s", dataInterval)]    // AO current value
 	ta.Value.Oscillators.AO.Prev1 = responseMap[key("AO[1]%s", dataInterval)] // AO previous 1 value
 	ta.Value.Oscillators.AO.Prev2 = responseMap[key("AO[2]%s", dataInterval)] // AO previous 2 value
 
 	// Momentum (10)
-	ta.Recommend.Oscillators.Mom = tvMom(responseMap[key("Mom%s", dataInterval)], responseMap[key("Mom[1]%s", dataInterval)])
+	ta.Recommend.Oscillators.Mom = indicator.RecommendMom(responseMap[key("Mom%s", dataInterval)], responseMap[key("Mom[1]%s", dataInterval)])
 	ta.Value.Oscillators.Mom = responseMap[key("Mom%s", dataInterval)]
 
 	// MACD Level (12, 26)
-	ta.Recommend.Oscillators.MACD = tvMacd(responseMap[key("MACD.macd%s", dataInterval)], responseMap[key("MACD.signal%s", dataInterval)])
+	ta.Recommend.Oscillators.MACD = indicator.RecommendMACD(responseMap[key("MACD.macd%s", dataInterval)], responseMap[key("MACD.signal%s", dataInterval)])
 	ta.Value.Oscillators.MACD.Macd = responseMap[key("MACD.macd%s", dataInterval)]     // MACD line
 	ta.Value.Oscillators.MACD.Signal = responseMap[key("MACD.signal%s", dataInterval)] // Signal line
 
 	// Stochastic RSI Fast (3, 3, 14, 14)
-	ta.Recommend.Oscillators.StochRSI = tvSimple(responseMap[key("Rec.Stoch.RSI%s", dataInterval)])
+	ta.Recommend.Oscillators.StochRSI = indicator.RecommendSimple(responseMap[key("Rec.Stoch.RSI%s", dataInterval)])
 	ta.Value.Oscillators.StochRSI = responseMap[key("Stoch.RSI.K%s", dataInterval)]
 
 	// Williams Percent Range (14)
-	ta.Recommend.Oscillators.WR = tvSimple(responseMap[key("Rec.WR%s", dataInterval)])
+	ta.Recommend.Oscillators.WR = indicator.RecommendSimple(responseMap[key("Rec.WR%s", dataInterval)])
 	ta.Value.Oscillators.WR = responseMap[key("W.R%s", dataInterval)]
 
 	// Bull Bear Power
-	ta.Recommend.Oscillators.BBP = tvSimple(responseMap[key("Rec.BBPower%s", dataInterval)])
+	ta.Recommend.Oscillators.BBP = indicator.RecommendSimple(responseMap[key("Rec.BBPower%s", dataInterval)])
 	ta.Value.Oscillators.BBP = responseMap[key("BBPower%s", dataInterval)]
 
 	// Ultimate Oscillator (7, 14, 28)
-	ta.Recommend.Oscillators.UO = tvSimple(responseMap[key("Rec.UO%s", dataInterval)])
+	ta.Recommend.Oscillators.UO = indicator.RecommendSimple(responseMap[key("Rec.UO%s", dataInterval)])
 	ta.Value.Oscillators.UO = responseMap[key("UO%s", dataInterval)]
 
 	// Moving Averages
 	// Exponential Moving Average (EMA)
-	ta.Recommend.MovingAverages.EMA10 = tvMa(responseMap[key("EMA10%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.EMA10 = indicator.RecommendMA(responseMap[key("EMA10%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.EMA10 = responseMap[key("EMA10%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.EMA20 = tvMa(responseMap[key("EMA20%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.EMA20 = indicator.RecommendMA(responseMap[key("EMA20%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.EMA20 = responseMap[key("EMA20%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.EMA30 = tvMa(responseMap[key("EMA30%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.EMA30 = indicator.RecommendMA(responseMap[key("EMA30%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.EMA30 = responseMap[key("EMA30%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.EMA50 = tvMa(responseMap[key("EMA50%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.EMA50 = indicator.RecommendMA(responseMap[key("EMA50%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.EMA50 = responseMap[key("EMA50%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.EMA100 = tvMa(responseMap[key("EMA100%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.EMA100 = indicator.RecommendMA(responseMap[key("EMA100%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.EMA100 = responseMap[key("EMA100%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.EMA200 = tvMa(responseMap[key("EMA200%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.EMA200 = indicator.RecommendMA(responseMap[key("EMA200%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.EMA200 = responseMap[key("EMA200%s", dataInterval)]
 
 	// Simple Moving Average (SMA)
-	ta.Recommend.MovingAverages.SMA10 = tvMa(responseMap[key("SMA10%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.SMA10 = indicator.RecommendMA(responseMap[key("SMA10%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.SMA10 = responseMap[key("SMA10%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.SMA20 = tvMa(responseMap[key("SMA20%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.SMA20 = indicator.RecommendMA(responseMap[key("SMA20%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.SMA20 = responseMap[key("SMA20%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.SMA30 = tvMa(responseMap[key("SMA30%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.SMA30 = indicator.RecommendMA(responseMap[key("SMA30%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.SMA30 = responseMap[key("SMA30%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.SMA50 = tvMa(responseMap[key("SMA50%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.SMA50 = indicator.RecommendMA(responseMap[key("SMA50%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.SMA50 = responseMap[key("SMA50%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.SMA100 = tvMa(responseMap[key("SMA100%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.SMA100 = indicator.RecommendMA(responseMap[key("SMA100%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.SMA100 = responseMap[key("SMA100%s", dataInterval)]
 
-	ta.Recommend.MovingAverages.SMA200 = tvMa(responseMap[key("SMA200%s", dataInterval)], responseMap[key("close%s", dataInterval)])
+	ta.Recommend.MovingAverages.SMA200 = indicator.RecommendMA(responseMap[key("SMA200%s", dataInterval)], responseMap[key("close%s", dataInterval)])
 	ta.Value.MovingAverages.SMA200 = responseMap[key("SMA200%s", dataInterval)]
 
 	// Ichimoku Base Line (9, 26, 52, 26)
-	ta.Recommend.MovingAverages.Ichimoku = tvSimple(responseMap[key("Rec.Ichimoku%s", dataInterval)])
+	ta.Recommend.MovingAverages.Ichimoku = indicator.RecommendSimple(responseMap[key("Rec.Ichimoku%s", dataInterval)])
 	ta.Value.MovingAverages.Ichimoku = responseMap[key("Ichimoku.BLine%s", dataInterval)]
 
 	// Volume Weighted Moving Average (20)
-	ta.Recommend.MovingAverages.VWMA = tvSimple(responseMap[key("Rec.VWMA%s", dataInterval)])
+	ta.Recommend.MovingAverages.VWMA = indicator.RecommendSimple(responseMap[key("Rec.VWMA%s", dataInterval)])
 	ta.Value.MovingAverages.VWMA = responseMap[key("VWMA%s", dataInterval)]
 
 	// Hull Moving Average (9)
-	ta.Recommend.MovingAverages.HullMA = tvSimple(responseMap[key("Rec.HullMA9%s", dataInterval)])
+	ta.Recommend.MovingAverages.HullMA = indicator.RecommendSimple(responseMap[key("Rec.HullMA9%s", dataInterval)])
 	ta.Value.MovingAverages.HullMA = responseMap[key("HullMA9%s", dataInterval)]
 
 	// Pivots
@@ -399,132 +400,6 @@ func key(indicator, dataInterval string) string {
 	return fmt.Sprintf(indicator, dataInterval)
 }
 
-// tvComputeRecommend - Compute Recommend
-func tvComputeRecommend(v float64) int {
-	switch {
-	case v > 0.1 && v <= 0.5:
-		return dto.TradingViewSignalBuy // BUY
-	case v > 0.5 && v <= 1:
-		return dto.TradingViewSignalStrongBuy // STRONG_BUY
-	case v >= -0.1 && v <= 0.1:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	case v >= -1 && v < -0.5:
-		return dto.TradingViewSignalStrongSell // STRONG_SELL
-	case v >= -0.5 && v < -0.1:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvRsi - Compute Relative Strength Index
-func tvRsi(rsi, rsi1 float64) int {
-	switch {
-	case rsi < 30 && rsi1 < rsi:
-		return dto.TradingViewSignalBuy // BUY
-	case rsi > 70 && rsi1 > rsi:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvStoch - Compute Stochastic
-func tvStoch(k, d, k1, d1 float64) int {
-	switch {
-	case k < 20 && d < 20 && k > d && k1 < d1:
-		return dto.TradingViewSignalBuy // BUY
-	case k > 80 && d > 80 && k < d && k1 > d1:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvCci20 - Compute Commodity Channel Index 20
-func tvCci20(cci20, cci201 float64) int {
-	switch {
-	case cci20 < -100 && cci20 > cci201:
-		return dto.TradingViewSignalBuy // BUY
-	case cci20 > 100 && cci20 < cci201:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvAdx - Compute Average Directional Index
-func tvAdx(adx, adxpdi, adxndi, adxpdi1, adxndi1 float64) int {
-	switch {
-	case adx > 20 && adxpdi1 < adxndi1 && adxpdi > adxndi:
-		return dto.TradingViewSignalBuy // BUY
-	case adx > 20 && adxpdi1 > adxndi1 && adxpdi < adxndi:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvAo - Compute Awesome Oscillator
-func tvAo(ao, ao1, ao2 float64) int {
-	switch {
-	case (ao > 0 && ao1 < 0) || (ao > 0 && ao1 > 0 && ao > ao1 && ao2 > ao1):
-		return dto.TradingViewSignalBuy // BUY
-	case (ao < 0 && ao1 > 0) || (ao < 0 && ao1 < 0 && ao < ao1 && ao2 < ao1):
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvMom - Compute Momentum
-func tvMom(mom, mom1 float64) int {
-	switch {
-	case mom > mom1:
-		return dto.TradingViewSignalBuy // BUY
-	case mom < mom1:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvMacd - Compute Moving Average Convergence/Divergence
-func tvMacd(macd, s float64) int {
-	switch {
-	case macd > s:
-		return dto.TradingViewSignalBuy // BUY
-	case macd < s:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvSimple - Compute Simple
-func tvSimple(v float64) int {
-	switch {
-	case v == 1:
-		return dto.TradingViewSignalBuy // BUY
-	case v == -1:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
-// tvMa - Compute Moving Average
-func tvMa(ma, close float64) int {
-	switch {
-	case ma < close:
-		return dto.TradingViewSignalBuy // BUY
-	case ma > close:
-		return dto.TradingViewSignalSell // SELL
-	default:
-		return dto.TradingViewSignalNeutral // NEUTRAL
-	}
-}
-
 func (t *tradingViewScreenersRepository) GetBuyList(ctx context.Context, payload map[string]interface{}) ([]dto.StockInfo, error) {
 	if payload["markets"] == nil {
 		return nil, fmt.Errorf("markets is required")
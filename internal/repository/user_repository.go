@@ -11,6 +11,7 @@ import (
 type UserRepository interface {
 	GetUserByTelegramID(ctx context.Context, telegramID int64, opts ...utils.DBOption) (*model.User, error)
 	CreateUser(ctx context.Context, user *model.User, opts ...utils.DBOption) error
+	UpdateUser(ctx context.Context, user *model.User, opts ...utils.DBOption) error
 }
 
 type userRepository struct {
@@ -43,3 +44,8 @@ func (r *userRepository) CreateUser(ctx context.Context, user *model.User, opts
 	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
 	return tx.Create(user).Error
 }
+
+func (r *userRepository) UpdateUser(ctx context.Context, user *model.User, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Save(user).Error
+}
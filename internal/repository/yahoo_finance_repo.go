@@ -17,8 +17,25 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// interval chart yahoo finance, 4h tidak terdokumentasi tapi dipakai default timeframe analisa
+// 1w & 1M (format binance / profil timeframe) diterjemahkan ke 1wk & 1mo saat request
+var yahooFinanceIntervals = []string{"1m", "2m", "5m", "15m", "30m", "60m", "90m", "1h", "4h", "1d", "5d", "1w", "1wk", "1M", "1mo", "3mo"}
+
+var yahooFinanceIntervalAliases = map[string]string{
+	"1w": "1wk",
+	"1M": "1mo",
+}
+
+// exchange bawaan jika YAHOO_FINANCE_EXCHANGES kosong
+var yahooFinanceDefaultExchanges = []string{common.EXCHANGE_IDX, common.EXCHANGE_NASDAQ, common.EXCHANGE_NYSE}
+
+// suffix symbol yahoo untuk bursa non-US
+var yahooFinanceSymbolSuffix = map[string]string{
+	common.EXCHANGE_IDX: ".JK",
+}
+
 type YahooFinanceRepository interface {
-	Get(ctx context.Context, param dto.GetStockDataParam) (*dto.StockData, error)
+	MarketDataProvider
 }
 
 // yahooFinanceRepository is an implementation of NewsAnalyzerRepository that uses the Google Gemini API.
@@ -60,24 +77,26 @@ func (r *yahooFinanceRepository) Get(ctx context.Context, param dto.GetStockData
 		return nil, err
 	}
 
-	symbol, err := r.parseSymbol(param.StockCode, param.Exchange)
-	if err != nil {
-		return nil, err
-	}
-
-	param.StockCode = symbol
+	param.StockCode = r.NormalizeSymbol(param.Exchange, param.StockCode)
 
 	// Build URL with query parameters
 	endpoint := "/" + param.StockCode
 
 	period1, period2 := utils.MapPeriodeStringToUnix(param.Range)
+	if param.StartTime > 0 && param.EndTime > 0 {
+		period1, period2 = param.StartTime, param.EndTime
+	}
 	if period1 == 0 || period2 == 0 {
 		return nil, fmt.Errorf("invalid period")
 	}
+	interval := param.Interval
+	if alias, ok := yahooFinanceIntervalAliases[interval]; ok {
+		interval = alias
+	}
 	queryParams := map[string]string{
 		"period1":        fmt.Sprintf("%d", period1),
 		"period2":        fmt.Sprintf("%d", period2),
-		"interval":       param.Interval,
+		"interval":       interval,
 		"includePrePost": "false",
 		"events":         "div,split",
 	}
@@ -131,7 +150,10 @@ func (r *yahooFinanceRepository) Get(ctx context.Context, param dto.GetStockData
 	quote := result.Indicators.Quote[0]
 
 	// Convert to OHLCVData format
-	var ohlcvData []dto.StockOHLCV
+	var (
+		ohlcvData   []dto.StockOHLCV
+		validCloses int
+	)
 	for i, timestamp := range result.Timestamp {
 		// Skip if any required data is missing
 		if i >= len(quote.Open) || i >= len(quote.High) || i >= len(quote.Low) ||
@@ -139,12 +161,10 @@ func (r *yahooFinanceRepository) Get(ctx context.Context, param dto.GetStockData
 			continue
 		}
 
-		// Skip if any value is 0 (missing data)
-		if quote.Open[i] == 0 || quote.High[i] == 0 || quote.Low[i] == 0 ||
-			quote.Close[i] == 0 || quote.Volume[i] == 0 {
-			continue
+		// nilai null dari yahoo terbaca 0, diperbaiki / dibuang oleh validasi kualitas candle di CandleRepository
+		if quote.Close[i] > 0 {
+			validCloses++
 		}
-
 		ohlcvData = append(ohlcvData, dto.StockOHLCV{
 			Timestamp: timestamp,
 			Open:      quote.Open[i],
@@ -155,7 +175,7 @@ func (r *yahooFinanceRepository) Get(ctx context.Context, param dto.GetStockData
 		})
 	}
 
-	if len(ohlcvData) == 0 {
+	if validCloses == 0 {
 		return nil, fmt.Errorf("no valid OHLCV data found for symbol: %s", param.StockCode)
 	}
 
@@ -166,19 +186,55 @@ func (r *yahooFinanceRepository) Get(ctx context.Context, param dto.GetStockData
 	}
 
 	return &dto.StockData{
-		MarketPrice: marketPrice,
-		OHLCV:       ohlcvData,
-		Range:       param.Range,
-		Interval:    param.Interval,
+		MarketPrice:      marketPrice,
+		OHLCV:            ohlcvData,
+		Range:            param.Range,
+		Interval:         param.Interval,
+		CorporateActions: result.Events.CorporateActions(),
 	}, nil
 }
 
-func (r *yahooFinanceRepository) parseSymbol(symbol string, exchange string) (string, error) {
-	symbol = strings.ToUpper(symbol)
+func (r *yahooFinanceRepository) Name() string {
+	return "yahoo_finance"
+}
 
-	if exchange == common.EXCHANGE_IDX {
-		return symbol + ".JK", nil
+func (r *yahooFinanceRepository) Exchanges() []string {
+	if len(r.cfg.YahooFinance.Exchanges) > 0 {
+		return r.cfg.YahooFinance.Exchanges
 	}
+	return yahooFinanceDefaultExchanges
+}
+
+func (r *yahooFinanceRepository) SupportedIntervals() []string {
+	return yahooFinanceIntervals
+}
+
+func (r *yahooFinanceRepository) SupportedRanges() []string {
+	return marketDataRanges
+}
 
-	return symbol, nil
+func (r *yahooFinanceRepository) NormalizeSymbol(exchange string, symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	suffix := yahooFinanceSymbolSuffix[exchange]
+	if suffix == "" || strings.HasSuffix(symbol, suffix) {
+		return symbol
+	}
+	return symbol + suffix
+}
+
+// LastPrice regular market price dari meta chart harian
+func (r *yahooFinanceRepository) LastPrice(ctx context.Context, exchange, symbol string) (float64, error) {
+	data, err := r.Get(ctx, dto.GetStockDataParam{
+		StockCode: symbol,
+		Exchange:  exchange,
+		Range:     "14d",
+		Interval:  "1d",
+	})
+	if err != nil {
+		return 0, err
+	}
+	if data.MarketPrice == 0 {
+		return 0, fmt.Errorf("no market price returned for symbol: %s", symbol)
+	}
+	return data.MarketPrice, nil
 }
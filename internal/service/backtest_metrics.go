@@ -0,0 +1,171 @@
+package service
+
+import (
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/common"
+	"golang-trading/pkg/utils"
+	"math"
+	"time"
+)
+
+// nilai awal equity curve backtest single simbol (index, bukan nominal modal)
+const equityCurveBase = 100.0
+
+// pricePoint harga close yang dipakai untuk mark-to-market equity curve
+type pricePoint struct {
+	time  time.Time
+	close float64
+}
+
+// equityDate tanggal (WIB) yang dipakai sebagai key equity curve harian
+func equityDate(t time.Time) time.Time {
+	wib := utils.TimeToWIB(t)
+	return time.Date(wib.Year(), wib.Month(), wib.Day(), 0, 0, 0, 0, wib.Location())
+}
+
+// periodsPerYear jumlah hari trading dalam setahun untuk annualisasi Sharpe / Sortino,
+// crypto trading setiap hari sedangkan saham ~252 hari bursa
+func periodsPerYear(exchanges ...string) float64 {
+	if len(exchanges) == 0 {
+		return 252
+	}
+	for _, exchange := range exchanges {
+		if !common.IsBinanceExchange(exchange) {
+			return 252
+		}
+	}
+	return 365
+}
+
+// buildTradeEquityCurve membangun equity curve harian dari trade (compounding, seluruh equity masuk di setiap trade).
+// Posisi yang masih terbuka dinilai dengan harga close hari itu, sehingga drawdown di tengah trade ikut terhitung.
+func buildTradeEquityCurve(trades []dto.TradeLog, marks []pricePoint) []dto.EquityPoint {
+	if len(marks) == 0 {
+		for _, trade := range trades {
+			marks = append(marks, pricePoint{time: trade.EntryDate, close: trade.EntryPrice}, pricePoint{time: trade.ExitDate, close: trade.ExitPrice})
+		}
+	}
+
+	var (
+		curve  []dto.EquityPoint
+		equity = equityCurveBase
+		next   int // trade berikutnya yang belum direalisasi
+	)
+	for _, mark := range marks {
+		day := equityDate(mark.time)
+		for next < len(trades) && !equityDate(trades[next].ExitDate).After(day) {
+			equity *= 1 + trades[next].ProfitLossPercent/100
+			next++
+		}
+
+		point := dto.EquityPoint{Date: day, Equity: equity, Cash: equity}
+		if next < len(trades) && !equityDate(trades[next].EntryDate).After(day) && trades[next].EntryPrice > 0 && mark.close > 0 {
+			point.Equity = equity * (1 + dto.ProfitPercent(trades[next].Side, trades[next].EntryPrice, mark.close)/100)
+			point.Cash = 0
+			point.Exposure = 100
+			point.OpenPositions = 1
+		}
+
+		if n := len(curve); n > 0 && curve[n-1].Date.Equal(day) {
+			curve[n-1] = point
+			continue
+		}
+		curve = append(curve, point)
+	}
+	return curve
+}
+
+// calculateMaxDrawdown penurunan terbesar (persen) dari puncak equity, beserta tanggal puncak dan lembahnya
+func calculateMaxDrawdown(curve []dto.EquityPoint) (float64, time.Time, time.Time) {
+	var (
+		maxDrawdown          float64
+		peakDate, troughDate time.Time
+	)
+	if len(curve) == 0 {
+		return 0, peakDate, troughDate
+	}
+
+	peak := curve[0]
+	for _, point := range curve {
+		if point.Equity > peak.Equity {
+			peak = point
+			continue
+		}
+		if peak.Equity <= 0 {
+			continue
+		}
+		drawdown := (peak.Equity - point.Equity) / peak.Equity * 100
+		if drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+			peakDate, troughDate = peak.Date, point.Date
+		}
+	}
+	return maxDrawdown, peakDate, troughDate
+}
+
+// calculateRiskRatios Sharpe & Sortino tahunan dari return harian equity curve (risk free rate = 0)
+func calculateRiskRatios(curve []dto.EquityPoint, periods float64) (float64, float64) {
+	var returns []float64
+	for i := 1; i < len(curve); i++ {
+		if curve[i-1].Equity <= 0 {
+			continue
+		}
+		returns = append(returns, curve[i].Equity/curve[i-1].Equity-1)
+	}
+	if len(returns) < 2 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance, downside := 0.0, 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+
+	var sharpe, sortino float64
+	if stdDev > 0 {
+		sharpe = mean / stdDev * math.Sqrt(periods)
+	}
+	if downsideDev > 0 {
+		sortino = mean / downsideDev * math.Sqrt(periods)
+	}
+	return sharpe, sortino
+}
+
+// longestLosingStreak jumlah trade rugi berturut-turut terpanjang
+func longestLosingStreak(trades []dto.TradeLog) int {
+	longest, current := 0, 0
+	for _, trade := range trades {
+		if trade.ProfitLoss > 0 {
+			current = 0
+			continue
+		}
+		current++
+		longest = max(longest, current)
+	}
+	return longest
+}
+
+// exposurePercent persentase hari di equity curve yang memiliki posisi terbuka
+func exposurePercent(curve []dto.EquityPoint) float64 {
+	if len(curve) == 0 {
+		return 0
+	}
+	days := 0
+	for _, point := range curve {
+		if point.OpenPositions > 0 {
+			days++
+		}
+	}
+	return float64(days) / float64(len(curve)) * 100
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTradeEquityCurve(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 2, 0, 0, 0, time.UTC) }
+
+	trades := []dto.TradeLog{
+		{EntryDate: day(2), EntryPrice: 100, ExitDate: day(4), ExitPrice: 110, ProfitLoss: 10, ProfitLossPercent: 10},
+		{EntryDate: day(5), EntryPrice: 100, ExitDate: day(6), ExitPrice: 95, ProfitLoss: -5, ProfitLossPercent: -5},
+	}
+	marks := []pricePoint{
+		{day(1), 100}, {day(2), 100}, {day(3), 90}, {day(4), 110}, {day(5), 100}, {day(6), 95},
+	}
+
+	curve := buildTradeEquityCurve(trades, marks)
+	assert.Len(t, curve, 6)
+	assert.InDelta(t, 100, curve[0].Equity, 1e-9)
+	assert.InDelta(t, 90, curve[2].Equity, 1e-9) // posisi terbuka dinilai di harga close
+	assert.Equal(t, 1, curve[2].OpenPositions)
+	assert.InDelta(t, 110, curve[3].Equity, 1e-9)
+	assert.Equal(t, 0, curve[3].OpenPositions)
+	assert.InDelta(t, 104.5, curve[5].Equity, 1e-9)
+
+	maxDrawdown, peak, trough := calculateMaxDrawdown(curve)
+	assert.InDelta(t, 10, maxDrawdown, 1e-9)
+	assert.Equal(t, equityDate(day(1)), peak)
+	assert.Equal(t, equityDate(day(3)), trough)
+
+	assert.InDelta(t, 50, exposurePercent(curve), 1e-9)
+}
+
+func TestLongestLosingStreak(t *testing.T) {
+	trades := []dto.TradeLog{{ProfitLoss: -1}, {ProfitLoss: 2}, {ProfitLoss: -1}, {ProfitLoss: 0}, {ProfitLoss: -3}, {ProfitLoss: 1}}
+	assert.Equal(t, 3, longestLosingStreak(trades))
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/logger"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	// batas jumlah kombinasi parameter supaya satu request optimasi tidak berjalan berjam-jam
+	maxOptimizeCandidates     = 500
+	maxWalkForwardWindows     = 100
+	maxWalkForwardReplays     = 20000 // window x kandidat, setiap replay menjalankan analisa seluruh window train
+	maxParamRangeValues       = 10000 // batas nilai grid satu parameter, juga untuk sampling random
+	defaultOptimizeIterations = 50
+	defaultOptimizeMinTrades  = 3
+)
+
+// ErrInvalidWalkForwardRequest rentang tanggal / search space request walk-forward tidak valid atau melebihi batas
+var ErrInvalidWalkForwardRequest = errors.New("invalid walk-forward request")
+
+// defaultSearchSpace dipakai jika request tidak menyertakan search space
+var defaultSearchSpace = []dto.ParamRange{
+	{Name: "target_risk_reward", Values: []float64{1, 1.5, 2}},
+	{Name: "max_stop_loss_percent", Values: []float64{0.03, 0.05, 0.07}},
+	{Name: "max_take_profit_percent", Values: []float64{0.05, 0.07, 0.1}},
+	{Name: "buy_signal_score", Values: []float64{40, 50, 60}},
+}
+
+// RunWalkForward mencari parameter trade plan terbaik di setiap window train lalu menguji hasilnya
+// di window test berikutnya (out-of-sample), window digeser sejauh TestDays sampai EndDate.
+func (s *backtestService) RunWalkForward(ctx context.Context, req dto.WalkForwardRequest) (*dto.WalkForwardResult, error) {
+	ctx = withBacktestMode(ctx)
+	ctx = WithTradeSide(ctx, req.Side)
+
+	if req.Objective == "" {
+		req.Objective = dto.OptimizeObjectiveSharpe
+	}
+	if req.MinTrades == 0 {
+		req.MinTrades = defaultOptimizeMinTrades
+	}
+
+	windows, err := buildWalkForwardWindows(req.StartDate, req.EndDate, req.TrainDays, req.TestDays)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("%w: date range is shorter than train_days + test_days", ErrInvalidWalkForwardRequest)
+	}
+
+	stock := dto.StockInfo{StockCode: req.StockCode, Exchange: req.Exchange}
+	timeframes, err := s.getReplayTimeframes(ctx, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	baseParams, err := s.baseTradePlanParams(ctx, req.Exchange, timeframes)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := buildParamCandidates(req, baseParams)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to build walk-forward parameter candidates", logger.ErrorField(err))
+		return nil, err
+	}
+	if replays := len(windows) * len(candidates); replays > maxWalkForwardReplays {
+		return nil, fmt.Errorf("%w: %d replays needed (%d windows x %d candidates), maximum is %d",
+			ErrInvalidWalkForwardRequest, replays, len(windows), len(candidates), maxWalkForwardReplays)
+	}
+
+	rs, err := s.loadReplaySymbol(ctx, stock, timeframes, req.StartDate, req.EndDate, false)
+	if err != nil {
+		return nil, err
+	}
+	rs.analyses = make(map[time.Time][]model.StockAnalysis)
+
+	result := &dto.WalkForwardResult{
+		StockCode:  req.StockCode,
+		Exchange:   req.Exchange,
+		Objective:  req.Objective,
+		Candidates: len(candidates),
+	}
+
+	var (
+		trainScores = make([][]float64, len(candidates)) // skor train setiap kandidat per window
+		oosTrades   []dto.TradeLog
+		oosMarks    []pricePoint
+	)
+
+	for _, window := range windows {
+		bestIdx, bestScore, bestTrades := -1, math.Inf(-1), 0
+		for i, params := range candidates {
+			trades, marks, err := s.replaySymbolRange(withTradePlanParams(ctx, params), rs, window.TrainStart, window.TrainEnd, req.FillPriority)
+			if err != nil {
+				return nil, err
+			}
+
+			trainResult := calculateBacktestResult(dto.BacktestRequest{Exchange: req.Exchange}, trades, marks)
+			score := optimizeScore(trainResult, req.Objective, req.MinTrades)
+			trainScores[i] = append(trainScores[i], score)
+
+			if score > bestScore {
+				bestIdx, bestScore, bestTrades = i, score, trainResult.TotalTrades
+			}
+		}
+
+		if bestIdx < 0 {
+			// tidak ada kandidat yang memenuhi MinTrades, window test dijalankan dengan parameter config saat ini
+			bestScore = 0
+			window.Params = baseParams
+		} else {
+			window.Params = candidates[bestIdx]
+		}
+		window.TrainScore = bestScore
+		window.TrainTrades = bestTrades
+
+		trades, marks, err := s.replaySymbolRange(withTradePlanParams(ctx, window.Params), rs, window.TestStart, window.TestEnd, req.FillPriority)
+		if err != nil {
+			return nil, err
+		}
+		oosTrades = append(oosTrades, trades...)
+		oosMarks = append(oosMarks, marks...)
+
+		window.TestResult = calculateBacktestResult(dto.BacktestRequest{
+			StockCode: req.StockCode,
+			Exchange:  req.Exchange,
+			StartDate: window.TestStart,
+			EndDate:   window.TestEnd,
+		}, trades, marks)
+		// detail trade & equity curve cukup di OutOfSample
+		window.TestResult.Trades = nil
+		window.TestResult.EquityCurve = nil
+
+		result.Windows = append(result.Windows, window)
+
+		s.log.DebugContext(ctx, "Walk-forward window completed",
+			logger.StringField("stock_code", req.StockCode),
+			logger.StringField("test_start", window.TestStart.String()),
+			logger.IntField("test_trades", window.TestResult.TotalTrades),
+		)
+	}
+
+	robustIdx, robustScore := selectRobustCandidate(trainScores)
+	if robustIdx >= 0 {
+		result.RobustParams = candidates[robustIdx]
+		result.RobustScore = robustScore
+	} else {
+		result.RobustParams = baseParams
+	}
+
+	result.OutOfSample = calculateBacktestResult(dto.BacktestRequest{
+		StockCode: req.StockCode,
+		Exchange:  req.Exchange,
+		StartDate: windows[0].TestStart,
+		EndDate:   windows[len(windows)-1].TestEnd,
+	}, oosTrades, oosMarks)
+
+	s.log.InfoContext(ctx, "Walk-forward optimization completed",
+		logger.StringField("stock_code", req.StockCode),
+		logger.IntField("windows", len(result.Windows)),
+		logger.IntField("candidates", len(candidates)),
+		logger.IntField("oos_trades", result.OutOfSample.TotalTrades),
+	)
+	return result, nil
+}
+
+// baseTradePlanParams parameter TRADING_PLAN_CONFIG untuk exchange & timeframe dengan bobot tertinggi
+// (timeframe yang dipakai CreateTradePlan), menjadi dasar kandidat optimasi
+func (s *backtestService) baseTradePlanParams(ctx context.Context, exchange string, timeframes []dto.DataTimeframe) (dto.TradePlanParams, error) {
+	planConfig, err := s.systemParamRepo.GetTradingPlanConfig(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get trading plan config for walk-forward", logger.ErrorField(err))
+		return dto.TradePlanParams{}, err
+	}
+
+	var highestWeightTF dto.DataTimeframe
+	for _, tf := range timeframes {
+		if tf.Weight > highestWeightTF.Weight {
+			highestWeightTF = tf
+		}
+	}
+	return planConfig.Resolve(exchange, highestWeightTF.Interval)
+}
+
+// buildWalkForwardWindows membagi rentang menjadi window train/test yang bergeser sejauh testDays.
+// Window test terakhir dipotong di endDate, error jika jumlah window melebihi maxWalkForwardWindows.
+func buildWalkForwardWindows(startDate, endDate time.Time, trainDays, testDays int) ([]dto.WalkForwardWindow, error) {
+	train := time.Duration(trainDays) * 24 * time.Hour
+	test := time.Duration(testDays) * 24 * time.Hour
+
+	var windows []dto.WalkForwardWindow
+	for trainStart := startDate; ; trainStart = trainStart.Add(test) {
+		testStart := trainStart.Add(train)
+		if !testStart.Before(endDate) {
+			break
+		}
+
+		if len(windows) == maxWalkForwardWindows {
+			return nil, fmt.Errorf("%w: more than %d windows, increase test_days or shorten the date range", ErrInvalidWalkForwardRequest, maxWalkForwardWindows)
+		}
+
+		testEnd := testStart.Add(test)
+		if testEnd.After(endDate) {
+			testEnd = endDate
+		}
+
+		windows = append(windows, dto.WalkForwardWindow{
+			TrainStart: trainStart,
+			TrainEnd:   testStart.Add(-time.Nanosecond),
+			TestStart:  testStart,
+			TestEnd:    testEnd.Add(-time.Nanosecond),
+		})
+	}
+	return windows, nil
+}
+
+// buildParamCandidates membentuk kombinasi parameter dari search space (grid atau random) di atas base,
+// kombinasi yang tidak valid (mis. min SL > max SL) dibuang
+func buildParamCandidates(req dto.WalkForwardRequest, base dto.TradePlanParams) ([]dto.TradePlanParams, error) {
+	space := req.SearchSpace
+	if len(space) == 0 {
+		space = defaultSearchSpace
+	}
+
+	// ukuran grid dihitung & dibatasi sebelum nilai grid dibuat, step yang sangat kecil tidak boleh menghabiskan memori.
+	// gridSize float64 supaya perkalian tidak overflow.
+	gridSize := 1.0
+	for _, pr := range space {
+		size, err := paramRangeSize(pr)
+		if err != nil {
+			return nil, err
+		}
+		gridSize *= float64(size)
+	}
+	if req.Method != dto.OptimizeMethodRandom && gridSize > maxOptimizeCandidates {
+		return nil, fmt.Errorf("%w: search space has %.0f combinations, maximum is %d", ErrInvalidWalkForwardRequest, gridSize, maxOptimizeCandidates)
+	}
+
+	values := make([][]float64, len(space))
+	for i, pr := range space {
+		values[i] = paramRangeValues(pr)
+	}
+
+	var combos [][]float64
+	if req.Method == dto.OptimizeMethodRandom {
+		iterations := req.Iterations
+		if iterations <= 0 {
+			iterations = defaultOptimizeIterations
+		}
+		if iterations > maxOptimizeCandidates {
+			return nil, fmt.Errorf("%w: iterations must not exceed %d", ErrInvalidWalkForwardRequest, maxOptimizeCandidates)
+		}
+
+		rnd := rand.New(rand.NewSource(req.Seed))
+		for n := 0; n < iterations; n++ {
+			combo := make([]float64, len(space))
+			for i, pr := range space {
+				combo[i] = sampleParamRange(rnd, pr, values[i])
+			}
+			combos = append(combos, combo)
+		}
+	} else {
+		combos = cartesian(values)
+	}
+
+	var candidates []dto.TradePlanParams
+	for _, combo := range combos {
+		params := base
+		for i, pr := range space {
+			if err := params.Set(pr.Name, combo[i]); err != nil {
+				return nil, err
+			}
+		}
+		if params.Validate() != nil {
+			continue
+		}
+		candidates = append(candidates, params)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: search space does not contain any valid parameter combination", ErrInvalidWalkForwardRequest)
+	}
+	return candidates, nil
+}
+
+// paramRangeSize jumlah nilai grid satu parameter tanpa membuat grid-nya, error jika range tidak valid / terlalu besar
+func paramRangeSize(pr dto.ParamRange) (int, error) {
+	for _, v := range append([]float64{pr.Min, pr.Max, pr.Step}, pr.Values...) {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("%w: parameter %s must be a finite number", ErrInvalidWalkForwardRequest, pr.Name)
+		}
+	}
+
+	size := 1.0
+	if len(pr.Values) > 0 {
+		size = float64(len(pr.Values))
+	} else if pr.Step > 0 && pr.Max > pr.Min {
+		size = math.Floor((pr.Max-pr.Min)/pr.Step+1e-9) + 1
+	}
+	if size > maxParamRangeValues {
+		return 0, fmt.Errorf("%w: parameter %s has %.0f values, maximum is %d", ErrInvalidWalkForwardRequest, pr.Name, size, maxParamRangeValues)
+	}
+	return int(size), nil
+}
+
+// paramRangeValues nilai grid satu parameter, ukurannya harus sudah dicek paramRangeSize
+func paramRangeValues(pr dto.ParamRange) []float64 {
+	if len(pr.Values) > 0 {
+		return pr.Values
+	}
+	if pr.Step <= 0 || pr.Max <= pr.Min {
+		return []float64{pr.Min}
+	}
+
+	size := int(math.Floor((pr.Max-pr.Min)/pr.Step+1e-9)) + 1
+	values := make([]float64, 0, size)
+	for k := 0; k < size; k++ {
+		values = append(values, math.Round((pr.Min+float64(k)*pr.Step)*1e8)/1e8)
+	}
+	return values
+}
+
+// sampleParamRange nilai acak satu parameter: pilih salah satu Values, atau uniform di Min..Max (dibulatkan ke Step)
+func sampleParamRange(rnd *rand.Rand, pr dto.ParamRange, grid []float64) float64 {
+	if len(pr.Values) > 0 || pr.Step > 0 {
+		return grid[rnd.Intn(len(grid))]
+	}
+	return pr.Min + rnd.Float64()*(pr.Max-pr.Min)
+}
+
+func cartesian(values [][]float64) [][]float64 {
+	combos := [][]float64{{}}
+	for _, vs := range values {
+		var next [][]float64
+		for _, combo := range combos {
+			for _, v := range vs {
+				c := make([]float64, len(combo), len(combo)+1)
+				copy(c, combo)
+				next = append(next, append(c, v))
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// optimizeScore nilai objective dari hasil backtest, -Inf jika jumlah trade kurang dari minTrades
+func optimizeScore(result *dto.BacktestResult, objective string, minTrades int) float64 {
+	if result.TotalTrades < minTrades {
+		return math.Inf(-1)
+	}
+
+	switch objective {
+	case dto.OptimizeObjectiveExpectancy:
+		return result.Expectancy
+	case dto.OptimizeObjectiveTotalReturn:
+		return result.TotalReturnPercent
+	case dto.OptimizeObjectiveProfitFactor:
+		if result.ProfitFactor == 0 && result.LosingTrades == 0 {
+			// semua trade profit, profit factor tak hingga
+			return math.MaxFloat64
+		}
+		return result.ProfitFactor
+	default:
+		return result.SharpeRatio
+	}
+}
+
+// selectRobustCandidate memilih kandidat dengan median skor train tertinggi di semua window.
+// Kandidat yang tidak punya skor valid di minimal setengah window diabaikan.
+func selectRobustCandidate(scores [][]float64) (int, float64) {
+	bestIdx, bestMedian := -1, math.Inf(-1)
+	for i, candidateScores := range scores {
+		var valid []float64
+		for _, score := range candidateScores {
+			if !math.IsInf(score, -1) {
+				valid = append(valid, score)
+			}
+		}
+		if len(valid) == 0 || len(valid)*2 < len(candidateScores) {
+			continue
+		}
+
+		sort.Float64s(valid)
+		median := valid[len(valid)/2]
+		if len(valid)%2 == 0 {
+			median = (valid[len(valid)/2-1] + valid[len(valid)/2]) / 2
+		}
+
+		if median > bestMedian {
+			bestIdx, bestMedian = i, median
+		}
+	}
+	if bestIdx < 0 {
+		return -1, 0
+	}
+	return bestIdx, bestMedian
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildWalkForwardWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 100)
+
+	windows, err := buildWalkForwardWindows(start, end, 60, 20)
+	assert.NoError(t, err)
+	assert.Len(t, windows, 2)
+	assert.Equal(t, start.AddDate(0, 0, 60), windows[0].TestStart)
+	assert.Equal(t, start.AddDate(0, 0, 20), windows[1].TrainStart)
+	assert.Equal(t, end.Add(-time.Nanosecond), windows[1].TestEnd)
+
+	windows, err = buildWalkForwardWindows(start, end, 100, 20)
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+
+	// window 1 hari selama 10 tahun ditolak sebelum window dibuat semua
+	_, err = buildWalkForwardWindows(start, start.AddDate(10, 0, 0), 1, 1)
+	assert.ErrorIs(t, err, ErrInvalidWalkForwardRequest)
+	windows, err = buildWalkForwardWindows(start, start.AddDate(0, 0, maxWalkForwardWindows+1), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, windows, maxWalkForwardWindows)
+}
+
+func TestBuildParamCandidates(t *testing.T) {
+	candidates, err := buildParamCandidates(dto.WalkForwardRequest{
+		SearchSpace: []dto.ParamRange{
+			{Name: "min_stop_loss_percent", Min: 0.02, Max: 0.06, Step: 0.02},
+			{Name: "max_stop_loss_percent", Values: []float64{0.03, 0.05}},
+		},
+	}, dto.DefaultTradePlanParams())
+	assert.NoError(t, err)
+	// kombinasi min SL > max SL dibuang: (0.04, 0.03), (0.06, 0.03), (0.06, 0.05)
+	assert.Len(t, candidates, 3)
+
+	_, err = buildParamCandidates(dto.WalkForwardRequest{SearchSpace: []dto.ParamRange{{Name: "unknown", Min: 1}}}, dto.DefaultTradePlanParams())
+	assert.Error(t, err)
+
+	random, err := buildParamCandidates(dto.WalkForwardRequest{Method: dto.OptimizeMethodRandom, Iterations: 10, Seed: 1}, dto.DefaultTradePlanParams())
+	assert.NoError(t, err)
+	assert.Len(t, random, 10)
+
+	// step sangat kecil / nilai tidak hingga ditolak sebelum grid dibuat
+	tiny := []dto.ParamRange{{Name: "target_risk_reward", Min: 1, Max: 3, Step: 1e-9}}
+	_, err = buildParamCandidates(dto.WalkForwardRequest{SearchSpace: tiny}, dto.DefaultTradePlanParams())
+	assert.Error(t, err)
+	_, err = buildParamCandidates(dto.WalkForwardRequest{Method: dto.OptimizeMethodRandom, SearchSpace: tiny}, dto.DefaultTradePlanParams())
+	assert.Error(t, err)
+	_, err = buildParamCandidates(dto.WalkForwardRequest{SearchSpace: []dto.ParamRange{{Name: "target_risk_reward", Min: 1, Max: math.Inf(1), Step: 1}}}, dto.DefaultTradePlanParams())
+	assert.Error(t, err)
+	_, err = buildParamCandidates(dto.WalkForwardRequest{SearchSpace: []dto.ParamRange{{Name: "target_risk_reward", Values: []float64{1, math.NaN()}}}}, dto.DefaultTradePlanParams())
+	assert.Error(t, err)
+
+	// perkalian ukuran grid banyak parameter tidak overflow
+	wide := make([]dto.ParamRange, 8)
+	for i := range wide {
+		wide[i] = dto.ParamRange{Name: "target_risk_reward", Min: 0, Max: 9999, Step: 1}
+	}
+	_, err = buildParamCandidates(dto.WalkForwardRequest{SearchSpace: wide}, dto.DefaultTradePlanParams())
+	assert.ErrorContains(t, err, "maximum is 500")
+}
+
+func TestSelectRobustCandidate(t *testing.T) {
+	inf := math.Inf(-1)
+	idx, score := selectRobustCandidate([][]float64{
+		{5, -1, -1},    // satu window sangat bagus, median rendah
+		{1, 1.5, 0.5},  // stabil
+		{inf, inf, 10}, // kurang dari setengah window valid
+	})
+	assert.Equal(t, 1, idx)
+	assert.Equal(t, 1.0, score)
+}
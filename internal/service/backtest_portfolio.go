@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-trading/internal/costmodel"
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultMaxConcurrentPositions = 5
+
+// ErrMixedCurrencyPortfolio simbol portfolio berbeda mata uang sehingga tidak bisa berbagi satu modal
+var ErrMixedCurrencyPortfolio = errors.New("portfolio backtest symbols must share one currency")
+
+// RunPortfolioBacktest menjalankan candle replay untuk banyak simbol sekaligus dengan satu modal bersama.
+//
+// Di setiap waktu bar (timeframe utama):
+//  1. posisi terbuka dicek SL/TP terhadap high-low bar, modal kembali ke cash
+//  2. entry pending di-fill berurutan dari skor tertinggi selama slot posisi & cash masih cukup
+//  3. saat close bar, posisi dievaluasi atau trade plan baru dibuat
+//
+// Buy list dari TradingView diambil sekali di awal (kondisi hari ini), sehingga hasil masih bisa mengandung survivorship bias.
+func (s *backtestService) RunPortfolioBacktest(ctx context.Context, req dto.PortfolioBacktestRequest) (*dto.PortfolioBacktestResult, error) {
+	ctx = withBacktestMode(ctx)
+	ctx = WithTradeSide(ctx, req.Side)
+
+	symbols, err := s.resolvePortfolioSymbols(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := portfolioCurrency(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PortfolioBacktestResult{
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Currency:       currency,
+		InitialCapital: req.InitialCapital,
+		FinalEquity:    req.InitialCapital,
+		Symbols:        symbols,
+	}
+
+	if len(symbols) == 0 {
+		s.log.InfoContext(ctx, "No symbols found for portfolio backtest")
+		return result, nil
+	}
+
+	var replaySymbols []*replaySymbol
+	for _, stock := range symbols {
+		timeframes, err := s.getReplayTimeframes(ctx, stock)
+		if err != nil {
+			result.FailedSymbols = append(result.FailedSymbols, stock.Exchange+":"+stock.StockCode)
+			continue
+		}
+		rs, err := s.loadReplaySymbol(ctx, stock, timeframes, req.StartDate, req.EndDate, req.AdjustDividends)
+		if err != nil {
+			result.FailedSymbols = append(result.FailedSymbols, stock.Exchange+":"+stock.StockCode)
+			continue
+		}
+		replaySymbols = append(replaySymbols, rs)
+	}
+
+	maxPositions := req.MaxConcurrentPositions
+	if maxPositions <= 0 {
+		maxPositions = defaultMaxConcurrentPositions
+	}
+
+	fillPriority := req.FillPriority
+	if fillPriority == "" {
+		fillPriority = dto.FillPriorityStopLoss
+	}
+
+	var (
+		cash        = req.InitialCapital
+		lastClose   = make(map[*replaySymbol]float64)
+		lastBarTime = make(map[*replaySymbol]time.Time)
+		tradeLogs   []dto.TradeLog
+		equityDays  = make(map[time.Time]int)
+	)
+
+	openPositions := func() int {
+		count := 0
+		for _, rs := range replaySymbols {
+			if rs.position != nil {
+				count++
+			}
+		}
+		return count
+	}
+
+	closeTrade := func(trade *dto.TradeLog) bool {
+		if trade == nil {
+			return false
+		}
+		// modal awal posisi kembali ditambah P/L bersih, berlaku untuk long maupun short
+		cash += costmodel.ForExchange(trade.Exchange).BuyCost(trade.EntryPrice, trade.Quantity) + trade.ProfitLossAmount
+		tradeLogs = append(tradeLogs, *trade)
+		return true
+	}
+
+	for _, barTime := range portfolioTimeline(replaySymbols, req.StartDate, req.EndDate) {
+		if !utils.ShouldContinue(ctx, s.log) {
+			return nil, ctx.Err()
+		}
+
+		// simbol yang punya bar di barTime, urutan tetap mengikuti replaySymbols supaya hasil deterministik
+		var active []*replaySymbol
+		barIndex := make(map[*replaySymbol]int)
+		for _, rs := range replaySymbols {
+			if i := rs.barIndexAt(barTime); i >= 0 {
+				active = append(active, rs)
+				barIndex[rs] = i
+			}
+		}
+
+		// 1. SL / TP posisi yang sudah terbuka
+		exited := make(map[*replaySymbol]bool)
+		for _, rs := range active {
+			exited[rs] = closeTrade(rs.checkExit(rs.main.candles[barIndex[rs]], barTime, fillPriority))
+		}
+
+		// 2. Fill entry pending, skor tertinggi didahulukan
+		var pending []*replaySymbol
+		for _, rs := range active {
+			if rs.position == nil && rs.pendingPlan != nil {
+				pending = append(pending, rs)
+			}
+		}
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].pendingPlan.Score > pending[j].pendingPlan.Score
+		})
+
+		for _, rs := range pending {
+			bar := rs.main.candles[barIndex[rs]]
+			fillPrice, ok := fillReplayEntry(rs.pendingPlan.Side, rs.pendingPlan.Entry, rs.pendingClose, bar)
+			if !ok {
+				rs.pendingPlan = nil
+				continue
+			}
+			fillPrice = rs.cost.EntryFillPrice(fillPrice, dto.IsShort(rs.pendingPlan.Side))
+
+			quantity := 0.0
+			if openPositions() < maxPositions {
+				equity := portfolioEquity(cash, replaySymbols, lastClose)
+				allocation := math.Min(positionAllocation(req, equity, maxPositions), cash)
+				quantity = rs.cost.RoundQuantity(allocation / rs.cost.BuyCost(fillPrice, 1))
+			}
+
+			if quantity <= 0 {
+				result.SkippedSignals++
+				rs.pendingPlan = nil
+				continue
+			}
+
+			rs.openPosition(fillPrice, quantity, barTime)
+			rs.pendingPlan = nil
+			cash -= rs.cost.BuyCost(fillPrice, quantity)
+
+			// posisi baru juga bisa langsung kena SL / TP di bar yang sama
+			exited[rs] = closeTrade(rs.checkExit(bar, barTime, fillPriority))
+		}
+
+		// 3. Evaluasi saat bar close
+		for _, rs := range active {
+			bar := rs.main.candles[barIndex[rs]]
+			lastClose[rs], lastBarTime[rs] = bar.Close, barTime
+			if exited[rs] {
+				continue
+			}
+			closeTrade(s.evaluateReplayClose(ctx, rs, bar, barTime))
+		}
+
+		// equity curve harian, titik terakhir di hari yang sama menimpa sebelumnya
+		point := portfolioEquityPoint(barTime, cash, replaySymbols, lastClose)
+		day := point.Date
+		if idx, ok := equityDays[day]; ok {
+			result.EquityCurve[idx] = point
+		} else {
+			equityDays[day] = len(result.EquityCurve)
+			result.EquityCurve = append(result.EquityCurve, point)
+		}
+	}
+
+	// tutup posisi yang masih terbuka di harga close terakhir
+	for _, rs := range replaySymbols {
+		if rs.position == nil {
+			continue
+		}
+		trade := rs.closePosition(lastBarTime[rs], lastClose[rs], "End of Backtest")
+		closeTrade(&trade)
+	}
+
+	calculatePortfolioResult(result, cash, tradeLogs)
+
+	exchanges := make([]string, 0, len(replaySymbols))
+	for _, rs := range replaySymbols {
+		exchanges = append(exchanges, rs.stock.Exchange)
+	}
+	result.MaxDrawdown, result.MaxDrawdownPeakDate, result.MaxDrawdownTroughDate = calculateMaxDrawdown(result.EquityCurve)
+	result.SharpeRatio, result.SortinoRatio = calculateRiskRatios(result.EquityCurve, periodsPerYear(exchanges...))
+
+	s.log.InfoContext(ctx, "Portfolio backtest completed",
+		logger.IntField("total_symbols", len(replaySymbols)),
+		logger.IntField("total_trades", result.TotalTrades),
+	)
+	return result, nil
+}
+
+// resolvePortfolioSymbols menggabungkan Symbols dengan hasil buy list TradingView, tanpa duplikat
+func (s *backtestService) resolvePortfolioSymbols(ctx context.Context, req dto.PortfolioBacktestRequest) ([]dto.StockInfo, error) {
+	var symbols []dto.StockInfo
+	seen := map[string]bool{}
+	add := func(stock dto.StockInfo) {
+		key := stock.Exchange + ":" + stock.StockCode
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		symbols = append(symbols, stock)
+	}
+
+	for _, params := range req.TradingViewBuyListParams {
+		buyList, err := s.tradingViewScreenersRepo.GetBuyList(ctx, params)
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to get buy list for portfolio backtest", logger.ErrorField(err))
+			return nil, fmt.Errorf("failed to get buy list: %w", err)
+		}
+		for _, stock := range buyList {
+			add(stock)
+		}
+	}
+
+	for _, stock := range req.Symbols {
+		add(stock)
+	}
+
+	return symbols, nil
+}
+
+// portfolioCurrency mata uang modal portfolio, ditolak jika simbol berasal dari exchange dengan mata uang berbeda
+func portfolioCurrency(symbols []dto.StockInfo) (string, error) {
+	var (
+		currencies []string
+		exchanges  = map[string][]string{}
+	)
+	for _, stock := range symbols {
+		currency := costmodel.ForExchange(stock.Exchange).Currency
+		if _, ok := exchanges[currency]; !ok {
+			currencies = append(currencies, currency)
+		}
+		if !slices.Contains(exchanges[currency], stock.Exchange) {
+			exchanges[currency] = append(exchanges[currency], stock.Exchange)
+		}
+	}
+	if len(currencies) <= 1 {
+		return strings.Join(currencies, ""), nil
+	}
+
+	groups := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		groups = append(groups, fmt.Sprintf("%s (%s)", currency, strings.Join(exchanges[currency], ", ")))
+	}
+	return "", fmt.Errorf("%w, got %s", ErrMixedCurrencyPortfolio, strings.Join(groups, " and "))
+}
+
+// portfolioTimeline gabungan open time bar timeframe utama semua simbol dalam rentang backtest, terurut
+func portfolioTimeline(replaySymbols []*replaySymbol, startDate, endDate time.Time) []time.Time {
+	seen := map[time.Time]bool{}
+	var timeline []time.Time
+	for _, rs := range replaySymbols {
+		for _, t := range rs.main.times {
+			if t.Before(startDate) || t.After(endDate) || seen[t] {
+				continue
+			}
+			seen[t] = true
+			timeline = append(timeline, t)
+		}
+	}
+	sort.Slice(timeline, func(i, j int) bool {
+		return timeline[i].Before(timeline[j])
+	})
+	return timeline
+}
+
+func positionAllocation(req dto.PortfolioBacktestRequest, equity float64, maxPositions int) float64 {
+	switch req.Sizing {
+	case dto.SizingPercentEquity:
+		return equity * req.SizingValue / 100
+	case dto.SizingFixedAmount:
+		return req.SizingValue
+	default:
+		return equity / float64(maxPositions)
+	}
+}
+
+func portfolioEquity(cash float64, replaySymbols []*replaySymbol, lastClose map[*replaySymbol]float64) float64 {
+	equity := cash
+	for _, rs := range replaySymbols {
+		if rs.position != nil {
+			equity += positionValue(rs, lastClose)
+		}
+	}
+	return equity
+}
+
+func portfolioEquityPoint(barTime time.Time, cash float64, replaySymbols []*replaySymbol, lastClose map[*replaySymbol]float64) dto.EquityPoint {
+	point := dto.EquityPoint{
+		Date: equityDate(barTime),
+		Cash: cash,
+	}
+
+	invested := 0.0
+	for _, rs := range replaySymbols {
+		if rs.position == nil {
+			continue
+		}
+		invested += positionValue(rs, lastClose)
+		point.OpenPositions++
+	}
+
+	point.Equity = cash + invested
+	if point.Equity > 0 {
+		point.Exposure = invested / point.Equity * 100
+	}
+	return point
+}
+
+// positionValue nilai posisi terbuka di harga mark, posisi short bernilai modal awal ditambah selisih turunnya harga
+func positionValue(rs *replaySymbol, lastClose map[*replaySymbol]float64) float64 {
+	mark := markPrice(rs, lastClose)
+	if dto.IsShort(rs.position.Side) {
+		return rs.quantity * (2*rs.position.BuyPrice - mark)
+	}
+	return rs.quantity * mark
+}
+
+// markPrice harga close terakhir simbol, fallback ke harga beli jika belum ada bar yang close
+func markPrice(rs *replaySymbol, lastClose map[*replaySymbol]float64) float64 {
+	if price, ok := lastClose[rs]; ok && price > 0 {
+		return price
+	}
+	return rs.position.BuyPrice
+}
+
+func calculatePortfolioResult(result *dto.PortfolioBacktestResult, cash float64, trades []dto.TradeLog) {
+	result.Trades = trades
+	result.FinalEquity = cash
+	if result.InitialCapital > 0 {
+		result.TotalReturnPercent = (result.FinalEquity - result.InitialCapital) / result.InitialCapital * 100
+	}
+
+	contributions := make(map[string]*dto.SymbolContribution)
+	var order []string
+	for _, trade := range trades {
+		result.TotalTrades++
+		if trade.ProfitLossAmount > 0 {
+			result.WinningTrades++
+		} else {
+			result.LosingTrades++
+		}
+
+		key := trade.Exchange + ":" + trade.Symbol
+		contribution, ok := contributions[key]
+		if !ok {
+			contribution = &dto.SymbolContribution{Symbol: trade.Symbol, Exchange: trade.Exchange}
+			contributions[key] = contribution
+			order = append(order, key)
+		}
+		contribution.TotalTrades++
+		contribution.ProfitLoss += trade.ProfitLossAmount
+		if trade.ProfitLossAmount > 0 {
+			contribution.WinningTrades++
+		}
+	}
+
+	if result.TotalTrades > 0 {
+		result.WinRate = float64(result.WinningTrades) / float64(result.TotalTrades) * 100
+	}
+
+	for _, key := range order {
+		contribution := contributions[key]
+		if result.InitialCapital > 0 {
+			contribution.ContributionPercent = contribution.ProfitLoss / result.InitialCapital * 100
+		}
+		result.SymbolContributions = append(result.SymbolContributions, *contribution)
+	}
+	sort.Slice(result.SymbolContributions, func(i, j int) bool {
+		return result.SymbolContributions[i].ProfitLoss > result.SymbolContributions[j].ProfitLoss
+	})
+
+	if len(result.EquityCurve) > 0 {
+		totalExposure := 0.0
+		for _, point := range result.EquityCurve {
+			totalExposure += point.Exposure
+		}
+		result.AvgExposure = totalExposure / float64(len(result.EquityCurve))
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"golang-trading/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortfolioCurrency(t *testing.T) {
+	currency, err := portfolioCurrency([]dto.StockInfo{{StockCode: "BBCA", Exchange: "IDX"}, {StockCode: "BBRI", Exchange: "IDX"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "IDR", currency)
+
+	// spot & futures binance sama-sama USDT
+	currency, err = portfolioCurrency([]dto.StockInfo{{StockCode: "BTCUSDT", Exchange: "BINANCE"}, {StockCode: "ETHUSDT", Exchange: "BINANCE_FUTURES"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "USDT", currency)
+
+	_, err = portfolioCurrency([]dto.StockInfo{{StockCode: "BBCA", Exchange: "IDX"}, {StockCode: "BTCUSDT", Exchange: "BINANCE"}, {StockCode: "AAPL", Exchange: "NASDAQ"}})
+	assert.ErrorIs(t, err, ErrMixedCurrencyPortfolio)
+	assert.ErrorContains(t, err, "IDR (IDX) and USDT (BINANCE) and USD (NASDAQ)")
+}
+
+func TestRunPortfolioBacktestRejectsMixedCurrency(t *testing.T) {
+	s := &backtestService{}
+	_, err := s.RunPortfolioBacktest(context.Background(), dto.PortfolioBacktestRequest{
+		Symbols: []dto.StockInfo{{StockCode: "BBCA", Exchange: "IDX"}, {StockCode: "BTCUSDT", Exchange: "BINANCE"}},
+	})
+	assert.ErrorIs(t, err, ErrMixedCurrencyPortfolio)
+}
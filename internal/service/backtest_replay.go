@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-trading/internal/costmodel"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/indicator"
+	"golang-trading/internal/model"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	// jumlah bar maksimal yang dipakai untuk menghitung indikator di setiap langkah replay
+	replayMaxIndicatorBars = indicator.MinCandles + 100
+)
+
+type backtestModeCtxKey struct{}
+
+// withBacktestMode menandai context sebagai simulasi, harga terakhir dari cache (harga live) tidak dipakai
+func withBacktestMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backtestModeCtxKey{}, true)
+}
+
+func isBacktestMode(ctx context.Context) bool {
+	v, _ := ctx.Value(backtestModeCtxKey{}).(bool)
+	return v
+}
+
+// replaySeries candle satu timeframe yang dipakai saat replay
+type replaySeries struct {
+	tf       dto.DataTimeframe
+	duration time.Duration
+	candles  []dto.StockOHLCV
+	times    []time.Time // open time setiap candle
+}
+
+// replaySymbol state replay satu simbol: candle semua timeframe, posisi terbuka dan entry yang pending
+type replaySymbol struct {
+	stock        dto.StockInfo
+	cost         costmodel.Model
+	series       []*replaySeries
+	main         *replaySeries
+	position     *model.StockPosition
+	quantity     float64
+	pendingPlan  *dto.TradePlanResult
+	pendingClose float64
+
+	// analyses cache hasil buildReplayAnalyses per open time bar (nil = tanpa cache). Analisis tidak bergantung
+	// pada parameter trade plan, sehingga bisa dipakai ulang saat replay diulang untuk optimasi parameter
+	analyses map[time.Time][]model.StockAnalysis
+}
+
+// runCandleReplay menjalankan backtest bar-by-bar di atas candle timeframe utama.
+// Di setiap bar, StockAnalysis dibangun ulang hanya dari candle yang sudah close (tanpa look-ahead).
+//
+// Urutan per bar:
+//  1. entry yang pending dari bar sebelumnya di-fill di bar ini (market di open, limit jika low menyentuh entry)
+//  2. SL/TP dicek terhadap high/low bar, gap melewati level di-fill di harga open
+//  3. saat close bar, posisi dievaluasi (EvaluatePositionMonitoring) atau trade plan baru dibuat (CreateTradePlan)
+func (s *backtestService) runCandleReplay(ctx context.Context, req dto.BacktestRequest) (*dto.BacktestResult, error) {
+	stock := dto.StockInfo{StockCode: req.StockCode, Exchange: req.Exchange}
+	timeframes, err := s.getReplayTimeframes(ctx, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := s.loadReplaySymbol(ctx, stock, timeframes, req.StartDate, req.EndDate, req.AdjustDividends)
+	if err != nil {
+		return nil, err
+	}
+
+	tradeLogs, marks, err := s.replaySymbolRange(ctx, rs, req.StartDate, req.EndDate, req.FillPriority)
+	if err != nil {
+		return nil, err
+	}
+
+	result := calculateBacktestResult(req, tradeLogs, marks)
+
+	s.log.InfoContext(ctx, "Candle replay backtest completed", logger.StringField("stock_code", req.StockCode), logger.IntField("total_trades", result.TotalTrades))
+	return result, nil
+}
+
+// replaySymbolRange menjalankan replay satu simbol untuk bar dengan open time di [startDate, endDate].
+// State posisi direset di awal, sehingga replaySymbol yang sama bisa dipakai berulang kali.
+func (s *backtestService) replaySymbolRange(ctx context.Context, rs *replaySymbol, startDate, endDate time.Time, fillPriority string) ([]dto.TradeLog, []pricePoint, error) {
+	if fillPriority == "" {
+		fillPriority = dto.FillPriorityStopLoss
+	}
+	rs.position, rs.quantity, rs.pendingPlan = nil, 0, nil
+
+	var (
+		tradeLogs   []dto.TradeLog
+		marks       []pricePoint
+		lastBar     *dto.StockOHLCV
+		lastBarTime time.Time
+	)
+
+	for i, bar := range rs.main.candles {
+		if !utils.ShouldContinue(ctx, s.log) {
+			return nil, nil, ctx.Err()
+		}
+
+		barTime := rs.main.times[i]
+		if barTime.Before(startDate) {
+			continue
+		}
+		if barTime.After(endDate) {
+			break
+		}
+		lastBar, lastBarTime = &rs.main.candles[i], barTime
+		marks = append(marks, pricePoint{time: barTime, close: bar.Close})
+
+		// 1. Fill entry yang pending
+		if rs.position == nil && rs.pendingPlan != nil {
+			if fillPrice, ok := fillReplayEntry(rs.pendingPlan.Side, rs.pendingPlan.Entry, rs.pendingClose, bar); ok {
+				rs.openPosition(rs.cost.EntryFillPrice(fillPrice, dto.IsShort(rs.pendingPlan.Side)), 1, barTime)
+			}
+			rs.pendingPlan = nil
+		}
+
+		// 2. Cek SL / TP terhadap high-low bar
+		if trade := rs.checkExit(bar, barTime, fillPriority); trade != nil {
+			tradeLogs = append(tradeLogs, *trade)
+			continue
+		}
+
+		// 3. Evaluasi saat bar close
+		if trade := s.evaluateReplayClose(ctx, rs, bar, barTime); trade != nil {
+			tradeLogs = append(tradeLogs, *trade)
+		}
+	}
+
+	if rs.position != nil && lastBar != nil {
+		tradeLogs = append(tradeLogs, rs.closePosition(lastBarTime, lastBar.Close, "End of Backtest"))
+	}
+	rs.pendingPlan = nil
+
+	return tradeLogs, marks, nil
+}
+
+// getReplayTimeframes timeframe profil analisa saham, sama seperti analisa live
+func (s *backtestService) getReplayTimeframes(ctx context.Context, stock dto.StockInfo) ([]dto.DataTimeframe, error) {
+	profile, err := s.timeframeProfileRepo.Resolve(ctx, stock.Exchange, stock.StockCode)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get analysis timeframes for backtest", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
+		return nil, err
+	}
+
+	if len(profile.Timeframes) == 0 {
+		return nil, fmt.Errorf("no analysis timeframes configured")
+	}
+	return profile.Timeframes, nil
+}
+
+// loadReplaySymbol mengambil candle semua timeframe untuk satu simbol, timeframe utama (IsMain) menjadi langkah replay
+func (s *backtestService) loadReplaySymbol(ctx context.Context, stock dto.StockInfo, timeframes []dto.DataTimeframe, startDate, endDate time.Time, adjusted bool) (*replaySymbol, error) {
+	mainTF := timeframes[0]
+	for _, tf := range timeframes {
+		if tf.IsMain {
+			mainTF = tf
+			break
+		}
+	}
+
+	rs := &replaySymbol{stock: stock, cost: costmodel.ForExchange(stock.Exchange)}
+	for _, tf := range timeframes {
+		series, err := s.loadReplaySeries(ctx, stock, tf, startDate, endDate, adjusted)
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to load candles for backtest", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode), logger.StringField("interval", tf.Interval))
+			return nil, err
+		}
+		rs.series = append(rs.series, series)
+		if tf.Interval == mainTF.Interval {
+			rs.main = series
+		}
+	}
+	return rs, nil
+}
+
+// loadReplaySeries mengambil candle dari startDate (dikurangi warmup indikator) sampai endDate
+func (s *backtestService) loadReplaySeries(ctx context.Context, stock dto.StockInfo, tf dto.DataTimeframe, startDate, endDate time.Time, adjusted bool) (*replaySeries, error) {
+	duration := utils.IntervalToDuration(tf.Interval)
+	if duration == 0 {
+		return nil, fmt.Errorf("unsupported interval: %s", tf.Interval)
+	}
+
+	stockData, err := s.candleRepo.Get(ctx, dto.GetStockDataParam{
+		StockCode: stock.StockCode,
+		Exchange:  stock.Exchange,
+		Interval:  tf.Interval,
+		StartTime: startDate.Add(-replayWarmup(duration)).Unix(),
+		EndTime:   endDate.Add(duration).Unix(),
+		Adjusted:  adjusted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &replaySeries{
+		tf:       tf,
+		duration: duration,
+		candles:  stockData.OHLCV,
+		times:    make([]time.Time, len(stockData.OHLCV)),
+	}
+	for i, c := range stockData.OHLCV {
+		rs.times[i] = utils.CandleTimestampToTime(stock.Exchange, c.Timestamp)
+	}
+	return rs, nil
+}
+
+// evaluateReplayClose evaluasi saat bar close: posisi terbuka dievaluasi dengan EvaluatePositionMonitoring
+// (exit di harga close), jika tidak ada posisi trade plan baru dibuat dan disimpan sebagai entry pending
+func (s *backtestService) evaluateReplayClose(ctx context.Context, rs *replaySymbol, bar dto.StockOHLCV, barTime time.Time) *dto.TradeLog {
+	analyses, cached := rs.analyses[barTime]
+	if !cached {
+		var err error
+		analyses, err = s.buildReplayAnalyses(rs.stock, rs.series, barTime.Add(rs.main.duration), bar.Close)
+		if err != nil {
+			s.log.WarnContext(ctx, "Failed to build analyses during backtest, skipping bar", logger.ErrorField(err), logger.StringField("stock_code", rs.stock.StockCode), logger.StringField("date", barTime.String()))
+		}
+		if rs.analyses != nil {
+			// bar yang gagal juga di-cache (nil) supaya tidak dihitung ulang
+			rs.analyses[barTime] = analyses
+		}
+	}
+	if analyses == nil {
+		return nil
+	}
+
+	if rs.position != nil {
+		supports, resistances, err := s.tradingService.CalculateSupportResistance(ctx, analyses)
+		if err != nil {
+			s.log.WarnContext(ctx, "Failed to calculate S/R during backtest, skipping bar", logger.ErrorField(err), logger.StringField("stock_code", rs.stock.StockCode), logger.StringField("date", barTime.String()))
+			return nil
+		}
+
+		posAnalysis, err := s.tradingService.EvaluatePositionMonitoring(ctx, rs.position, analyses, supports, resistances)
+		if err != nil {
+			s.log.WarnContext(ctx, "Failed to evaluate position during backtest, skipping bar", logger.ErrorField(err), logger.StringField("stock_code", rs.stock.StockCode), logger.StringField("date", barTime.String()))
+			return nil
+		}
+
+		if posAnalysis.Signal == dto.CutLoss || posAnalysis.Signal == dto.TakeProfit || posAnalysis.Signal == dto.TimeStop {
+			trade := rs.closePosition(barTime, bar.Close, string(posAnalysis.Signal))
+			return &trade
+		}
+
+		if posAnalysis.Signal == dto.TrailingStop && dto.IsMoreFavorable(rs.position.Side, posAnalysis.TrailingStopPrice, rs.position.StopLossPrice) {
+			rs.position.StopLossPrice = posAnalysis.TrailingStopPrice
+		}
+		rs.position.TrailingProfitPrice = posAnalysis.TrailingProfitPrice
+		rs.position.HighestPriceSinceTTP = posAnalysis.HighestPriceSinceTTP
+		return nil
+	}
+
+	tradePlan, err := s.tradingService.CreateTradePlan(ctx, analyses)
+	if err != nil {
+		s.log.WarnContext(ctx, "Failed to create trade plan during backtest, skipping bar", logger.ErrorField(err), logger.StringField("stock_code", rs.stock.StockCode), logger.StringField("date", barTime.String()))
+		return nil
+	}
+
+	if isBacktestEntry(tradePlan) {
+		rs.pendingPlan = tradePlan
+		rs.pendingClose = bar.Close
+	}
+	return nil
+}
+
+func (rs *replaySymbol) openPosition(price, quantity float64, barTime time.Time) {
+	rs.position = &model.StockPosition{
+		StockCode:       rs.stock.StockCode,
+		Exchange:        rs.stock.Exchange,
+		BuyPrice:        price,
+		TakeProfitPrice: rs.pendingPlan.TakeProfit,
+		StopLossPrice:   rs.pendingPlan.StopLoss,
+		Side:            rs.pendingPlan.Side,
+		BuyDate:         barTime,
+	}
+	rs.quantity = quantity
+}
+
+func (rs *replaySymbol) checkExit(bar dto.StockOHLCV, barTime time.Time, fillPriority string) *dto.TradeLog {
+	if rs.position == nil {
+		return nil
+	}
+
+	exitPrice, reason, ok := fillReplayExit(rs.position, bar, fillPriority)
+	if !ok {
+		return nil
+	}
+
+	trade := rs.closePosition(barTime, exitPrice, reason)
+	return &trade
+}
+
+func (rs *replaySymbol) closePosition(exitDate time.Time, exitPrice float64, reason string) dto.TradeLog {
+	trade := closePosition(rs.position, exitDate, exitPrice, reason)
+	trade.Exchange = rs.stock.Exchange
+	trade.Quantity = rs.quantity
+	trade.ProfitLossAmount = trade.ProfitLoss * rs.quantity
+
+	rs.position = nil
+	rs.quantity = 0
+	return trade
+}
+
+// barIndexAt index candle timeframe utama dengan open time t, -1 jika simbol tidak punya bar di t
+func (rs *replaySymbol) barIndexAt(t time.Time) int {
+	i := sort.Search(len(rs.main.times), func(i int) bool {
+		return !rs.main.times[i].Before(t)
+	})
+	if i < len(rs.main.times) && rs.main.times[i].Equal(t) {
+		return i
+	}
+	return -1
+}
+
+// replayWarmup durasi tambahan sebelum StartDate supaya indikator sudah terisi di bar pertama,
+// dilebihkan untuk weekend / libur dan jam bursa yang tidak 24 jam
+func replayWarmup(duration time.Duration) time.Duration {
+	if duration >= 24*time.Hour {
+		return duration * indicator.MinCandles * 3 / 2
+	}
+	return duration * indicator.MinCandles * 5
+}
+
+// buildReplayAnalyses membangun StockAnalysis setiap timeframe hanya dari candle yang sudah close sebelum closeTime
+func (s *backtestService) buildReplayAnalyses(stock dto.StockInfo, series []*replaySeries, closeTime time.Time, marketPrice float64) ([]model.StockAnalysis, error) {
+	analyses := make([]model.StockAnalysis, 0, len(series))
+	for _, rs := range series {
+		// jumlah candle yang open time + durasi <= closeTime
+		completed := sort.Search(len(rs.times), func(i int) bool {
+			return rs.times[i].Add(rs.duration).After(closeTime)
+		})
+		if completed < 2 {
+			continue
+		}
+
+		window := rs.candles[max(0, completed-replayMaxIndicatorBars):completed]
+		technicalData, err := indicator.Compute(window, rs.tf.Interval)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonTechnical, err := json.Marshal(technicalData)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonOHLCV, err := json.Marshal(trimReplayOHLCV(stock.Exchange, window, rs.tf.Range, closeTime))
+		if err != nil {
+			return nil, err
+		}
+
+		analyses = append(analyses, model.StockAnalysis{
+			StockCode:      stock.StockCode,
+			Exchange:       stock.Exchange,
+			Timeframe:      rs.tf.Interval,
+			Timestamp:      closeTime,
+			MarketPrice:    marketPrice,
+			OHLCV:          datatypes.JSON(jsonOHLCV),
+			TechnicalData:  datatypes.JSON(jsonTechnical),
+			Recommendation: dto.MapTradingViewScreenerRecommend(technicalData.Recommend.Global.Summary),
+		})
+	}
+
+	if len(analyses) == 0 {
+		return nil, fmt.Errorf("not enough candles before %s", closeTime)
+	}
+	return analyses, nil
+}
+
+// trimReplayOHLCV memotong candle sesuai range timeframe, dihitung mundur dari closeTime
+func trimReplayOHLCV(exchange string, candles []dto.StockOHLCV, dataRange string, closeTime time.Time) []dto.StockOHLCV {
+	start, end := utils.MapPeriodeStringToUnix(dataRange)
+	if start == 0 || end == 0 {
+		return candles
+	}
+
+	from := closeTime.Add(-time.Duration(end-start) * time.Second)
+	for i, c := range candles {
+		if !utils.CandleTimestampToTime(exchange, c.Timestamp).Before(from) {
+			return candles[i:]
+		}
+	}
+	return candles
+}
+
+// fillReplayEntry entry >= close saat sinyal dianggap market order (fill di open),
+// selain itu limit order yang hanya berlaku di bar berikutnya. Untuk SHORT semua arah dicerminkan
+// (entry <= close market order, limit terisi jika high menyentuh entry).
+func fillReplayEntry(side string, entry, signalClose float64, bar dto.StockOHLCV) (float64, bool) {
+	if entry <= 0 {
+		return 0, false
+	}
+
+	direction := dto.SideDirection(side)
+	if (entry-signalClose)*direction >= 0 || (bar.Open-entry)*direction <= 0 {
+		return bar.Open, true
+	}
+
+	if (!dto.IsShort(side) && bar.Low <= entry) || (dto.IsShort(side) && bar.High >= entry) {
+		return entry, true
+	}
+
+	return 0, false
+}
+
+// fillReplayExit cek SL / TP terhadap high-low bar. Jika bar gap melewati level, fill di harga open.
+// Jika SL dan TP sama-sama tersentuh di bar yang sama, urutan ditentukan oleh fillPriority.
+// Saat mode trailing take profit aktif, TrailingProfitPrice menjadi stop dan TP tidak lagi dipakai.
+func fillReplayExit(pos *model.StockPosition, bar dto.StockOHLCV, fillPriority string) (float64, string, bool) {
+	side := pos.Side
+	stopLoss := 0.0
+	for _, stop := range []float64{pos.StopLossPrice, pos.TrailingStopPrice, pos.TrailingProfitPrice} {
+		if dto.IsMoreFavorable(side, stop, stopLoss) {
+			stopLoss = stop
+		}
+	}
+	takeProfit := pos.TakeProfitPrice
+	if pos.TrailingProfitPrice > 0 {
+		takeProfit = 0
+	}
+
+	if dto.IsStopReached(side, bar.Open, stopLoss) {
+		return bar.Open, "Stop Loss Hit (Gap)", true
+	}
+	if dto.IsTargetReached(side, bar.Open, takeProfit) {
+		return bar.Open, "Take Profit Hit (Gap)", true
+	}
+
+	// harga terburuk & terbaik bar untuk arah posisi
+	adverse, favorable := bar.Low, bar.High
+	if dto.IsShort(side) {
+		adverse, favorable = bar.High, bar.Low
+	}
+	slHit := dto.IsStopReached(side, adverse, stopLoss)
+	tpHit := dto.IsTargetReached(side, favorable, takeProfit)
+
+	switch {
+	case slHit && tpHit:
+		switch fillPriority {
+		case dto.FillPriorityTakeProfit:
+			return takeProfit, "Take Profit Hit", true
+		case dto.FillPriorityNearestOpen:
+			if math.Abs(takeProfit-bar.Open) < math.Abs(bar.Open-stopLoss) {
+				return takeProfit, "Take Profit Hit", true
+			}
+		}
+		return stopLoss, "Stop Loss Hit", true
+	case slHit:
+		return stopLoss, "Stop Loss Hit", true
+	case tpHit:
+		return takeProfit, "Take Profit Hit", true
+	}
+
+	return 0, "", false
+}
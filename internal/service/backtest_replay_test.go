@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillReplayExit(t *testing.T) {
+	pos := &model.StockPosition{BuyPrice: 100, StopLossPrice: 95, TakeProfitPrice: 110}
+
+	tests := []struct {
+		name         string
+		bar          dto.StockOHLCV
+		fillPriority string
+		wantPrice    float64
+		wantReason   string
+		wantOk       bool
+	}{
+		{"no touch", dto.StockOHLCV{Open: 100, High: 105, Low: 97, Close: 101}, dto.FillPriorityStopLoss, 0, "", false},
+		{"stop loss", dto.StockOHLCV{Open: 100, High: 105, Low: 94, Close: 96}, dto.FillPriorityStopLoss, 95, "Stop Loss Hit", true},
+		{"take profit", dto.StockOHLCV{Open: 100, High: 111, Low: 99, Close: 108}, dto.FillPriorityStopLoss, 110, "Take Profit Hit", true},
+		{"gap down", dto.StockOHLCV{Open: 90, High: 92, Low: 88, Close: 91}, dto.FillPriorityStopLoss, 90, "Stop Loss Hit (Gap)", true},
+		{"gap up", dto.StockOHLCV{Open: 112, High: 115, Low: 111, Close: 114}, dto.FillPriorityStopLoss, 112, "Take Profit Hit (Gap)", true},
+		{"both hit sl first", dto.StockOHLCV{Open: 108, High: 111, Low: 94, Close: 100}, dto.FillPriorityStopLoss, 95, "Stop Loss Hit", true},
+		{"both hit tp first", dto.StockOHLCV{Open: 96, High: 111, Low: 94, Close: 100}, dto.FillPriorityTakeProfit, 110, "Take Profit Hit", true},
+		{"both hit nearest open", dto.StockOHLCV{Open: 108, High: 111, Low: 94, Close: 100}, dto.FillPriorityNearestOpen, 110, "Take Profit Hit", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, reason, ok := fillReplayExit(pos, tt.bar, tt.fillPriority)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantPrice, price)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestFillReplayExitShort(t *testing.T) {
+	pos := &model.StockPosition{BuyPrice: 100, StopLossPrice: 105, TakeProfitPrice: 90, Side: model.PositionSideShort}
+
+	tests := []struct {
+		name       string
+		bar        dto.StockOHLCV
+		wantPrice  float64
+		wantReason string
+		wantOk     bool
+	}{
+		{"no touch", dto.StockOHLCV{Open: 100, High: 103, Low: 95, Close: 99}, 0, "", false},
+		{"stop loss", dto.StockOHLCV{Open: 100, High: 106, Low: 98, Close: 104}, 105, "Stop Loss Hit", true},
+		{"take profit", dto.StockOHLCV{Open: 100, High: 101, Low: 89, Close: 92}, 90, "Take Profit Hit", true},
+		{"gap up", dto.StockOHLCV{Open: 108, High: 110, Low: 107, Close: 109}, 108, "Stop Loss Hit (Gap)", true},
+		{"gap down", dto.StockOHLCV{Open: 88, High: 89, Low: 85, Close: 86}, 88, "Take Profit Hit (Gap)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, reason, ok := fillReplayExit(pos, tt.bar, dto.FillPriorityStopLoss)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantPrice, price)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+
+	// trailing stop SHORT berada di bawah SL awal
+	trailing := *pos
+	trailing.TrailingStopPrice = 100
+	price, reason, ok := fillReplayExit(&trailing, dto.StockOHLCV{Open: 98, High: 101, Low: 97, Close: 99}, dto.FillPriorityStopLoss)
+	assert.True(t, ok)
+	assert.Equal(t, 100.0, price)
+	assert.Equal(t, "Stop Loss Hit", reason)
+}
+
+func TestFillReplayEntry(t *testing.T) {
+	// market order: entry >= close saat sinyal
+	price, ok := fillReplayEntry(model.PositionSideLong, 100, 99, dto.StockOHLCV{Open: 101, High: 102, Low: 100})
+	assert.True(t, ok)
+	assert.Equal(t, 101.0, price)
+
+	// limit order tersentuh
+	price, ok = fillReplayEntry(model.PositionSideLong, 95, 100, dto.StockOHLCV{Open: 99, High: 101, Low: 94})
+	assert.True(t, ok)
+	assert.Equal(t, 95.0, price)
+
+	// limit order tidak tersentuh
+	_, ok = fillReplayEntry(model.PositionSideLong, 95, 100, dto.StockOHLCV{Open: 99, High: 101, Low: 96})
+	assert.False(t, ok)
+}
+
+func TestFillReplayEntryShort(t *testing.T) {
+	// market order: entry <= close saat sinyal
+	price, ok := fillReplayEntry(model.PositionSideShort, 100, 101, dto.StockOHLCV{Open: 99, High: 100, Low: 98})
+	assert.True(t, ok)
+	assert.Equal(t, 99.0, price)
+
+	// limit order di atas harga tersentuh
+	price, ok = fillReplayEntry(model.PositionSideShort, 105, 100, dto.StockOHLCV{Open: 101, High: 106, Low: 99})
+	assert.True(t, ok)
+	assert.Equal(t, 105.0, price)
+
+	// limit order tidak tersentuh
+	_, ok = fillReplayEntry(model.PositionSideShort, 105, 100, dto.StockOHLCV{Open: 101, High: 104, Low: 99})
+	assert.False(t, ok)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/internal/repository"
@@ -14,12 +15,18 @@ import (
 // BacktestService mendefinisikan interface untuk layanan backtesting.
 type BacktestService interface {
 	RunBacktest(ctx context.Context, req dto.BacktestRequest) (*dto.BacktestResult, error)
+	RunPortfolioBacktest(ctx context.Context, req dto.PortfolioBacktestRequest) (*dto.PortfolioBacktestResult, error)
+	RunWalkForward(ctx context.Context, req dto.WalkForwardRequest) (*dto.WalkForwardResult, error)
 }
 
 type backtestService struct {
-	log               *logger.Logger
-	tradingService    TradingService
-	stockAnalysisRepo repository.StockAnalysisRepository
+	log                      *logger.Logger
+	tradingService           TradingService
+	stockAnalysisRepo        repository.StockAnalysisRepository
+	candleRepo               repository.CandleRepository
+	systemParamRepo          repository.SystemParamRepository
+	timeframeProfileRepo     repository.TimeframeProfileRepository
+	tradingViewScreenersRepo repository.TradingViewScreenersRepository
 }
 
 // NewBacktestService membuat instance baru dari backtestService.
@@ -27,16 +34,38 @@ func NewBacktestService(
 	log *logger.Logger,
 	tradingService TradingService,
 	stockAnalysisRepo repository.StockAnalysisRepository,
+	candleRepo repository.CandleRepository,
+	systemParamRepo repository.SystemParamRepository,
+	timeframeProfileRepo repository.TimeframeProfileRepository,
+	tradingViewScreenersRepo repository.TradingViewScreenersRepository,
 ) BacktestService {
 	return &backtestService{
-		log:               log,
-		tradingService:    tradingService,
-		stockAnalysisRepo: stockAnalysisRepo,
+		log:                      log,
+		tradingService:           tradingService,
+		stockAnalysisRepo:        stockAnalysisRepo,
+		candleRepo:               candleRepo,
+		systemParamRepo:          systemParamRepo,
+		timeframeProfileRepo:     timeframeProfileRepo,
+		tradingViewScreenersRepo: tradingViewScreenersRepo,
 	}
 }
 
 // RunBacktest menjalankan simulasi trading berdasarkan data historis.
 func (s *backtestService) RunBacktest(ctx context.Context, req dto.BacktestRequest) (*dto.BacktestResult, error) {
+	ctx = withBacktestMode(ctx)
+	ctx = WithTradeSide(ctx, req.Side)
+
+	if req.TradePlanParams != nil {
+		if err := req.TradePlanParams.Validate(); err != nil {
+			return nil, err
+		}
+		ctx = withTradePlanParams(ctx, *req.TradePlanParams)
+	}
+
+	if req.Mode == dto.BacktestModeCandleReplay {
+		return s.runCandleReplay(ctx, req)
+	}
+
 	// 1. Ambil semua data analisis historis untuk rentang waktu yang diberikan.
 	historicalData, err := s.stockAnalysisRepo.GetHistoricalAnalyses(ctx, req.StockCode, req.Exchange, req.StartDate, req.EndDate)
 	if err != nil {
@@ -68,6 +97,7 @@ func (s *backtestService) RunBacktest(ctx context.Context, req dto.BacktestReque
 
 	var currentPosition *model.StockPosition
 	var tradeLogs []dto.TradeLog
+	var marks []pricePoint
 
 	// 3. Iterasi melalui setiap hari dalam data historis
 	for _, day := range sortedDays {
@@ -78,16 +108,17 @@ func (s *backtestService) RunBacktest(ctx context.Context, req dto.BacktestReque
 
 		// Ambil harga pasar dari data analisis terakhir pada hari itu
 		marketPrice := analysesForDay[len(analysesForDay)-1].MarketPrice
+		marks = append(marks, pricePoint{time: day, close: marketPrice})
 
 		// Jika ada posisi yang sedang terbuka
 		if currentPosition != nil {
 			// Cek apakah harga menyentuh SL atau TP
-			if marketPrice <= currentPosition.StopLossPrice {
+			if dto.IsStopReached(currentPosition.Side, marketPrice, currentPosition.StopLossPrice) {
 				tradeLogs = append(tradeLogs, closePosition(currentPosition, day, marketPrice, "Stop Loss Hit"))
 				currentPosition = nil
 				continue // Lanjut ke hari berikutnya setelah menutup posisi
 			}
-			if marketPrice >= currentPosition.TakeProfitPrice {
+			if dto.IsTargetReached(currentPosition.Side, marketPrice, currentPosition.TakeProfitPrice) {
 				tradeLogs = append(tradeLogs, closePosition(currentPosition, day, marketPrice, "Take Profit Hit"))
 				currentPosition = nil
 				continue
@@ -108,11 +139,11 @@ func (s *backtestService) RunBacktest(ctx context.Context, req dto.BacktestReque
 			}
 
 			// Cek sinyal untuk keluar dari posisi
-			if posAnalysis.Signal == dto.CutLoss || posAnalysis.Signal == dto.TakeProfit {
+			if posAnalysis.Signal == dto.CutLoss || posAnalysis.Signal == dto.TakeProfit || posAnalysis.Signal == dto.TimeStop {
 				tradeLogs = append(tradeLogs, closePosition(currentPosition, day, marketPrice, string(posAnalysis.Signal)))
 				currentPosition = nil
 				continue
-			} else if posAnalysis.Signal == dto.TrailingStop && posAnalysis.TrailingStopPrice > currentPosition.StopLossPrice {
+			} else if posAnalysis.Signal == dto.TrailingStop && dto.IsMoreFavorable(currentPosition.Side, posAnalysis.TrailingStopPrice, currentPosition.StopLossPrice) {
 				// Update trailing stop loss
 				currentPosition.StopLossPrice = posAnalysis.TrailingStopPrice
 			}
@@ -125,47 +156,70 @@ func (s *backtestService) RunBacktest(ctx context.Context, req dto.BacktestReque
 				continue
 			}
 
-			if tradePlan != nil && tradePlan.IsBuySignal && tradePlan.Score >= 50 { // Entry threshold
+			if isBacktestEntry(tradePlan) {
 				currentPosition = &model.StockPosition{
 					StockCode:       req.StockCode,
 					Exchange:        req.Exchange,
-					BuyPrice:        tradePlan.Entry,
+					BuyPrice:        costmodel.ForExchange(req.Exchange).EntryFillPrice(tradePlan.Entry, dto.IsShort(tradePlan.Side)),
 					TakeProfitPrice: tradePlan.TakeProfit,
 					StopLossPrice:   tradePlan.StopLoss,
+					Side:            tradePlan.Side,
 					BuyDate:         day,
 				}
 			}
 		}
 	}
 
-	result := calculateBacktestResult(req, tradeLogs)
+	result := calculateBacktestResult(req, tradeLogs, marks)
 
 	s.log.InfoContext(ctx, "Backtest simulation completed", logger.StringField("stock_code", req.StockCode), logger.IntField("total_trades", result.TotalTrades))
 	return result, nil
 }
 
+// isBacktestEntry trade plan dianggap sinyal entry jika buy signal dan memenuhi ambang skor & risk reward
+// dari parameter yang dipakai membentuk plan tersebut
+func isBacktestEntry(tradePlan *dto.TradePlanResult) bool {
+	return tradePlan != nil &&
+		tradePlan.IsBuySignal &&
+		tradePlan.Score >= tradePlan.Params.BuySignalScore &&
+		tradePlan.RiskReward >= tradePlan.Params.BuySignalRiskReward
+}
+
 // closePosition adalah helper untuk menutup posisi dan membuat log transaksi.
+// Harga exit dikenai slippage dan P/L dihitung bersih setelah fee sesuai model biaya exchange & arah posisi.
 func closePosition(pos *model.StockPosition, exitDate time.Time, exitPrice float64, reason string) dto.TradeLog {
-	pl := exitPrice - pos.BuyPrice
+	cost := costmodel.ForExchange(pos.Exchange)
+	short := dto.IsShort(pos.Side)
+	exitPrice = cost.ExitFillPrice(exitPrice, short)
+	pl := cost.PositionProfitLoss(pos.BuyPrice, exitPrice, short)
+	plPercent := cost.PositionProfitLossPercent(pos.BuyPrice, exitPrice, short)
+	fees := pos.BuyPrice*cost.BuyFeePercent/100 + exitPrice*cost.SellFeePercent/100
+	if short {
+		fees = pos.BuyPrice*cost.SellFeePercent/100 + exitPrice*cost.BuyFeePercent/100
+	}
 	holdingDays := int(exitDate.Sub(pos.BuyDate).Hours() / 24)
 	if holdingDays == 0 {
 		holdingDays = 1
 	}
 
 	return dto.TradeLog{
-		Symbol:        pos.StockCode,
-		EntryDate:     pos.BuyDate,
-		EntryPrice:    pos.BuyPrice,
-		ExitDate:      exitDate,
-		ExitPrice:     exitPrice,
-		ExitReason:    reason,
-		ProfitLoss:    pl,
-		HoldingPeriod: holdingDays,
+		Symbol:            pos.StockCode,
+		Side:              dto.NormalizeSide(pos.Side),
+		EntryDate:         pos.BuyDate,
+		EntryPrice:        pos.BuyPrice,
+		ExitDate:          exitDate,
+		ExitPrice:         exitPrice,
+		ExitReason:        reason,
+		ProfitLoss:        pl,
+		ProfitLossPercent: plPercent,
+		Fees:              fees,
+		HoldingPeriod:     holdingDays,
 	}
 }
 
 // calculateBacktestResult menghitung semua metrik kinerja dari log perdagangan.
-func calculateBacktestResult(req dto.BacktestRequest, trades []dto.TradeLog) *dto.BacktestResult {
+// marks adalah harga close setiap bar / hari backtest untuk equity curve harian.
+func calculateBacktestResult(req dto.BacktestRequest, trades []dto.TradeLog, marks []pricePoint) *dto.BacktestResult {
 	result := &dto.BacktestResult{
 		StockCode: req.StockCode,
 		StartDate: req.StartDate,
@@ -177,7 +231,10 @@ func calculateBacktestResult(req dto.BacktestRequest, trades []dto.TradeLog) *dt
 		return result
 	}
 
-	var totalHoldingPeriod int
+	var (
+		totalHoldingPeriod        int
+		totalWinPct, totalLossPct float64
+	)
 	for _, trade := range trades {
 		result.TotalTrades++
 		result.TotalProfitLoss += trade.ProfitLoss
@@ -186,22 +243,38 @@ func calculateBacktestResult(req dto.BacktestRequest, trades []dto.TradeLog) *dt
 		if trade.ProfitLoss > 0 {
 			result.WinningTrades++
 			result.TotalProfit += trade.ProfitLoss
+			totalWinPct += trade.ProfitLossPercent
 		} else {
 			result.LosingTrades++
 			result.TotalLoss += trade.ProfitLoss // Loss is negative
+			totalLossPct += trade.ProfitLossPercent
 		}
 	}
 
-	if result.TotalTrades > 0 {
-		result.WinRate = (float64(result.WinningTrades) / float64(result.TotalTrades)) * 100
-		result.AvgHoldingPeriod = float64(totalHoldingPeriod) / float64(result.TotalTrades)
+	result.WinRate = (float64(result.WinningTrades) / float64(result.TotalTrades)) * 100
+	result.AvgHoldingPeriod = float64(totalHoldingPeriod) / float64(result.TotalTrades)
+
+	if result.WinningTrades > 0 {
+		result.AvgWinPercent = totalWinPct / float64(result.WinningTrades)
 	}
+	if result.LosingTrades > 0 {
+		result.AvgLossPercent = totalLossPct / float64(result.LosingTrades)
+	}
+	result.Expectancy = result.WinRate/100*result.AvgWinPercent + (1-result.WinRate/100)*result.AvgLossPercent
 
-	if result.TotalLoss != 0 {
-		result.ProfitFactor = result.TotalProfit / -result.TotalLoss
+	if totalLossPct != 0 {
+		result.ProfitFactor = totalWinPct / -totalLossPct
 	}
 
-	// TODO: Implement MaxDrawdown calculation
+	result.LongestLosingStreak = longestLosingStreak(trades)
+
+	result.EquityCurve = buildTradeEquityCurve(trades, marks)
+	if n := len(result.EquityCurve); n > 0 {
+		result.TotalReturnPercent = (result.EquityCurve[n-1].Equity/equityCurveBase - 1) * 100
+	}
+	result.MaxDrawdown, result.MaxDrawdownPeakDate, result.MaxDrawdownTroughDate = calculateMaxDrawdown(result.EquityCurve)
+	result.SharpeRatio, result.SortinoRatio = calculateRiskRatios(result.EquityCurve, periodsPerYear(req.Exchange))
+	result.ExposurePercent = exposurePercent(result.EquityCurve)
 
 	return result
 }
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/repository"
+	"golang-trading/internal/strategy"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+	"slices"
+
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrJobNotFound           = errors.New("job not found")
+	ErrTaskScheduleNotFound  = errors.New("task schedule not found")
+	ErrInvalidJobRequest     = errors.New("invalid job request")
+	ErrJobDependencyNotFound = errors.New("job dependency not found")
+)
+
+// JobService kelola job & schedule scheduler lewat API, payload dan cron divalidasi sebelum disimpan
+type JobService interface {
+	GetJobs(ctx context.Context) ([]model.Job, error)
+	GetJob(ctx context.Context, id uint) (*model.Job, error)
+	CreateJob(ctx context.Context, req dto.JobRequest) (*model.Job, error)
+	UpdateJob(ctx context.Context, id uint, req dto.JobRequest) (*model.Job, error)
+	DeleteJob(ctx context.Context, id uint) error
+	CreateTaskSchedule(ctx context.Context, jobID uint, req dto.TaskScheduleRequest) (*model.TaskSchedule, error)
+	UpdateTaskSchedule(ctx context.Context, id uint, req dto.TaskScheduleRequest) (*model.TaskSchedule, error)
+	DeleteTaskSchedule(ctx context.Context, id uint) error
+	// PauseJob menonaktifkan semua schedule job, ResumeJob mengaktifkan kembali dengan eksekusi berikutnya dihitung dari sekarang
+	PauseJob(ctx context.Context, id uint) error
+	ResumeJob(ctx context.Context, id uint) error
+	// RunJob menjalankan job sekarang di luar jadwal, hasilnya tercatat di history
+	RunJob(ctx context.Context, id uint) error
+	GetTaskExecutionHistories(ctx context.Context, filter dto.TaskExecutionHistoryFilter) (*dto.PaginatedResponse, error)
+	// GetJobDependencies job upstream yang ditunggu job id di pipeline
+	GetJobDependencies(ctx context.Context, id uint) ([]model.JobDependency, error)
+	// AddJobDependency job id dijalankan setelah dependsOnJobID di pipeline, ditolak jika membentuk siklus
+	AddJobDependency(ctx context.Context, id uint, dependsOnJobID uint) error
+	RemoveJobDependency(ctx context.Context, id uint, dependsOnJobID uint) error
+}
+
+type jobService struct {
+	log        *logger.Logger
+	jobRepo    repository.JobRepository
+	unitOfWork repository.UnitOfWork
+	scheduler  SchedulerService
+}
+
+func NewJobService(log *logger.Logger, jobRepo repository.JobRepository, unitOfWork repository.UnitOfWork, scheduler SchedulerService) JobService {
+	return &jobService{
+		log:        log,
+		jobRepo:    jobRepo,
+		unitOfWork: unitOfWork,
+		scheduler:  scheduler,
+	}
+}
+
+func (s *jobService) GetJobs(ctx context.Context) ([]model.Job, error) {
+	jobs, err := s.jobRepo.Get(ctx, &model.GetJobParam{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	// join schedule menghasilkan satu baris per schedule
+	seen := make(map[uint]bool, len(jobs))
+	unique := make([]model.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if !seen[job.ID] {
+			seen[job.ID] = true
+			unique = append(unique, job)
+		}
+	}
+	return unique, nil
+}
+
+func (s *jobService) GetJob(ctx context.Context, id uint) (*model.Job, error) {
+	jobs, err := s.jobRepo.Get(ctx, &model.GetJobParam{IDs: []uint{id}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, ErrJobNotFound
+	}
+	return &jobs[0], nil
+}
+
+func (s *jobService) CreateJob(ctx context.Context, req dto.JobRequest) (*model.Job, error) {
+	job := &model.Job{}
+	if err := s.applyJobRequest(job, req); err != nil {
+		return nil, err
+	}
+	err := s.unitOfWork.Run(func(opts ...utils.DBOption) error {
+		if err := s.jobRepo.CreateJob(ctx, job, opts...); err != nil {
+			return fmt.Errorf("failed to create job: %w", err)
+		}
+		if req.DependsOn == nil {
+			return nil
+		}
+		return s.saveJobDependencies(ctx, job.ID, req.DependsOn, true, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.InfoContext(ctx, "Job created", logger.IntField("job_id", int(job.ID)), logger.StringField("type", job.Type))
+	return job, nil
+}
+
+func (s *jobService) UpdateJob(ctx context.Context, id uint, req dto.JobRequest) (*model.Job, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyJobRequest(job, req); err != nil {
+		return nil, err
+	}
+	err = s.unitOfWork.Run(func(opts ...utils.DBOption) error {
+		if err := s.jobRepo.UpdateJob(ctx, job, opts...); err != nil {
+			return fmt.Errorf("failed to update job: %w", err)
+		}
+		if req.DependsOn == nil {
+			return nil
+		}
+		return s.saveJobDependencies(ctx, job.ID, req.DependsOn, true, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.InfoContext(ctx, "Job updated", logger.IntField("job_id", int(job.ID)), logger.StringField("type", job.Type))
+	return job, nil
+}
+
+// applyJobRequest validasi payload terhadap payload struct strategy & retry policy seperti saat dijalankan scheduler
+func (s *jobService) applyJobRequest(job *model.Job, req dto.JobRequest) error {
+	if err := strategy.ValidateJobPayload(strategy.JobType(req.Type), req.Payload); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJobRequest, err)
+	}
+	if _, err := dto.ParseRetryPolicy(req.RetryPolicy); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJobRequest, err)
+	}
+
+	job.Name = req.Name
+	job.Description = req.Description
+	job.Type = req.Type
+	job.Payload = datatypes.JSON(req.Payload)
+	job.RetryPolicy = nil
+	if len(req.RetryPolicy) > 0 {
+		job.RetryPolicy = datatypes.JSON(req.RetryPolicy)
+	}
+	job.Timeout = req.Timeout
+	if job.Timeout == 0 {
+		job.Timeout = 60
+	}
+	return nil
+}
+
+func (s *jobService) DeleteJob(ctx context.Context, id uint) error {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return err
+	}
+	if err := s.jobRepo.DeleteJob(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	s.log.InfoContext(ctx, "Job deleted", logger.IntField("job_id", int(id)))
+	return nil
+}
+
+func (s *jobService) CreateTaskSchedule(ctx context.Context, jobID uint, req dto.TaskScheduleRequest) (*model.TaskSchedule, error) {
+	if _, err := s.GetJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	schedule := &model.TaskSchedule{JobID: jobID, IsActive: true}
+	if err := s.applyTaskScheduleRequest(ctx, schedule, req); err != nil {
+		return nil, err
+	}
+	if err := s.jobRepo.CreateTaskSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create task schedule: %w", err)
+	}
+	s.log.InfoContext(ctx, "Task schedule created",
+		logger.IntField("job_id", int(jobID)),
+		logger.IntField("schedule_id", int(schedule.ID)),
+		logger.StringField("cron_expression", schedule.CronExpression),
+	)
+	return schedule, nil
+}
+
+func (s *jobService) UpdateTaskSchedule(ctx context.Context, id uint, req dto.TaskScheduleRequest) (*model.TaskSchedule, error) {
+	schedule, err := s.findTaskSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTaskScheduleRequest(ctx, schedule, req); err != nil {
+		return nil, err
+	}
+	if err := s.jobRepo.SaveTaskSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to update task schedule: %w", err)
+	}
+	s.log.InfoContext(ctx, "Task schedule updated",
+		logger.IntField("job_id", int(schedule.JobID)),
+		logger.IntField("schedule_id", int(schedule.ID)),
+		logger.StringField("cron_expression", schedule.CronExpression),
+	)
+	return schedule, nil
+}
+
+// applyTaskScheduleRequest validasi cron dengan parser scheduler & hitung ulang eksekusi berikutnya dari sekarang
+func (s *jobService) applyTaskScheduleRequest(ctx context.Context, schedule *model.TaskSchedule, req dto.TaskScheduleRequest) error {
+	schedule.CronExpression = req.CronExpression
+	schedule.Exchange = req.Exchange
+	schedule.MarketHours = req.MarketHours
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+
+	next, err := s.scheduler.NextExecution(ctx, *schedule, utils.TimeNowWIB())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJobRequest, err)
+	}
+	schedule.NextExecution = sql.NullTime{Time: next, Valid: true}
+	return nil
+}
+
+func (s *jobService) DeleteTaskSchedule(ctx context.Context, id uint) error {
+	schedule, err := s.findTaskSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.jobRepo.DeleteTaskSchedule(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete task schedule: %w", err)
+	}
+	s.log.InfoContext(ctx, "Task schedule deleted", logger.IntField("job_id", int(schedule.JobID)), logger.IntField("schedule_id", int(id)))
+	return nil
+}
+
+func (s *jobService) PauseJob(ctx context.Context, id uint) error {
+	return s.setJobActive(ctx, id, false)
+}
+
+func (s *jobService) ResumeJob(ctx context.Context, id uint) error {
+	return s.setJobActive(ctx, id, true)
+}
+
+func (s *jobService) setJobActive(ctx context.Context, id uint, active bool) error {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(job.Schedules) == 0 {
+		return fmt.Errorf("%w: job %d has no schedule", ErrInvalidJobRequest, id)
+	}
+
+	now := utils.TimeNowWIB()
+	for _, schedule := range job.Schedules {
+		schedule.IsActive = active
+		if active {
+			// jadwal yang terlewat selama pause tidak dijalankan susulan
+			next, err := s.scheduler.NextExecution(ctx, schedule, now)
+			if err != nil {
+				return fmt.Errorf("%w: schedule %d: %v", ErrInvalidJobRequest, schedule.ID, err)
+			}
+			schedule.NextExecution = sql.NullTime{Time: next, Valid: true}
+		}
+		if err := s.jobRepo.SaveTaskSchedule(ctx, &schedule); err != nil {
+			return fmt.Errorf("failed to update task schedule: %w", err)
+		}
+	}
+	s.log.InfoContext(ctx, "Job active state changed", logger.IntField("job_id", int(id)), logger.Field("is_active", active))
+	return nil
+}
+
+func (s *jobService) RunJob(ctx context.Context, id uint) error {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(job.Schedules) == 0 {
+		return fmt.Errorf("%w: job %d has no schedule", ErrInvalidJobRequest, id)
+	}
+	return s.scheduler.RunJobTask(ctx, id)
+}
+
+func (s *jobService) GetTaskExecutionHistories(ctx context.Context, filter dto.TaskExecutionHistoryFilter) (*dto.PaginatedResponse, error) {
+	param, err := filter.ToParam()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobRequest, err)
+	}
+
+	histories, total, err := s.jobRepo.GetTaskExecutionHistories(ctx, param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task execution histories: %w", err)
+	}
+	items := make([]dto.TaskExecutionHistoryResponse, 0, len(histories))
+	for _, history := range histories {
+		items = append(items, dto.NewTaskExecutionHistoryResponse(history))
+	}
+	return &dto.PaginatedResponse{
+		Items:    items,
+		Page:     param.Offset/param.Limit + 1,
+		PageSize: param.Limit,
+		Total:    total,
+	}, nil
+}
+
+func (s *jobService) GetJobDependencies(ctx context.Context, id uint) ([]model.JobDependency, error) {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return nil, err
+	}
+	dependencies, err := s.jobRepo.GetJobDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job dependencies: %w", err)
+	}
+
+	upstream := make([]model.JobDependency, 0)
+	for _, dependency := range dependencies {
+		if dependency.JobID == id {
+			upstream = append(upstream, dependency)
+		}
+	}
+	return upstream, nil
+}
+
+func (s *jobService) AddJobDependency(ctx context.Context, id uint, dependsOnJobID uint) error {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return err
+	}
+	err := s.unitOfWork.Run(func(opts ...utils.DBOption) error {
+		return s.saveJobDependencies(ctx, id, []uint{dependsOnJobID}, false, opts...)
+	})
+	if err != nil {
+		return err
+	}
+	s.log.InfoContext(ctx, "Job dependency added", logger.IntField("job_id", int(id)), logger.IntField("depends_on_job_id", int(dependsOnJobID)))
+	return nil
+}
+
+func (s *jobService) RemoveJobDependency(ctx context.Context, id uint, dependsOnJobID uint) error {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return err
+	}
+	deleted, err := s.jobRepo.DeleteJobDependencies(ctx, id, []uint{dependsOnJobID})
+	if err != nil {
+		return fmt.Errorf("failed to delete job dependency: %w", err)
+	}
+	if deleted == 0 {
+		return ErrJobDependencyNotFound
+	}
+	s.log.InfoContext(ctx, "Job dependency removed", logger.IntField("job_id", int(id)), logger.IntField("depends_on_job_id", int(dependsOnJobID)))
+	return nil
+}
+
+// saveJobDependencies menambah upstream dependsOn milik jobID (replace mengganti seluruh upstream-nya) setelah
+// memastikan job upstream ada & graf dependency tidak membentuk siklus. Dipanggil di dalam transaksi.
+func (s *jobService) saveJobDependencies(ctx context.Context, jobID uint, dependsOn []uint, replace bool, opts ...utils.DBOption) error {
+	dependsOn = slices.Compact(slices.Sorted(slices.Values(dependsOn)))
+	if len(dependsOn) > 0 {
+		jobs, err := s.jobRepo.Get(ctx, &model.GetJobParam{IDs: dependsOn}, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to get upstream jobs: %w", err)
+		}
+		for _, upstreamID := range dependsOn {
+			if !slices.ContainsFunc(jobs, func(job model.Job) bool { return job.ID == upstreamID }) {
+				return fmt.Errorf("%w: upstream job %d not found", ErrInvalidJobRequest, upstreamID)
+			}
+		}
+	}
+
+	if err := s.jobRepo.LockJobDependencies(ctx, opts...); err != nil {
+		return fmt.Errorf("failed to lock job dependencies: %w", err)
+	}
+	existing, err := s.jobRepo.GetJobDependencies(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to get job dependencies: %w", err)
+	}
+
+	dependencies := make([]model.JobDependency, 0, len(existing)+len(dependsOn))
+	for _, dependency := range existing {
+		if !replace || dependency.JobID != jobID {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	added := make([]model.JobDependency, 0, len(dependsOn))
+	for _, upstreamID := range dependsOn {
+		dependency := model.JobDependency{JobID: jobID, DependsOnJobID: upstreamID}
+		if !slices.Contains(dependencies, dependency) {
+			added = append(added, dependency)
+		}
+	}
+
+	// setiap siklus baru pasti melewati jobID, cukup dicek dari pipeline jobID
+	if _, err := dto.BuildJobPipeline(jobID, append(dependencies, added...)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJobRequest, err)
+	}
+
+	if replace {
+		if _, err := s.jobRepo.DeleteJobDependencies(ctx, jobID, nil, opts...); err != nil {
+			return fmt.Errorf("failed to delete job dependencies: %w", err)
+		}
+	}
+	if err := s.jobRepo.CreateJobDependencies(ctx, added, opts...); err != nil {
+		return fmt.Errorf("failed to create job dependencies: %w", err)
+	}
+	return nil
+}
+
+func (s *jobService) findTaskSchedule(ctx context.Context, id uint) (*model.TaskSchedule, error) {
+	schedule, err := s.jobRepo.FindTaskScheduleByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task schedule: %w", err)
+	}
+	if schedule == nil {
+		return nil, ErrTaskScheduleNotFound
+	}
+	return schedule, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/repository"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func (r *fakeJobRepository) Get(ctx context.Context, param *model.GetJobParam, opts ...utils.DBOption) ([]model.Job, error) {
+	var jobs []model.Job
+	for _, job := range r.jobs {
+		if len(param.IDs) == 0 || slices.Contains(param.IDs, job.ID) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeJobRepository) CreateJob(ctx context.Context, job *model.Job, opts ...utils.DBOption) error {
+	job.ID = uint(len(r.jobs) + 1)
+	r.jobs = append(r.jobs, *job)
+	return nil
+}
+
+func (r *fakeJobRepository) UpdateJob(ctx context.Context, job *model.Job, opts ...utils.DBOption) error {
+	for i := range r.jobs {
+		if r.jobs[i].ID == job.ID {
+			r.jobs[i] = *job
+		}
+	}
+	return nil
+}
+
+func (r *fakeJobRepository) SaveTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error {
+	r.saved = append(r.saved, *schedule)
+	return nil
+}
+
+func (r *fakeJobRepository) LockJobDependencies(ctx context.Context, opts ...utils.DBOption) error {
+	return nil
+}
+
+func (r *fakeJobRepository) CreateJobDependencies(ctx context.Context, dependencies []model.JobDependency, opts ...utils.DBOption) error {
+	r.dependencies = append(r.dependencies, dependencies...)
+	return nil
+}
+
+func (r *fakeJobRepository) DeleteJobDependencies(ctx context.Context, jobID uint, dependsOnJobIDs []uint, opts ...utils.DBOption) (int64, error) {
+	before := len(r.dependencies)
+	r.dependencies = slices.DeleteFunc(r.dependencies, func(dependency model.JobDependency) bool {
+		return dependency.JobID == jobID && (len(dependsOnJobIDs) == 0 || slices.Contains(dependsOnJobIDs, dependency.DependsOnJobID))
+	})
+	return int64(before - len(r.dependencies)), nil
+}
+
+// fakeUnitOfWork menjalankan fn tanpa transaksi
+type fakeUnitOfWork struct {
+	repository.UnitOfWork
+}
+
+func (u *fakeUnitOfWork) Run(fn func(opts ...utils.DBOption) error) error {
+	return fn()
+}
+
+func newTestJobService(jobRepo *fakeJobRepository) JobService {
+	return NewJobService(&logger.Logger{Logger: zap.NewNop()}, jobRepo, &fakeUnitOfWork{}, newTestScheduler(jobRepo))
+}
+
+func TestJobServiceValidateRequest(t *testing.T) {
+	jobRepo := &fakeJobRepository{jobs: []model.Job{{ID: 1}}}
+	s := newTestJobService(jobRepo)
+	ctx := context.Background()
+
+	_, err := s.CreateJob(ctx, dto.JobRequest{Name: "cleanup", Type: "data_clean_up", Payload: []byte(`{"retention": 30}`)})
+	assert.ErrorIs(t, err, ErrInvalidJobRequest)
+	_, err = s.CreateJob(ctx, dto.JobRequest{Name: "cleanup", Type: "data_clean_up", Payload: []byte(`{"retention_days": 30}`), RetryPolicy: []byte(`{"max_retries": 2, "initial_interval": "soon"}`)})
+	assert.ErrorIs(t, err, ErrInvalidJobRequest)
+
+	_, err = s.CreateTaskSchedule(ctx, 1, dto.TaskScheduleRequest{CronExpression: "0 25 * * *"})
+	assert.ErrorIs(t, err, ErrInvalidJobRequest)
+	_, err = s.CreateTaskSchedule(ctx, 2, dto.TaskScheduleRequest{CronExpression: "0 8 * * *"})
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestJobServicePauseResume(t *testing.T) {
+	jobRepo := &fakeJobRepository{jobs: []model.Job{{ID: 1, Schedules: []model.TaskSchedule{
+		{ID: 1, JobID: 1, CronExpression: "*/5 * * * *", IsActive: true},
+		{ID: 2, JobID: 1, CronExpression: "@daily", IsActive: true},
+	}}}}
+	s := newTestJobService(jobRepo)
+	ctx := context.Background()
+
+	assert.NoError(t, s.PauseJob(ctx, 1))
+	assert.Len(t, jobRepo.saved, 2)
+	for _, schedule := range jobRepo.saved {
+		assert.False(t, schedule.IsActive)
+	}
+
+	// jadwal yang terlewat selama pause tidak dijalankan susulan
+	jobRepo.saved = nil
+	assert.NoError(t, s.ResumeJob(ctx, 1))
+	assert.Len(t, jobRepo.saved, 2)
+	for _, schedule := range jobRepo.saved {
+		assert.True(t, schedule.IsActive)
+		assert.True(t, schedule.NextExecution.Valid)
+		assert.True(t, schedule.NextExecution.Time.After(time.Now()))
+	}
+
+	assert.ErrorIs(t, s.PauseJob(ctx, 2), ErrJobNotFound)
+}
+
+func TestJobServiceDependencies(t *testing.T) {
+	jobRepo := &fakeJobRepository{
+		jobs:         []model.Job{{ID: 1}, {ID: 2}, {ID: 3}},
+		dependencies: []model.JobDependency{{JobID: 2, DependsOnJobID: 1}},
+	}
+	s := newTestJobService(jobRepo)
+	ctx := context.Background()
+
+	assert.NoError(t, s.AddJobDependency(ctx, 3, 2))
+	// edge yang sudah ada tidak diduplikasi
+	assert.NoError(t, s.AddJobDependency(ctx, 3, 2))
+	assert.Equal(t, []model.JobDependency{{JobID: 2, DependsOnJobID: 1}, {JobID: 3, DependsOnJobID: 2}}, jobRepo.dependencies)
+
+	// 1 -> 2 -> 3 -> 1 dan job yang menunggu dirinya sendiri membentuk siklus
+	assert.ErrorIs(t, s.AddJobDependency(ctx, 1, 3), ErrInvalidJobRequest)
+	assert.ErrorIs(t, s.AddJobDependency(ctx, 1, 1), ErrInvalidJobRequest)
+	assert.ErrorIs(t, s.AddJobDependency(ctx, 1, 9), ErrInvalidJobRequest)
+	assert.ErrorIs(t, s.AddJobDependency(ctx, 9, 1), ErrJobNotFound)
+	assert.Len(t, jobRepo.dependencies, 2)
+
+	upstream, err := s.GetJobDependencies(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.JobDependency{{JobID: 3, DependsOnJobID: 2}}, upstream)
+
+	assert.NoError(t, s.RemoveJobDependency(ctx, 2, 1))
+	assert.ErrorIs(t, s.RemoveJobDependency(ctx, 2, 1), ErrJobDependencyNotFound)
+	assert.ErrorIs(t, s.RemoveJobDependency(ctx, 9, 1), ErrJobNotFound)
+	// setelah 1 -> 2 dilepas, 1 boleh menunggu 3 (2 -> 3 -> 1)
+	assert.NoError(t, s.AddJobDependency(ctx, 1, 3))
+}
+
+func TestJobServiceDependsOn(t *testing.T) {
+	jobRepo := &fakeJobRepository{jobs: []model.Job{{ID: 1}, {ID: 2}}}
+	s := newTestJobService(jobRepo)
+	ctx := context.Background()
+	req := dto.JobRequest{Name: "cleanup", Type: "data_clean_up", Payload: []byte(`{"retention_days": 30}`)}
+
+	req.DependsOn = []uint{2, 1, 2}
+	job, err := s.CreateJob(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), job.ID)
+	assert.Equal(t, []model.JobDependency{{JobID: 3, DependsOnJobID: 1}, {JobID: 3, DependsOnJobID: 2}}, jobRepo.dependencies)
+
+	// depends_on tidak dikirim, upstream tidak diubah
+	req.DependsOn = nil
+	_, err = s.UpdateJob(ctx, 3, req)
+	assert.NoError(t, err)
+	assert.Len(t, jobRepo.dependencies, 2)
+
+	// depends_on mengganti seluruh upstream job
+	req.DependsOn = []uint{2}
+	_, err = s.UpdateJob(ctx, 3, req)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.JobDependency{{JobID: 3, DependsOnJobID: 2}}, jobRepo.dependencies)
+
+	// 2 -> 3 -> 2 membentuk siklus
+	req.DependsOn = []uint{3}
+	_, err = s.UpdateJob(ctx, 2, req)
+	assert.ErrorIs(t, err, ErrInvalidJobRequest)
+	req.DependsOn = []uint{9}
+	_, err = s.UpdateJob(ctx, 1, req)
+	assert.ErrorIs(t, err, ErrInvalidJobRequest)
+	assert.Equal(t, []model.JobDependency{{JobID: 3, DependsOnJobID: 2}}, jobRepo.dependencies)
+
+	req.DependsOn = []uint{}
+	_, err = s.UpdateJob(ctx, 3, req)
+	assert.NoError(t, err)
+	assert.Empty(t, jobRepo.dependencies)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/repository"
+)
+
+type MarketDataService interface {
+	GetProviderHealth(ctx context.Context) []dto.MarketDataProviderHealth
+}
+
+type marketDataService struct {
+	marketDataRegistry repository.MarketDataRegistry
+}
+
+func NewMarketDataService(marketDataRegistry repository.MarketDataRegistry) MarketDataService {
+	return &marketDataService{
+		marketDataRegistry: marketDataRegistry,
+	}
+}
+
+// GetProviderHealth statistik sukses / gagal, latency dan divergensi harga tiap provider market data
+func (s *marketDataService) GetProviderHealth(ctx context.Context) []dto.MarketDataProviderHealth {
+	return s.marketDataRegistry.Health()
+}
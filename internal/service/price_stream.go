@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/repository"
+	"golang-trading/internal/strategy"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+)
+
+// PriceStreamService alert TP / SL / trailing real-time dari price stream websocket, melengkapi job polling stock_price_alert
+type PriceStreamService interface {
+	// Run berjalan sampai ctx selesai
+	Run(ctx context.Context)
+}
+
+type priceStreamService struct {
+	cfg                      *config.Config
+	log                      *logger.Logger
+	streams                  []repository.PriceStream
+	stockPositionsRepository repository.StockPositionsRepository
+	priceAlerter             strategy.PriceAlerter
+
+	mu        sync.Mutex
+	positions map[string]map[string][]*model.StockPosition // exchange -> symbol -> posisi dengan price alert aktif
+}
+
+func NewPriceStreamService(
+	cfg *config.Config,
+	log *logger.Logger,
+	streams []repository.PriceStream,
+	stockPositionsRepository repository.StockPositionsRepository,
+	priceAlerter strategy.PriceAlerter,
+) PriceStreamService {
+	return &priceStreamService{
+		cfg:                      cfg,
+		log:                      log,
+		streams:                  streams,
+		stockPositionsRepository: stockPositionsRepository,
+		priceAlerter:             priceAlerter,
+		positions:                make(map[string]map[string][]*model.StockPosition),
+	}
+}
+
+func (s *priceStreamService) Run(ctx context.Context) {
+	if !s.cfg.PriceStream.Enabled || len(s.streams) == 0 {
+		s.log.InfoContext(ctx, "Price stream disabled")
+		return
+	}
+
+	refreshInterval := s.cfg.PriceStream.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = 30 * time.Second
+	}
+
+	s.refresh(ctx)
+
+	var wg sync.WaitGroup
+	for _, stream := range s.streams {
+		wg.Add(1)
+		go func(stream repository.PriceStream) {
+			defer wg.Done()
+			stream.Run(ctx, s.onTick)
+		}(stream)
+	}
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			s.log.InfoContext(ctx, "Price stream stopped")
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+// refresh memuat ulang posisi dengan price alert aktif lalu mengganti symbol yang di-subscribe setiap stream
+func (s *priceStreamService) refresh(ctx context.Context) {
+	for _, stream := range s.streams {
+		exchange := stream.Exchange()
+		positions, err := s.stockPositionsRepository.Get(ctx, dto.GetStockPositionsParam{
+			PriceAlert: utils.ToPointer(true),
+			IsActive:   utils.ToPointer(true),
+			Exchange:   utils.ToPointer(exchange),
+		})
+		if err != nil {
+			// daftar symbol lama dipertahankan sampai refresh berikutnya
+			s.log.ErrorContext(ctx, "Failed to get price alert positions", logger.ErrorField(err), logger.StringField("exchange", exchange))
+			continue
+		}
+
+		bySymbol := make(map[string][]*model.StockPosition)
+		for i := range positions {
+			symbol := stream.NormalizeSymbol(positions[i].StockCode)
+			bySymbol[symbol] = append(bySymbol[symbol], &positions[i])
+		}
+
+		symbols := make([]string, 0, len(bySymbol))
+		for symbol := range bySymbol {
+			symbols = append(symbols, symbol)
+		}
+
+		s.mu.Lock()
+		s.positions[exchange] = bySymbol
+		s.mu.Unlock()
+
+		stream.SetSymbols(symbols)
+	}
+}
+
+func (s *priceStreamService) onTick(ctx context.Context, tick dto.PriceTick) {
+	s.mu.Lock()
+	positions := s.positions[tick.Exchange][tick.Symbol]
+	s.mu.Unlock()
+
+	for _, position := range positions {
+		_, err := s.priceAlerter.EvaluatePrice(ctx, position, tick.Price, strategy.PriceAlertOptions{
+			CacheDuration:               s.cfg.PriceStream.AlertCacheDuration,
+			AlertResendThresholdPercent: s.cfg.PriceStream.AlertResendThresholdPercent,
+		})
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to evaluate streamed price alert",
+				logger.ErrorField(err),
+				logger.StringField("stock_code", position.StockCode),
+				logger.StringField("exchange", position.Exchange),
+			)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-trading/config"
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/repository"
+	"golang-trading/internal/strategy"
+	"golang-trading/pkg/cache"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/telegram"
+	"golang-trading/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/telebot.v3"
+)
+
+// fakeStockPositionsRepository menyimpan posisi per ID, Get mengembalikan salinan seperti query DB
+type fakeStockPositionsRepository struct {
+	repository.StockPositionsRepository
+	mu        sync.Mutex
+	positions map[uint]model.StockPosition
+}
+
+func (r *fakeStockPositionsRepository) Get(ctx context.Context, param dto.GetStockPositionsParam, opts ...utils.DBOption) ([]model.StockPosition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var positions []model.StockPosition
+	for _, position := range r.positions {
+		if *position.IsActive && position.Exchange == *param.Exchange {
+			positions = append(positions, position)
+		}
+	}
+	return positions, nil
+}
+
+func (r *fakeStockPositionsRepository) Update(ctx context.Context, stockPosition model.StockPosition, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.positions[stockPosition.ID] = stockPosition
+	return nil
+}
+
+func (r *fakeStockPositionsRepository) UpdateLastPriceAlertAt(ctx context.Context, id uint, at time.Time, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	position := r.positions[id]
+	position.LastPriceAlertAt = &at
+	r.positions[id] = position
+	return nil
+}
+
+type fakePriceStream struct {
+	repository.PriceStream
+}
+
+func (s *fakePriceStream) Exchange() string                     { return "BINANCE" }
+func (s *fakePriceStream) NormalizeSymbol(symbol string) string { return symbol }
+func (s *fakePriceStream) SetSymbols(symbols []string)          {}
+
+func TestPriceStreamAlertKeepsNewerPosition(t *testing.T) {
+	// API telegram palsu, alert dianggap terkirim
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+	bot, err := telebot.NewBot(telebot.Settings{Token: "test", URL: server.URL, Offline: true})
+	assert.NoError(t, err)
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	telegramCfg := &config.TelegramConfig{MaxGlobalRequestPerSecond: 30, MaxUserRequestPerSecond: 30, MaxEditMessagePerSecond: 30}
+	positionsRepo := &fakeStockPositionsRepository{positions: map[uint]model.StockPosition{
+		1: {ID: 1, StockCode: "BTCUSDT", Exchange: "BINANCE", Side: model.PositionSideLong, BuyPrice: 100, Quantity: 2,
+			TakeProfitPrice: 110, StopLossPrice: 90, IsActive: utils.ToPointer(true), PriceAlert: utils.ToPointer(true)},
+	}}
+	alerter := strategy.NewStockPriceAlertStrategy(&config.Config{}, log, cache.NewCache(time.Minute, time.Minute), nil,
+		telegram.NewTelegramRateLimiter(telegramCfg, log, bot), positionsRepo, nil)
+
+	cfg := &config.Config{PriceStream: config.PriceStream{AlertCacheDuration: time.Minute}}
+	s := NewPriceStreamService(cfg, log, []repository.PriceStream{&fakePriceStream{}}, positionsRepo, alerter).(*priceStreamService)
+	ctx := context.Background()
+	s.refresh(ctx)
+
+	// posisi di-exit & quantity diubah setelah refresh, stream masih memegang salinan lama
+	positionsRepo.mu.Lock()
+	exited := positionsRepo.positions[1]
+	exited.IsActive = utils.ToPointer(false)
+	exited.Quantity = 1
+	exited.TakeProfitPrice = 120
+	positionsRepo.positions[1] = exited
+	positionsRepo.mu.Unlock()
+
+	s.onTick(ctx, dto.PriceTick{Exchange: "BINANCE", Symbol: "BTCUSDT", Price: 111})
+
+	position := positionsRepo.positions[1]
+	assert.False(t, *position.IsActive)
+	assert.Equal(t, 1.0, position.Quantity)
+	assert.Equal(t, 120.0, position.TakeProfitPrice)
+	assert.NotNil(t, position.LastPriceAlertAt)
+}
@@ -1,23 +1,45 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"golang-trading/config"
+	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/internal/repository"
+	"golang-trading/internal/strategy"
 	"golang-trading/pkg/logger"
 	"golang-trading/pkg/utils"
+	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
+var (
+	// ErrJobAlreadyRunning lease schedule masih dipegang run terjadwal / run manual lain
+	ErrJobAlreadyRunning = errors.New("job is already running")
+	// ErrSchedulerBusy semua slot concurrency scheduler sedang terpakai
+	ErrSchedulerBusy = errors.New("all scheduler slots are busy")
+)
+
 type SchedulerService interface {
+	// Run loop scheduler: menjalankan Execute setiap PollInterval sampai ctx selesai,
+	// lalu menunggu job yang sedang jalan paling lama ShutdownTimeout
+	Run(ctx context.Context)
 	Execute(ctx context.Context) error
 	GetJobSchedule(ctx context.Context, param model.GetJobParam) ([]model.Job, error)
+	// RunJobTask menjalankan job sekarang di luar jadwal memakai schedule dengan ID terkecil, jadwal cron tidak diubah.
+	// ErrSchedulerBusy jika slot penuh, ErrJobAlreadyRunning jika lease schedule masih dipegang run lain.
 	RunJobTask(ctx context.Context, jobID uint) error
+	// NextExecution eksekusi cron task berikutnya setelah from, di zona waktu exchange task jika diisi.
+	// Error jika cron expression tidak valid.
+	NextExecution(ctx context.Context, task model.TaskSchedule, from time.Time) (time.Time, error)
 }
 
 type schedulerService struct {
@@ -25,31 +47,121 @@ type schedulerService struct {
 	log          *logger.Logger
 	cronParser   cron.Parser
 	jobRepo      repository.JobRepository
+	calendarRepo repository.TradingCalendarRepository
 	taskExecutor TaskExecutor
 	semaphore    chan struct{}
+	instanceID   string
+
+	executeMu sync.Mutex     // loop & trigger HTTP tidak boleh memproses jadwal yang sama bersamaan
+	inFlight  sync.WaitGroup // job yang sedang jalan, ditunggu saat shutdown
+	stopping  chan struct{}  // ditutup saat shutdown, retry yang sedang backoff dibatalkan
+	stopOnce  sync.Once
 }
 
 func NewSchedulerService(
 	cfg *config.Config,
 	log *logger.Logger,
 	jobRepo repository.JobRepository,
+	calendarRepo repository.TradingCalendarRepository,
 	taskExecutor TaskExecutor,
 ) *schedulerService {
 	return &schedulerService{
 		cfg:          cfg,
 		log:          log,
 		jobRepo:      jobRepo,
+		calendarRepo: calendarRepo,
 		cronParser:   cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor),
 		taskExecutor: taskExecutor,
 		semaphore:    make(chan struct{}, cfg.Scheduler.MaxConcurrency),
+		instanceID:   schedulerInstanceID(cfg),
+		stopping:     make(chan struct{}),
+	}
+}
+
+// schedulerInstanceID SCHEDULER_INSTANCE_ID, default hostname-pid supaya unik per replika
+func schedulerInstanceID(cfg *config.Config) string {
+	if cfg.Scheduler.InstanceID != "" {
+		return cfg.Scheduler.InstanceID
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func (s *schedulerService) Run(ctx context.Context) {
+	pollInterval := s.cfg.Scheduler.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	s.log.InfoContext(ctx, "Scheduler started", logger.StringField("poll_interval", pollInterval.String()))
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		if err := s.Execute(ctx); err != nil && ctx.Err() == nil {
+			// alert sekali per rangkaian kegagalan supaya tidak spam setiap poll
+			if !failing {
+				s.log.ErrorContextWithAlert(ctx, "Scheduler poll failed, jobs are not running", logger.ErrorField(err))
+			}
+			failing = true
+		} else if failing && err == nil {
+			s.log.InfoContext(ctx, "Scheduler poll recovered")
+			failing = false
+		}
+
+		select {
+		case <-ctx.Done():
+			s.shutdown(ctx)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shutdown menghentikan retry yang sedang backoff lalu menunggu job yang sedang jalan
+func (s *schedulerService) shutdown(ctx context.Context) {
+	s.stopOnce.Do(func() { close(s.stopping) })
+
+	timeout := s.cfg.Scheduler.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	s.log.InfoContext(ctx, "Scheduler stopping, waiting for running jobs", logger.StringField("timeout", timeout.String()))
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.log.InfoContext(ctx, "Scheduler stopped")
+	case <-time.After(timeout):
+		s.log.WarnContext(ctx, "Timeout while waiting for running jobs, forcing shutdown")
 	}
 }
 
 func (s *schedulerService) Execute(ctx context.Context) error {
-	jobs, err := s.jobRepo.FindJobsToSchedule(ctx, utils.WithPreload("Job"))
+	s.executeMu.Lock()
+	defer s.executeMu.Unlock()
+
+	// klaim hanya sebanyak slot kosong: schedule yang antre semaphore terlalu lama bisa kedaluwarsa lease-nya
+	// lalu dijalankan replika lain
+	free := cap(s.semaphore) - len(s.semaphore)
+	if free <= 0 {
+		s.log.InfoContext(ctx, "All scheduler slots are busy, skipping claim", logger.IntField("max_concurrency", cap(s.semaphore)))
+		return nil
+	}
+
+	jobs, err := s.jobRepo.ClaimJobsToSchedule(ctx, s.instanceID, s.leaseDuration(), free, utils.WithPreload("Job"))
 	if err != nil {
-		s.log.ErrorContext(ctx, "Failed to find jobs to schedule", logger.ErrorField(err))
-		return fmt.Errorf("failed to find jobs to schedule: %w", err)
+		s.log.ErrorContext(ctx, "Failed to claim jobs to schedule", logger.ErrorField(err), logger.StringField("instance_id", s.instanceID))
+		return fmt.Errorf("failed to claim jobs to schedule: %w", err)
 	}
 
 	if len(jobs) == 0 {
@@ -59,15 +171,34 @@ func (s *schedulerService) Execute(ctx context.Context) error {
 	s.log.InfoContext(ctx, "Start running jobs",
 		logger.IntField("job_count", len(jobs)),
 		logger.IntField("max_concurrency", s.cfg.Scheduler.MaxConcurrency),
+		logger.StringField("instance_id", s.instanceID),
 	)
 
-	for _, job := range jobs {
+	// heartbeat lease dimulai sejak klaim, bukan saat job mendapat slot semaphore
+	leases := make([]func(), len(jobs))
+	for i, job := range jobs {
+		leases[i] = s.holdLease(job)
+	}
+
+	for i, job := range jobs {
 		if ctx.Err() != nil {
 			s.log.WarnContext(ctx, "Job execution cancelled", logger.ErrorField(ctx.Err()))
+			// schedule yang belum diproses dilepas supaya langsung bisa diklaim replika lain
+			for _, releaseLease := range leases[i:] {
+				releaseLease()
+			}
 			return nil
 		}
 
-		err := s.executeJob(ctx, job, s.semaphore)
+		if reason, nextOpen := s.marketClosed(ctx, job); reason != "" {
+			if err := s.skipJob(ctx, job, reason, nextOpen); err != nil {
+				s.log.ErrorContext(ctx, "Failed to skip job", logger.ErrorField(err), logger.IntField("schedule_id", int(job.ID)))
+			}
+			leases[i]()
+			continue
+		}
+
+		err := s.executeJob(ctx, job, s.semaphore, leases[i])
 		if err != nil {
 			s.log.ErrorContextWithAlert(ctx, "Failed to execute job",
 				logger.ErrorField(err),
@@ -88,7 +219,8 @@ func (s *schedulerService) Execute(ctx context.Context) error {
 	return nil
 }
 
-func (s *schedulerService) executeJob(ctx context.Context, task model.TaskSchedule, semaphore chan struct{}) error {
+// executeJob menjalankan job di goroutine. releaseLease (dari holdLease) dipanggil setelah job & pipeline selesai.
+func (s *schedulerService) executeJob(ctx context.Context, task model.TaskSchedule, semaphore chan struct{}, releaseLease func()) error {
 	s.log.DebugContext(ctx, "Executing job",
 		logger.IntField("job_id", int(task.JobID)),
 		logger.IntField("schedule_id", int(task.ID)),
@@ -104,47 +236,412 @@ func (s *schedulerService) executeJob(ctx context.Context, task model.TaskSchedu
 	history := &model.TaskExecutionHistory{
 		JobID:      task.JobID,
 		ScheduleID: task.ID,
+		Attempt:    1,
+		InstanceID: s.instanceID,
 		Status:     model.StatusRunning,
 		StartedAt:  now,
 	}
 
 	if err := s.jobRepo.CreateTaskExecutionHistory(ctx, history); err != nil {
 		s.log.ErrorContext(ctx, "Failed to create task history", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+		releaseLease()
 		return fmt.Errorf("failed to create task history: %w", err)
 	}
 
+	// jadwal berikutnya disimpan sebelum job jalan: lease baru dilepas setelah schedule tidak jatuh tempo lagi,
+	// dan tetap disimpan walau ctx dibatalkan supaya job tidak jalan dua kali
+	task.LastExecution = sql.NullTime{Time: now, Valid: true}
+	scheduleErr := s.scheduleNext(context.WithoutCancel(ctx), &task, now)
+
 	semaphore <- struct{}{}
+	s.runInBackground(ctx, task, history, semaphore, releaseLease)
+
+	return scheduleErr
+}
+
+// runInBackground menjalankan job & pipeline-nya di goroutine, slot semaphore harus sudah diambil.
+// Slot & lease dilepas setelah selesai.
+func (s *schedulerService) runInBackground(ctx context.Context, task model.TaskSchedule, history *model.TaskExecutionHistory, semaphore chan struct{}, releaseLease func()) {
+	policy := s.retryPolicy(ctx, task.Job)
+
+	s.inFlight.Add(1)
 	utils.GoSafe(func() {
 
 		defer func() {
 			<-semaphore
+			releaseLease()
+			s.inFlight.Done()
 		}()
 
-		newCtx, cancel := context.WithTimeout(context.Background(), time.Duration(task.Job.Timeout)*time.Second)
-		defer cancel()
+		history = s.runWithRetry(task, history, policy)
+		s.runPipeline(task, history)
+	}).Run()
+}
 
-		if err := s.taskExecutor.Execute(newCtx, history); err != nil {
-			s.log.ErrorContextWithAlert(newCtx, "Failed to execute task", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+// runWithRetry menjalankan job sesuai retry policy sampai selesai, retry habis atau scheduler berhenti.
+// Mengembalikan history attempt terakhir.
+func (s *schedulerService) runWithRetry(task model.TaskSchedule, history *model.TaskExecutionHistory, policy dto.RetryPolicy) *model.TaskExecutionHistory {
+	ctx := context.Background()
+	for {
+		s.executeAttempt(task, history)
+		if !policy.ShouldRetry(history.Attempt, history.ExitCode.Int32) {
+			break
 		}
-	}).Run()
 
-	// Update schedule for next run
-	cronSchedule, err := s.cronParser.Parse(task.CronExpression)
+		backoff := policy.Backoff(history.Attempt)
+		s.log.WarnContext(ctx, "Job attempt failed, retrying",
+			logger.IntField("job_id", int(task.JobID)),
+			logger.IntField("schedule_id", int(task.ID)),
+			logger.StringField("job_name", task.Job.Name),
+			logger.IntField("attempt", history.Attempt),
+			logger.IntField("exit_code", int(history.ExitCode.Int32)),
+			logger.StringField("backoff", backoff.String()),
+		)
+
+		// slot concurrency dilepas selama backoff supaya job lain tetap bisa jalan
+		<-s.semaphore
+		select {
+		case <-time.After(backoff):
+		case <-s.stopping:
+			s.semaphore <- struct{}{}
+			s.log.WarnContext(ctx, "Scheduler stopping, job retry cancelled",
+				logger.IntField("job_id", int(task.JobID)),
+				logger.IntField("attempt", history.Attempt),
+			)
+			s.finishExecution(task, history, policy)
+			return history
+		}
+		s.semaphore <- struct{}{}
+
+		next := &model.TaskExecutionHistory{
+			JobID:      task.JobID,
+			ScheduleID: task.ID,
+			Attempt:    history.Attempt + 1,
+			InstanceID: s.instanceID,
+			ParentID:   history.ParentID,
+			Status:     model.StatusRunning,
+			StartedAt:  utils.TimeNowWIB(),
+		}
+		if err := s.jobRepo.CreateTaskExecutionHistory(ctx, next); err != nil {
+			s.log.ErrorContextWithAlert(ctx, "Failed to create retry task history", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+			break
+		}
+		history = next
+	}
+
+	s.finishExecution(task, history, policy)
+	return history
+}
+
+// runPipeline menjalankan job hilir (job_dependencies) secara berurutan setelah job schedule selesai.
+// Step dicatat sebagai child history job upstream; step dilewati jika ada upstream yang tidak completed.
+func (s *schedulerService) runPipeline(task model.TaskSchedule, root *model.TaskExecutionHistory) {
+	ctx := context.Background()
+
+	dependencies, err := s.jobRepo.GetJobDependencies(ctx)
 	if err != nil {
-		s.log.ErrorContext(ctx, "Failed to parse cron expression", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
-		return fmt.Errorf("failed to parse cron expression: %w", err)
+		s.log.ErrorContextWithAlert(ctx, "Failed to get job dependencies, pipeline not executed", logger.ErrorField(err), logger.IntField("job_id", int(task.JobID)))
+		return
+	}
+	steps, err := dto.BuildJobPipeline(task.JobID, dependencies)
+	if err != nil {
+		s.log.ErrorContextWithAlert(ctx, "Invalid job pipeline", logger.ErrorField(err), logger.IntField("job_id", int(task.JobID)))
+		return
+	}
+	if len(steps) == 0 {
+		return
 	}
 
-	task.LastExecution = sql.NullTime{Time: now, Valid: true}
-	task.NextExecution = sql.NullTime{Time: cronSchedule.Next(now), Valid: true}
+	s.log.InfoContext(ctx, "Running job pipeline",
+		logger.IntField("job_id", int(task.JobID)),
+		logger.IntField("history_id", int(root.ID)),
+		logger.IntField("step_count", len(steps)),
+	)
+
+	statuses := map[uint]model.TaskExecutionStatus{task.JobID: root.Status}
+	for _, step := range steps {
+		history := &model.TaskExecutionHistory{
+			JobID:      step.JobID,
+			ScheduleID: task.ID,
+			Attempt:    1,
+			InstanceID: s.instanceID,
+			ParentID:   &root.ID,
+			Status:     model.StatusRunning,
+			StartedAt:  utils.TimeNowWIB(),
+		}
+
+		if reason := s.pipelineSkipReason(step, statuses); reason != "" {
+			history.Status = model.StatusSkipped
+			history.CompletedAt = sql.NullTime{Time: history.StartedAt, Valid: true}
+			history.Output = sql.NullString{String: reason, Valid: true}
+			statuses[step.JobID] = model.StatusSkipped
+			if err := s.jobRepo.CreateTaskExecutionHistory(ctx, history); err != nil {
+				s.log.ErrorContext(ctx, "Failed to create task history", logger.ErrorField(err), logger.IntField("job_id", int(step.JobID)))
+			}
+			continue
+		}
+
+		job, err := s.jobRepo.FindByID(ctx, step.JobID)
+		if err != nil {
+			s.log.ErrorContextWithAlert(ctx, "Failed to find pipeline job", logger.ErrorField(err), logger.IntField("job_id", int(step.JobID)))
+			statuses[step.JobID] = model.StatusFailed
+			continue
+		}
+		if err := s.jobRepo.CreateTaskExecutionHistory(ctx, history); err != nil {
+			s.log.ErrorContextWithAlert(ctx, "Failed to create task history", logger.ErrorField(err), logger.IntField("job_id", int(step.JobID)))
+			statuses[step.JobID] = model.StatusFailed
+			continue
+		}
+
+		// step dijalankan atas nama schedule yang memicu pipeline
+		stepTask := task
+		stepTask.JobID = job.ID
+		stepTask.Job = *job
+		history = s.runWithRetry(stepTask, history, s.retryPolicy(ctx, *job))
+		statuses[step.JobID] = history.Status
+	}
+}
+
+// pipelineSkipReason alasan step dilewati, kosong jika semua upstream completed
+func (s *schedulerService) pipelineSkipReason(step dto.JobPipelineStep, statuses map[uint]model.TaskExecutionStatus) string {
+	select {
+	case <-s.stopping:
+		return "scheduler is stopping"
+	default:
+	}
+	for _, upstream := range step.Upstream {
+		if status := statuses[upstream]; status != model.StatusCompleted {
+			return fmt.Sprintf("upstream job %d is %s", upstream, status)
+		}
+	}
+	return ""
+}
+
+func (s *schedulerService) leaseDuration() time.Duration {
+	if s.cfg.Scheduler.LeaseDuration <= 0 {
+		return 2 * time.Minute
+	}
+	return s.cfg.Scheduler.LeaseDuration
+}
+
+// holdLease memperpanjang lease schedule yang diklaim instance ini selama job (termasuk retry) jalan.
+// Fungsi kembalian menghentikan heartbeat lalu melepas lease.
+func (s *schedulerService) holdLease(task model.TaskSchedule) func() {
+	if task.LockedBy != s.instanceID {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.leaseDuration() / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ok, err := s.jobRepo.ExtendTaskScheduleLease(ctx, task.ID, s.instanceID, s.leaseDuration())
+				if err != nil && ctx.Err() == nil {
+					s.log.WarnContext(ctx, "Failed to extend task schedule lease", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+				} else if err == nil && !ok {
+					s.log.ErrorContextWithAlert(ctx, "Task schedule lease lost, job may run twice",
+						logger.IntField("schedule_id", int(task.ID)),
+						logger.StringField("job_name", task.Job.Name),
+						logger.StringField("instance_id", s.instanceID),
+					)
+					return
+				}
+			}
+		}
+	}()
 
-	if err := s.jobRepo.UpdateTaskSchedule(ctx, &task); err != nil {
+	return func() {
+		cancel()
+		<-done
+		s.releaseLease(task)
+	}
+}
+
+// releaseLease melepas lease schedule milik instance ini, lease yang tidak dilepas kedaluwarsa sendiri
+func (s *schedulerService) releaseLease(task model.TaskSchedule) {
+	if task.LockedBy != s.instanceID {
+		return
+	}
+	if err := s.jobRepo.ReleaseTaskSchedule(context.Background(), task.ID, s.instanceID); err != nil {
+		s.log.Error("Failed to release task schedule lease", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+	}
+}
+
+// executeAttempt menjalankan satu attempt dengan timeout job sendiri. Error executor dicatat sebagai attempt gagal
+// supaya tetap bisa di-retry.
+func (s *schedulerService) executeAttempt(task model.TaskSchedule, history *model.TaskExecutionHistory) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(task.Job.Timeout)*time.Second)
+	defer cancel()
+
+	err := s.taskExecutor.Execute(ctx, history)
+	if err == nil {
+		return
+	}
+
+	s.log.ErrorContext(ctx, "Failed to execute task", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)), logger.IntField("attempt", history.Attempt))
+	history.Status = model.StatusFailed
+	history.ExitCode = sql.NullInt32{Int32: strategy.JOB_EXIT_CODE_FAILED, Valid: true}
+	history.ErrorMessage = sql.NullString{String: err.Error(), Valid: true}
+	history.CompletedAt = sql.NullTime{Time: utils.TimeNowWIB(), Valid: true}
+	if err := s.jobRepo.UpdateTaskExecutionHistory(context.Background(), history); err != nil {
+		s.log.ErrorContext(ctx, "Failed to update task execution history", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+	}
+}
+
+// finishExecution mengirim alert untuk attempt terakhir yang gagal. Attempt yang menghabiskan retry policy
+// ditandai dead letter supaya tidak tertukar dengan kegagalan biasa.
+func (s *schedulerService) finishExecution(task model.TaskSchedule, history *model.TaskExecutionHistory, policy dto.RetryPolicy) {
+	ctx := context.Background()
+
+	msg := "Failed to execute job"
+	exhausted := policy.MaxRetries > 0 && history.Attempt > policy.MaxRetries && slices.Contains(policy.RetryOnExitCodes, history.ExitCode.Int32)
+	if exhausted {
+		msg = "Job moved to dead letter, retries exhausted"
+		history.Status = model.StatusDeadLetter
+		if err := s.jobRepo.UpdateTaskExecutionHistory(ctx, history); err != nil {
+			s.log.ErrorContext(ctx, "Failed to update task execution history", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+		}
+	} else if history.Status != model.StatusFailed && history.Status != model.StatusTimeout {
+		return
+	}
+
+	s.log.ErrorContextWithAlert(ctx, msg,
+		logger.IntField("job_id", int(task.JobID)),
+		logger.IntField("schedule_id", int(task.ID)),
+		logger.StringField("job_name", task.Job.Name),
+		logger.StringField("job_type", task.Job.Type),
+		logger.IntField("attempt", history.Attempt),
+		logger.IntField("exit_code", int(history.ExitCode.Int32)),
+		logger.StringField("error", history.ErrorMessage.String),
+	)
+}
+
+// retryPolicy retry policy job dengan exit code default JOB_EXIT_CODE_FAILED.
+// Policy yang tidak valid tidak menggagalkan job, job jalan tanpa retry.
+func (s *schedulerService) retryPolicy(ctx context.Context, job model.Job) dto.RetryPolicy {
+	policy, err := dto.ParseRetryPolicy(job.RetryPolicy)
+	if err != nil {
+		s.log.WarnContext(ctx, "Invalid job retry policy, running without retry",
+			logger.ErrorField(err),
+			logger.IntField("job_id", int(job.ID)),
+			logger.StringField("job_name", job.Name),
+		)
+		return dto.RetryPolicy{}
+	}
+	if len(policy.RetryOnExitCodes) == 0 {
+		policy.RetryOnExitCodes = []int32{strategy.JOB_EXIT_CODE_FAILED}
+	}
+	return policy
+}
+
+// marketClosed alasan schedule dilewati karena market exchange tutup, kosong jika job boleh jalan.
+// nextOpen waktu market berikutnya buka sesuai mode MarketHours schedule.
+func (s *schedulerService) marketClosed(ctx context.Context, task model.TaskSchedule) (string, time.Time) {
+	if task.Exchange == "" || task.MarketHours == dto.MarketHoursAny {
+		return "", time.Time{}
+	}
+
+	calendar := s.calendarRepo.Get(ctx, task.Exchange)
+	now := time.Now().In(calendar.Location())
+
+	switch task.MarketHours {
+	case dto.MarketHoursTradingDay:
+		if calendar.IsTradingDay(now) {
+			return "", time.Time{}
+		}
+		nextOpen := calendar.NextOpen(now)
+		// awal hari bursa berikutnya supaya cron sebelum jam buka (mis. 08:10) tetap jalan
+		return fmt.Sprintf("%s is not a trading day for %s", now.Format("2006-01-02"), task.Exchange), utils.TruncateDay(nextOpen)
+	case dto.MarketHoursSession:
+		if calendar.IsOpen(now) {
+			return "", time.Time{}
+		}
+		nextOpen := calendar.NextOpen(now)
+		return fmt.Sprintf("%s market is closed, next open at %s", task.Exchange, nextOpen.Format(time.RFC3339)), nextOpen
+	case dto.MarketHoursExtended:
+		if _, ok := calendar.SessionAt(now); ok {
+			return "", time.Time{}
+		}
+		nextOpen := calendar.NextOpen(now)
+		if sessions := calendar.Sessions(nextOpen); len(sessions) > 0 && sessions[0].Open.Before(nextOpen) && sessions[0].Open.After(now) {
+			nextOpen = sessions[0].Open
+		}
+		return fmt.Sprintf("%s market is closed, next session at %s", task.Exchange, nextOpen.Format(time.RFC3339)), nextOpen
+	default:
+		s.log.WarnContext(ctx, "Unknown market hours mode, job is not skipped",
+			logger.IntField("schedule_id", int(task.ID)),
+			logger.StringField("market_hours", task.MarketHours),
+		)
+		return "", time.Time{}
+	}
+}
+
+// skipJob mencatat history skipped lalu melompati schedule ke eksekusi cron pertama setelah market buka.
+// Lease schedule dilepas pemanggil.
+func (s *schedulerService) skipJob(ctx context.Context, task model.TaskSchedule, reason string, nextOpen time.Time) error {
+	now := utils.TimeNowWIB()
+	s.log.InfoContext(ctx, "Job skipped, market is closed",
+		logger.IntField("job_id", int(task.JobID)),
+		logger.IntField("schedule_id", int(task.ID)),
+		logger.StringField("job_name", task.Job.Name),
+		logger.StringField("reason", reason),
+	)
+
+	history := &model.TaskExecutionHistory{
+		JobID:       task.JobID,
+		ScheduleID:  task.ID,
+		InstanceID:  s.instanceID,
+		Status:      model.StatusSkipped,
+		StartedAt:   now,
+		CompletedAt: sql.NullTime{Time: now, Valid: true},
+		Output:      sql.NullString{String: reason, Valid: true},
+	}
+	if err := s.jobRepo.CreateTaskExecutionHistory(ctx, history); err != nil {
+		return fmt.Errorf("failed to create task history: %w", err)
+	}
+
+	from := now
+	if !nextOpen.IsZero() && nextOpen.After(now) {
+		from = nextOpen.Add(-time.Nanosecond)
+	}
+	return s.scheduleNext(ctx, &task, from)
+}
+
+// scheduleNext menghitung eksekusi berikutnya setelah from, di zona waktu exchange schedule jika diisi
+func (s *schedulerService) scheduleNext(ctx context.Context, task *model.TaskSchedule, from time.Time) error {
+	next, err := s.NextExecution(ctx, *task, from)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to parse cron expression", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
+		return err
+	}
+	task.NextExecution = sql.NullTime{Time: next, Valid: true}
+
+	if err := s.jobRepo.UpdateTaskSchedule(ctx, task); err != nil {
 		s.log.ErrorContext(ctx, "Failed to update task schedule", logger.ErrorField(err), logger.IntField("schedule_id", int(task.ID)))
 		return fmt.Errorf("failed to update task schedule: %w", err)
 	}
 	return nil
 }
 
+func (s *schedulerService) NextExecution(ctx context.Context, task model.TaskSchedule, from time.Time) (time.Time, error) {
+	cronSchedule, err := s.cronParser.Parse(task.CronExpression)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse cron expression: %w", err)
+	}
+
+	if task.Exchange != "" {
+		from = from.In(s.calendarRepo.Get(ctx, task.Exchange).Location())
+	}
+	return cronSchedule.Next(from), nil
+}
+
 func (s *schedulerService) GetJobSchedule(ctx context.Context, param model.GetJobParam) ([]model.Job, error) {
 	return s.jobRepo.Get(ctx, &param)
 }
@@ -166,5 +663,45 @@ func (s *schedulerService) RunJobTask(ctx context.Context, jobID uint) error {
 		return fmt.Errorf("schedule not found")
 	}
 
-	return s.executeJob(ctx, job[0].Schedules[0], s.semaphore)
+	task := slices.MinFunc(job[0].Schedules, func(a, b model.TaskSchedule) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	task.Job = job[0]
+	task.Job.Schedules = nil
+
+	// run manual tidak menunggu slot, request HTTP langsung mendapat jawaban
+	select {
+	case s.semaphore <- struct{}{}:
+	default:
+		return ErrSchedulerBusy
+	}
+
+	// lease diklaim supaya run terjadwal (replika mana pun) tidak jalan bersamaan dengan run manual
+	claimed, err := s.jobRepo.ClaimTaskSchedule(ctx, task.ID, s.instanceID, s.leaseDuration())
+	if err != nil || !claimed {
+		<-s.semaphore
+		if err != nil {
+			return fmt.Errorf("failed to claim task schedule: %w", err)
+		}
+		return ErrJobAlreadyRunning
+	}
+	task.LockedBy = s.instanceID
+	releaseLease := s.holdLease(task)
+
+	history := &model.TaskExecutionHistory{
+		JobID:      task.JobID,
+		ScheduleID: task.ID,
+		Attempt:    1,
+		InstanceID: s.instanceID,
+		Status:     model.StatusRunning,
+		StartedAt:  utils.TimeNowWIB(),
+	}
+	if err := s.jobRepo.CreateTaskExecutionHistory(ctx, history); err != nil {
+		<-s.semaphore
+		releaseLease()
+		return fmt.Errorf("failed to create task history: %w", err)
+	}
+
+	s.runInBackground(ctx, task, history, s.semaphore, releaseLease)
+	return nil
 }
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-trading/config"
+	"golang-trading/internal/model"
+	"golang-trading/internal/repository"
+	"golang-trading/internal/strategy"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeJobRepository struct {
+	repository.JobRepository
+	mu           sync.Mutex
+	histories    []model.TaskExecutionHistory // salinan history per ID, goroutine scheduler tetap memegang pointer aslinya
+	due          []model.TaskSchedule         // dikembalikan sekali oleh ClaimJobsToSchedule
+	released     []uint
+	extended     int           // jumlah heartbeat lease
+	leased       map[uint]bool // lease schedule yang dipegang run lain, ditolak ClaimTaskSchedule
+	claimed      []uint
+	rescheduled  int // jumlah UpdateTaskSchedule
+	dependencies []model.JobDependency
+	jobs         []model.Job
+	saved        []model.TaskSchedule // schedule yang disimpan SaveTaskSchedule
+}
+
+func (r *fakeJobRepository) GetJobDependencies(ctx context.Context, opts ...utils.DBOption) ([]model.JobDependency, error) {
+	return r.dependencies, nil
+}
+
+func (r *fakeJobRepository) FindByID(ctx context.Context, id uint) (*model.Job, error) {
+	return &model.Job{ID: id, Timeout: 5}, nil
+}
+
+// children history pipeline milik history parentID, "job_id:status"
+func (r *fakeJobRepository) children(parentID uint) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var children []string
+	for _, history := range r.histories {
+		if history.ParentID != nil && *history.ParentID == parentID {
+			children = append(children, fmt.Sprintf("%d:%s", history.JobID, history.Status))
+		}
+	}
+	return children
+}
+
+func (r *fakeJobRepository) ClaimJobsToSchedule(ctx context.Context, instanceID string, lease time.Duration, limit int, opts ...utils.DBOption) ([]model.TaskSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	due := r.due
+	r.due = nil
+	if limit > 0 && len(due) > limit {
+		due, r.due = due[:limit], due[limit:]
+	}
+	for i := range due {
+		due[i].LockedBy = instanceID
+		due[i].LockedUntil = sql.NullTime{Time: time.Now().Add(lease), Valid: true}
+	}
+	return due, nil
+}
+
+func (r *fakeJobRepository) ExtendTaskScheduleLease(ctx context.Context, scheduleID uint, instanceID string, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extended++
+	return true, nil
+}
+
+func (r *fakeJobRepository) ReleaseTaskSchedule(ctx context.Context, scheduleID uint, instanceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.released = append(r.released, scheduleID)
+	return nil
+}
+
+func (r *fakeJobRepository) CreateTaskExecutionHistory(ctx context.Context, history *model.TaskExecutionHistory, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history.ID = uint(len(r.histories) + 1)
+	r.histories = append(r.histories, *history)
+	return nil
+}
+
+func (r *fakeJobRepository) UpdateTaskExecutionHistory(ctx context.Context, history *model.TaskExecutionHistory, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.histories[history.ID-1] = *history
+	return nil
+}
+
+func (r *fakeJobRepository) ClaimTaskSchedule(ctx context.Context, scheduleID uint, instanceID string, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.leased[scheduleID] {
+		return false, nil
+	}
+	r.claimed = append(r.claimed, scheduleID)
+	return true, nil
+}
+
+func (r *fakeJobRepository) UpdateTaskSchedule(ctx context.Context, schedule *model.TaskSchedule, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rescheduled++
+	return nil
+}
+
+func (r *fakeJobRepository) statuses() []model.TaskExecutionStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statuses := make([]model.TaskExecutionStatus, 0, len(r.histories))
+	for _, history := range r.histories {
+		statuses = append(statuses, history.Status)
+	}
+	return statuses
+}
+
+func (r *fakeJobRepository) attempts() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts := make([]int, 0, len(r.histories))
+	for _, history := range r.histories {
+		attempts = append(attempts, history.Attempt)
+	}
+	return attempts
+}
+
+// fakeTaskExecutor mengembalikan exit code berurutan per attempt, jobExitCodes untuk job selain schedule
+type fakeTaskExecutor struct {
+	jobRepo      *fakeJobRepository
+	exitCodes    []int32
+	jobExitCodes map[uint]int32
+	duration     time.Duration
+}
+
+func (e *fakeTaskExecutor) Execute(ctx context.Context, history *model.TaskExecutionHistory) error {
+	time.Sleep(e.duration)
+	exitCode, ok := e.jobExitCodes[history.JobID]
+	if !ok {
+		exitCode = e.exitCodes[history.Attempt-1]
+	}
+	history.Status = model.StatusCompleted
+	if exitCode == strategy.JOB_EXIT_CODE_FAILED {
+		history.Status = model.StatusFailed
+	}
+	history.ExitCode = sql.NullInt32{Int32: exitCode, Valid: true}
+	return e.jobRepo.UpdateTaskExecutionHistory(ctx, history)
+}
+
+func newTestScheduler(jobRepo *fakeJobRepository, exitCodes ...int32) *schedulerService {
+	cfg := &config.Config{Scheduler: config.Scheduler{MaxConcurrency: 1, InstanceID: "replica-1"}}
+	return NewSchedulerService(cfg, &logger.Logger{Logger: zap.NewNop()}, jobRepo, nil, &fakeTaskExecutor{jobRepo: jobRepo, exitCodes: exitCodes})
+}
+
+func testSchedule(retryPolicy string) model.TaskSchedule {
+	return model.TaskSchedule{
+		ID:             1,
+		JobID:          1,
+		CronExpression: "0 * * * *",
+		Job:            model.Job{ID: 1, Name: "test", Timeout: 5, RetryPolicy: []byte(retryPolicy)},
+	}
+}
+
+func TestSchedulerRetryUntilSuccess(t *testing.T) {
+	jobRepo := &fakeJobRepository{}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_FAILED, strategy.JOB_EXIT_CODE_SUCCESS)
+
+	err := s.executeJob(context.Background(), testSchedule(`{"max_retries": 2, "initial_interval": "1ms"}`), s.semaphore, func() {})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		statuses := jobRepo.statuses()
+		return len(statuses) == 2 && statuses[1] == model.StatusCompleted
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []model.TaskExecutionStatus{model.StatusFailed, model.StatusCompleted}, jobRepo.statuses())
+	assert.Equal(t, []int{1, 2}, jobRepo.attempts())
+}
+
+func TestSchedulerRetryExhaustedDeadLetter(t *testing.T) {
+	jobRepo := &fakeJobRepository{}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_PARTIAL_SUCCESS, strategy.JOB_EXIT_CODE_FAILED, strategy.JOB_EXIT_CODE_FAILED)
+
+	policy := `{"max_retries": 2, "backoff_strategy": "exponential", "initial_interval": "1ms", "retry_on_exit_codes": [500, 206]}`
+	err := s.executeJob(context.Background(), testSchedule(policy), s.semaphore, func() {})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		statuses := jobRepo.statuses()
+		return len(statuses) == 3 && statuses[2] == model.StatusDeadLetter
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []model.TaskExecutionStatus{model.StatusCompleted, model.StatusFailed, model.StatusDeadLetter}, jobRepo.statuses())
+}
+
+func TestSchedulerRetryPolicy(t *testing.T) {
+	s := newTestScheduler(&fakeJobRepository{})
+	ctx := context.Background()
+
+	// placeholder / policy tidak valid jalan tanpa retry
+	policy := s.retryPolicy(ctx, model.Job{RetryPolicy: []byte(`{"max_retries": 2, "backoff_strategy": "string", "initial_interval": "string"}`)})
+	assert.Equal(t, 0, policy.MaxRetries)
+
+	policy = s.retryPolicy(ctx, model.Job{RetryPolicy: []byte(`{"max_retries": 1, "initial_interval": "1m"}`)})
+	assert.True(t, policy.ShouldRetry(1, strategy.JOB_EXIT_CODE_FAILED))
+	assert.False(t, policy.ShouldRetry(1, strategy.JOB_EXIT_CODE_PARTIAL_SUCCESS))
+}
+
+func TestSchedulerRunWaitsForRunningJobs(t *testing.T) {
+	jobRepo := &fakeJobRepository{due: []model.TaskSchedule{testSchedule("")}}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_SUCCESS)
+	s.taskExecutor.(*fakeTaskExecutor).duration = 50 * time.Millisecond
+	s.cfg.Scheduler.PollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool { return len(jobRepo.statuses()) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	// Run baru selesai setelah job yang sedang jalan selesai
+	assert.Equal(t, []model.TaskExecutionStatus{model.StatusCompleted}, jobRepo.statuses())
+	// lease schedule yang diklaim dilepas & history mencatat instance
+	assert.Equal(t, []uint{1}, jobRepo.released)
+	assert.Equal(t, "replica-1", jobRepo.histories[0].InstanceID)
+}
+
+func TestSchedulerKeepsOtherInstanceLease(t *testing.T) {
+	jobRepo := &fakeJobRepository{}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_SUCCESS)
+
+	// schedule milik replika lain tidak dilepas oleh eksekusi instance ini
+	task := testSchedule("")
+	task.LockedBy = "replica-2"
+	assert.NoError(t, s.executeJob(context.Background(), task, s.semaphore, s.holdLease(task)))
+	assert.Eventually(t, func() bool {
+		statuses := jobRepo.statuses()
+		return len(statuses) == 1 && statuses[0] == model.StatusCompleted
+	}, time.Second, time.Millisecond)
+	s.inFlight.Wait()
+	assert.Empty(t, jobRepo.released)
+}
+
+func TestSchedulerRunPipeline(t *testing.T) {
+	// 1 -> 2 -> 3, 4 menunggu 1 & 3; 2 gagal sehingga 3 & 4 dilewati, 5 hanya menunggu 1
+	jobRepo := &fakeJobRepository{dependencies: []model.JobDependency{
+		{JobID: 2, DependsOnJobID: 1},
+		{JobID: 3, DependsOnJobID: 2},
+		{JobID: 4, DependsOnJobID: 1},
+		{JobID: 4, DependsOnJobID: 3},
+		{JobID: 5, DependsOnJobID: 1},
+	}}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_SUCCESS)
+	s.taskExecutor.(*fakeTaskExecutor).jobExitCodes = map[uint]int32{2: strategy.JOB_EXIT_CODE_FAILED, 5: strategy.JOB_EXIT_CODE_SUCCESS}
+
+	assert.NoError(t, s.executeJob(context.Background(), testSchedule(""), s.semaphore, func() {}))
+	s.inFlight.Wait()
+
+	assert.Equal(t, []string{"2:failed", "3:skipped", "4:skipped", "5:completed"}, jobRepo.children(1))
+}
+
+func TestSchedulerClaimsOnlyFreeSlots(t *testing.T) {
+	// 3 schedule jatuh tempo, 1 slot: schedule lain tidak diklaim sehingga lease-nya tidak kedaluwarsa sambil antre
+	due := []model.TaskSchedule{testSchedule(""), testSchedule(""), testSchedule("")}
+	due[1].ID, due[2].ID = 2, 3
+	jobRepo := &fakeJobRepository{due: due}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_SUCCESS)
+	s.taskExecutor.(*fakeTaskExecutor).duration = 50 * time.Millisecond
+	s.cfg.Scheduler.LeaseDuration = 15 * time.Millisecond
+
+	assert.NoError(t, s.Execute(context.Background()))
+	// slot penuh, poll berikutnya tidak mengklaim apa pun
+	assert.NoError(t, s.Execute(context.Background()))
+	s.inFlight.Wait()
+
+	jobRepo.mu.Lock()
+	defer jobRepo.mu.Unlock()
+	assert.Len(t, jobRepo.histories, 1)
+	assert.Len(t, jobRepo.due, 2)
+	assert.Equal(t, []uint{1}, jobRepo.released)
+	// lease diperpanjang selama job jalan
+	assert.Greater(t, jobRepo.extended, 0)
+}
+
+func TestSchedulerRunJobTask(t *testing.T) {
+	job := model.Job{ID: 1, Name: "test", Timeout: 5, Schedules: []model.TaskSchedule{{ID: 3, JobID: 1}, {ID: 2, JobID: 1}}}
+	jobRepo := &fakeJobRepository{jobs: []model.Job{job}, leased: map[uint]bool{}}
+	s := newTestScheduler(jobRepo, strategy.JOB_EXIT_CODE_SUCCESS)
+	ctx := context.Background()
+
+	// schedule dengan ID terkecil dipakai, jadwal cron tidak diubah
+	assert.NoError(t, s.RunJobTask(ctx, 1))
+	s.inFlight.Wait()
+	assert.Equal(t, []uint{2}, jobRepo.claimed)
+	assert.Equal(t, []uint{2}, jobRepo.released)
+	assert.Equal(t, 0, jobRepo.rescheduled)
+	assert.Equal(t, []model.TaskExecutionStatus{model.StatusCompleted}, jobRepo.statuses())
+	assert.Equal(t, uint(2), jobRepo.histories[0].ScheduleID)
+
+	// lease dipegang run lain, slot dikembalikan
+	jobRepo.leased[2] = true
+	assert.ErrorIs(t, s.RunJobTask(ctx, 1), ErrJobAlreadyRunning)
+	assert.Empty(t, s.semaphore)
+
+	// slot penuh, langsung ditolak tanpa menunggu
+	jobRepo.leased[2] = false
+	s.semaphore <- struct{}{}
+	assert.ErrorIs(t, s.RunJobTask(ctx, 1), ErrSchedulerBusy)
+	assert.Len(t, jobRepo.statuses(), 1)
+}
@@ -169,14 +169,15 @@ func (s *sendSignalService) SendBuySignal(ctx context.Context, analyses []model.
 
 	sb.WriteString("\n")
 
-	sb.WriteString("👉 <i>Klik tombol di bawah ini untuk melihat detail analisa</i>")
+	footer := "👉 <i>Klik tombol di bawah ini untuk melihat detail analisa</i>"
 	menu := &telebot.ReplyMarkup{}
 	btnAnalyze := menu.Data("📄 Detail Analisa", "btn_general_analisis", fmt.Sprintf("%s:%s", analyses[0].Exchange, analyses[0].StockCode))
 	btnDeleteMessage := menu.Data("🗑️ Hapus Pesan", "btn_delete_message")
 	menu.Inline(menu.Row(btnAnalyze, btnDeleteMessage))
 
 	for _, user := range userMap {
-		errSend := s.telegram.SendMessageUser(ctx, sb.String(), user.TelegramID, menu, telebot.ModeHTML)
+		msg := sb.String() + userPositionSizeText(*tradePlan, user) + footer
+		errSend := s.telegram.SendMessageUser(ctx, msg, user.TelegramID, menu, telebot.ModeHTML)
 		if errSend != nil {
 			s.log.ErrorContextWithAlert(ctx, "Failed to send buy signal", logger.ErrorField(errSend))
 		}
@@ -184,6 +185,19 @@ func (s *sendSignalService) SendBuySignal(ctx context.Context, analyses []model.
 	return true, nil
 }
 
+// userPositionSizeText ukuran posisi sesuai modal & risk per trade masing-masing user
+func userPositionSizeText(tradePlan dto.TradePlanResult, user model.User) string {
+	sizing := dto.PositionSizing{AccountSize: user.AccountSize, RiskPerTradePercent: user.RiskPerTradePercent}
+	if !sizing.IsSet() {
+		return ""
+	}
+	applyPositionSizing(&tradePlan, sizing)
+	if tradePlan.Quantity == 0 {
+		return "⚖️ <b>Size</b>: modal tidak cukup untuk 1 lot sesuai risk per trade\n\n"
+	}
+	return fmt.Sprintf("⚖️ <b>Size</b>: %s\n\n", tradePlan.PositionSizeText())
+}
+
 func (s *sendSignalService) GenerateHashIdentifier(ctx context.Context, analyses []model.StockAnalysis, score, marketPrice float64) string {
 
 	parts := []string{
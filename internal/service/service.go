@@ -11,11 +11,14 @@ import (
 
 type Service struct {
 	SchedulerService   SchedulerService
+	JobService         JobService
 	TaskExecutor       TaskExecutor
 	TelegramBotService TelegramBotService
 	TradingService     TradingService
 	BacktestService    BacktestService
 	SendSignalService  SendSignalService
+	MarketDataService  MarketDataService
+	PriceStreamService PriceStreamService
 }
 
 func NewService(
@@ -25,31 +28,36 @@ func NewService(
 	inmemoryCache cache.Cache,
 	telegram *telegram.TelegramRateLimiter,
 ) *Service {
-	tradingService := NewTradingService(cfg, log, repo.SystemParamRepo)
+	tradingService := NewTradingService(cfg, log, repo.SystemParamRepo, repo.TimeframeProfileRepo)
 	signalService := NewSendSignalService(cfg, log, telegram, repo.StockPositionsRepo, repo.UserSignalAlertRepo, tradingService, inmemoryCache)
 
-	analyzerStrategy := strategy.NewStockAnalyzerStrategy(cfg, log, inmemoryCache, repo.StockPositionsRepo, repo.TradingViewScreenersRepo, repo.CandleRepo, repo.StockAnalysisRepo, repo.SystemParamRepo, repo.UserSignalAlertRepo, telegram, tradingService, signalService)
+	analyzerStrategy := strategy.NewStockAnalyzerStrategy(cfg, log, inmemoryCache, repo.StockPositionsRepo, repo.TradingViewScreenersRepo, repo.CandleRepo, repo.TradingCalendarRepo, repo.StockAnalysisRepo, repo.TimeframeProfileRepo, repo.UserSignalAlertRepo, telegram, tradingService, signalService)
 	buySignalGeneratorStrategy := strategy.NewBuySignalGeneratorStrategy(cfg, log, repo.CandleRepo, inmemoryCache, signalService, repo.StockAnalysisRepo)
 	stockPositionMonitoringStrategy := strategy.NewStockPositionMonitoringStrategy(log, cfg, inmemoryCache, repo.TradingViewScreenersRepo, telegram, repo.StockPositionsRepo, analyzerStrategy, repo.StockPositionMonitoringRepo, repo.SystemParamRepo, tradingService)
 	executorStrategies := make(map[strategy.JobType]strategy.JobExecutionStrategy)
-	executorStrategies[strategy.JobTypeStockPriceAlert] = strategy.NewStockPriceAlertStrategy(cfg, log, inmemoryCache, repo.TradingViewScreenersRepo, telegram, repo.StockPositionsRepo, repo.CandleRepo)
+	priceAlertStrategy := strategy.NewStockPriceAlertStrategy(cfg, log, inmemoryCache, repo.TradingViewScreenersRepo, telegram, repo.StockPositionsRepo, repo.CandleRepo)
+	executorStrategies[strategy.JobTypeStockPriceAlert] = priceAlertStrategy
 	executorStrategies[strategy.JobTypeStockAnalyzer] = analyzerStrategy
 	executorStrategies[strategy.JobTypeBuySignalGenerator] = buySignalGeneratorStrategy
 	executorStrategies[strategy.JobTypeStockPositionMonitor] = stockPositionMonitoringStrategy
 	executorStrategies[strategy.JobTypeDataCleanUp] = strategy.NewDataCleanUpStrategy(cfg, log, repo.StockAnalysisRepo, repo.JobRepo)
+	executorStrategies[strategy.JobTypeCorporateActionAdjuster] = strategy.NewCorporateActionAdjusterStrategy(log, repo.CorporateActionRepo, repo.UnitOfWork, repo.StockPositionsRepo, repo.StockPositionTransactionRepo, repo.CandleRepo, telegram)
 
 	taskExecutor := NewTaskExecutor(cfg, log, repo.JobRepo, executorStrategies)
 
-	schedulerService := NewSchedulerService(cfg, log, repo.JobRepo, taskExecutor)
-	telegramBotService := NewTelegramBotService(log, cfg, telegram, inmemoryCache, repo.StockAnalysisRepo, repo.SystemParamRepo, analyzerStrategy, stockPositionMonitoringStrategy, repo.GeminiAIRepo, repo.UserRepo, repo.StockPositionsRepo, repo.StockPositionMonitoringRepo, repo.UnitOfWork, repo.UserSignalAlertRepo)
-	backtestService := NewBacktestService(log, tradingService, repo.StockAnalysisRepo)
+	schedulerService := NewSchedulerService(cfg, log, repo.JobRepo, repo.TradingCalendarRepo, taskExecutor)
+	telegramBotService := NewTelegramBotService(log, cfg, telegram, inmemoryCache, repo.StockAnalysisRepo, repo.SystemParamRepo, repo.TimeframeProfileRepo, analyzerStrategy, stockPositionMonitoringStrategy, repo.GeminiAIRepo, repo.UserRepo, repo.StockPositionsRepo, repo.StockPositionMonitoringRepo, repo.UnitOfWork, repo.UserSignalAlertRepo, repo.StockPositionTransactionRepo)
+	backtestService := NewBacktestService(log, tradingService, repo.StockAnalysisRepo, repo.CandleRepo, repo.SystemParamRepo, repo.TimeframeProfileRepo, repo.TradingViewScreenersRepo)
 
 	return &Service{
 		SchedulerService:   schedulerService,
+		JobService:         NewJobService(log, repo.JobRepo, repo.UnitOfWork, schedulerService),
 		TaskExecutor:       taskExecutor,
 		TelegramBotService: telegramBotService,
 		TradingService:     tradingService,
 		BacktestService:    backtestService,
 		SendSignalService:  signalService,
+		MarketDataService:  NewMarketDataService(repo.MarketDataRegistry),
+		PriceStreamService: NewPriceStreamService(cfg, log, repo.PriceStreams, repo.StockPositionsRepo, priceAlertStrategy),
 	}
 }
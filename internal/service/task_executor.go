@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"golang-trading/config"
 	"golang-trading/internal/model"
@@ -41,17 +42,25 @@ func (t *taskExecutor) Execute(ctx context.Context, taskHistory *model.TaskExecu
 		return fmt.Errorf("failed to find job: %w", err)
 	}
 
-	strategy := t.executorStrategies[strategy.JobType(job.Type)]
-	if strategy == nil {
+	executor := t.executorStrategies[strategy.JobType(job.Type)]
+	if executor == nil {
 		t.log.ErrorContext(ctx, "Job type not found", logger.IntField("job_id", int(taskHistory.JobID)))
 		taskHistory.Status = model.StatusFailed
+		taskHistory.ExitCode = sql.NullInt32{Int32: strategy.JOB_EXIT_CODE_FAILED, Valid: true}
 		taskHistory.ErrorMessage = sql.NullString{String: "job type not found", Valid: true}
 	} else {
-		result, err := strategy.Execute(ctx, job)
+		result, err := executor.Execute(ctx, job)
 		if err != nil {
-			t.log.ErrorContextWithAlert(ctx, "Failed to execute job", logger.ErrorField(err), logger.IntField("job_id", int(taskHistory.JobID)))
+			// alert dikirim scheduler setelah retry policy diputuskan, bukan per attempt
+			t.log.ErrorContext(ctx, "Failed to execute job", logger.ErrorField(err), logger.IntField("job_id", int(taskHistory.JobID)), logger.IntField("attempt", taskHistory.Attempt))
 			taskHistory.Status = model.StatusFailed
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				taskHistory.Status = model.StatusTimeout
+			}
 			taskHistory.ErrorMessage = sql.NullString{String: err.Error(), Valid: true}
+			if result.ExitCode == 0 {
+				result.ExitCode = strategy.JOB_EXIT_CODE_FAILED
+			}
 		} else {
 			taskHistory.Status = model.StatusCompleted
 		}
@@ -65,7 +74,8 @@ func (t *taskExecutor) Execute(ctx context.Context, taskHistory *model.TaskExecu
 	}
 
 	taskHistory.CompletedAt = sql.NullTime{Time: utils.TimeNowWIB(), Valid: true}
-	if err := t.jobRepo.UpdateTaskExecutionHistory(ctx, taskHistory); err != nil {
+	// context job bisa sudah timeout, history tetap harus tersimpan
+	if err := t.jobRepo.UpdateTaskExecutionHistory(context.WithoutCancel(ctx), taskHistory); err != nil {
 		t.log.ErrorContext(ctx, "Failed to update task execution history", logger.ErrorField(err), logger.IntField("job_id", int(taskHistory.JobID)))
 		return fmt.Errorf("failed to update task execution history: %w", err)
 	}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"golang-trading/config"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/internal/repository"
@@ -13,11 +15,19 @@ import (
 	"golang-trading/pkg/logger"
 	"golang-trading/pkg/telegram"
 	"golang-trading/pkg/utils"
+	"math"
+	"slices"
 	"strings"
 
 	"gopkg.in/telebot.v3"
 )
 
+var (
+	ErrPositionAlreadyExists      = errors.New("position already exists")
+	ErrPositionQuantityNotTracked = errors.New("position quantity is not tracked")
+	ErrShortNotSupported          = errors.New("short position is not supported on this exchange")
+)
+
 type TelegramBotService interface {
 	ExecuteStockAnalyzer(ctx context.Context, symbol string) ([]model.StockAnalysis, error)
 	AnalyzeStock(ctx context.Context, c telebot.Context, symbol string) ([]model.StockAnalysis, error)
@@ -31,6 +41,12 @@ type TelegramBotService interface {
 	GetAlertSignal(ctx context.Context, telegramID int64) ([]model.UserSignalAlert, error)
 	SetAlertSignal(ctx context.Context, telegramID int64, exchange string, isActive bool) error
 	AnalyzePosition(ctx context.Context, stockPosition model.StockPosition) error
+	AddPositionTransaction(ctx context.Context, telegramID int64, data *dto.RequestPositionTransactionData) (*dto.PositionLedger, error)
+	GetPositionSizing(ctx context.Context, telegramID int64) (dto.PositionSizing, error)
+	SetPositionSizing(ctx context.Context, userTelegram *dto.RequestUserTelegram, sizing dto.PositionSizing) error
+	GetTimeframeProfiles(ctx context.Context) ([]dto.TimeframeProfile, error)
+	GetTimeframeProfile(ctx context.Context, telegramID int64) (string, error)
+	SetTimeframeProfile(ctx context.Context, userTelegram *dto.RequestUserTelegram, profile string) error
 }
 
 type telegramBotService struct {
@@ -40,6 +56,7 @@ type telegramBotService struct {
 	inmemoryCache                     cache.Cache
 	stockAnalysisRepository           repository.StockAnalysisRepository
 	systemParamRepository             repository.SystemParamRepository
+	timeframeProfileRepository        repository.TimeframeProfileRepository
 	stockAnalyzer                     strategy.StockAnalyzer
 	positionMonitoringStrategy        strategy.PositionMonitoringEvaluator
 	aiRepository                      repository.AIRepository
@@ -48,6 +65,7 @@ type telegramBotService struct {
 	stockPositionMonitoringRepository repository.StockPositionMonitoringRepository
 	uow                               repository.UnitOfWork
 	userSignalAlertRepository         repository.UserSignalAlertRepository
+	stockPositionTransactionRepo      repository.StockPositionTransactionRepository
 }
 
 func NewTelegramBotService(
@@ -57,6 +75,7 @@ func NewTelegramBotService(
 	inmemoryCache cache.Cache,
 	stockAnalysisRepository repository.StockAnalysisRepository,
 	systemParamRepository repository.SystemParamRepository,
+	timeframeProfileRepository repository.TimeframeProfileRepository,
 	stockAnalyzer strategy.StockAnalyzer,
 	positionMonitoringStrategy strategy.PositionMonitoringEvaluator,
 	aiRepository repository.AIRepository,
@@ -65,6 +84,7 @@ func NewTelegramBotService(
 	stockPositionMonitoringRepository repository.StockPositionMonitoringRepository,
 	uow repository.UnitOfWork,
 	userSignalAlertRepository repository.UserSignalAlertRepository,
+	stockPositionTransactionRepo repository.StockPositionTransactionRepository,
 ) TelegramBotService {
 	return &telegramBotService{
 		log:                               log,
@@ -73,6 +93,7 @@ func NewTelegramBotService(
 		inmemoryCache:                     inmemoryCache,
 		stockAnalysisRepository:           stockAnalysisRepository,
 		systemParamRepository:             systemParamRepository,
+		timeframeProfileRepository:        timeframeProfileRepository,
 		stockAnalyzer:                     stockAnalyzer,
 		positionMonitoringStrategy:        positionMonitoringStrategy,
 		aiRepository:                      aiRepository,
@@ -81,6 +102,7 @@ func NewTelegramBotService(
 		stockPositionMonitoringRepository: stockPositionMonitoringRepository,
 		uow:                               uow,
 		userSignalAlertRepository:         userSignalAlertRepository,
+		stockPositionTransactionRepo:      stockPositionTransactionRepo,
 	}
 }
 
@@ -119,11 +141,19 @@ func (s *telegramBotService) ExecuteStockAnalyzer(ctx context.Context, symbol st
 }
 
 func (s *telegramBotService) GetLatestAnalyses(ctx context.Context, stockCode string, exchange string) ([]model.StockAnalysis, error) {
+	profile, err := s.timeframeProfileRepository.Resolve(ctx, exchange, stockCode)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to resolve timeframe profile", logger.ErrorField(err))
+		return nil, err
+	}
+
+	// hanya analisa dengan timeframe profil yang sama yang dipakai ulang
 	latestAnalyses, err := s.stockAnalysisRepository.GetLatestAnalyses(ctx, model.GetLatestAnalysisParam{
 		StockCode:       stockCode,
 		Exchange:        exchange,
 		TimestampAfter:  utils.TimeNowWIB().Add(-s.cfg.Telegram.FeatureStockAnalyze.AfterTimestampDuration),
-		ExpectedTFCount: s.cfg.Telegram.FeatureStockAnalyze.ExpectedTFCount,
+		Timeframes:      profile.Intervals(),
+		ExpectedTFCount: len(profile.Timeframes),
 	})
 
 	if err != nil {
@@ -182,6 +212,10 @@ func (s *telegramBotService) AnalyzeStockAI(ctx context.Context, c telebot.Conte
 }
 
 func (s *telegramBotService) SetStockPosition(ctx context.Context, data *dto.RequestSetPositionData) error {
+	if dto.IsShort(data.Side) && !costmodel.ForExchange(data.Exchange).Shortable {
+		return ErrShortNotSupported
+	}
+
 	user, err := s.userRepo.GetUserByTelegramID(ctx, data.UserTelegram.ID)
 	if err != nil {
 		s.log.ErrorContext(ctx, "Failed to get user", logger.ErrorField(err))
@@ -200,7 +234,7 @@ func (s *telegramBotService) SetStockPosition(ctx context.Context, data *dto.Req
 	}
 	if len(positions) > 0 {
 		s.log.WarnContext(ctx, "position already exists", logger.IntField("count", len(positions)))
-		return fmt.Errorf("position already exists")
+		return ErrPositionAlreadyExists
 	}
 
 	err = s.uow.Run(func(opts ...utils.DBOption) error {
@@ -222,6 +256,15 @@ func (s *telegramBotService) SetStockPosition(ctx context.Context, data *dto.Req
 			s.log.ErrorContext(ctx, "Failed to create stock position", logger.ErrorField(err))
 			return fmt.Errorf("failed to create stock position: %w", err)
 		}
+
+		if stockPosition.Quantity > 0 {
+			tx := dto.NewPositionTransaction(costmodel.ForExchange(stockPosition.Exchange), dto.OpenTransactionType(stockPosition.Side), stockPosition.Quantity, stockPosition.BuyPrice, stockPosition.BuyDate)
+			tx.StockPositionID = stockPosition.ID
+			if err := s.stockPositionTransactionRepo.Create(ctx, &tx, opts...); err != nil {
+				s.log.ErrorContext(ctx, "Failed to create stock position transaction", logger.ErrorField(err))
+				return fmt.Errorf("failed to create stock position transaction: %w", err)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -319,14 +362,132 @@ func (s *telegramBotService) ExitStockPosition(ctx context.Context, telegramID i
 
 	positions[0].IsActive = utils.ToPointer(false)
 
-	return s.stockPositionRepository.Update(ctx, positions[0])
+	cost := costmodel.ForExchange(positions[0].Exchange)
+	ledger := dto.BuildPositionLedger(positions[0], cost)
+
+	return s.uow.Run(func(opts ...utils.DBOption) error {
+		// sisa quantity dicatat sebagai transaksi penutup supaya realized P/L lengkap
+		if ledger.RemainingQuantity > 0 && positions[0].ExitPrice != nil && positions[0].ExitDate != nil {
+			if err := s.ensureInitialTransaction(ctx, &positions[0], cost, opts...); err != nil {
+				return err
+			}
+			tx := dto.NewPositionTransaction(cost, dto.CloseTransactionType(positions[0].Side), ledger.RemainingQuantity, *positions[0].ExitPrice, *positions[0].ExitDate)
+			tx.StockPositionID = positions[0].ID
+			if err := s.stockPositionTransactionRepo.Create(ctx, &tx, opts...); err != nil {
+				return fmt.Errorf("failed to create stock position transaction: %w", err)
+			}
+		}
+		return s.stockPositionRepository.Update(ctx, positions[0], opts...)
+	})
+}
+
+// AddPositionTransaction mencatat tambahan posisi (average down / scaling-in) atau penutupan sebagian.
+// Harga entry & quantity posisi diperbarui mengikuti average cost, posisi ditutup jika quantity habis.
+func (s *telegramBotService) AddPositionTransaction(ctx context.Context, telegramID int64, data *dto.RequestPositionTransactionData) (*dto.PositionLedger, error) {
+	positions, err := s.stockPositionRepository.Get(ctx, dto.GetStockPositionsParam{
+		TelegramID: &telegramID,
+		IDs:        []uint{data.StockPositionID},
+		IsActive:   utils.ToPointer(true),
+		Monitoring: &dto.StockPositionMonitoringQueryParam{
+			ShowNewest: utils.ToPointer(true),
+			Limit:      utils.ToPointer(1),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock positions: %w", err)
+	}
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("position not found")
+	}
+
+	position := positions[0]
+	cost := costmodel.ForExchange(position.Exchange)
+	ledger := dto.BuildPositionLedger(position, cost)
+	if !ledger.IsTracked() {
+		return nil, ErrPositionQuantityNotTracked
+	}
+
+	quantity := cost.RoundQuantity(data.Quantity)
+	if quantity <= 0 || data.Price <= 0 {
+		return nil, fmt.Errorf("invalid quantity or price")
+	}
+	if data.Type == dto.CloseTransactionType(position.Side) && quantity > ledger.RemainingQuantity {
+		return nil, fmt.Errorf("close quantity %.8g exceeds remaining quantity %.8g", quantity, ledger.RemainingQuantity)
+	}
+
+	tx := dto.NewPositionTransaction(cost, data.Type, quantity, data.Price, data.Date)
+	tx.StockPositionID = position.ID
+
+	err = s.uow.Run(func(opts ...utils.DBOption) error {
+		if err := s.ensureInitialTransaction(ctx, &position, cost, opts...); err != nil {
+			return err
+		}
+		if err := s.stockPositionTransactionRepo.Create(ctx, &tx, opts...); err != nil {
+			return fmt.Errorf("failed to create stock position transaction: %w", err)
+		}
+		position.Transactions = append(position.Transactions, tx)
+		ledger = dto.BuildPositionLedger(position, cost)
+
+		if ledger.RemainingQuantity > 0 {
+			position.BuyPrice = ledger.AveragePrice
+			position.Quantity = ledger.RemainingQuantity
+			if data.MoveStopLossToBreakEven {
+				position.StopLossPrice = breakEvenStopLoss(position, ledger, cost)
+			}
+		} else {
+			position.IsActive = utils.ToPointer(false)
+			position.ExitPrice = utils.ToPointer(ledger.AverageExitPrice)
+			position.ExitDate = utils.ToPointer(ledger.LastExitDate)
+			if len(position.StockPositionMonitorings) > 0 {
+				var evalSummary model.PositionTechnicalAnalysisSummary
+				if err := json.Unmarshal(position.StockPositionMonitorings[0].EvaluationSummary, &evalSummary); err == nil {
+					position.FinalScore = evalSummary.Score
+				}
+			}
+		}
+		return s.stockPositionRepository.Update(ctx, position, opts...)
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to add stock position transaction", logger.ErrorField(err))
+		return nil, err
+	}
+
+	return &ledger, nil
+}
+
+// breakEvenStopLoss SL digeser ke harga break even sisa posisi, tidak pernah melonggarkan SL yang sudah ada
+func breakEvenStopLoss(position model.StockPosition, ledger dto.PositionLedger, cost costmodel.Model) float64 {
+	if dto.IsShort(position.Side) {
+		return math.Min(position.StopLossPrice, cost.RoundTickDown(ledger.AverageCost))
+	}
+	return math.Max(position.StopLossPrice, cost.RoundTickUp(ledger.AverageCost))
+}
+
+// ensureInitialTransaction menyimpan transaksi entry awal untuk posisi lama yang belum punya riwayat transaksi
+func (s *telegramBotService) ensureInitialTransaction(ctx context.Context, position *model.StockPosition, cost costmodel.Model, opts ...utils.DBOption) error {
+	if len(position.Transactions) > 0 || position.Quantity <= 0 {
+		return nil
+	}
+	tx := dto.NewPositionTransaction(cost, dto.OpenTransactionType(position.Side), position.Quantity, position.BuyPrice, position.BuyDate)
+	tx.StockPositionID = position.ID
+	if err := s.stockPositionTransactionRepo.Create(ctx, &tx, opts...); err != nil {
+		return fmt.Errorf("failed to create initial stock position transaction: %w", err)
+	}
+	position.Transactions = append(position.Transactions, tx)
+	return nil
 }
 
 func (s *telegramBotService) GetAllLatestAnalyses(ctx context.Context, exchange string) ([]model.StockAnalysis, error) {
+	// analisa dari job memakai profil symbol / exchange, jumlah timeframe mengikuti profil exchange
+	profile, err := s.timeframeProfileRepository.Resolve(ctx, exchange, "")
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to resolve timeframe profile", logger.ErrorField(err))
+		return nil, err
+	}
 
 	latestAnalyses, err := s.stockAnalysisRepository.GetLatestAnalyses(ctx, model.GetLatestAnalysisParam{
 		TimestampAfter:  utils.TimeNowWIB().Add(-s.cfg.Telegram.FeatureStockAnalyze.AfterTimestampDuration),
-		ExpectedTFCount: s.cfg.Telegram.FeatureStockAnalyze.ExpectedTFCount,
+		ExpectedTFCount: len(profile.Timeframes),
 		Exchange:        exchange,
 	})
 
@@ -368,3 +529,101 @@ func (s *telegramBotService) AnalyzePosition(ctx context.Context, stockPosition
 	_, err := s.positionMonitoringStrategy.EvaluateStockPosition(ctx, []model.StockPosition{stockPosition}, 1)
 	return err
 }
+
+// GetPositionSizing modal & risk per trade user, kosong jika user belum terdaftar / belum mengatur
+func (s *telegramBotService) GetPositionSizing(ctx context.Context, telegramID int64) (dto.PositionSizing, error) {
+	user, err := s.userRepo.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get user", logger.ErrorField(err))
+		return dto.PositionSizing{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return dto.PositionSizing{}, nil
+	}
+	return dto.PositionSizing{
+		AccountSize:         user.AccountSize,
+		RiskPerTradePercent: user.RiskPerTradePercent,
+	}, nil
+}
+
+func (s *telegramBotService) SetPositionSizing(ctx context.Context, userTelegram *dto.RequestUserTelegram, sizing dto.PositionSizing) error {
+	user, err := s.userRepo.GetUserByTelegramID(ctx, userTelegram.ID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get user", logger.ErrorField(err))
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user == nil {
+		user = userTelegram.ToUserEntity()
+		user.AccountSize = sizing.AccountSize
+		user.RiskPerTradePercent = sizing.RiskPerTradePercent
+		if err := s.userRepo.CreateUser(ctx, user); err != nil {
+			s.log.ErrorContext(ctx, "Failed to create user", logger.ErrorField(err))
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return nil
+	}
+
+	user.AccountSize = sizing.AccountSize
+	user.RiskPerTradePercent = sizing.RiskPerTradePercent
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		s.log.ErrorContext(ctx, "Failed to update user position sizing", logger.ErrorField(err))
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+func (s *telegramBotService) GetTimeframeProfiles(ctx context.Context) ([]dto.TimeframeProfile, error) {
+	return s.timeframeProfileRepository.Profiles(ctx)
+}
+
+// GetTimeframeProfile preferensi profil timeframe user, kosong jika user belum memilih (otomatis per exchange)
+func (s *telegramBotService) GetTimeframeProfile(ctx context.Context, telegramID int64) (string, error) {
+	user, err := s.userRepo.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get user", logger.ErrorField(err))
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return "", nil
+	}
+	return user.TimeframeProfile, nil
+}
+
+// SetTimeframeProfile menyimpan preferensi profil timeframe user, kosong untuk kembali ke profil symbol / exchange
+func (s *telegramBotService) SetTimeframeProfile(ctx context.Context, userTelegram *dto.RequestUserTelegram, profile string) error {
+	profile = strings.ToLower(strings.TrimSpace(profile))
+	if profile != "" {
+		profiles, err := s.timeframeProfileRepository.Profiles(ctx)
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to get timeframe profiles", logger.ErrorField(err))
+			return fmt.Errorf("failed to get timeframe profiles: %w", err)
+		}
+		if !slices.ContainsFunc(profiles, func(p dto.TimeframeProfile) bool { return p.Name == profile }) {
+			return fmt.Errorf("timeframe profile %q not found", profile)
+		}
+	}
+
+	user, err := s.userRepo.GetUserByTelegramID(ctx, userTelegram.ID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get user", logger.ErrorField(err))
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user == nil {
+		user = userTelegram.ToUserEntity()
+		user.TimeframeProfile = profile
+		if err := s.userRepo.CreateUser(ctx, user); err != nil {
+			s.log.ErrorContext(ctx, "Failed to create user", logger.ErrorField(err))
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return nil
+	}
+
+	user.TimeframeProfile = profile
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		s.log.ErrorContext(ctx, "Failed to update user timeframe profile", logger.ErrorField(err))
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"golang-trading/config"
 	"golang-trading/internal/contract"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/internal/repository"
@@ -14,6 +15,7 @@ import (
 	"golang-trading/pkg/logger"
 	"golang-trading/pkg/utils"
 	"math"
+	"slices"
 	"sort"
 )
 
@@ -25,26 +27,47 @@ type TradingService interface {
 }
 
 type tradingService struct {
-	cfg                   *config.Config
-	log                   *logger.Logger
-	systemParamRepository repository.SystemParamRepository
+	cfg                        *config.Config
+	log                        *logger.Logger
+	systemParamRepository      repository.SystemParamRepository
+	timeframeProfileRepository repository.TimeframeProfileRepository
 }
 
 func NewTradingService(
 	cfg *config.Config,
 	log *logger.Logger,
 	systemParamRepository repository.SystemParamRepository,
+	timeframeProfileRepository repository.TimeframeProfileRepository,
 ) TradingService {
 	return &tradingService{
-		cfg:                   cfg,
-		log:                   log,
-		systemParamRepository: systemParamRepository,
+		cfg:                        cfg,
+		log:                        log,
+		systemParamRepository:      systemParamRepository,
+		timeframeProfileRepository: timeframeProfileRepository,
 	}
 }
 
+// analysisTimeframes timeframe (weight & timeframe utama) dari profil yang dipakai saat analyses dibuat
+func (s *tradingService) analysisTimeframes(ctx context.Context, exchange, stockCode string, analyses []model.StockAnalysis) ([]dto.DataTimeframe, error) {
+	intervals := make([]string, 0, len(analyses))
+	for _, analysis := range analyses {
+		intervals = append(intervals, analysis.Timeframe)
+	}
+
+	profile, err := s.timeframeProfileRepository.Match(ctx, exchange, stockCode, intervals)
+	if err != nil {
+		return nil, err
+	}
+	// salinan, findMainAnalysisData mengurutkan timeframe
+	return slices.Clone(profile.Timeframes), nil
+}
+
 // CalculateSupportResistance menghitung level support dan resistance dari data analisis teknikal.
 func (s *tradingService) CalculateSupportResistance(ctx context.Context, analyses []model.StockAnalysis) ([]dto.Level, []dto.Level, error) {
-	timeframes, err := s.systemParamRepository.GetDefaultAnalysisTimeframes(ctx)
+	if len(analyses) == 0 {
+		return nil, nil, fmt.Errorf("no main analysis data found to calculate S/R")
+	}
+	timeframes, err := s.analysisTimeframes(ctx, analyses[0].Exchange, analyses[0].StockCode, analyses)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -82,9 +105,11 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 		return nil, fmt.Errorf("no latest analysis")
 	}
 
-	timeframes, err := s.systemParamRepository.GetDefaultAnalysisTimeframes(ctx)
+	lastAnalysis := latestAnalyses[len(latestAnalyses)-1]
+
+	timeframes, err := s.analysisTimeframes(ctx, lastAnalysis.Exchange, lastAnalysis.StockCode, latestAnalyses)
 	if err != nil {
-		s.log.ErrorContext(ctx, "Failed to get default analysis timeframes", logger.ErrorField(err))
+		s.log.ErrorContext(ctx, "Failed to get analysis timeframes", logger.ErrorField(err))
 		return nil, err
 	}
 
@@ -97,11 +122,16 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 		}
 	}
 
-	lastAnalysis := latestAnalyses[len(latestAnalyses)-1]
+	side := tradeSideFromContext(ctx)
+	if dto.IsShort(side) && !costmodel.ForExchange(lastAnalysis.Exchange).Shortable {
+		return nil, ErrShortNotSupported
+	}
 
 	stockCodeWithExchange := lastAnalysis.Exchange + ":" + lastAnalysis.StockCode
-	cacheKey := fmt.Sprintf(common.KEY_LAST_PRICE, stockCodeWithExchange)
-	marketPrice, _ = cache.GetFromCache[float64](cacheKey)
+	if !isBacktestMode(ctx) {
+		cacheKey := fmt.Sprintf(common.KEY_LAST_PRICE, stockCodeWithExchange)
+		marketPrice, _ = cache.GetFromCache[float64](cacheKey)
+	}
 
 	if marketPrice == 0 {
 		marketPrice = lastAnalysis.MarketPrice
@@ -145,11 +175,19 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 	s.log.DebugContext(ctx, "Create Trade Plan", logger.StringField("stock_code", stockCodeWithExchange))
 
 	// Calculate ATR using the main timeframe's candles
+	params, paramsVersion := s.resolveTradePlanParams(ctx, lastAnalysis.Exchange, highestWeightTF)
+
 	atr14 := s.calculateATR(mainTFCandles, 14)
-	slAtrMultiplier := s.getATRMultiplierForSL(&tfHighestTechnicalData)
+	slAtrMultiplier := s.getATRMultiplierForSL(&tfHighestTechnicalData, params)
 
-	entryResult := s.calculateSmartEntry(float64(marketPrice), &tfHighestTechnicalData, mainTFCandles, supports, resistances, emaData)
-	plan := s.calculatePlan(entryResult.Price, supports, resistances, emaData, priceBuckets, atr14, slAtrMultiplier, &tfHighestTechnicalData)
+	var entryResult EntryResult
+	if dto.IsShort(side) {
+		entryResult = s.calculateShortEntry(float64(marketPrice), &tfHighestTechnicalData, mainTFCandles, resistances)
+	} else {
+		entryResult = s.calculateSmartEntry(float64(marketPrice), &tfHighestTechnicalData, mainTFCandles, supports, resistances, emaData)
+	}
+	plan := s.calculatePlan(side, entryResult.Price, supports, resistances, emaData, priceBuckets, atr14, slAtrMultiplier, &tfHighestTechnicalData, params)
+	plan = roundPlanToTick(plan, costmodel.ForExchange(lastAnalysis.Exchange))
 
 	positionAnalysis, err := s.EvaluatePositionMonitoring(ctx, &model.StockPosition{
 		StockCode:       lastAnalysis.StockCode,
@@ -157,6 +195,7 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 		BuyPrice:        plan.Entry,
 		TakeProfitPrice: plan.TakeProfit,
 		StopLossPrice:   plan.StopLoss,
+		Side:            side,
 	}, latestAnalyses, supports, resistances)
 
 	if err != nil {
@@ -169,6 +208,10 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 		s.log.ErrorContext(ctx, "Failed to calculate summary score", logger.ErrorField(err))
 		return nil, err
 	}
+	if dto.IsShort(side) {
+		// summary teknikal tinggi berarti bullish, dicerminkan supaya skor tinggi selalu searah plan
+		scoreTA = 100 - scoreTA
+	}
 
 	finalScore := (float64(scoreTA)*0.7 + float64(plan.Score)*0.3)
 
@@ -184,7 +227,8 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 		Score:              finalScore,
 		PositionScore:      positionAnalysis.Score,
 		PlanScore:          plan.Score,
-		IsBuySignal:        positionAnalysis.TechnicalSignal == dto.SignalBuy || positionAnalysis.TechnicalSignal == dto.SignalStrongBuy,
+		IsBuySignal:        dto.IsSignalWithSide(side, positionAnalysis.TechnicalSignal),
+		Side:               side,
 		SLReason:           plan.SLReason,
 		TPReason:           plan.TPReason,
 		EntryReason:        entryResult.Reason,
@@ -192,6 +236,11 @@ func (s *tradingService) CreateTradePlan(ctx context.Context, latestAnalyses []m
 		Insights:           positionAnalysis.Insight,
 		Exchange:           lastAnalysis.Exchange,
 		PlanType:           plan.PlanType,
+		Params:             params,
+		ParamsVersion:      paramsVersion,
+	}
+	if sizing, ok := positionSizingFromContext(ctx); ok {
+		applyPositionSizing(result, sizing)
 	}
 	s.log.DebugContext(ctx, "Finished create trade plan", logger.StringField("stock_code", stockCodeWithExchange))
 
@@ -451,7 +500,7 @@ func (s *tradingService) calculateATR(candles []dto.StockOHLCV, period int) floa
 	return atr
 }
 
-func (s *tradingService) getATRMultiplierForSL(ta *dto.TradingViewScanner) float64 {
+func (s *tradingService) getATRMultiplierForSL(ta *dto.TradingViewScanner, params dto.TradePlanParams) float64 {
 	// =========================================================================
 	// Bagian 1: Konfigurasi Bobot dan Rentang Multiplier
 	// =========================================================================
@@ -461,9 +510,9 @@ func (s *tradingService) getATRMultiplierForSL(ta *dto.TradingViewScanner) float
 	const rsiWeight float64 = 0.30 // RSI (momentum/jenuh) memiliki bobot lebih rendah.
 	totalWeight := adxWeight + rsiWeight
 
-	// Rentang multiplier yang diinginkan.
-	const multiplierMax float64 = 2.25 // SL Terlebar: Digunakan saat pasar lemah/ranging (skor -1.0).
-	const multiplierMin float64 = 1.25 // SL Terketat: Digunakan saat tren kuat (skor +1.0).
+	// Rentang multiplier yang diinginkan (TradingPlanConfig).
+	multiplierMax := params.SLATRMultiplierMax // SL Terlebar: Digunakan saat pasar lemah/ranging (skor -1.0).
+	multiplierMin := params.SLATRMultiplierMin // SL Terketat: Digunakan saat tren kuat (skor +1.0).
 
 	// =========================================================================
 	// Bagian 2: Penilaian (Scoring) Indikator
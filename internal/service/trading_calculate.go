@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"golang-trading/internal/costmodel"
 	"golang-trading/internal/dto"
 	"golang-trading/internal/model"
 	"golang-trading/pkg/logger"
@@ -12,21 +13,80 @@ import (
 )
 
 const (
-	minTouches       = 1
-	slFromEMAAdj     = 0.995
-	targetRiskReward = 1.0
-
-	maxStopLossPercent   = 0.05
-	minStopLossPercent   = 0.02
-	maxTakeProfitPercent = 0.07
-	minTakeProfitPercent = 0.02
-
-	fallbackMaxTakeProfitPercent = 0.14
-	fallbackMinTakeProfitPercent = 0.01
-	fallbackMaxStopLossPercent   = 0.10
-	fallbackMinStopLossPercent   = 0.01
+	minTouches = 1
 )
 
+type tradePlanParamsCtxKey struct{}
+
+// withTradePlanParams mengganti parameter trade plan (batas SL/TP, ambang sinyal beli) untuk context ini,
+// dipakai optimasi backtest untuk mencoba kombinasi parameter tanpa mengubah konfigurasi live
+func withTradePlanParams(ctx context.Context, params dto.TradePlanParams) context.Context {
+	return context.WithValue(ctx, tradePlanParamsCtxKey{}, params)
+}
+
+func tradePlanParamsFromContext(ctx context.Context) (dto.TradePlanParams, bool) {
+	params, ok := ctx.Value(tradePlanParamsCtxKey{}).(dto.TradePlanParams)
+	return params, ok
+}
+
+type positionSizingCtxKey struct{}
+
+// WithPositionSizing menyertakan modal & risk per trade user, CreateTradePlan akan mengisi quantity,
+// modal yang dibutuhkan dan uang yang dipertaruhkan sesuai lot / step exchange
+func WithPositionSizing(ctx context.Context, sizing dto.PositionSizing) context.Context {
+	return context.WithValue(ctx, positionSizingCtxKey{}, sizing)
+}
+
+func positionSizingFromContext(ctx context.Context) (dto.PositionSizing, bool) {
+	sizing, ok := ctx.Value(positionSizingCtxKey{}).(dto.PositionSizing)
+	return sizing, ok && sizing.IsSet()
+}
+
+type tradeSideCtxKey struct{}
+
+// WithTradeSide arah trade plan (LONG / SHORT) untuk context ini, default LONG
+func WithTradeSide(ctx context.Context, side string) context.Context {
+	return context.WithValue(ctx, tradeSideCtxKey{}, dto.NormalizeSide(side))
+}
+
+func tradeSideFromContext(ctx context.Context) string {
+	if side, ok := ctx.Value(tradeSideCtxKey{}).(string); ok {
+		return side
+	}
+	return model.PositionSideLong
+}
+
+// applyPositionSizing mengisi ukuran posisi trade plan berdasarkan entry & stop loss
+func applyPositionSizing(plan *dto.TradePlanResult, sizing dto.PositionSizing) {
+	size := costmodel.ForExchange(plan.Exchange).PositionSize(sizing.AccountSize, sizing.RiskPerTradePercent, plan.Entry, plan.StopLoss)
+	plan.Quantity = size.Quantity
+	plan.Lots = size.Lots
+	plan.CapitalRequired = size.CapitalRequired
+	plan.RiskAmount = size.RiskAmount
+}
+
+// resolveTradePlanParams parameter trade plan untuk exchange & timeframe utama plan.
+// Override dari context (optimasi backtest) didahulukan, lalu TRADING_PLAN_CONFIG. Jika config gagal dimuat
+// atau tidak valid, parameter default dipakai supaya sinyal tetap berjalan.
+func (s *tradingService) resolveTradePlanParams(ctx context.Context, exchange, timeframe string) (dto.TradePlanParams, string) {
+	if params, ok := tradePlanParamsFromContext(ctx); ok {
+		return params, dto.TradingPlanConfigOverrideVersion
+	}
+
+	planConfig, err := s.systemParamRepository.GetTradingPlanConfig(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to load trading plan config, using default params", logger.ErrorField(err))
+		return dto.DefaultTradePlanParams(), dto.TradingPlanConfigDefaultVersion
+	}
+
+	params, err := planConfig.Resolve(exchange, timeframe)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to resolve trading plan config, using default params", logger.ErrorField(err), logger.StringField("exchange", exchange), logger.StringField("timeframe", timeframe))
+		return dto.DefaultTradePlanParams(), dto.TradingPlanConfigDefaultVersion
+	}
+	return params, planConfig.Version
+}
+
 type SLSource struct {
 	Price  float64
 	Type   string
@@ -43,15 +103,17 @@ type TPSource struct {
 
 // getSLCandidates gathers all potential SL levels from various sources (supports, EMAs, price buckets)
 // and returns them as a sorted slice of SLSource.
-func getSLCandidates(marketPrice float64, supports []dto.Level, emas []dto.EMAData, priceBuckets []dto.PriceBucket, atr float64) []SLSource {
+// Untuk SHORT level diambil dari resistance & EMA di atas harga, SL diletakkan di atasnya.
+func getSLCandidates(side string, marketPrice float64, supports, resistances []dto.Level, emas []dto.EMAData, priceBuckets []dto.PriceBucket, atr float64, emaAdj float64) []SLSource {
 	var candidates []SLSource
 	uniquePrices := make(map[float64]struct{})
+	direction := dto.SideDirection(side)
 
-	// Helper to add a candidate if its price is unique and below market price
+	// Helper to add a candidate if its price is unique and on the losing side of market price
 	addCandidate := func(price float64, sourceType, reason string, score float64) {
-		adjustedPrice := price - atr
-		if adjustedPrice >= marketPrice {
-			return // Skip if the adjusted SL is at or above the market price
+		adjustedPrice := price - direction*atr
+		if (adjustedPrice-marketPrice)*direction >= 0 {
+			return // Skip if the adjusted SL is at or beyond the market price in the trade direction
 		}
 		if _, exists := uniquePrices[adjustedPrice]; !exists {
 			candidates = append(candidates, SLSource{Price: adjustedPrice, Type: sourceType, Reason: reason, Score: score})
@@ -59,18 +121,29 @@ func getSLCandidates(marketPrice float64, supports []dto.Level, emas []dto.EMADa
 		}
 	}
 
-	// 1. Add from Supports
-	for _, s := range supports {
-		addCandidate(s.Price, "SL_SUPPORT", fmt.Sprintf("Support Level (%d touches)", s.Touches), float64(s.Touches))
+	// 1. Add from Supports (LONG) / Resistances (SHORT)
+	if dto.IsShort(side) {
+		for _, r := range resistances {
+			addCandidate(r.Price, "SL_RESISTANCE", fmt.Sprintf("Resistance Level (%d touches)", r.Touches), float64(r.Touches))
+		}
+	} else {
+		for _, s := range supports {
+			addCandidate(s.Price, "SL_SUPPORT", fmt.Sprintf("Support Level (%d touches)", s.Touches), float64(s.Touches))
+		}
 	}
 
-	// 2. Add from EMAs
+	// 2. Add from EMAs, emaAdj dicerminkan untuk SHORT (mis. 0.98 -> 1.02 dari EMA)
+	emaFactor := 1 - direction*(1-emaAdj)
+	emaPosition := "Below"
+	if dto.IsShort(side) {
+		emaPosition = "Above"
+	}
 	for _, ema := range emas {
 		if ema.IsMain {
 			// The score for EMAs can be constant or based on their period (e.g., longer-term EMA is stronger)
-			addCandidate(ema.EMA10, "SL_EMA10", "Below EMA10", 1.5)
-			addCandidate(ema.EMA20, "SL_EMA20", "Below EMA20", 2.0)
-			addCandidate(ema.EMA50, "SL_EMA50", "Below EMA50", 2.5)
+			addCandidate(ema.EMA10*emaFactor, "SL_EMA10", emaPosition+" EMA10", 1.5)
+			addCandidate(ema.EMA20*emaFactor, "SL_EMA20", emaPosition+" EMA20", 2.0)
+			addCandidate(ema.EMA50*emaFactor, "SL_EMA50", emaPosition+" EMA50", 2.5)
 		}
 	}
 
@@ -88,13 +161,15 @@ func getSLCandidates(marketPrice float64, supports []dto.Level, emas []dto.EMADa
 }
 
 // getTPCandidates gathers all potential TP levels from resistances and price buckets.
-func getTPCandidates(marketPrice float64, resistances []dto.Level, priceBuckets []dto.PriceBucket, atr float64) []TPSource {
+// Untuk SHORT target diambil dari support & price bucket di bawah harga.
+func getTPCandidates(side string, marketPrice float64, supports, resistances []dto.Level, priceBuckets []dto.PriceBucket, atr float64) []TPSource {
 	var candidates []TPSource
 	uniquePrices := make(map[float64]struct{})
+	direction := dto.SideDirection(side)
 
 	addCandidate := func(price float64, sourceType, reason string, score float64) {
-		adjustedPrice := price - atr
-		if adjustedPrice <= marketPrice {
+		adjustedPrice := price - direction*atr
+		if (adjustedPrice-marketPrice)*direction <= 0 {
 			return
 		}
 		if _, exists := uniquePrices[adjustedPrice]; !exists {
@@ -103,10 +178,15 @@ func getTPCandidates(marketPrice float64, resistances []dto.Level, priceBuckets
 		}
 	}
 
-	// 1. Add from Resistances
-	for _, r := range resistances {
-
-		addCandidate(r.Price, "TP_RESISTANCE", fmt.Sprintf("Resistance Level (%d touches)", r.Touches), float64(r.Touches))
+	// 1. Add from Resistances (LONG) / Supports (SHORT)
+	if dto.IsShort(side) {
+		for _, s := range supports {
+			addCandidate(s.Price, "TP_SUPPORT", fmt.Sprintf("Support Level (%d touches)", s.Touches), float64(s.Touches))
+		}
+	} else {
+		for _, r := range resistances {
+			addCandidate(r.Price, "TP_RESISTANCE", fmt.Sprintf("Resistance Level (%d touches)", r.Touches), float64(r.Touches))
+		}
 	}
 
 	// 2. Add from Price Buckets
@@ -114,9 +194,9 @@ func getTPCandidates(marketPrice float64, resistances []dto.Level, priceBuckets
 		addCandidate(pb.Bucket, "TP_BUCKET", fmt.Sprintf("Price Consolidation (%d touches)", pb.Count), float64(pb.Count)/10.0)
 	}
 
-	// Sort candidates by price, ascending. The best TP is the lowest one above the market price.
+	// Sort candidates by distance from market price, nearest first.
 	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].Price < candidates[j].Price
+		return candidates[i].Price*direction < candidates[j].Price*direction
 	})
 
 	return candidates
@@ -125,6 +205,7 @@ func getTPCandidates(marketPrice float64, resistances []dto.Level, priceBuckets
 // findBestPlanForRRR adalah fungsi pembantu yang mencari trade plan terbaik
 // untuk target Risk/Reward Ratio (RRR) TERTENTU.
 func (s *tradingService) findBestPlanForRRR(
+	side string,
 	marketPrice float64,
 	slCandidates []SLSource,
 	tpCandidates []TPSource,
@@ -135,6 +216,7 @@ func (s *tradingService) findBestPlanForRRR(
 	var bestPlan dto.TradePlan
 	highestScore := -1.0
 	found := false
+	direction := dto.SideDirection(side)
 
 	normalize := func(value, max float64) float64 {
 		if value <= 0 {
@@ -147,7 +229,7 @@ func (s *tradingService) findBestPlanForRRR(
 	}
 
 	for _, sl := range slCandidates {
-		risk := marketPrice - sl.Price
+		risk := (marketPrice - sl.Price) * direction
 		if risk <= 0 {
 			continue
 		}
@@ -158,7 +240,7 @@ func (s *tradingService) findBestPlanForRRR(
 
 		for i := 0; i < len(tpCandidates); i++ {
 			tp := tpCandidates[i]
-			reward := tp.Price - marketPrice
+			reward := (tp.Price - marketPrice) * direction
 			if reward <= 0 {
 				continue
 			}
@@ -200,6 +282,7 @@ func (s *tradingService) findBestPlanForRRR(
 					SLType: sl.Type, SLReason: sl.Reason, TPType: tp.Type, TPReason: tp.Reason,
 					Score:    currentScore,
 					PlanType: config.Type,
+					Side:     side,
 				}
 			}
 		}
@@ -209,32 +292,34 @@ func (s *tradingService) findBestPlanForRRR(
 
 // findIdealPlan mencari trade plan terbaik berdasarkan level teknis (support/resistance)
 func (s *tradingService) findIdealPlan(
+	side string,
 	marketPrice float64,
 	slCandidates []SLSource,
 	tpCandidates []TPSource,
 	entryQualityScore int,
+	params dto.TradePlanParams,
 ) dto.TradePlan {
 
 	config := dto.TradeConfig{
-		TargetRiskReward:     targetRiskReward,
-		MaxStopLossPercent:   maxStopLossPercent,
-		MinStopLossPercent:   minStopLossPercent,
-		MaxTakeProfitPercent: maxTakeProfitPercent,
-		MinTakeProfitPercent: minTakeProfitPercent,
+		TargetRiskReward:     params.TargetRiskReward,
+		MaxStopLossPercent:   params.MaxStopLossPercent,
+		MinStopLossPercent:   params.MinStopLossPercent,
+		MaxTakeProfitPercent: params.MaxTakeProfitPercent,
+		MinTakeProfitPercent: params.MinTakeProfitPercent,
 		Type:                 dto.PlanTypePrimary,
 		Score:                3,
 	}
-	if bestPlan, found := s.findBestPlanForRRR(marketPrice, slCandidates, tpCandidates, config, entryQualityScore); found {
+	if bestPlan, found := s.findBestPlanForRRR(side, marketPrice, slCandidates, tpCandidates, config, entryQualityScore); found {
 		return bestPlan
 	}
 
-	config.MaxTakeProfitPercent = fallbackMaxTakeProfitPercent
-	config.MinTakeProfitPercent = fallbackMinTakeProfitPercent
-	config.MaxStopLossPercent = fallbackMaxStopLossPercent
-	config.MinStopLossPercent = fallbackMinStopLossPercent
+	config.MaxTakeProfitPercent = params.FallbackMaxTakeProfitPercent
+	config.MinTakeProfitPercent = params.FallbackMinTakeProfitPercent
+	config.MaxStopLossPercent = params.FallbackMaxStopLossPercent
+	config.MinStopLossPercent = params.FallbackMinStopLossPercent
 	config.Type = dto.PlanTypeSecondary
 	config.Score = 0
-	if bestPlan, found := s.findBestPlanForRRR(marketPrice, slCandidates, tpCandidates, config, entryQualityScore); found {
+	if bestPlan, found := s.findBestPlanForRRR(side, marketPrice, slCandidates, tpCandidates, config, entryQualityScore); found {
 		return bestPlan
 	}
 
@@ -242,19 +327,21 @@ func (s *tradingService) findIdealPlan(
 }
 
 // calculateEntryQualityScore calculates a score based on the quality of the entry price.
-func (s *tradingService) calculateEntryQualityScore(marketPrice float64, technicalData *dto.TradingViewScanner) int {
+// Untuk SHORT kondisi dicerminkan: dekat EMA20 dari bawah, RSI melemah dan EMA20 di bawah EMA50.
+func (s *tradingService) calculateEntryQualityScore(side string, marketPrice float64, technicalData *dto.TradingViewScanner) int {
 	if technicalData == nil {
 		return 0
 	}
 
 	score := 0
+	short := dto.IsShort(side)
 	ema20 := technicalData.Value.MovingAverages.EMA20
 	ema50 := technicalData.Value.MovingAverages.EMA50
 	rsi := technicalData.Value.Oscillators.RSI
 
 	// 1. Proximity to Support (the closer to EMA20, the better for a pullback)
 	if ema20 > 0 {
-		distanceToEMA20 := (marketPrice - ema20) / ema20
+		distanceToEMA20 := (marketPrice - ema20) / ema20 * dto.SideDirection(side)
 		if distanceToEMA20 <= 0.01 { // Within 1% of EMA20
 			score += 20
 		} else if distanceToEMA20 <= 0.03 { // Within 3% of EMA20
@@ -265,15 +352,21 @@ func (s *tradingService) calculateEntryQualityScore(marketPrice float64, technic
 	}
 
 	// 2. RSI Condition
-	if rsi >= 50 && rsi <= 65 {
+	if short {
+		if rsi >= 35 && rsi <= 50 {
+			score += 15 // Healthy downside momentum
+		} else if rsi < 30 {
+			score -= 10 // Oversold, rawan short squeeze
+		}
+	} else if rsi >= 50 && rsi <= 65 {
 		score += 15 // Healthy momentum
 	} else if rsi > 70 {
 		score -= 10 // Overbought, higher risk
 	}
 
 	// 3. Trend Confirmation
-	if ema20 > ema50 {
-		score += 15 // Solid uptrend confirmation
+	if (!short && ema20 > ema50) || (short && ema20 < ema50) {
+		score += 15 // Solid trend confirmation
 	}
 
 	return score
@@ -281,17 +374,18 @@ func (s *tradingService) calculateEntryQualityScore(marketPrice float64, technic
 
 // createATRBasedPlan is a fallback function to create a simple trade plan based on ATR.
 // This is used when no suitable plan can be found from support/resistance levels.
-func (s *tradingService) createATRBasedPlan(marketPrice, atr float64, slATRMultiplier float64) dto.TradePlan {
+func (s *tradingService) createATRBasedPlan(side string, marketPrice, atr float64, slATRMultiplier, tpATRMultiplier float64) dto.TradePlan {
 	if atr <= 0 {
 		return dto.TradePlan{}
 	}
 
 	// Define SL and TP based on ATR multipliers. Example: SL=2*ATR, TP=3*ATR for a 1.5 RRR.
-	stopLoss := marketPrice - (slATRMultiplier * atr)
-	takeProfit := marketPrice + (3 * atr)
+	direction := dto.SideDirection(side)
+	stopLoss := marketPrice - direction*(slATRMultiplier*atr)
+	takeProfit := marketPrice + direction*(tpATRMultiplier*atr)
 
-	risk := marketPrice - stopLoss
-	reward := takeProfit - marketPrice
+	risk := (marketPrice - stopLoss) * direction
+	reward := (takeProfit - marketPrice) * direction
 
 	if risk <= 0 || reward <= 0 {
 		return dto.TradePlan{}
@@ -305,18 +399,45 @@ func (s *tradingService) createATRBasedPlan(marketPrice, atr float64, slATRMulti
 		Reward:     reward,
 		RiskReward: reward / risk,
 		PlanType:   dto.PlanTypeATR,
+		Side:       side,
 		SLType:     "ATR_FALLBACK",
 		SLReason:   fmt.Sprintf("Fallback based on %fx ATR (%.2f)", slATRMultiplier, atr),
 		TPType:     "ATR_FALLBACK",
-		TPReason:   fmt.Sprintf("Fallback based on 3x ATR (%.2f)", atr),
+		TPReason:   fmt.Sprintf("Fallback based on %.2fx ATR (%.2f)", tpATRMultiplier, atr),
 		Score:      0.5, // Low score to indicate it's a fallback plan
 	}
 
 	return plan
 }
 
+// roundPlanToTick menyesuaikan entry, SL dan TP ke fraksi harga yang valid di exchange (mis. fraksi IDX).
+// SL dan TP dibulatkan ke bawah (ke atas untuk SHORT) supaya order tetap bisa dipasang dan target tidak lebih optimis.
+func roundPlanToTick(plan dto.TradePlan, cost costmodel.Model) dto.TradePlan {
+	if plan.Entry == 0 {
+		return plan
+	}
+
+	plan.Entry = cost.RoundTick(plan.Entry)
+	if dto.IsShort(plan.Side) {
+		plan.StopLoss = cost.RoundTickUp(plan.StopLoss)
+		plan.TakeProfit = cost.RoundTickUp(plan.TakeProfit)
+	} else {
+		plan.StopLoss = cost.RoundTickDown(plan.StopLoss)
+		plan.TakeProfit = cost.RoundTickDown(plan.TakeProfit)
+	}
+
+	direction := dto.SideDirection(plan.Side)
+	plan.Risk = (plan.Entry - plan.StopLoss) * direction
+	plan.Reward = (plan.TakeProfit - plan.Entry) * direction
+	if plan.Risk > 0 {
+		plan.RiskReward = plan.Reward / plan.Risk
+	}
+	return plan
+}
+
 // calculatePlan evaluates all possible SL/TP combinations and selects the best one based on a scoring system.
 func (s *tradingService) calculatePlan(
+	side string,
 	marketPrice float64,
 	supports []dto.Level,
 	resistances []dto.Level,
@@ -325,22 +446,23 @@ func (s *tradingService) calculatePlan(
 	atr float64,
 	slATRMultiplier float64,
 	technicalData *dto.TradingViewScanner,
+	params dto.TradePlanParams,
 ) dto.TradePlan {
 	slDistance := atr * slATRMultiplier
 	tpDistance := atr * 0.1 // 10% of ATR
-	slCandidates := getSLCandidates(marketPrice, supports, emas, priceBuckets, slDistance)
-	tpCandidates := getTPCandidates(marketPrice, resistances, priceBuckets, tpDistance)
+	slCandidates := getSLCandidates(side, marketPrice, supports, resistances, emas, priceBuckets, slDistance, params.SLFromEMAAdj)
+	tpCandidates := getTPCandidates(side, marketPrice, supports, resistances, priceBuckets, tpDistance)
 
 	// Calculate the entry quality score
-	entryQualityScore := s.calculateEntryQualityScore(marketPrice, technicalData)
+	entryQualityScore := s.calculateEntryQualityScore(side, marketPrice, technicalData)
 
 	// First, try to find the ideal plan from technical levels
-	plan := s.findIdealPlan(marketPrice, slCandidates, tpCandidates, entryQualityScore)
+	plan := s.findIdealPlan(side, marketPrice, slCandidates, tpCandidates, entryQualityScore, params)
 
 	// If no plan is found, use the ATR-based fallback
 	if plan.Entry == 0 {
 		s.log.Info("No ideal plan found, creating ATR-based fallback plan.")
-		plan = s.createATRBasedPlan(marketPrice, atr, slATRMultiplier)
+		plan = s.createATRBasedPlan(side, marketPrice, atr, slATRMultiplier, params.TPATRMultiplier)
 	}
 
 	return plan
@@ -447,6 +569,29 @@ func (s *tradingService) calculateSmartEntry(
 	return EntryResult{Price: finalEntry, Reason: entryReason}
 }
 
+// calculateShortEntry entry untuk posisi SHORT: saat trend turun jual di dekat resistance terdekat,
+// selain itu di harga pasar. Entry short tidak pernah di bawah harga pasar.
+func (s *tradingService) calculateShortEntry(
+	marketPrice float64,
+	technicalData *dto.TradingViewScanner,
+	candles []dto.StockOHLCV,
+	resistances []dto.Level,
+) EntryResult {
+	if len(candles) == 0 || technicalData == nil {
+		return EntryResult{Price: marketPrice, Reason: "Entry short di harga pasar - data teknikal tidak lengkap"}
+	}
+
+	if s.calculateEnhancedTrendScore(technicalData, candles) < 0 {
+		entry := s.getBearishEntry(marketPrice, resistances, technicalData, s.calculateATR(candles, 14))
+		if entry > marketPrice {
+			return EntryResult{Price: entry, Reason: "Trend turun - entry short di dekat resistance"}
+		}
+		return EntryResult{Price: marketPrice, Reason: "Trend turun - entry short di harga pasar"}
+	}
+
+	return EntryResult{Price: marketPrice, Reason: "Entry short di harga pasar saat ini"}
+}
+
 // calculateTrendScore analyzes multiple indicators to determine overall trend strength
 func (s *tradingService) calculateTrendScore(technicalData *dto.TradingViewScanner) int {
 	score := 0
@@ -881,7 +1026,8 @@ func (s *tradingService) calculateVolatilityAdjustment(atr, volatility float64)
 	return math.Min(0.02, adjustment) // Max 2% adjustment
 }
 
-// getBearishEntry gets entry for bearish scenarios
+// getBearishEntry gets entry for bearish scenarios.
+// Resistance dalam 3% di atas harga juga dipakai sebagai entry SHORT (lihat calculateShortEntry).
 func (s *tradingService) getBearishEntry(currentPrice float64, resistances []dto.Level, technicalData *dto.TradingViewScanner, atr float64) float64 {
 	// In bearish trend, look for resistance levels to short or reduce position
 	for _, resistance := range resistances {
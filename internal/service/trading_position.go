@@ -8,10 +8,24 @@ import (
 	"golang-trading/internal/model"
 	"golang-trading/pkg/cache"
 	"golang-trading/pkg/common"
+	"golang-trading/pkg/utils"
 	"math"
 	"sort"
+	"time"
 )
 
+// maxTimeDecayPenalty pengurangan skor maksimal ketika posisi tidak bergerak menuju TP sampai batas max holding
+const maxTimeDecayPenalty = 15.0
+
+// rentang skor mentah scoreTrend & scoreMomentum, dipakai untuk mencerminkan skor posisi SHORT
+const (
+	minTrendScore, maxTrendScore       = -80.0, 100.0
+	minMomentumScore, maxMomentumScore = -70.0, 110.0
+)
+
+// funding rate (persen per 8 jam) yang dianggap tinggi / pasar crowded
+const highFundingRatePercent = 0.05
+
 func (s *tradingService) EvaluatePositionMonitoring(
 	ctx context.Context,
 	stockPosition *model.StockPosition,
@@ -32,15 +46,15 @@ func (s *tradingService) EvaluatePositionMonitoring(
 		HighestPriceSinceTTP: stockPosition.HighestPriceSinceTTP,
 	}
 
-	if stockPosition.TrailingProfitPrice > stockPosition.TakeProfitPrice {
+	if dto.IsMoreFavorable(stockPosition.Side, stockPosition.TrailingProfitPrice, stockPosition.TakeProfitPrice) {
 		result.TakeProfitPrice = stockPosition.TrailingProfitPrice
 	}
 
-	if stockPosition.TrailingStopPrice > stockPosition.StopLossPrice {
+	if dto.IsMoreFavorable(stockPosition.Side, stockPosition.TrailingStopPrice, stockPosition.StopLossPrice) {
 		result.StopLossPrice = stockPosition.TrailingStopPrice
 	}
 
-	timeframes, err := s.systemParamRepository.GetDefaultAnalysisTimeframes(ctx)
+	timeframes, err := s.analysisTimeframes(ctx, stockPosition.Exchange, stockPosition.StockCode, analyses)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +70,10 @@ func (s *tradingService) EvaluatePositionMonitoring(
 	}
 
 	symbolWithExchange := stockPosition.Exchange + ":" + stockPosition.StockCode
-	marketPrice, _ := cache.GetFromCache[float64](fmt.Sprintf(common.KEY_LAST_PRICE, symbolWithExchange))
+	var marketPrice float64
+	if !isBacktestMode(ctx) {
+		marketPrice, _ = cache.GetFromCache[float64](fmt.Sprintf(common.KEY_LAST_PRICE, symbolWithExchange))
+	}
 	if marketPrice == 0 && len(analyses) > 0 {
 		marketPrice = analyses[len(analyses)-1].MarketPrice
 	}
@@ -71,7 +88,7 @@ func (s *tradingService) EvaluatePositionMonitoring(
 	var finalSignal dto.Signal = ""
 
 	// --- Prioritas #1: Cut Loss ---
-	if result.LastPrice <= result.StopLossPrice {
+	if dto.IsStopReached(stockPosition.Side, result.LastPrice, result.StopLossPrice) {
 		result.Status = dto.Dangerous
 		finalSignal = dto.CutLoss
 		result.Insight = append(result.Insight, dto.Insight{Text: fmt.Sprintf("SINYAL CUT LOSS: Harga (%.2f) telah menyentuh Stop Loss (%.2f).", result.LastPrice, result.StopLossPrice), Weight: 100})
@@ -81,6 +98,9 @@ func (s *tradingService) EvaluatePositionMonitoring(
 	if err != nil {
 		return result, err
 	}
+	if dto.IsShort(stockPosition.Side) {
+		scoreTA = 100 - scoreTA
+	}
 	// --- Dapatkan Evaluasi Baseline menggunakan Sistem Skor Baru ---
 	score, insights, techSignal := s.calculateAdvancedScore(stockPosition, mainData.MainTA, mainData.SecondaryTA, mainData.MainOHLCV, marketPrice, supports, resistances)
 	finalScore := (float64(scoreTA)*0.6 + float64(score)*0.4)
@@ -88,6 +108,12 @@ func (s *tradingService) EvaluatePositionMonitoring(
 	result.TechnicalSignal = techSignal
 	result.Insight = append(result.Insight, insights...)
 
+	// Time decay: skor turun jika harga tertinggal dari waktu hold yang sudah berjalan
+	isTimeStop := s.evaluateHoldingPeriod(ctx, result, stockPosition, mainData.MainOHLCV)
+
+	// Estimasi funding yang sudah dibayar / diterima selama hold (exchange futures)
+	s.evaluateFundingCost(ctx, result, stockPosition, mainData.MainTA)
+
 	// Prioritas #2: Kelola Trailing Take Profit (TTP)
 	// Fungsi ini akan mengubah nilai TTP di dalam 'result' dan bisa menyarankan sinyal exit
 	s.evaluateTrailingTakeProfit(result, stockPosition, mainData.MainTA, mainData.MainOHLCV)
@@ -96,10 +122,18 @@ func (s *tradingService) EvaluatePositionMonitoring(
 	}
 
 	// Evaluasi Trailing Stop Loss
-	s.evaluateTrailingStop(result, mainData.MainTA, mainData.SecondaryTA, techSignal, mainData.MainOHLCV)
+	s.evaluateTrailingStop(result, stockPosition.Side, mainData.MainTA, mainData.SecondaryTA, techSignal, mainData.MainOHLCV)
 
 	// Tentukan status akhir
-	s.determineFinalStatus(result)
+	s.determineFinalStatus(result, stockPosition.Side)
+
+	// Prioritas #3: Time Stop, batas max holding terlewati dan belum ada sinyal exit lain
+	if isTimeStop && finalSignal == "" {
+		finalSignal = dto.TimeStop
+		if result.Status == dto.Safe {
+			result.Status = dto.Warning
+		}
+	}
 
 	// --- Bagian Akhir: Tentukan Sinyal Final ---
 	if finalSignal != "" {
@@ -123,11 +157,91 @@ func (s *tradingService) EvaluatePositionMonitoring(
 	return result, nil
 }
 
+// evaluateHoldingPeriod menerapkan time decay ke skor posisi yang punya batas max holding
+// dan mengembalikan true jika batas tersebut sudah terlewati (time stop).
+func (s *tradingService) evaluateHoldingPeriod(ctx context.Context, result *dto.PositionAnalysis, pos *model.StockPosition, mainOHLCV []dto.StockOHLCV) bool {
+	if pos.MaxHoldingDays <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	if isBacktestMode(ctx) && len(mainOHLCV) > 0 {
+		now = utils.CandleTimestampToTime(pos.Exchange, mainOHLCV[len(mainOHLCV)-1].Timestamp)
+	}
+	elapsedDays := utils.DaysBetween(pos.BuyDate, now)
+	timeProgress := float64(elapsedDays) / float64(pos.MaxHoldingDays)
+
+	var priceProgress float64
+	direction := dto.SideDirection(pos.Side)
+	if targetRange := (pos.TakeProfitPrice - pos.BuyPrice) * direction; targetRange > 0 {
+		priceProgress = (result.LastPrice - pos.BuyPrice) * direction / targetRange
+	}
+
+	if penalty := timeDecayPenalty(timeProgress, priceProgress); penalty > 0 {
+		result.Score = math.Max(0, result.Score-penalty)
+		result.Insight = append(result.Insight, dto.Insight{
+			Text:   fmt.Sprintf("[Time Decay]: Hold %d/%d hari, progres ke TP baru %.0f%%. Skor dikurangi %.1f.", elapsedDays, pos.MaxHoldingDays, math.Max(priceProgress, 0)*100, penalty),
+			Weight: 60,
+		})
+	}
+
+	if elapsedDays < pos.MaxHoldingDays {
+		return false
+	}
+	result.Insight = append(result.Insight, dto.Insight{
+		Text:   fmt.Sprintf("SINYAL TIME STOP: Posisi sudah di-hold %d hari, melewati batas max holding %d hari.", elapsedDays, pos.MaxHoldingDays),
+		Weight: 95,
+	})
+	return true
+}
+
+// evaluateFundingCost menambahkan insight estimasi funding selama posisi futures di-hold,
+// dihitung dari rata-rata funding rate terakhir sehingga hanya perkiraan.
+func (s *tradingService) evaluateFundingCost(ctx context.Context, result *dto.PositionAnalysis, pos *model.StockPosition, mainTA *dto.TradingViewScanner) {
+	if mainTA.Derivatives == nil || pos.BuyDate.IsZero() || isBacktestMode(ctx) {
+		return
+	}
+
+	hours := time.Since(pos.BuyDate).Hours()
+	if hours < dto.FundingIntervalHours {
+		return
+	}
+
+	cost := mainTA.Derivatives.FundingCostPercent(pos.Side, hours)
+	switch {
+	case cost > 0:
+		result.Insight = append(result.Insight, dto.Insight{
+			Text:   fmt.Sprintf("[Funding]: Estimasi funding dibayar selama hold %.0f jam ~%.3f%% dari nilai posisi.", hours, cost),
+			Weight: 40,
+		})
+	case cost < 0:
+		result.Insight = append(result.Insight, dto.Insight{
+			Text:   fmt.Sprintf("[Funding]: Estimasi funding diterima selama hold %.0f jam ~%.3f%% dari nilai posisi.", hours, -cost),
+			Weight: 20,
+		})
+	}
+}
+
+// timeDecayPenalty pengurangan skor berdasarkan selisih progres waktu hold dengan progres harga menuju TP.
+// Penalti baru berlaku setelah setengah periode hold berjalan.
+func timeDecayPenalty(timeProgress, priceProgress float64) float64 {
+	if timeProgress < 0.5 {
+		return 0
+	}
+	timeProgress = math.Min(timeProgress, 1)
+	lag := timeProgress - math.Max(priceProgress, 0)
+	if lag <= 0 {
+		return 0
+	}
+	return lag * maxTimeDecayPenalty
+}
+
 // determineFinalStatus menetapkan status akhir posisi (Safe, Warning, Dangerous) berdasarkan skor dan kondisi kritis.
-func (s *tradingService) determineFinalStatus(result *dto.PositionAnalysis) {
+func (s *tradingService) determineFinalStatus(result *dto.PositionAnalysis, side string) {
 	// Prioritas 1: Periksa kondisi kritis yang paling berbahaya, seperti jarak ke Stop Loss.
-	riskRange := result.LastPrice - result.StopLossPrice
-	entryToSLRange := result.EntryPrice - result.StopLossPrice
+	direction := dto.SideDirection(side)
+	riskRange := (result.LastPrice - result.StopLossPrice) * direction
+	entryToSLRange := (result.EntryPrice - result.StopLossPrice) * direction
 	if entryToSLRange > 0 && (riskRange/entryToSLRange) < 0.25 {
 		result.Status = dto.Dangerous
 		// Insight ini sangat penting dan spesifik, jadi kita pertahankan.
@@ -155,6 +269,18 @@ func (s *tradingService) isBearishEngulfing(last, prev dto.StockOHLCV) bool {
 	return last.Close < last.Open && prev.Close > prev.Open && last.Open > prev.Close && last.Close < prev.Open
 }
 
+func (s *tradingService) isBullishEngulfing(last, prev dto.StockOHLCV) bool {
+	return last.Close > last.Open && prev.Close < prev.Open && last.Open < prev.Close && last.Close > prev.Open
+}
+
+// isReversalAgainstSide pola engulfing yang berlawanan dengan arah posisi
+func (s *tradingService) isReversalAgainstSide(side string, last, prev dto.StockOHLCV) bool {
+	if dto.IsShort(side) {
+		return s.isBullishEngulfing(last, prev)
+	}
+	return s.isBearishEngulfing(last, prev)
+}
+
 func (s *tradingService) calculateAverageVolume(ohlcv []dto.StockOHLCV, period int) float64 {
 	if len(ohlcv) < period {
 		period = len(ohlcv)
@@ -205,78 +331,112 @@ func (s *tradingService) findMainAnalysisData(analyses []model.StockAnalysis, ti
 }
 
 // evaluateTrailingStop menentukan apakah sinyal Trailing Stop harus diberikan dengan logika yang lebih cerdas.
-func (s *tradingService) evaluateTrailingStop(result *dto.PositionAnalysis, mainTA, secondaryTA *dto.TradingViewScanner, technicalSignal string, mainOHLCV []dto.StockOHLCV) {
-	totalProfitRange := result.TakeProfitPrice - result.EntryPrice
-	currentProfit := result.LastPrice - result.EntryPrice
+// Untuk SHORT semua level dicerminkan: breakdown support S1, high candle sebelumnya dan SL diturunkan.
+func (s *tradingService) evaluateTrailingStop(result *dto.PositionAnalysis, side string, mainTA, secondaryTA *dto.TradingViewScanner, technicalSignal string, mainOHLCV []dto.StockOHLCV) {
+	short := dto.IsShort(side)
+	direction := dto.SideDirection(side)
+	totalProfitRange := (result.TakeProfitPrice - result.EntryPrice) * direction
+	currentProfit := (result.LastPrice - result.EntryPrice) * direction
 	hasSignificantProfit := totalProfitRange > 0 && (currentProfit/totalProfitRange) > 0.6
-	isSignalStrong := (technicalSignal == dto.SignalStrongBuy || technicalSignal == dto.SignalBuy)
+	isSignalStrong := dto.IsSignalWithSide(side, technicalSignal)
 	triggerA := isSignalStrong && hasSignificantProfit
-	var breakoutResistanceLevel float64
+	var breakoutLevel float64
 	triggerB := false
 	if secondaryTA != nil {
-		resistanceR1_4H := secondaryTA.Value.Pivots.Classic.R1
-		if currentProfit > 0 && result.LastPrice > resistanceR1_4H && resistanceR1_4H > result.EntryPrice*1.01 {
-			triggerB = true
-			breakoutResistanceLevel = resistanceR1_4H
+		if short {
+			supportS1_4H := secondaryTA.Value.Pivots.Classic.S1
+			if currentProfit > 0 && supportS1_4H > 0 && result.LastPrice < supportS1_4H && supportS1_4H < result.EntryPrice*0.99 {
+				triggerB = true
+				breakoutLevel = supportS1_4H
+			}
+		} else {
+			resistanceR1_4H := secondaryTA.Value.Pivots.Classic.R1
+			if currentProfit > 0 && result.LastPrice > resistanceR1_4H && resistanceR1_4H > result.EntryPrice*1.01 {
+				triggerB = true
+				breakoutLevel = resistanceR1_4H
+			}
 		}
 	}
 	if !triggerA && !triggerB {
 		return
 	}
+	levelName, slAction := "resistance", "naikkan"
+	if short {
+		levelName, slAction = "support", "turunkan"
+	}
 	bestProposedSL := 0.0
 	reasonForUpdate := ""
-	if result.EntryPrice > bestProposedSL {
+	if dto.IsMoreFavorable(side, result.EntryPrice, bestProposedSL) {
 		bestProposedSL = result.EntryPrice
 		reasonForUpdate = "mengamankan posisi ke breakeven"
 	}
-	if breakoutResistanceLevel > bestProposedSL {
-		bestProposedSL = breakoutResistanceLevel
-		reasonForUpdate = fmt.Sprintf("resistance kunci di %s (%.2f) telah ditembus", secondaryTA.Timeframe, breakoutResistanceLevel)
+	if dto.IsMoreFavorable(side, breakoutLevel, bestProposedSL) {
+		bestProposedSL = breakoutLevel
+		reasonForUpdate = fmt.Sprintf("%s kunci di %s (%.2f) telah ditembus", levelName, secondaryTA.Timeframe, breakoutLevel)
 	}
 	if len(mainOHLCV) >= 2 {
-		dynamicSL := mainOHLCV[len(mainOHLCV)-2].Low
-		if dynamicSL > bestProposedSL {
+		prevCandle := mainOHLCV[len(mainOHLCV)-2]
+		dynamicSL, dynamicReason := prevCandle.Low, "mengikuti support dinamis dari low candle"
+		if short {
+			dynamicSL, dynamicReason = prevCandle.High, "mengikuti resistance dinamis dari high candle"
+		}
+		if dto.IsMoreFavorable(side, dynamicSL, bestProposedSL) {
 			bestProposedSL = dynamicSL
-			reasonForUpdate = fmt.Sprintf("mengikuti support dinamis dari low candle %s", mainTA.Timeframe)
+			reasonForUpdate = fmt.Sprintf("%s %s", dynamicReason, mainTA.Timeframe)
 		}
 	}
-	if bestProposedSL > result.StopLossPrice {
+	if dto.IsMoreFavorable(side, bestProposedSL, result.StopLossPrice) {
 		if result.Signal == dto.Hold || result.Signal == "" {
 			result.Signal = dto.TrailingStop
 		}
 		triggerReason := ""
 		if triggerB {
-			triggerReason = fmt.Sprintf("karena harga menembus resistance kunci di %s", secondaryTA.Timeframe)
+			triggerReason = fmt.Sprintf("karena harga menembus %s kunci di %s", levelName, secondaryTA.Timeframe)
 		} else if triggerA {
 			triggerReason = fmt.Sprintf("karena posisi profit signifikan dgn sinyal kuat (%s)", technicalSignal)
 		}
-		insightText := fmt.Sprintf("SINYAL TRAILING STOP: %s. Rekomendasi naikkan SL ke %.2f untuk %s.", triggerReason, bestProposedSL, reasonForUpdate)
+		insightText := fmt.Sprintf("SINYAL TRAILING STOP: %s. Rekomendasi %s SL ke %.2f untuk %s.", triggerReason, slAction, bestProposedSL, reasonForUpdate)
 		result.Insight = append(result.Insight, dto.Insight{Text: insightText, Weight: 90})
 		result.TrailingStopPrice = bestProposedSL
 	}
 }
 
 // Helper untuk menganalisis potensi di level Take Profit.
-func (s *tradingService) evaluatePotentialAtTakeProfit(mainTA *dto.TradingViewScanner, mainOHLCV []dto.StockOHLCV) (bool, dto.Insight, float64) {
+// Untuk SHORT yang dicari candle bearish kuat dan target berikutnya di S2.
+func (s *tradingService) evaluatePotentialAtTakeProfit(side string, mainTA *dto.TradingViewScanner, mainOHLCV []dto.StockOHLCV) (bool, dto.Insight, float64) {
 	if mainTA == nil || len(mainOHLCV) == 0 {
 		return false, dto.Insight{}, 0
 	}
+	short := dto.IsShort(side)
 	lastCandle := mainOHLCV[len(mainOHLCV)-1]
 	isStrongCandle := s.isStrongBullishCandle(lastCandle)
+	isRSIHealthy := mainTA.Value.Oscillators.RSI < 85
+	if short {
+		isStrongCandle = s.isStrongBearishCandle(lastCandle)
+		isRSIHealthy = mainTA.Value.Oscillators.RSI > 15
+	}
 	avgVolume := s.calculateAverageVolume(mainOHLCV, 20)
 	isHighVolume := avgVolume > 0 && float64(lastCandle.Volume) > (avgVolume*1.5)
-	isRSIHealthy := mainTA.Value.Oscillators.RSI < 85
 	if isStrongCandle && isHighVolume && isRSIHealthy {
 		nextTarget := mainTA.Value.Pivots.Classic.R2
 		if nextTarget == 0 {
 			nextTarget = mainTA.Value.Pivots.Fibonacci.R2
 		}
+		if short {
+			nextTarget = mainTA.Value.Pivots.Classic.S2
+			if nextTarget == 0 {
+				nextTarget = mainTA.Value.Pivots.Fibonacci.S2
+			}
+		}
 		explanation := dto.Insight{
 			Text:   fmt.Sprintf("[POTENSI LANJUTAN] Harga menembus TP dengan candle kuat dan volume tinggi (%.0f vs avg %.0f).", float64(lastCandle.Volume), avgVolume),
 			Weight: 70, // High importance for TTP decision
 		}
 		return true, explanation, nextTarget
 	}
+	if short {
+		return false, dto.Insight{Text: "Momentum tidak cukup kuat untuk melanjutkan penurunan secara signifikan.", Weight: 50}, 0
+	}
 	return false, dto.Insight{Text: "Momentum tidak cukup kuat untuk melanjutkan kenaikan secara signifikan.", Weight: 50}, 0
 }
 
@@ -285,13 +445,22 @@ func (s *tradingService) calculateAdvancedScore(pos *model.StockPosition, mainTA
 	var totalScore float64
 	var insights []dto.Insight
 
+	// Skor indikator dihitung dari sudut pandang LONG, untuk SHORT dicerminkan dalam rentangnya
+	short := dto.IsShort(pos.Side)
+
 	// 1. Analisis Tren (Bobot: 45%)
 	trendScore, trendInsights := s.scoreTrend(mainTA)
+	if short {
+		trendScore = mirrorScore(trendScore, minTrendScore, maxTrendScore)
+	}
 	totalScore += trendScore * 0.45
 	insights = append(insights, trendInsights...)
 
 	// 2. Analisis Momentum (Bobot: 25%)
 	momentumScore, momentumInsights := s.scoreMomentum(mainTA)
+	if short {
+		momentumScore = mirrorScore(momentumScore, minMomentumScore, maxMomentumScore)
+	}
 	totalScore += momentumScore * 0.25
 	insights = append(insights, momentumInsights...)
 
@@ -302,16 +471,29 @@ func (s *tradingService) calculateAdvancedScore(pos *model.StockPosition, mainTA
 
 	// 4. Analisis Price Action & Volume (Bobot: 15%)
 	priceActionScore, priceActionInsights := s.scorePriceActionAndVolume(mainOHLCV)
+	if short {
+		priceActionScore = mirrorScore(priceActionScore, 0, 100)
+	}
 	totalScore += priceActionScore * 0.15
 	insights = append(insights, priceActionInsights...)
 
 	// 5. Analisis Multi-Timeframe (Bobot: 10%)
 	if secondaryTA != nil {
 		multiTimeframeScore, multiTimeframeInsights := s.scoreMultiTimeframe(mainTA, secondaryTA)
+		if short {
+			multiTimeframeScore = mirrorScore(multiTimeframeScore, 0, 100)
+		}
 		totalScore += multiTimeframeScore * 0.10 // Bobotnya lebih kecil karena bersifat konfirmasi
 		insights = append(insights, multiTimeframeInsights...)
 	}
 
+	// 6. Data derivatif futures (open interest & funding), penyesuaian skor sudah searah posisi
+	if mainTA.Derivatives != nil {
+		derivativesScore, derivativesInsights := s.scoreDerivatives(pos.Side, mainTA.Derivatives)
+		totalScore += derivativesScore
+		insights = append(insights, derivativesInsights...)
+	}
+
 	// Normalisasi skor ke rentang 0-100
 	if totalScore > 100 {
 		totalScore = 100
@@ -324,6 +506,52 @@ func (s *tradingService) calculateAdvancedScore(pos *model.StockPosition, mainTA
 	return totalScore, insights, techSignal
 }
 
+// scoreDerivatives penyesuaian skor (-10 s/d +8) dari konfirmasi open interest dan funding rate.
+// Open interest yang naik searah pergerakan harga posisi mengkonfirmasi tren, funding tinggi yang
+// dibayar posisi menandakan pasar crowded sekaligus biaya hold.
+func (s *tradingService) scoreDerivatives(side string, d *dto.DerivativesData) (float64, []dto.Insight) {
+	var score float64
+	var insights []dto.Insight
+
+	withSide, againstSide := dto.OpenInterestNewLongs, dto.OpenInterestNewShorts
+	if dto.IsShort(side) {
+		withSide, againstSide = againstSide, withSide
+	}
+
+	switch oiSignal := d.OpenInterestSignal(); oiSignal {
+	case withSide:
+		score += 6
+		insights = append(insights, dto.Insight{Text: fmt.Sprintf("Open interest berubah %.2f%% bersama harga %.2f%%, pergerakan searah posisi didukung posisi baru.", d.OpenInterestChangePercent, d.PriceChangePercent), Weight: 30})
+	case againstSide:
+		score -= 6
+		insights = append(insights, dto.Insight{Text: fmt.Sprintf("Open interest naik %.2f%% saat harga bergerak %.2f%% melawan posisi, tekanan berlawanan bertambah.", d.OpenInterestChangePercent, d.PriceChangePercent), Weight: 60})
+	case dto.OpenInterestShortCovering:
+		score -= 3
+		insights = append(insights, dto.Insight{Text: fmt.Sprintf("Harga naik %.2f%% dengan open interest turun %.2f%% (short covering), kenaikan kurang didukung posisi baru.", d.PriceChangePercent, d.OpenInterestChangePercent), Weight: 40})
+	case dto.OpenInterestLongLiquidation:
+		score -= 3
+		insights = append(insights, dto.Insight{Text: fmt.Sprintf("Harga turun %.2f%% dengan open interest turun %.2f%% (long liquidation), penurunan kurang didukung posisi baru.", d.PriceChangePercent, d.OpenInterestChangePercent), Weight: 40})
+	}
+
+	fundingPerDay := d.FundingCostPercent(side, 24)
+	if math.Abs(d.FundingRate) >= highFundingRatePercent {
+		if fundingPerDay > 0 {
+			score -= 4
+			insights = append(insights, dto.Insight{Text: fmt.Sprintf("Funding rate tinggi (%.4f%% per 8 jam), posisi %s membayar ~%.3f%% per hari dan pasar cenderung crowded.", d.FundingRate, dto.NormalizeSide(side), fundingPerDay), Weight: 50})
+		} else {
+			score += 2
+			insights = append(insights, dto.Insight{Text: fmt.Sprintf("Funding rate %.4f%% per 8 jam, posisi %s menerima ~%.3f%% per hari.", d.FundingRate, dto.NormalizeSide(side), -fundingPerDay), Weight: 20})
+		}
+	}
+
+	return score, insights
+}
+
+// mirrorScore mencerminkan skor dalam rentang [min, max], skor bullish tertinggi menjadi terendah
+func mirrorScore(score, min, max float64) float64 {
+	return min + max - score
+}
+
 // scoreTrend memberikan skor pada kekuatan dan arah tren.
 func (s *tradingService) scoreTrend(ta *dto.TradingViewScanner) (float64, []dto.Insight) {
 	score := 0.0
@@ -463,7 +691,7 @@ func (s *tradingService) scorePositionHealth(pos *model.StockPosition, lastPrice
 
 	// --- Skor Kesehatan Posisi (Original) ---
 	healthScore := 0.0
-	profitPercentage := ((lastPrice - pos.BuyPrice) / pos.BuyPrice) * 100
+	profitPercentage := dto.ProfitPercent(pos.Side, pos.BuyPrice, lastPrice)
 
 	if profitPercentage > 0 {
 		healthScore += 50 + (profitPercentage * 2)
@@ -475,8 +703,9 @@ func (s *tradingService) scorePositionHealth(pos *model.StockPosition, lastPrice
 		healthScore += 50
 	}
 
-	riskRange := lastPrice - pos.StopLossPrice
-	entryToSLRange := pos.BuyPrice - pos.StopLossPrice
+	direction := dto.SideDirection(pos.Side)
+	riskRange := (lastPrice - pos.StopLossPrice) * direction
+	entryToSLRange := (pos.BuyPrice - pos.StopLossPrice) * direction
 	if entryToSLRange > 0 {
 		riskRatio := riskRange / entryToSLRange
 		if riskRatio < 0.25 {
@@ -491,11 +720,16 @@ func (s *tradingService) scorePositionHealth(pos *model.StockPosition, lastPrice
 	}
 
 	// --- Skor Probabilitas Mencapai TP (Baru) ---
-	tpReachScore, tpInsights := s.calculateTPReachScore(pos, resistances)
+	// Target LONG terhalang resistance & SL dilindungi support, untuk SHORT sebaliknya
+	targetLevels, protectiveLevels := resistances, supports
+	if dto.IsShort(pos.Side) {
+		targetLevels, protectiveLevels = supports, resistances
+	}
+	tpReachScore, tpInsights := s.calculateTPReachScore(pos, targetLevels)
 	insights = append(insights, tpInsights...)
 
 	// --- Skor Penempatan SL (Baru) ---
-	slPlacementScore, slInsights := s.calculateSLPlacementScore(pos, supports)
+	slPlacementScore, slInsights := s.calculateSLPlacementScore(pos, protectiveLevels)
 	insights = append(insights, slInsights...)
 
 	// --- Gabungkan Skor ---
@@ -506,42 +740,49 @@ func (s *tradingService) scorePositionHealth(pos *model.StockPosition, lastPrice
 }
 
 // calculateSLPlacementScore menganalisis kualitas penempatan Stop Loss.
-func (s *tradingService) calculateSLPlacementScore(pos *model.StockPosition, supports []dto.Level) (float64, []dto.Insight) {
+// levels berisi support untuk LONG dan resistance untuk SHORT.
+func (s *tradingService) calculateSLPlacementScore(pos *model.StockPosition, levels []dto.Level) (float64, []dto.Insight) {
 	score := 50.0 // Skor awal netral
 	var insights []dto.Insight
 
-	if len(supports) == 0 {
+	if len(levels) == 0 {
 		return score, insights
 	}
 
-	// Cari support terdekat
+	direction := dto.SideDirection(pos.Side)
+	levelName, safeSide, unsafeSide := "support", "di bawah", "di atas"
+	if dto.IsShort(pos.Side) {
+		levelName, safeSide, unsafeSide = "resistance", "di atas", "di bawah"
+	}
+
+	// Cari level pelindung terdekat
 	minDistance := math.MaxFloat64
-	var nearestSupport dto.Level
-	for _, sup := range supports {
-		if sup.Price < pos.BuyPrice { // Hanya pertimbangkan support di bawah harga beli
-			distance := math.Abs(pos.StopLossPrice - sup.Price)
+	var nearestLevel dto.Level
+	for _, lvl := range levels {
+		if (lvl.Price-pos.BuyPrice)*direction < 0 { // Hanya pertimbangkan level di sisi rugi harga entry
+			distance := math.Abs(pos.StopLossPrice - lvl.Price)
 			if distance < minDistance {
 				minDistance = distance
-				nearestSupport = sup
+				nearestLevel = lvl
 			}
 		}
 	}
 
-	if nearestSupport.Price > 0 {
-		isSLSafe := pos.StopLossPrice < nearestSupport.Price
+	if nearestLevel.Price > 0 {
+		isSLSafe := (pos.StopLossPrice-nearestLevel.Price)*direction < 0
 		proximityFactor := 1.0 - (minDistance / pos.StopLossPrice) // 0-1, 1 sangat dekat
-		strengthFactor := float64(nearestSupport.Touches)          // Jumlah sentuhan
+		strengthFactor := float64(nearestLevel.Touches)            // Jumlah sentuhan
 
 		if isSLSafe {
-			// Bonus jika SL di bawah support kuat (penempatan aman)
+			// Bonus jika SL di balik level kuat (penempatan aman)
 			bonus := (strengthFactor * proximityFactor) * 20
 			score += bonus
-			insights = append(insights, dto.Insight{Text: fmt.Sprintf("Penempatan SL baik, berada di bawah support kuat (%.2f, disentuh %d kali).", nearestSupport.Price, nearestSupport.Touches), Weight: 15})
+			insights = append(insights, dto.Insight{Text: fmt.Sprintf("Penempatan SL baik, berada %s %s kuat (%.2f, disentuh %d kali).", safeSide, levelName, nearestLevel.Price, nearestLevel.Touches), Weight: 15})
 		} else {
-			// Penalti jika SL di atas support (rawan tersentuh)
+			// Penalti jika SL di depan level (rawan tersentuh)
 			penalty := (strengthFactor * proximityFactor) * 25
 			score -= penalty
-			insights = append(insights, dto.Insight{Text: fmt.Sprintf("Peringatan: SL berada di atas support (%.2f, disentuh %d kali), rawan tersentuh.", nearestSupport.Price, nearestSupport.Touches), Weight: 40})
+			insights = append(insights, dto.Insight{Text: fmt.Sprintf("Peringatan: SL berada %s %s (%.2f, disentuh %d kali), rawan tersentuh.", unsafeSide, levelName, nearestLevel.Price, nearestLevel.Touches), Weight: 40})
 		}
 	}
 
@@ -549,13 +790,20 @@ func (s *tradingService) calculateSLPlacementScore(pos *model.StockPosition, sup
 }
 
 // calculateTPReachScore menganalisis probabilitas harga mencapai target Take Profit.
-func (s *tradingService) calculateTPReachScore(pos *model.StockPosition, resistances []dto.Level) (float64, []dto.Insight) {
+// levels berisi resistance untuk LONG dan support untuk SHORT.
+func (s *tradingService) calculateTPReachScore(pos *model.StockPosition, levels []dto.Level) (float64, []dto.Insight) {
 	var insights []dto.Insight
 
+	direction := dto.SideDirection(pos.Side)
+	levelName, beyondSide, beforeSide := "resistance", "di atas", "di bawah"
+	if dto.IsShort(pos.Side) {
+		levelName, beyondSide, beforeSide = "support", "di bawah", "di atas"
+	}
+
 	// 1. Analisis Risk/Reward Ratio (RRR) - Bobot 40%
 	rrrScore := 0.0
-	entryToTPRange := pos.TakeProfitPrice - pos.BuyPrice
-	entryToSLRange := pos.BuyPrice - pos.StopLossPrice
+	entryToTPRange := (pos.TakeProfitPrice - pos.BuyPrice) * direction
+	entryToSLRange := (pos.BuyPrice - pos.StopLossPrice) * direction
 
 	if entryToSLRange > 0 && entryToTPRange > 0 {
 		rrr := entryToTPRange / entryToSLRange
@@ -573,43 +821,43 @@ func (s *tradingService) calculateTPReachScore(pos *model.StockPosition, resista
 		rrrScore = 20 // Penalti jika RRR tidak valid
 	}
 
-	// 2. Analisis Posisi TP vs. Resistance - Bobot 60%
-	resistanceScore := 50.0 // Skor awal netral
-	if len(resistances) > 0 {
-		// Cari resistance terdekat
+	// 2. Analisis Posisi TP vs. Level Penghalang - Bobot 60%
+	levelScore := 50.0 // Skor awal netral
+	if len(levels) > 0 {
+		// Cari level penghalang terdekat
 		minDistance := math.MaxFloat64
-		var nearestResistance dto.Level
-		for _, r := range resistances {
-			if r.Price > pos.BuyPrice { // Hanya pertimbangkan resistance di atas harga beli
-				distance := math.Abs(r.Price - pos.TakeProfitPrice)
+		var nearestLevel dto.Level
+		for _, lvl := range levels {
+			if (lvl.Price-pos.BuyPrice)*direction > 0 { // Hanya pertimbangkan level di sisi profit harga entry
+				distance := math.Abs(lvl.Price - pos.TakeProfitPrice)
 				if distance < minDistance {
 					minDistance = distance
-					nearestResistance = r
+					nearestLevel = lvl
 				}
 			}
 		}
 
-		if nearestResistance.Price > 0 {
-			isTPAmbitions := pos.TakeProfitPrice > nearestResistance.Price
+		if nearestLevel.Price > 0 {
+			isTPAmbitions := (pos.TakeProfitPrice-nearestLevel.Price)*direction > 0
 			proximityFactor := 1.0 - (minDistance / pos.TakeProfitPrice) // 0-1, 1 sangat dekat
-			strengthFactor := float64(nearestResistance.Touches)         // Jumlah sentuhan
+			strengthFactor := float64(nearestLevel.Touches)              // Jumlah sentuhan
 
 			if isTPAmbitions {
-				// Penalti jika TP di atas resistance kuat
+				// Penalti jika TP melewati level kuat
 				penalty := (strengthFactor * proximityFactor) * 20 // Penalti bisa sampai 100+
-				resistanceScore -= penalty
-				insights = append(insights, dto.Insight{Text: fmt.Sprintf("Target TP ambisius, berada di atas resistance kuat (%.2f, disentuh %d kali).", nearestResistance.Price, nearestResistance.Touches), Weight: 40})
+				levelScore -= penalty
+				insights = append(insights, dto.Insight{Text: fmt.Sprintf("Target TP ambisius, berada %s %s kuat (%.2f, disentuh %d kali).", beyondSide, levelName, nearestLevel.Price, nearestLevel.Touches), Weight: 40})
 			} else {
-				// Bonus jika TP di bawah resistance kuat
+				// Bonus jika TP sebelum level kuat
 				bonus := (strengthFactor * proximityFactor) * 15 // Bonus bisa sampai 75+
-				resistanceScore += bonus
-				insights = append(insights, dto.Insight{Text: fmt.Sprintf("Target TP realistis, di bawah resistance kuat (%.2f, disentuh %d kali).", nearestResistance.Price, nearestResistance.Touches), Weight: 15})
+				levelScore += bonus
+				insights = append(insights, dto.Insight{Text: fmt.Sprintf("Target TP realistis, %s %s kuat (%.2f, disentuh %d kali).", beforeSide, levelName, nearestLevel.Price, nearestLevel.Touches), Weight: 15})
 			}
 		}
 	}
 
-	// Gabungkan skor RRR dan Resistance
-	finalScore := (s.normalizeScore(rrrScore) * 0.4) + (s.normalizeScore(resistanceScore) * 0.6)
+	// Gabungkan skor RRR dan level
+	finalScore := (s.normalizeScore(rrrScore) * 0.4) + (s.normalizeScore(levelScore) * 0.6)
 
 	return s.normalizeScore(finalScore), insights
 }
@@ -715,16 +963,34 @@ func (s *tradingService) isStrongBullishCandle(candle dto.StockOHLCV) bool {
 	return (bodySize / totalRange) > 0.7
 }
 
+func (s *tradingService) isStrongBearishCandle(candle dto.StockOHLCV) bool {
+	// Candle harus merah
+	if candle.Close >= candle.Open {
+		return false
+	}
+
+	totalRange := candle.High - candle.Low
+	if totalRange == 0 {
+		return true
+	}
+
+	// Badan candle harus lebih dari 70% dari total range
+	return ((candle.Open - candle.Close) / totalRange) > 0.7
+}
+
+// evaluateTrailingTakeProfit mengelola mode TTP. Untuk SHORT HighestPriceSinceTTP menyimpan harga terendah
+// sejak TTP aktif dan trigger berada 3% di atasnya.
 func (s *tradingService) evaluateTrailingTakeProfit(
 	result *dto.PositionAnalysis,
 	pos *model.StockPosition,
 	mainTA *dto.TradingViewScanner,
 	mainOHLCV []dto.StockOHLCV,
 ) {
-	canGoHigher, explanation, nextTarget := s.evaluatePotentialAtTakeProfit(mainTA, mainOHLCV)
+	side := pos.Side
+	canGoHigher, explanation, nextTarget := s.evaluatePotentialAtTakeProfit(side, mainTA, mainOHLCV)
 	// --- Logika #1: Aktivasi Mode Trailing Take Profit (TTP) ---
-	if result.LastPrice >= pos.TakeProfitPrice && pos.TrailingProfitPrice == 0 {
-		if canGoHigher && nextTarget > result.TakeProfitPrice {
+	if dto.IsTargetReached(side, result.LastPrice, pos.TakeProfitPrice) && pos.TrailingProfitPrice == 0 {
+		if canGoHigher && nextTarget > 0 && dto.IsMoreFavorable(side, nextTarget, result.TakeProfitPrice) {
 			// Aktifkan mode TTP
 			result.Signal = dto.TrailingProfit
 			result.TrailingProfitPrice = result.TakeProfitPrice // Jaring pengaman awal di TP Price
@@ -746,14 +1012,17 @@ func (s *tradingService) evaluateTrailingTakeProfit(
 	// --- Logika #2: Manajemen Mode TTP yang Sedang Aktif ---
 	if pos.TrailingProfitPrice > 0 {
 		result.TrailingProfitPrice = pos.TrailingProfitPrice // Bawa nilai TTP lama
-		newHighestPrice := math.Max(result.LastPrice, pos.HighestPriceSinceTTP)
+		newHighestPrice := pos.HighestPriceSinceTTP
+		if dto.IsMoreFavorable(side, result.LastPrice, newHighestPrice) {
+			newHighestPrice = result.LastPrice
+		}
 		result.HighestPriceSinceTTP = newHighestPrice
 
-		// Tentukan trigger price baru (misal: 3% di bawah harga tertinggi baru)
-		newTriggerPrice := newHighestPrice * 0.97
+		// Tentukan trigger price baru (misal: 3% di bawah harga tertinggi baru, 3% di atas harga terendah untuk SHORT)
+		newTriggerPrice := newHighestPrice * (1 - dto.SideDirection(side)*0.03)
 
-		// Pastikan trigger price tidak pernah turun
-		if newTriggerPrice > result.TrailingProfitPrice {
+		// Pastikan trigger price tidak pernah mundur
+		if dto.IsMoreFavorable(side, newTriggerPrice, result.TrailingProfitPrice) {
 			result.TrailingProfitPrice = newTriggerPrice
 		}
 
@@ -770,24 +1039,24 @@ func (s *tradingService) evaluateTrailingTakeProfit(
 		}
 
 		// Kondisi 2: Harga menyentuh trigger price
-		if result.LastPrice <= result.TrailingProfitPrice {
+		if dto.IsStopReached(side, result.LastPrice, result.TrailingProfitPrice) {
 			result.Signal = dto.TakeProfit
 			result.Status = dto.Safe
 			result.Insight = append(result.Insight, dto.Insight{Text: fmt.Sprintf("SINYAL TAKE PROFIT (Trailing): Harga (%.2f) telah menyentuh trigger price (%.2f) dari puncaknya (%.2f).", result.LastPrice, result.TrailingProfitPrice, newHighestPrice), Weight: 90})
 			return
 		}
 
-		// Kondisi 3: Terbentuk pola reversal kuat (misal: Bearish Engulfing)
-		if len(mainOHLCV) >= 2 && s.isBearishEngulfing(mainOHLCV[len(mainOHLCV)-1], mainOHLCV[len(mainOHLCV)-2]) {
+		// Kondisi 3: Terbentuk pola reversal kuat (misal: Bearish Engulfing, Bullish Engulfing untuk SHORT)
+		if len(mainOHLCV) >= 2 && s.isReversalAgainstSide(side, mainOHLCV[len(mainOHLCV)-1], mainOHLCV[len(mainOHLCV)-2]) {
 			result.Signal = dto.TakeProfit
 			result.Status = dto.Safe
-			result.Insight = append(result.Insight, dto.Insight{Text: "SINYAL TAKE PROFIT (Trailing): Terbentuk pola Bearish Engulfing, mengindikasikan pembalikan momentum.", Weight: 90})
+			result.Insight = append(result.Insight, dto.Insight{Text: "SINYAL TAKE PROFIT (Trailing): Terbentuk pola engulfing berlawanan arah posisi, mengindikasikan pembalikan momentum.", Weight: 90})
 			return
 		}
 
-		// Kondisi 4: Sinyal teknikal umum melemah signifikan
+		// Kondisi 4: Sinyal teknikal umum berbalik melawan posisi
 		techSignal := dto.MapTradingViewScreenerRecommend(mainTA.Recommend.Global.Summary)
-		if techSignal == dto.SignalSell || techSignal == dto.SignalStrongSell {
+		if dto.IsSignalAgainstSide(side, techSignal) {
 			result.Signal = dto.TakeProfit
 			result.Status = dto.Safe
 			result.Insight = append(result.Insight, dto.Insight{Text: "SINYAL TP (Trailing): Sinyal teknikal umum melemah.", Weight: 90})
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeDecayPenalty(t *testing.T) {
+	// belum setengah periode hold
+	assert.Equal(t, 0.0, timeDecayPenalty(0.4, 0))
+	// progres harga sudah mendahului waktu
+	assert.Equal(t, 0.0, timeDecayPenalty(0.6, 0.8))
+	// setengah periode, harga belum bergerak
+	assert.InDelta(t, 7.5, timeDecayPenalty(0.5, 0), 1e-9)
+	// melewati batas hold dan harga di bawah entry, penalti maksimal
+	assert.InDelta(t, maxTimeDecayPenalty, timeDecayPenalty(1.4, -0.3), 1e-9)
+}
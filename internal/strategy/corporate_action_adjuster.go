@@ -0,0 +1,247 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang-trading/internal/dto"
+	"golang-trading/internal/model"
+	"golang-trading/internal/repository"
+	"golang-trading/pkg/logger"
+	"golang-trading/pkg/telegram"
+	"golang-trading/pkg/utils"
+
+	"gopkg.in/telebot.v3"
+)
+
+// CorporateActionAdjusterStrategy menyesuaikan posisi aktif terhadap split / rights issue yang sudah lewat ex-date
+type CorporateActionAdjusterStrategy struct {
+	logger                       *logger.Logger
+	corporateActionRepo          repository.CorporateActionRepository
+	unitOfWork                   repository.UnitOfWork
+	stockPositionsRepo           repository.StockPositionsRepository
+	stockPositionTransactionRepo repository.StockPositionTransactionRepository
+	candleRepository             repository.CandleRepository
+	telegram                     *telegram.TelegramRateLimiter
+}
+
+// CorporateActionAdjusterPayload range & interval candle yang di-refresh untuk mendeteksi corporate action baru
+type CorporateActionAdjusterPayload struct {
+	DataRange    string `json:"data_range"`
+	DataInterval string `json:"data_interval"`
+}
+
+type CorporateActionAdjusterResult struct {
+	StockCode         string `json:"stock_code"`
+	Type              string `json:"type,omitempty"`
+	ExDate            string `json:"ex_date,omitempty"`
+	AdjustedPositions int    `json:"adjusted_positions"`
+	Errors            string `json:"errors,omitempty"`
+}
+
+// adjustedPosition posisi yang sudah disesuaikan beserta harga lama untuk notifikasi
+type adjustedPosition struct {
+	position      model.StockPosition
+	oldBuyPrice   float64
+	oldTakeProfit float64
+	oldStopLoss   float64
+	oldQuantity   float64
+}
+
+func NewCorporateActionAdjusterStrategy(
+	logger *logger.Logger,
+	corporateActionRepo repository.CorporateActionRepository,
+	unitOfWork repository.UnitOfWork,
+	stockPositionsRepo repository.StockPositionsRepository,
+	stockPositionTransactionRepo repository.StockPositionTransactionRepository,
+	candleRepository repository.CandleRepository,
+	telegram *telegram.TelegramRateLimiter) JobExecutionStrategy {
+	return &CorporateActionAdjusterStrategy{
+		logger:                       logger,
+		corporateActionRepo:          corporateActionRepo,
+		unitOfWork:                   unitOfWork,
+		stockPositionsRepo:           stockPositionsRepo,
+		stockPositionTransactionRepo: stockPositionTransactionRepo,
+		candleRepository:             candleRepository,
+		telegram:                     telegram,
+	}
+}
+
+func (s *CorporateActionAdjusterStrategy) GetType() JobType {
+	return JobTypeCorporateActionAdjuster
+}
+
+func (s *CorporateActionAdjusterStrategy) Execute(ctx context.Context, job *model.Job) (JobResult, error) {
+	s.logger.DebugContext(ctx, "Executing corporate action adjuster job", logger.IntField("job_id", int(job.ID)))
+
+	var (
+		payload  CorporateActionAdjusterPayload
+		results  []CorporateActionAdjusterResult
+		hasError bool
+	)
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to unmarshal job payload", logger.ErrorField(err), logger.IntField("job_id", int(job.ID)))
+		return JobResult{ExitCode: JOB_EXIT_CODE_FAILED, Output: fmt.Sprintf("failed to unmarshal job payload: %v", err)}, fmt.Errorf("failed to unmarshal job payload: %w", err)
+	}
+
+	stockPositions, err := s.stockPositionsRepo.Get(ctx, dto.GetStockPositionsParam{
+		IsActive: utils.ToPointer(true),
+	})
+	if err != nil {
+		return JobResult{ExitCode: JOB_EXIT_CODE_FAILED, Output: fmt.Sprintf("failed to get stocks positions: %v", err)}, err
+	}
+
+	// refresh candle sekaligus mencatat corporate action terbaru dari provider
+	refreshed := make(map[string]bool)
+	for _, position := range stockPositions {
+		key := position.Exchange + ":" + position.StockCode
+		if refreshed[key] {
+			continue
+		}
+		refreshed[key] = true
+
+		_, err := s.candleRepository.Get(ctx, dto.GetStockDataParam{
+			StockCode: position.StockCode,
+			Exchange:  position.Exchange,
+			Range:     payload.DataRange,
+			Interval:  payload.DataInterval,
+		})
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to refresh stock data", logger.ErrorField(err), logger.StringField("stock_code", key))
+			results = append(results, CorporateActionAdjusterResult{StockCode: key, Errors: err.Error()})
+			hasError = true
+		}
+	}
+
+	actions, err := s.corporateActionRepo.Get(ctx, model.GetCorporateActionsParam{
+		Types:    []string{dto.CorporateActionSplit, dto.CorporateActionRights},
+		Pending:  utils.ToPointer(true),
+		ExDateTo: utils.TimeNowWIB(),
+	})
+	if err != nil {
+		return JobResult{ExitCode: JOB_EXIT_CODE_FAILED, Output: fmt.Sprintf("failed to get corporate actions: %v", err)}, err
+	}
+
+	for _, action := range actions {
+		result := CorporateActionAdjusterResult{
+			StockCode: action.Exchange + ":" + action.StockCode,
+			Type:      action.Type,
+			ExDate:    action.ExDate.Format("2006-01-02"),
+		}
+
+		adjusted, err := s.applyAction(ctx, action, stockPositions)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to apply corporate action", logger.ErrorField(err), logger.StringField("stock_code", result.StockCode), logger.IntField("corporate_action_id", int(action.ID)))
+			result.Errors = err.Error()
+			hasError = true
+			results = append(results, result)
+			continue
+		}
+		result.AdjustedPositions = len(adjusted)
+		results = append(results, result)
+
+		for _, item := range adjusted {
+			s.sendAdjustmentNotice(ctx, action, item)
+		}
+	}
+
+	resultJSON, err := json.Marshal(results)
+	if err != nil {
+		return JobResult{ExitCode: JOB_EXIT_CODE_FAILED, Output: fmt.Sprintf("failed to marshal results: %v", err)}, fmt.Errorf("failed to marshal results: %w", err)
+	}
+
+	if hasError {
+		return JobResult{ExitCode: JOB_EXIT_CODE_PARTIAL_SUCCESS, Output: string(resultJSON)}, nil
+	}
+	return JobResult{ExitCode: JOB_EXIT_CODE_SUCCESS, Output: string(resultJSON)}, nil
+}
+
+// applyAction menyesuaikan posisi aktif yang dibuka sebelum ex-date lalu menandai corporate action sudah diterapkan,
+// dalam satu transaksi supaya posisi tidak disesuaikan dua kali. stockPositions ikut diperbarui setelah commit
+// sehingga corporate action berikutnya pada saham yang sama memakai harga terbaru.
+func (s *CorporateActionAdjusterStrategy) applyAction(ctx context.Context, action model.CorporateAction, stockPositions []model.StockPosition) ([]adjustedPosition, error) {
+	if action.PriceFactor <= 0 {
+		// rights issue tanpa faktor harga tetap pending sampai diisi manual
+		s.logger.WarnContext(ctx, "Corporate action has no valid price factor, skipping",
+			logger.StringField("stock_code", action.Exchange+":"+action.StockCode),
+			logger.IntField("corporate_action_id", int(action.ID)),
+		)
+		return nil, nil
+	}
+
+	var (
+		adjusted []adjustedPosition
+		indexes  []int
+	)
+	err := s.unitOfWork.Run(func(opts ...utils.DBOption) error {
+		for i := range stockPositions {
+			position := stockPositions[i]
+			if position.Exchange != action.Exchange || position.StockCode != action.StockCode || !position.BuyDate.Before(action.ExDate) {
+				continue
+			}
+			position.Transactions = slices.Clone(position.Transactions)
+
+			item := adjustedPosition{
+				oldBuyPrice:   position.BuyPrice,
+				oldTakeProfit: position.TakeProfitPrice,
+				oldStopLoss:   position.StopLossPrice,
+				oldQuantity:   position.Quantity,
+			}
+
+			transactions := dto.ApplyPriceFactor(&position, action.PriceFactor, action.ExDate)
+			if err := s.stockPositionsRepo.Update(ctx, position, opts...); err != nil {
+				return fmt.Errorf("failed to update stock position %d: %w", position.ID, err)
+			}
+			for k := range transactions {
+				if err := s.stockPositionTransactionRepo.Update(ctx, &transactions[k], opts...); err != nil {
+					return fmt.Errorf("failed to update stock position transaction %d: %w", transactions[k].ID, err)
+				}
+			}
+
+			item.position = position
+			adjusted = append(adjusted, item)
+			indexes = append(indexes, i)
+		}
+		return s.corporateActionRepo.MarkApplied(ctx, action.ID, utils.TimeNowWIB(), opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for k, i := range indexes {
+		stockPositions[i] = adjusted[k].position
+	}
+	return adjusted, nil
+}
+
+func (s *CorporateActionAdjusterStrategy) sendAdjustmentNotice(ctx context.Context, action model.CorporateAction, item adjustedPosition) {
+	position := item.position
+	label := dto.CorporateActionLabel(action.Type, action.Numerator, action.Denominator, action.PriceFactor)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🧾 <b>Penyesuaian Corporate Action %s</b>\n\n", position.StockCode))
+	sb.WriteString(fmt.Sprintf("Posisi kamu disesuaikan karena <b>%s</b> (ex-date %s).\n\n", label, action.ExDate.Format("02 Jan 2006")))
+	sb.WriteString(fmt.Sprintf("💰 Harga Beli: %s → <b>%s</b>\n", utils.FormatPrice(item.oldBuyPrice, position.Exchange), utils.FormatPrice(position.BuyPrice, position.Exchange)))
+	sb.WriteString(fmt.Sprintf("🎯 Take Profit: %s → <b>%s</b>\n", utils.FormatPrice(item.oldTakeProfit, position.Exchange), utils.FormatPrice(position.TakeProfitPrice, position.Exchange)))
+	sb.WriteString(fmt.Sprintf("🛡 Stop Loss: %s → <b>%s</b>\n", utils.FormatPrice(item.oldStopLoss, position.Exchange), utils.FormatPrice(position.StopLossPrice, position.Exchange)))
+	if item.oldQuantity > 0 {
+		sb.WriteString(fmt.Sprintf("📦 Jumlah: %s → <b>%s</b>\n", formatQuantity(item.oldQuantity), formatQuantity(position.Quantity)))
+	}
+
+	menu := &telebot.ReplyMarkup{}
+	menu.Inline(
+		menu.Row(menu.Data("🔍 Detail Posisi", "btn_detail_stock_position", fmt.Sprintf("%d", position.ID))),
+		menu.Row(menu.Data("🗑️ Hapus Pesan", "btn_delete_message")),
+	)
+
+	if err := s.telegram.SendMessageUser(ctx, sb.String(), position.User.TelegramID, menu, telebot.ModeHTML); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to send corporate action notice", logger.ErrorField(err), logger.StringField("stock_code", position.StockCode))
+	}
+}
+
+func formatQuantity(quantity float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.4f", quantity), "0"), ".")
+}
@@ -15,15 +15,16 @@ const (
 type JobType string
 
 const (
-	JobTypeHTTP                   JobType = "http_request"
-	JobTypeStockNewsScraper       JobType = "stock_news_scraper"
-	JobTypeStockNewsSummary       JobType = "stock_news_summary"
-	JobTypeStockPriceAlert        JobType = "stock_price_alert"
-	JobTypeStockAnalyzer          JobType = "stock_analyzer"
-	JobTypeStockPositionMonitor   JobType = "stock_position_monitor"
-	JobTypeStockTechnicalAnalysis JobType = "stock_technical_analysis"
-	JobTypeDataCleanUp            JobType = "data_clean_up"
-	JobTypeBuySignalGenerator     JobType = "buy_signal_generator"
+	JobTypeHTTP                    JobType = "http_request"
+	JobTypeStockNewsScraper        JobType = "stock_news_scraper"
+	JobTypeStockNewsSummary        JobType = "stock_news_summary"
+	JobTypeStockPriceAlert         JobType = "stock_price_alert"
+	JobTypeStockAnalyzer           JobType = "stock_analyzer"
+	JobTypeStockPositionMonitor    JobType = "stock_position_monitor"
+	JobTypeStockTechnicalAnalysis  JobType = "stock_technical_analysis"
+	JobTypeDataCleanUp             JobType = "data_clean_up"
+	JobTypeBuySignalGenerator      JobType = "buy_signal_generator"
+	JobTypeCorporateActionAdjuster JobType = "corporate_action_adjuster"
 )
 
 type JobResult struct {
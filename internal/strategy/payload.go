@@ -0,0 +1,120 @@
+package strategy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-trading/pkg/utils"
+)
+
+// jobPayload payload strategy yang bisa divalidasi sebelum job disimpan
+type jobPayload interface {
+	Validate() error
+}
+
+var jobPayloads = map[JobType]func() jobPayload{
+	JobTypeStockPriceAlert:         func() jobPayload { return &StockPriceAlertPayload{} },
+	JobTypeStockAnalyzer:           func() jobPayload { return &StockAnalyzerPayload{} },
+	JobTypeBuySignalGenerator:      func() jobPayload { return &BuySignalGeneratorPayload{} },
+	JobTypeStockPositionMonitor:    func() jobPayload { return &StockPositionMonitoringPayload{} },
+	JobTypeDataCleanUp:             func() jobPayload { return &DataCleanUpPayload{} },
+	JobTypeCorporateActionAdjuster: func() jobPayload { return &CorporateActionAdjusterPayload{} },
+}
+
+// ValidateJobPayload memastikan payload bisa dibaca strategy jobType: field yang tidak dikenal ditolak
+// dan nilai yang di-parse saat Execute (durasi, interval, range) valid
+func ValidateJobPayload(jobType JobType, payload []byte) error {
+	newPayload, ok := jobPayloads[jobType]
+	if !ok {
+		return fmt.Errorf("job type %q is not supported", jobType)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	target := newPayload()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", jobType, err)
+	}
+	if err := target.Validate(); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", jobType, err)
+	}
+	return nil
+}
+
+func (p *StockPriceAlertPayload) Validate() error {
+	if p.Exchange == "" {
+		return fmt.Errorf("exchange is required")
+	}
+	if err := validateDataRange(p.DataInterval, p.DataRange); err != nil {
+		return err
+	}
+	if _, err := time.ParseDuration(p.AlertCacheDuration); err != nil {
+		return fmt.Errorf("alert_cache_duration: %w", err)
+	}
+	if p.AlertResendThresholdPercent < 0 {
+		return fmt.Errorf("alert_resend_threshold_percent must not be negative")
+	}
+	return nil
+}
+
+func (p *StockAnalyzerPayload) Validate() error {
+	if len(p.TradingViewBuyListParams) == 0 && len(p.AdditionalStocks) == 0 {
+		return fmt.Errorf("trading_view_buy_list_params or additional_stocks is required")
+	}
+	for _, stock := range p.AdditionalStocks {
+		if stock.StockCode == "" || stock.Exchange == "" {
+			return fmt.Errorf("additional_stocks: stock_code and exchange are required")
+		}
+	}
+	return nil
+}
+
+func (p *BuySignalGeneratorPayload) Validate() error {
+	if p.Exchange == "" {
+		return fmt.Errorf("exchange is required")
+	}
+	if _, err := time.ParseDuration(p.LatestAnalysisDuration); err != nil {
+		return fmt.Errorf("latest_analysis_duration: %w", err)
+	}
+	if _, err := time.ParseDuration(p.LastPriceCacheDuration); err != nil {
+		return fmt.Errorf("last_price_cache_duration: %w", err)
+	}
+	if p.MaxConcurrency <= 0 {
+		return fmt.Errorf("max_concurrency must be positive")
+	}
+	return validateDataRange(p.Interval, p.Range)
+}
+
+func (p *StockPositionMonitoringPayload) Validate() error {
+	if p.Exchange == "" {
+		return fmt.Errorf("exchange is required")
+	}
+	if p.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+	return nil
+}
+
+func (p *DataCleanUpPayload) Validate() error {
+	if p.RetentionDays <= 0 {
+		return fmt.Errorf("retention_days must be positive")
+	}
+	return nil
+}
+
+func (p *CorporateActionAdjusterPayload) Validate() error {
+	return validateDataRange(p.DataInterval, p.DataRange)
+}
+
+// validateDataRange interval & range candle yang dikenal market data
+func validateDataRange(interval, dataRange string) error {
+	if utils.IntervalToDuration(interval) == 0 {
+		return fmt.Errorf("interval %q is not supported", interval)
+	}
+	if from, _ := utils.MapPeriodeStringToUnix(dataRange); from == 0 {
+		return fmt.Errorf("range %q is not supported", dataRange)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJobPayload(t *testing.T) {
+	// payload seed migration & payload lengkap strategy lain valid
+	valid := map[JobType]string{
+		JobTypeStockPriceAlert:         `{"data_range":"1d","data_interval":"1m","alert_cache_duration":"30m","exchange":"IDX","alert_resend_threshold_percent":2}`,
+		JobTypeStockAnalyzer:           `{"additional_stocks": [], "trading_view_buy_list_params": [{"markets": ["indonesia"], "range": [0, 50]}]}`,
+		JobTypeBuySignalGenerator:      `{"latest_analysis_duration":"2h","max_concurrency":5,"range":"3m","interval":"1d","last_price_cache_duration":"5m","score":3,"exchange":"IDX"}`,
+		JobTypeStockPositionMonitor:    `{"exchange":"IDX","max_concurrency":5}`,
+		JobTypeDataCleanUp:             `{"retention_days":30}`,
+		JobTypeCorporateActionAdjuster: `{"data_range":"1m","data_interval":"1d"}`,
+	}
+	for jobType, payload := range valid {
+		assert.NoError(t, ValidateJobPayload(jobType, []byte(payload)), jobType)
+	}
+
+	invalid := map[string]struct {
+		jobType JobType
+		payload string
+	}{
+		"unknown job type": {"http_request", `{}`},
+		"not json":         {JobTypeDataCleanUp, `retention_days`},
+		"unknown field":    {JobTypeDataCleanUp, `{"retention_days":30,"retention":30}`},
+		"wrong type":       {JobTypeDataCleanUp, `{"retention_days":"30"}`},
+		"zero retention":   {JobTypeDataCleanUp, `{"retention_days":0}`},
+		"invalid duration": {JobTypeStockPriceAlert, `{"data_range":"1d","data_interval":"1m","alert_cache_duration":"30","exchange":"IDX"}`},
+		"invalid interval": {JobTypeCorporateActionAdjuster, `{"data_range":"1m","data_interval":"1x"}`},
+		"invalid range":    {JobTypeCorporateActionAdjuster, `{"data_range":"10y","data_interval":"1d"}`},
+		"zero concurrency": {JobTypeBuySignalGenerator, `{"latest_analysis_duration":"2h","range":"3m","interval":"1d","last_price_cache_duration":"5m","exchange":"IDX"}`},
+		"missing exchange": {JobTypeStockPositionMonitor, `{"max_concurrency":5}`},
+		"empty stock list": {JobTypeStockAnalyzer, `{"additional_stocks": []}`},
+		"incomplete stock": {JobTypeStockAnalyzer, `{"additional_stocks": [{"stock_code":"BBCA"}]}`},
+	}
+	for name, tc := range invalid {
+		assert.Error(t, ValidateJobPayload(tc.jobType, []byte(tc.payload)), name)
+	}
+}
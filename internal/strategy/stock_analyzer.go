@@ -9,6 +9,7 @@ import (
 	"golang-trading/config"
 	"golang-trading/internal/contract"
 	"golang-trading/internal/dto"
+	"golang-trading/internal/indicator"
 	"golang-trading/internal/model"
 	"golang-trading/internal/repository"
 	"golang-trading/pkg/cache"
@@ -17,6 +18,7 @@ import (
 	"golang-trading/pkg/utils"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 	"gorm.io/datatypes"
@@ -34,8 +36,9 @@ type StockAnalyzerStrategy struct {
 	stockPositionRepo              repository.StockPositionsRepository
 	tradingViewScreenersRepository repository.TradingViewScreenersRepository
 	candleRepository               repository.CandleRepository
+	calendarRepo                   repository.TradingCalendarRepository
 	stockAnalysisRepo              repository.StockAnalysisRepository
-	systemParamRepository          repository.SystemParamRepository
+	timeframeProfileRepository     repository.TimeframeProfileRepository
 	telegram                       *telegram.TelegramRateLimiter
 	userSignalAlertRepo            repository.UserSignalAlertRepository
 	TradingPlanContract            contract.TradingPlanContract
@@ -59,8 +62,9 @@ func NewStockAnalyzerStrategy(
 	stockPositionsRepository repository.StockPositionsRepository,
 	tradingViewScreenersRepository repository.TradingViewScreenersRepository,
 	candleRepository repository.CandleRepository,
+	calendarRepo repository.TradingCalendarRepository,
 	stockAnalysisRepository repository.StockAnalysisRepository,
-	systemParamRepository repository.SystemParamRepository,
+	timeframeProfileRepository repository.TimeframeProfileRepository,
 	userSignalAlertRepo repository.UserSignalAlertRepository,
 	telegram *telegram.TelegramRateLimiter,
 	tradingPlanContract contract.TradingPlanContract,
@@ -72,8 +76,9 @@ func NewStockAnalyzerStrategy(
 		stockPositionRepo:              stockPositionsRepository,
 		tradingViewScreenersRepository: tradingViewScreenersRepository,
 		candleRepository:               candleRepository,
+		calendarRepo:                   calendarRepo,
 		stockAnalysisRepo:              stockAnalysisRepository,
-		systemParamRepository:          systemParamRepository,
+		timeframeProfileRepository:     timeframeProfileRepository,
 		userSignalAlertRepo:            userSignalAlertRepo,
 		telegram:                       telegram,
 		TradingPlanContract:            tradingPlanContract,
@@ -209,15 +214,17 @@ func (s *StockAnalyzerStrategy) AnalyzeStock(ctx context.Context, stock dto.Stoc
 		now           = utils.TimeNowWIB()
 	)
 
-	dataTF, err := s.systemParamRepository.GetDefaultAnalysisTimeframes(ctx)
+	profile, err := s.timeframeProfileRepository.Resolve(ctx, stock.Exchange, stock.StockCode)
 	if err != nil {
-		s.logger.Error("Failed to get system parameter", logger.ErrorField(err))
+		s.logger.Error("Failed to resolve timeframe profile", logger.ErrorField(err))
 		return nil, err
 	}
+	dataTF := profile.Timeframes
 
 	s.logger.Debug("Processing stock",
 		logger.StringField("stock_code", stock.StockCode),
 		logger.StringField("exchange", stock.Exchange),
+		logger.StringField("timeframe_profile", profile.Name),
 		logger.IntField("total_timeframe", len(dataTF)),
 	)
 
@@ -238,16 +245,40 @@ func (s *StockAnalyzerStrategy) AnalyzeStock(ctx context.Context, stock dto.Stoc
 
 			var stockAnalysis model.StockAnalysis
 
-			stockData, err := s.tradingViewScreenersRepository.Get(newCtxG,
-				stock.StockCode,
-				stock.Exchange,
-				tf.ToTradingViewScreenersInterval(),
-			)
+			// ambil candle lebih panjang dari tf.Range supaya indikator (EMA200, dst) punya cukup bar
+			stockDataOHCLV, err := s.candleRepository.Get(newCtxG, dto.GetStockDataParam{
+				StockCode: stock.StockCode,
+				Exchange:  stock.Exchange,
+				Range:     indicator.LookbackRange(tf.Interval, tf.Range),
+				Interval:  tf.Interval,
+			})
 			if err != nil {
-				s.logger.Error("Failed to get stock data", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
+				s.logger.Error("Failed to get OHCLV data", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
 				return err
 			}
 
+			if quality := stockDataOHCLV.Quality; quality != nil && quality.Score < s.cfg.StockAnalyzer.MinDataQualityScore {
+				s.logger.Error("Candle data quality below minimum",
+					logger.StringField("stock_code", stock.StockCode),
+					logger.StringField("interval", tf.Interval),
+					logger.Field("score", quality.Score),
+					logger.Field("issues", quality.Issues),
+				)
+				return fmt.Errorf("candle data quality score %.2f below minimum %.2f for %s %s", quality.Score, s.cfg.StockAnalyzer.MinDataQualityScore, stock.StockCode, tf.Interval)
+			}
+
+			if s.cfg.StockAnalyzer.ClosedBarOnly {
+				stockDataOHCLV.OHLCV = s.closedBars(newCtxG, stock.Exchange, tf.Interval, stockDataOHCLV.OHLCV)
+			}
+
+			stockData, err := indicator.Compute(stockDataOHCLV.OHLCV, tf.Interval)
+			if err != nil {
+				s.logger.Error("Failed to compute indicator", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
+				return err
+			}
+			// funding & open interest (exchange futures) ikut disimpan sebagai input indikator
+			stockData.Derivatives = stockDataOHCLV.Derivatives
+
 			jsonAnalysis, err := json.Marshal(stockData)
 			if err != nil {
 				s.logger.Error("Failed to marshal json", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
@@ -264,18 +295,7 @@ func (s *StockAnalyzerStrategy) AnalyzeStock(ctx context.Context, stock dto.Stoc
 					stockData.Recommend.Global.Summary),
 			}
 
-			stockDataOHCLV, err := s.candleRepository.Get(newCtxG, dto.GetStockDataParam{
-				StockCode: stock.StockCode,
-				Exchange:  stock.Exchange,
-				Range:     tf.Range,
-				Interval:  tf.Interval,
-			})
-			if err != nil {
-				s.logger.Error("Failed to get OHCLV data", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
-				return err
-			}
-
-			jsonOHCLV, err := json.Marshal(stockDataOHCLV.OHLCV)
+			jsonOHCLV, err := json.Marshal(s.trimOHLCVToRange(stock.Exchange, stockDataOHCLV.OHLCV, tf.Range))
 			if err != nil {
 				s.logger.Error("Failed to marshal OHCLV json", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
 				return err
@@ -283,6 +303,15 @@ func (s *StockAnalyzerStrategy) AnalyzeStock(ctx context.Context, stock dto.Stoc
 
 			stockAnalysis.OHLCV = datatypes.JSON(jsonOHCLV)
 			stockAnalysis.MarketPrice = stockDataOHCLV.MarketPrice
+			if quality := stockDataOHCLV.Quality; quality != nil {
+				jsonQuality, err := json.Marshal(quality)
+				if err != nil {
+					s.logger.Error("Failed to marshal data quality json", logger.ErrorField(err), logger.StringField("stock_code", stock.StockCode))
+					return err
+				}
+				stockAnalysis.DataQualityScore = &quality.Score
+				stockAnalysis.DataQuality = datatypes.JSON(jsonQuality)
+			}
 			stockAnalysis.HashIdentifier = s.GenerateHashIdentifier(&stockAnalysis)
 
 			// Append safely
@@ -312,6 +341,42 @@ func (s *StockAnalyzerStrategy) AnalyzeStock(ctx context.Context, stock dto.Stoc
 	return stockAnalyses, nil
 }
 
+// closedBars membuang bar terakhir jika menurut kalender bursa belum selesai terbentuk, market price tetap harga terkini
+func (s *StockAnalyzerStrategy) closedBars(ctx context.Context, exchange, interval string, ohlcv []dto.StockOHLCV) []dto.StockOHLCV {
+	if len(ohlcv) < 2 {
+		return ohlcv
+	}
+
+	last := ohlcv[len(ohlcv)-1]
+	calendar := s.calendarRepo.Get(ctx, exchange)
+	if calendar.IsBarComplete(interval, utils.CandleTimestampToTime(exchange, last.Timestamp), time.Now()) {
+		return ohlcv
+	}
+
+	s.logger.Debug("Latest bar is not complete, excluded from indicator",
+		logger.StringField("exchange", exchange),
+		logger.StringField("interval", interval),
+		logger.Field("timestamp", last.Timestamp),
+	)
+	return ohlcv[:len(ohlcv)-1]
+}
+
+// trimOHLCVToRange memotong candle lookback indikator kembali ke range timeframe yang disimpan
+func (s *StockAnalyzerStrategy) trimOHLCVToRange(exchange string, ohlcv []dto.StockOHLCV, dataRange string) []dto.StockOHLCV {
+	from, _ := utils.MapPeriodeStringToUnix(dataRange)
+	if from == 0 {
+		return ohlcv
+	}
+
+	fromTime := time.Unix(from, 0)
+	for i, c := range ohlcv {
+		if !utils.CandleTimestampToTime(exchange, c.Timestamp).Before(fromTime) {
+			return ohlcv[i:]
+		}
+	}
+	return nil
+}
+
 func (s *StockAnalyzerStrategy) GenerateHashIdentifier(data *model.StockAnalysis) string {
 	parts := []string{
 		data.StockCode,
@@ -157,6 +157,8 @@ func (s *StockPositionMonitoringStrategy) EvaluateStockPosition(ctx context.Cont
 				mu.Unlock()
 			}()
 
+			// posisi dianalisa dengan profil timeframe pilihan pemiliknya
+			ctx := repository.WithTimeframeProfile(ctx, stockPosition.User.TimeframeProfile)
 			stockAnalyses, err := s.stockAnalyzer.AnalyzeStock(ctx, dto.StockInfo{
 				StockCode: stockPosition.StockCode,
 				Exchange:  stockPosition.Exchange,
@@ -180,8 +182,8 @@ func (s *StockPositionMonitoringStrategy) EvaluateStockPosition(ctx context.Cont
 				return
 			}
 
-			isTrailing := positionAnalysis.TrailingProfitPrice > stockPosition.TrailingProfitPrice ||
-				positionAnalysis.TrailingStopPrice > stockPosition.TrailingStopPrice
+			isTrailing := dto.IsMoreFavorable(stockPosition.Side, positionAnalysis.TrailingProfitPrice, stockPosition.TrailingProfitPrice) ||
+				dto.IsMoreFavorable(stockPosition.Side, positionAnalysis.TrailingStopPrice, stockPosition.TrailingStopPrice)
 
 			// Convert []dto.Insight to []string
 			var insights []model.Insight
@@ -212,7 +214,7 @@ func (s *StockPositionMonitoringStrategy) EvaluateStockPosition(ctx context.Cont
 				},
 				PositionSignal: string(positionAnalysis.Signal),
 			}
-			if positionAnalysis.HighestPriceSinceTTP > stockPosition.HighestPriceSinceTTP {
+			if dto.IsMoreFavorable(stockPosition.Side, positionAnalysis.HighestPriceSinceTTP, stockPosition.HighestPriceSinceTTP) {
 				stockPosition.HighestPriceSinceTTP = positionAnalysis.HighestPriceSinceTTP
 			}
 
@@ -224,6 +226,10 @@ func (s *StockPositionMonitoringStrategy) EvaluateStockPosition(ctx context.Cont
 			}
 			lastScore := stockPosition.FinalScore
 			stockPosition.FinalScore = positionAnalysis.Score
+			isHoldingAlert := s.shouldSendHoldingAlert(&stockPosition, positionAnalysis.Signal)
+			if isHoldingAlert {
+				stockPosition.LastHoldingAlertAt = utils.ToPointer(utils.TimeNowWIB())
+			}
 			errUpdate := s.stockPositionsRepo.Update(ctx, stockPosition)
 			if errUpdate != nil {
 				s.logger.ErrorContextWithAlert(ctx, "Failed to update stock position", logger.ErrorField(errUpdate), logger.StringField("stock_code", stockPosition.StockCode))
@@ -256,7 +262,8 @@ func (s *StockPositionMonitoringStrategy) EvaluateStockPosition(ctx context.Cont
 
 			shouldSendTelegram := (summary.TechnicalAnalysis.Status == string(dto.Warning) && lastScore < stockPosition.FinalScore) ||
 				summary.TechnicalAnalysis.Status == string(dto.Dangerous) ||
-				isTrailing
+				isTrailing ||
+				isHoldingAlert
 
 			if shouldSendTelegram {
 				sendTelegramToUsers = append(sendTelegramToUsers, stockPosition)
@@ -281,6 +288,29 @@ func (s *StockPositionMonitoringStrategy) EvaluateStockPosition(ctx context.Cont
 	return results, nil
 }
 
+// shouldSendHoldingAlert true jika posisi sudah time stop atau mendekati batas max holding,
+// maksimal satu kali per hari per posisi.
+func (s *StockPositionMonitoringStrategy) shouldSendHoldingAlert(stockPosition *model.StockPosition, signal dto.Signal) bool {
+	if stockPosition.MaxHoldingDays <= 0 {
+		return false
+	}
+	if stockPosition.LastHoldingAlertAt != nil &&
+		utils.TimeToWIB(*stockPosition.LastHoldingAlertAt).Format("2006-01-02") == utils.TimeNowWIB().Format("2006-01-02") {
+		return false
+	}
+	if signal == dto.TimeStop {
+		return true
+	}
+	return utils.RemainingDays(stockPosition.MaxHoldingDays, stockPosition.BuyDate) <= s.maxHoldingWarningDays()
+}
+
+func (s *StockPositionMonitoringStrategy) maxHoldingWarningDays() int {
+	if s.cfg.Trading.MaxHoldingWarningDays <= 0 {
+		return 1
+	}
+	return s.cfg.Trading.MaxHoldingWarningDays
+}
+
 func (s *StockPositionMonitoringStrategy) GenerateHashIdentifier(data *model.StockPositionMonitoring) string {
 	parts := []string{
 		data.StockPosition.StockCode,
@@ -299,10 +329,16 @@ func (s *StockPositionMonitoringStrategy) SendMessageUser(ctx context.Context, s
 	marketPrice := stockAnalyses[0].MarketPrice
 	for _, stockPosition := range stockPositions {
 		sb := strings.Builder{}
-		if summary.PositionSignal == string(dto.TrailingStop) && summary.TechnicalAnalysis.Status == string(dto.Safe) {
+		remainingDays := utils.RemainingDays(stockPosition.MaxHoldingDays, stockPosition.BuyDate)
+		isNearMaxHolding := stockPosition.MaxHoldingDays > 0 && remainingDays <= s.maxHoldingWarningDays()
+		if summary.PositionSignal == string(dto.TimeStop) {
+			sb.WriteString(fmt.Sprintf("<b>⏰ EXIT: Time stop posisi saham %s:%s, batas hold %d hari terlewati!</b>\n", stockPosition.Exchange, stockPosition.StockCode, stockPosition.MaxHoldingDays))
+		} else if summary.PositionSignal == string(dto.TrailingStop) && summary.TechnicalAnalysis.Status == string(dto.Safe) {
 			sb.WriteString(fmt.Sprintf("<b>💰 Posisi Saham %s:%s amankan profit!</b>\n", stockPosition.Exchange, stockPosition.StockCode))
 		} else if summary.PositionSignal == string(dto.TrailingProfit) && summary.TechnicalAnalysis.Status == string(dto.Safe) {
 			sb.WriteString(fmt.Sprintf("<b>💰 Posisi Saham %s:%s naikkan profit!</b>\n", stockPosition.Exchange, stockPosition.StockCode))
+		} else if isNearMaxHolding && summary.TechnicalAnalysis.Status != string(dto.Dangerous) {
+			sb.WriteString(fmt.Sprintf("<b>⏰ Posisi Saham %s:%s mendekati batas hold (sisa %d hari)!</b>\n", stockPosition.Exchange, stockPosition.StockCode, remainingDays))
 		} else {
 			sb.WriteString(fmt.Sprintf("<b>⚠️ Posisi Saham %s:%s mulai melemah!</b>\n", stockPosition.Exchange, stockPosition.StockCode))
 		}
@@ -328,11 +364,18 @@ func (s *StockPositionMonitoringStrategy) SendMessageUser(ctx context.Context, s
 			stockPosition.StopLossPrice,
 			utils.FormatChange(float64(stockPosition.BuyPrice),
 				float64(stockPosition.StopLossPrice)),
-			utils.FormatChangeWithIcon(float64(stockPosition.BuyPrice),
-				float64(marketPrice))))
+			utils.FormatChgIcon(dto.ProfitPercent(stockPosition.Side, stockPosition.BuyPrice, marketPrice))))
 
 		sb.WriteString("\n")
 
+		if dto.IsShort(stockPosition.Side) {
+			sb.WriteString(fmt.Sprintf(" - Side: %s\n", dto.SideText(stockPosition.Side)))
+		}
+
+		if stockPosition.MaxHoldingDays > 0 {
+			sb.WriteString(fmt.Sprintf(" - Max Hold: %d hari (sisa %d hari)\n", stockPosition.MaxHoldingDays, max(remainingDays, 0)))
+		}
+
 		if summary.PositionSignal == string(dto.TrailingStop) {
 			sb.WriteString(fmt.Sprintf(" - Trailing Stop: %.2f (%s)\n", stockPosition.TrailingStopPrice, utils.FormatChange(float64(stockPosition.BuyPrice),
 				float64(stockPosition.TrailingStopPrice))))
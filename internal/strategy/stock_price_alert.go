@@ -19,6 +19,18 @@ import (
 	"gopkg.in/telebot.v3"
 )
 
+// PriceAlerter job polling harga posisi sekaligus evaluator alert per harga untuk price stream real-time.
+type PriceAlerter interface {
+	JobExecutionStrategy
+	EvaluatePrice(ctx context.Context, stockPosition *model.StockPosition, marketPrice float64, opts PriceAlertOptions) (bool, error)
+}
+
+// PriceAlertOptions pengaturan cache & pengiriman ulang alert
+type PriceAlertOptions struct {
+	CacheDuration               time.Duration
+	AlertResendThresholdPercent float64
+}
+
 // StockPriceAlertStrategy defines the strategy for scraping stock news.
 type StockPriceAlertStrategy struct {
 	logger                         *logger.Logger
@@ -52,7 +64,7 @@ func NewStockPriceAlertStrategy(
 	tradingViewScreenersRepository repository.TradingViewScreenersRepository,
 	telegram *telegram.TelegramRateLimiter,
 	stockPositionsRepository repository.StockPositionsRepository,
-	candleRepository repository.CandleRepository) JobExecutionStrategy {
+	candleRepository repository.CandleRepository) PriceAlerter {
 	return &StockPriceAlertStrategy{
 		logger:                         logger,
 		inmemoryCache:                  inmemoryCache,
@@ -116,88 +128,14 @@ func (s *StockPriceAlertStrategy) Execute(ctx context.Context, job *model.Job) (
 			continue
 		}
 
-		// set last price in Redis
-		stockCodeWithExchange := stockPosition.Exchange + ":" + stockPosition.StockCode
-		key := fmt.Sprintf(common.KEY_LAST_PRICE, stockCodeWithExchange)
-		s.inmemoryCache.Set(key, stockData.MarketPrice, alertCacheDuration)
-
-		isSendAlert := false
-		// check if market price already reach take profit or stop loss
-		targetTP := stockPosition.TakeProfitPrice
-		targetSL := stockPosition.StopLossPrice
-		targetTrailingProfit := stockPosition.TrailingProfitPrice
-		targetTrailingStop := stockPosition.TrailingStopPrice
-
-		if stockData.MarketPrice >= targetTP && targetTrailingProfit == 0 {
-			isSendAlert = true
-			err = s.sendTelegramMessageAlert(
-				ctx,
-				&stockPosition,
-				telegram.TakeProfit,
-				stockData.MarketPrice,
-				targetTP,
-				utils.TimeNowWIB().Unix(),
-				alertCacheDuration,
-				payload.AlertResendThresholdPercent,
-			)
-		} else if targetTrailingProfit > 0 && stockData.MarketPrice <= targetTrailingProfit {
-			isSendAlert = true
-			err = s.sendTelegramMessageAlert(
-				ctx,
-				&stockPosition,
-				telegram.TrailingProfit,
-				stockData.MarketPrice,
-				targetTrailingProfit,
-				utils.TimeNowWIB().Unix(),
-				alertCacheDuration,
-				payload.AlertResendThresholdPercent,
-			)
-		} else if targetTrailingStop > 0 && stockData.MarketPrice <= targetTrailingStop {
-			isSendAlert = true
-			err = s.sendTelegramMessageAlert(
-				ctx,
-				&stockPosition,
-				telegram.TrailingStop,
-				stockData.MarketPrice,
-				targetTrailingStop,
-				utils.TimeNowWIB().Unix(),
-				alertCacheDuration,
-				payload.AlertResendThresholdPercent,
-			)
-		} else if stockData.MarketPrice <= targetSL && targetTrailingStop == 0 {
-			isSendAlert = true
-			err = s.sendTelegramMessageAlert(
-				ctx,
-				&stockPosition,
-				telegram.StopLoss,
-				stockData.MarketPrice,
-				targetSL,
-				utils.TimeNowWIB().Unix(),
-				alertCacheDuration,
-				payload.AlertResendThresholdPercent,
-			)
-		}
-
-		if isSendAlert {
-			stockPosition.LastPriceAlertAt = utils.ToPointer(utils.TimeNowWIB())
-			errSql := s.stockPositionsRepository.Update(ctx, stockPosition)
-			if errSql != nil {
-				s.logger.Error("Failed to update stock position", logger.ErrorField(errSql), logger.StringField("stock_code", stockPosition.StockCode))
-				resultData.Errors = errSql.Error()
-				results = append(results, resultData)
-			}
-		}
-
-		// set result
-		if err != nil {
+		if _, err := s.EvaluatePrice(ctx, &stockPosition, stockData.MarketPrice, PriceAlertOptions{
+			CacheDuration:               alertCacheDuration,
+			AlertResendThresholdPercent: payload.AlertResendThresholdPercent,
+		}); err != nil {
 			s.logger.Error("Failed to send stock alert", logger.ErrorField(err), logger.StringField("stock_code", stockPosition.StockCode))
 			resultData.Errors = err.Error()
-			results = append(results, resultData)
-		} else if isSendAlert {
-			results = append(results, resultData)
-		} else {
-			results = append(results, resultData)
 		}
+		results = append(results, resultData)
 	}
 
 	resultJSON, err := json.Marshal(results)
@@ -208,6 +146,61 @@ func (s *StockPriceAlertStrategy) Execute(ctx context.Context, job *model.Job) (
 	return JobResult{ExitCode: JOB_EXIT_CODE_SUCCESS, Output: string(resultJSON)}, nil
 }
 
+// EvaluatePrice mengecek harga terhadap TP / SL / trailing posisi lalu mengirim alert, dipakai job polling maupun price stream.
+// Mengembalikan true jika alert terkirim.
+func (s *StockPriceAlertStrategy) EvaluatePrice(ctx context.Context, stockPosition *model.StockPosition, marketPrice float64, opts PriceAlertOptions) (bool, error) {
+	// set last price in Redis
+	stockCodeWithExchange := stockPosition.Exchange + ":" + stockPosition.StockCode
+	key := fmt.Sprintf(common.KEY_LAST_PRICE, stockCodeWithExchange)
+	s.inmemoryCache.Set(key, marketPrice, opts.CacheDuration)
+
+	var (
+		alertType   telegram.AlertType
+		targetPrice float64
+	)
+	// check if market price already reach take profit or stop loss, arah dicerminkan untuk posisi short
+	side := stockPosition.Side
+	targetTP := stockPosition.TakeProfitPrice
+	targetSL := stockPosition.StopLossPrice
+	targetTrailingProfit := stockPosition.TrailingProfitPrice
+	targetTrailingStop := stockPosition.TrailingStopPrice
+
+	if dto.IsTargetReached(side, marketPrice, targetTP) && targetTrailingProfit == 0 {
+		alertType, targetPrice = telegram.TakeProfit, targetTP
+	} else if dto.IsStopReached(side, marketPrice, targetTrailingProfit) {
+		alertType, targetPrice = telegram.TrailingProfit, targetTrailingProfit
+	} else if dto.IsStopReached(side, marketPrice, targetTrailingStop) {
+		alertType, targetPrice = telegram.TrailingStop, targetTrailingStop
+	} else if dto.IsStopReached(side, marketPrice, targetSL) && targetTrailingStop == 0 {
+		alertType, targetPrice = telegram.StopLoss, targetSL
+	} else {
+		return false, nil
+	}
+
+	sent, err := s.sendTelegramMessageAlert(
+		ctx,
+		stockPosition,
+		alertType,
+		marketPrice,
+		targetPrice,
+		utils.TimeNowWIB().Unix(),
+		opts.CacheDuration,
+		opts.AlertResendThresholdPercent,
+	)
+	if err != nil || !sent {
+		return false, err
+	}
+
+	// hanya kolom alert yang ditulis: posisi dari price stream bisa salinan lama (mis. sudah exit / TP diubah)
+	now := utils.TimeNowWIB()
+	stockPosition.LastPriceAlertAt = &now
+	if err := s.stockPositionsRepository.UpdateLastPriceAlertAt(ctx, stockPosition.ID, now); err != nil {
+		s.logger.Error("Failed to update stock position", logger.ErrorField(err), logger.StringField("stock_code", stockPosition.StockCode))
+		return true, err
+	}
+	return true, nil
+}
+
 func (s *StockPriceAlertStrategy) sendTelegramMessageAlert(ctx context.Context,
 	stockPosition *model.StockPosition,
 	alertType telegram.AlertType,
@@ -215,14 +208,14 @@ func (s *StockPriceAlertStrategy) sendTelegramMessageAlert(ctx context.Context,
 	targetPrice float64,
 	timestamp int64,
 	cacheDuration time.Duration,
-	alertResendThresholdPercent float64) error {
+	alertResendThresholdPercent float64) (bool, error) {
 	ok, err := s.shouldTriggerAlert(ctx, stockPosition, triggerPrice, alertType, alertResendThresholdPercent)
 	if err != nil {
 		s.logger.Error("Failed to check alert", logger.ErrorField(err), logger.StringField("stock_code", stockPosition.StockCode))
-		return err
+		return false, err
 	}
 	if !ok {
-		return nil
+		return false, nil
 	}
 
 	message := telegram.FormatStockAlertResultForTelegram(alertType, stockPosition.StockCode, triggerPrice, targetPrice, timestamp)
@@ -241,7 +234,7 @@ func (s *StockPriceAlertStrategy) sendTelegramMessageAlert(ctx context.Context,
 	s.logger.Debug("Send alert", logger.StringField("stock_code", stockPosition.StockCode), logger.StringField("alert_type", string(alertType)))
 
 	s.inmemoryCache.Set(fmt.Sprintf(common.KEY_STOCK_PRICE_ALERT, alertType, stockPosition.StockCode), triggerPrice, cacheDuration)
-	return nil
+	return true, nil
 }
 
 func (s *StockPriceAlertStrategy) getLastAlertPrice(ctx context.Context, stockPosition *model.StockPosition, alertType telegram.AlertType) (float64, error) {
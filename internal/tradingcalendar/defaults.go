@@ -0,0 +1,72 @@
+package tradingcalendar
+
+import (
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/common"
+)
+
+// Default jam bursa bawaan exchange tanpa hari libur, daftar libur tahunan diatur di system parameter EXCHANGE_CALENDARS
+func Default(exchange string) dto.ExchangeCalendar {
+	switch {
+	case common.IsCryptoExchange(exchange):
+		return dto.ExchangeCalendar{TimeZone: "UTC", Is24x7: true}
+	case exchange == common.EXCHANGE_NASDAQ || exchange == common.EXCHANGE_NYSE:
+		return dto.ExchangeCalendar{
+			TimeZone: "America/New_York",
+			Sessions: []dto.TradingSession{
+				{Name: "pre_market", Open: "04:00", Close: "09:30", PreMarket: true},
+				{Name: "regular", Open: "09:30", Close: "16:00"},
+			},
+		}
+	case exchange == common.EXCHANGE_IDX:
+		mondayToThursday := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday}
+		friday := []time.Weekday{time.Friday}
+		return dto.ExchangeCalendar{
+			TimeZone: "Asia/Jakarta",
+			Sessions: []dto.TradingSession{
+				{Name: "pre_opening", Open: "08:45", Close: "09:00", PreMarket: true},
+				{Name: "session_1", Open: "09:00", Close: "12:00", Weekdays: mondayToThursday},
+				{Name: "session_2", Open: "13:30", Close: "16:00", Weekdays: mondayToThursday},
+				{Name: "session_1", Open: "09:00", Close: "11:30", Weekdays: friday},
+				{Name: "session_2", Open: "14:00", Close: "16:00", Weekdays: friday},
+			},
+		}
+	default:
+		return dto.ExchangeCalendar{TimeZone: "Asia/Jakarta"}
+	}
+}
+
+// Merge menimpa field base dengan field override yang diisi
+func Merge(base, override dto.ExchangeCalendar) dto.ExchangeCalendar {
+	if override.TimeZone != "" {
+		base.TimeZone = override.TimeZone
+	}
+	if override.Is24x7 {
+		base.Is24x7 = true
+	}
+	if len(override.TradingDays) > 0 {
+		base.TradingDays = override.TradingDays
+	}
+	if len(override.Sessions) > 0 {
+		base.Sessions = override.Sessions
+	}
+	if len(override.Holidays) > 0 {
+		base.Holidays = override.Holidays
+	}
+	if len(override.EarlyCloses) > 0 {
+		base.EarlyCloses = override.EarlyCloses
+	}
+	return base
+}
+
+// DefaultCalendar kalender bawaan exchange, dipakai saat konfigurasi tidak tersedia
+func DefaultCalendar(exchange string) Calendar {
+	calendar, err := New(exchange, Default(exchange))
+	if err != nil {
+		// konfigurasi bawaan selalu valid, kecuali tzdata tidak tersedia
+		calendar, _ = New(exchange, dto.ExchangeCalendar{Is24x7: common.IsCryptoExchange(exchange)})
+	}
+	return calendar
+}
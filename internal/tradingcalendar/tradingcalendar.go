@@ -0,0 +1,295 @@
+package tradingcalendar
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/utils"
+)
+
+const dateLayout = "2006-01-02"
+
+// batas pencarian hari bursa berikutnya
+const maxLookaheadDays = 366
+
+// Session satu sesi bursa pada tanggal tertentu
+type Session struct {
+	Name      string
+	Open      time.Time
+	Close     time.Time
+	PreMarket bool
+}
+
+// Calendar kalender bursa: zona waktu, hari libur, sesi perdagangan dan penutupan bar candle
+type Calendar interface {
+	Exchange() string
+	// Is24x7 true untuk market yang tidak pernah tutup (crypto)
+	Is24x7() bool
+	Location() *time.Location
+	IsHoliday(date time.Time) bool
+	IsTradingDay(date time.Time) bool
+	// Sessions sesi pada tanggal date (zona waktu exchange) urut jam buka, kosong jika bukan hari bursa
+	Sessions(date time.Time) []Session
+	// SessionAt sesi yang sedang berjalan pada waktu t, termasuk pre-market
+	SessionAt(t time.Time) (Session, bool)
+	// IsOpen true jika t berada di sesi reguler
+	IsOpen(t time.Time) bool
+	// NextOpen waktu buka sesi reguler berikutnya, t jika market sedang buka
+	NextOpen(t time.Time) time.Time
+	// BarCloseTime waktu bar candle yang dimulai barStart selesai terbentuk
+	BarCloseTime(interval string, barStart time.Time) time.Time
+	IsBarComplete(interval string, barStart, now time.Time) bool
+}
+
+type sessionTemplate struct {
+	name      string
+	open      time.Duration // offset dari 00:00
+	close     time.Duration
+	weekdays  []time.Weekday
+	preMarket bool
+}
+
+type calendar struct {
+	exchange    string
+	location    *time.Location
+	alwaysOn    bool
+	tradingDays []time.Weekday
+	sessions    []sessionTemplate
+	holidays    map[string]bool
+	earlyCloses map[string]time.Duration
+}
+
+// New membangun kalender exchange dari konfigurasi, cfg biasanya hasil Merge(Default(exchange), override)
+func New(exchange string, cfg dto.ExchangeCalendar) (Calendar, error) {
+	c := &calendar{
+		exchange:    exchange,
+		location:    time.UTC,
+		alwaysOn:    cfg.Is24x7,
+		tradingDays: cfg.TradingDays,
+		holidays:    make(map[string]bool, len(cfg.Holidays)),
+		earlyCloses: make(map[string]time.Duration, len(cfg.EarlyCloses)),
+	}
+	if cfg.TimeZone != "" {
+		loc, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %s for exchange %s: %w", cfg.TimeZone, exchange, err)
+		}
+		c.location = loc
+	}
+	if len(c.tradingDays) == 0 {
+		c.tradingDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+
+	for _, session := range cfg.Sessions {
+		open, err := parseClock(session.Open)
+		if err != nil {
+			return nil, fmt.Errorf("invalid open of session %s for exchange %s: %w", session.Name, exchange, err)
+		}
+		close, err := parseClock(session.Close)
+		if err != nil {
+			return nil, fmt.Errorf("invalid close of session %s for exchange %s: %w", session.Name, exchange, err)
+		}
+		if close <= open {
+			return nil, fmt.Errorf("session %s for exchange %s closes before it opens", session.Name, exchange)
+		}
+		c.sessions = append(c.sessions, sessionTemplate{
+			name:      session.Name,
+			open:      open,
+			close:     close,
+			weekdays:  session.Weekdays,
+			preMarket: session.PreMarket,
+		})
+	}
+	// tanpa konfigurasi sesi, market dianggap buka sepanjang hari bursa
+	if len(c.sessions) == 0 {
+		c.sessions = []sessionTemplate{{name: "regular", open: 0, close: 24 * time.Hour}}
+	}
+	sort.SliceStable(c.sessions, func(i, j int) bool {
+		return c.sessions[i].open < c.sessions[j].open
+	})
+
+	for _, holiday := range cfg.Holidays {
+		if _, err := time.Parse(dateLayout, holiday); err != nil {
+			return nil, fmt.Errorf("invalid holiday %s for exchange %s: %w", holiday, exchange, err)
+		}
+		c.holidays[holiday] = true
+	}
+	for date, closeAt := range cfg.EarlyCloses {
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return nil, fmt.Errorf("invalid early close date %s for exchange %s: %w", date, exchange, err)
+		}
+		close, err := parseClock(closeAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid early close time %s for exchange %s: %w", closeAt, exchange, err)
+		}
+		c.earlyCloses[date] = close
+	}
+	return c, nil
+}
+
+func (c *calendar) Exchange() string {
+	return c.exchange
+}
+
+func (c *calendar) Is24x7() bool {
+	return c.alwaysOn
+}
+
+func (c *calendar) Location() *time.Location {
+	return c.location
+}
+
+func (c *calendar) IsHoliday(date time.Time) bool {
+	return !c.alwaysOn && c.holidays[date.In(c.location).Format(dateLayout)]
+}
+
+func (c *calendar) IsTradingDay(date time.Time) bool {
+	if c.alwaysOn {
+		return true
+	}
+	return slices.Contains(c.tradingDays, date.In(c.location).Weekday()) && !c.IsHoliday(date)
+}
+
+func (c *calendar) Sessions(date time.Time) []Session {
+	day := utils.TruncateDay(date.In(c.location))
+	if c.alwaysOn {
+		return []Session{{Name: "24x7", Open: day, Close: day.AddDate(0, 0, 1)}}
+	}
+	if !c.IsTradingDay(day) {
+		return nil
+	}
+
+	earlyClose, hasEarlyClose := c.earlyCloses[day.Format(dateLayout)]
+	sessions := make([]Session, 0, len(c.sessions))
+	for _, tmpl := range c.sessions {
+		if len(tmpl.weekdays) > 0 && !slices.Contains(tmpl.weekdays, day.Weekday()) {
+			continue
+		}
+		close := tmpl.close
+		if hasEarlyClose && !tmpl.preMarket {
+			if tmpl.open >= earlyClose {
+				continue
+			}
+			close = min(close, earlyClose)
+		}
+		sessions = append(sessions, Session{
+			Name:      tmpl.name,
+			Open:      atClock(day, tmpl.open),
+			Close:     atClock(day, close),
+			PreMarket: tmpl.preMarket,
+		})
+	}
+	return sessions
+}
+
+func (c *calendar) SessionAt(t time.Time) (Session, bool) {
+	var found *Session
+	for _, session := range c.Sessions(t) {
+		if t.Before(session.Open) || !t.Before(session.Close) {
+			continue
+		}
+		// sesi reguler lebih diutamakan jika overlap dengan pre-market
+		if found == nil || found.PreMarket {
+			found = &session
+		}
+	}
+	if found == nil {
+		return Session{}, false
+	}
+	return *found, true
+}
+
+func (c *calendar) IsOpen(t time.Time) bool {
+	session, ok := c.SessionAt(t)
+	return ok && !session.PreMarket
+}
+
+func (c *calendar) NextOpen(t time.Time) time.Time {
+	day := utils.TruncateDay(t.In(c.location))
+	for i := 0; i < maxLookaheadDays; i++ {
+		for _, session := range c.Sessions(day.AddDate(0, 0, i)) {
+			if session.PreMarket || !t.Before(session.Close) {
+				continue
+			}
+			if t.Before(session.Open) {
+				return session.Open
+			}
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *calendar) BarCloseTime(interval string, barStart time.Time) time.Time {
+	step := utils.IntervalToDuration(interval)
+	if step == 0 {
+		return barStart
+	}
+	end := barStart.Add(step)
+	if interval == "1mo" || interval == "1M" {
+		end = barStart.AddDate(0, 1, 0)
+	}
+	if c.alwaysOn {
+		return end
+	}
+
+	// bar intraday terakhir di sesi ditutup bersama sesinya
+	if step < 24*time.Hour {
+		for _, session := range c.regularSessions(barStart) {
+			if !barStart.Before(session.Open) && barStart.Before(session.Close) && session.Close.Before(end) {
+				return session.Close
+			}
+		}
+		return end
+	}
+
+	// bar harian ke atas selesai saat sesi hari bursa terakhir dalam periodenya tutup
+	closeTime := time.Time{}
+	lastDay := utils.TruncateDay(end.In(c.location))
+	for day := utils.TruncateDay(barStart.In(c.location)); day.Before(lastDay); day = day.AddDate(0, 0, 1) {
+		if sessions := c.regularSessions(day); len(sessions) > 0 {
+			closeTime = sessions[len(sessions)-1].Close
+		}
+	}
+	if closeTime.IsZero() {
+		return end
+	}
+	return closeTime
+}
+
+func (c *calendar) IsBarComplete(interval string, barStart, now time.Time) bool {
+	return !now.Before(c.BarCloseTime(interval, barStart))
+}
+
+func (c *calendar) regularSessions(date time.Time) []Session {
+	sessions := c.Sessions(date)
+	regular := sessions[:0:0]
+	for _, session := range sessions {
+		if !session.PreMarket {
+			regular = append(regular, session)
+		}
+	}
+	return regular
+}
+
+// parseClock jam HH:MM (00:00 - 24:00) menjadi offset dari awal hari
+func parseClock(value string) (time.Duration, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid clock %q, expected HH:MM", value)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute > 0) {
+		return 0, fmt.Errorf("invalid clock %q, expected HH:MM", value)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// atClock jam pada tanggal day, dihitung per komponen supaya aman di hari pergantian DST
+func atClock(day time.Time, offset time.Duration) time.Time {
+	hours := int(offset / time.Hour)
+	minutes := int((offset % time.Hour) / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, day.Location())
+}
@@ -0,0 +1,99 @@
+package tradingcalendar
+
+import (
+	"testing"
+	"time"
+
+	"golang-trading/internal/dto"
+	"golang-trading/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCalendar(t *testing.T, exchange string, override dto.ExchangeCalendar) Calendar {
+	calendar, err := New(exchange, Merge(Default(exchange), override))
+	assert.NoError(t, err)
+	return calendar
+}
+
+func TestIDXSessions(t *testing.T) {
+	calendar := newTestCalendar(t, common.EXCHANGE_IDX, dto.ExchangeCalendar{Holidays: []string{"2025-01-29"}})
+	wib := calendar.Location()
+
+	monday := time.Date(2025, 1, 27, 0, 0, 0, 0, wib)
+	sessions := calendar.Sessions(monday)
+	assert.Len(t, sessions, 3)
+	assert.True(t, sessions[0].PreMarket)
+	assert.Equal(t, time.Date(2025, 1, 27, 12, 0, 0, 0, wib), sessions[1].Close)
+
+	friday := time.Date(2025, 1, 31, 0, 0, 0, 0, wib)
+	sessions = calendar.Sessions(friday)
+	assert.Equal(t, time.Date(2025, 1, 31, 11, 30, 0, 0, wib), sessions[1].Close)
+	assert.Equal(t, time.Date(2025, 1, 31, 14, 0, 0, 0, wib), sessions[2].Open)
+
+	assert.True(t, calendar.IsOpen(time.Date(2025, 1, 27, 10, 0, 0, 0, wib)))
+	assert.False(t, calendar.IsOpen(time.Date(2025, 1, 27, 12, 30, 0, 0, wib)))
+	assert.False(t, calendar.IsOpen(time.Date(2025, 1, 27, 8, 50, 0, 0, wib)))
+	session, ok := calendar.SessionAt(time.Date(2025, 1, 27, 8, 50, 0, 0, wib))
+	assert.True(t, ok)
+	assert.Equal(t, "pre_opening", session.Name)
+
+	// libur & akhir pekan
+	assert.True(t, calendar.IsHoliday(time.Date(2025, 1, 29, 10, 0, 0, 0, wib)))
+	assert.False(t, calendar.IsTradingDay(time.Date(2025, 1, 29, 10, 0, 0, 0, wib)))
+	assert.False(t, calendar.IsOpen(time.Date(2025, 1, 29, 10, 0, 0, 0, wib)))
+	assert.False(t, calendar.IsTradingDay(time.Date(2025, 2, 1, 10, 0, 0, 0, wib)))
+	assert.Empty(t, calendar.Sessions(time.Date(2025, 2, 1, 0, 0, 0, 0, wib)))
+}
+
+func TestNextOpen(t *testing.T) {
+	calendar := newTestCalendar(t, common.EXCHANGE_IDX, dto.ExchangeCalendar{Holidays: []string{"2025-02-03"}})
+	wib := calendar.Location()
+
+	// jumat sore -> senin libur -> selasa 09:00
+	assert.Equal(t, time.Date(2025, 2, 4, 9, 0, 0, 0, wib), calendar.NextOpen(time.Date(2025, 1, 31, 17, 0, 0, 0, wib)))
+	// istirahat siang jumat -> sesi 2
+	assert.Equal(t, time.Date(2025, 1, 31, 14, 0, 0, 0, wib), calendar.NextOpen(time.Date(2025, 1, 31, 12, 0, 0, 0, wib)))
+	now := time.Date(2025, 1, 31, 10, 0, 0, 0, wib)
+	assert.Equal(t, now, calendar.NextOpen(now))
+}
+
+func TestBarCloseTime(t *testing.T) {
+	idx := newTestCalendar(t, common.EXCHANGE_IDX, dto.ExchangeCalendar{})
+	wib := idx.Location()
+
+	daily := time.Date(2025, 1, 27, 9, 0, 0, 0, wib)
+	assert.Equal(t, time.Date(2025, 1, 27, 16, 0, 0, 0, wib), idx.BarCloseTime("1d", daily))
+	assert.False(t, idx.IsBarComplete("1d", daily, time.Date(2025, 1, 27, 15, 0, 0, 0, wib)))
+	assert.True(t, idx.IsBarComplete("1d", daily, time.Date(2025, 1, 27, 16, 0, 0, 0, wib)))
+
+	// bar 1h terakhir sesi 1 jumat ditutup 11:30
+	hourly := time.Date(2025, 1, 31, 11, 0, 0, 0, wib)
+	assert.Equal(t, time.Date(2025, 1, 31, 11, 30, 0, 0, wib), idx.BarCloseTime("1h", hourly))
+
+	// bar mingguan selesai saat sesi jumat tutup
+	weekly := time.Date(2025, 1, 27, 9, 0, 0, 0, wib)
+	assert.Equal(t, time.Date(2025, 1, 31, 16, 0, 0, 0, wib), idx.BarCloseTime("1wk", weekly))
+
+	nyse := newTestCalendar(t, common.EXCHANGE_NYSE, dto.ExchangeCalendar{EarlyCloses: map[string]string{"2025-12-24": "13:00"}})
+	ny := nyse.Location()
+	assert.Equal(t, time.Date(2025, 12, 24, 13, 0, 0, 0, ny), nyse.BarCloseTime("1d", time.Date(2025, 12, 24, 9, 30, 0, 0, ny)))
+	assert.False(t, nyse.IsOpen(time.Date(2025, 12, 24, 14, 0, 0, 0, ny)))
+
+	binance := DefaultCalendar(common.EXCHANGE_BINANCE)
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, binance.Is24x7())
+	assert.True(t, binance.IsOpen(start))
+	assert.Equal(t, start.Add(4*time.Hour), binance.BarCloseTime("4h", start))
+}
+
+func TestNewInvalidCalendar(t *testing.T) {
+	_, err := New("IDX", dto.ExchangeCalendar{TimeZone: "Mars/Olympus"})
+	assert.Error(t, err)
+	_, err = New("IDX", dto.ExchangeCalendar{Holidays: []string{"2025-13-01"}})
+	assert.Error(t, err)
+	_, err = New("IDX", dto.ExchangeCalendar{Sessions: []dto.TradingSession{{Name: "regular", Open: "16:00", Close: "09:00"}}})
+	assert.Error(t, err)
+	_, err = New("IDX", dto.ExchangeCalendar{Sessions: []dto.TradingSession{{Name: "regular", Open: "9", Close: "16:00"}}})
+	assert.Error(t, err)
+}
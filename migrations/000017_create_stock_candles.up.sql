@@ -0,0 +1,17 @@
+CREATE TABLE stock_candles (
+  id BIGSERIAL PRIMARY KEY,
+  exchange VARCHAR(20) NOT NULL,
+  stock_code VARCHAR(50) NOT NULL,
+  interval VARCHAR(10) NOT NULL,
+  open_time TIMESTAMP NOT NULL,
+  open FLOAT NOT NULL,
+  high FLOAT NOT NULL,
+  low FLOAT NOT NULL,
+  close FLOAT NOT NULL,
+  volume FLOAT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now(),
+  UNIQUE (exchange, stock_code, interval, open_time)
+);
+
+CREATE INDEX idx_stock_candles_series_open_time ON stock_candles(exchange, stock_code, interval, open_time DESC);
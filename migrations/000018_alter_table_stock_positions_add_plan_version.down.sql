@@ -0,0 +1,2 @@
+ALTER TABLE stock_positions
+DROP COLUMN IF EXISTS plan_version;
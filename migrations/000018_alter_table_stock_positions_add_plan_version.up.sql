@@ -0,0 +1,2 @@
+ALTER TABLE stock_positions
+ADD COLUMN IF NOT EXISTS plan_version VARCHAR(50);
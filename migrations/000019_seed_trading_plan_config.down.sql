@@ -0,0 +1 @@
+DELETE FROM system_parameters WHERE name = 'TRADING_PLAN_CONFIG';
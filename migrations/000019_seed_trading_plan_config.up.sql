@@ -0,0 +1,49 @@
+INSERT INTO system_parameters (
+    name,
+    value,
+    description,
+    created_at,
+    updated_at
+)
+VALUES (
+    'TRADING_PLAN_CONFIG',
+    '{
+  "version": "v1",
+  "default": {
+    "target_risk_reward": 1.0,
+    "max_stop_loss_percent": 0.05,
+    "min_stop_loss_percent": 0.02,
+    "max_take_profit_percent": 0.07,
+    "min_take_profit_percent": 0.02,
+    "fallback_max_take_profit_percent": 0.14,
+    "fallback_min_take_profit_percent": 0.01,
+    "fallback_max_stop_loss_percent": 0.10,
+    "fallback_min_stop_loss_percent": 0.01,
+    "sl_from_ema_adj": 0.995,
+    "sl_atr_multiplier_min": 1.25,
+    "sl_atr_multiplier_max": 2.25,
+    "tp_atr_multiplier": 3,
+    "buy_signal_score": 50,
+    "buy_signal_risk_reward": 0
+  },
+  "exchanges": {
+    "BINANCE": {
+      "params": {
+        "max_stop_loss_percent": 0.08,
+        "min_stop_loss_percent": 0.03,
+        "max_take_profit_percent": 0.15,
+        "min_take_profit_percent": 0.03,
+        "fallback_max_stop_loss_percent": 0.15,
+        "fallback_max_take_profit_percent": 0.25,
+        "sl_atr_multiplier_min": 1.5,
+        "sl_atr_multiplier_max": 3
+      },
+      "timeframes": {}
+    }
+  }
+}'::jsonb,
+    'Parameter trade plan (batas SL/TP, ATR multiplier, ambang sinyal beli) dengan override per exchange & timeframe',
+    NOW(),
+    NOW()
+)
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,6 @@
+ALTER TABLE stock_positions
+DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS account_size,
+DROP COLUMN IF EXISTS risk_per_trade_percent;
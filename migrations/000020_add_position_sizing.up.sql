@@ -0,0 +1,6 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS account_size DECIMAL(20, 2) DEFAULT 0,
+ADD COLUMN IF NOT EXISTS risk_per_trade_percent DECIMAL(5, 2) DEFAULT 0;
+
+ALTER TABLE stock_positions
+ADD COLUMN IF NOT EXISTS quantity DECIMAL(24, 8) DEFAULT 0;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS stock_position_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    stock_position_id BIGINT NOT NULL REFERENCES stock_positions(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL,
+    quantity DECIMAL(24, 8) NOT NULL,
+    price DECIMAL(24, 8) NOT NULL,
+    fee DECIMAL(24, 8) NOT NULL DEFAULT 0,
+    transaction_date TIMESTAMP NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_position_transactions_position ON stock_position_transactions(stock_position_id, transaction_date);
+
+-- posisi lama tidak dimigrasi: ledger membentuk transaksi entry / exit awal dari quantity posisi
+-- dengan fee sesuai model biaya exchange, dan transaksi entry disimpan saat posisi pertama kali diubah
@@ -0,0 +1,3 @@
+ALTER TABLE stock_positions
+DROP COLUMN IF EXISTS max_holding_days,
+DROP COLUMN IF EXISTS last_holding_alert_at;
@@ -0,0 +1,3 @@
+ALTER TABLE stock_positions
+ADD COLUMN IF NOT EXISTS max_holding_days INT DEFAULT 0,
+ADD COLUMN IF NOT EXISTS last_holding_alert_at TIMESTAMP;
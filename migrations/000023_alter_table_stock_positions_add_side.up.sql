@@ -0,0 +1,2 @@
+ALTER TABLE stock_positions
+ADD COLUMN IF NOT EXISTS side VARCHAR(10) NOT NULL DEFAULT 'LONG';
@@ -0,0 +1,3 @@
+ALTER TABLE stock_analyses
+DROP COLUMN IF EXISTS data_quality_score,
+DROP COLUMN IF EXISTS data_quality;
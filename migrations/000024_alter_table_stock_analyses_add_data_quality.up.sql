@@ -0,0 +1,3 @@
+ALTER TABLE stock_analyses
+ADD COLUMN IF NOT EXISTS data_quality_score DECIMAL(5, 2),
+ADD COLUMN IF NOT EXISTS data_quality JSONB;
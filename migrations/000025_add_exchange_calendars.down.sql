@@ -0,0 +1,5 @@
+DELETE FROM system_parameters WHERE name = 'EXCHANGE_CALENDARS';
+
+ALTER TABLE task_schedules
+DROP COLUMN IF EXISTS exchange,
+DROP COLUMN IF EXISTS market_hours;
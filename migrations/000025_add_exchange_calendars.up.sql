@@ -0,0 +1,61 @@
+ALTER TABLE task_schedules
+ADD COLUMN IF NOT EXISTS exchange VARCHAR(50),
+ADD COLUMN IF NOT EXISTS market_hours VARCHAR(20);
+
+-- price alert hanya saat sesi IDX buka, analyzer & monitoring (mulai sebelum pre-opening) hanya di hari bursa
+UPDATE task_schedules SET exchange = 'IDX', market_hours = 'session' WHERE id = 7;
+UPDATE task_schedules SET exchange = 'IDX', market_hours = 'trading_day' WHERE id IN (8, 10);
+
+-- hari libur bursa (termasuk cuti bersama) dan early close, jam sesi memakai default exchange di aplikasi
+INSERT INTO system_parameters (
+    name,
+    value,
+    description,
+    created_at,
+    updated_at
+)
+VALUES (
+    'EXCHANGE_CALENDARS',
+    '{
+  "IDX": {
+    "holidays": [
+      "2025-01-01", "2025-01-27", "2025-01-28", "2025-01-29", "2025-03-28", "2025-03-31",
+      "2025-04-01", "2025-04-02", "2025-04-03", "2025-04-04", "2025-04-07", "2025-04-18",
+      "2025-05-01", "2025-05-12", "2025-05-13", "2025-05-29", "2025-05-30", "2025-06-06",
+      "2025-06-09", "2025-06-27", "2025-08-18", "2025-09-05", "2025-12-25", "2025-12-26",
+      "2025-12-31",
+      "2026-01-01", "2026-01-16", "2026-02-16", "2026-02-17", "2026-03-18", "2026-03-19",
+      "2026-03-20", "2026-03-23", "2026-03-24", "2026-04-03", "2026-05-01", "2026-05-14",
+      "2026-05-15", "2026-05-27", "2026-06-01", "2026-06-16", "2026-08-17", "2026-08-25",
+      "2026-12-24", "2026-12-25", "2026-12-31"
+    ]
+  },
+  "NASDAQ": {
+    "holidays": [
+      "2025-01-01", "2025-01-09", "2025-01-20", "2025-02-17", "2025-04-18", "2025-05-26",
+      "2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27", "2025-12-25",
+      "2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25", "2026-06-19",
+      "2026-07-03", "2026-09-07", "2026-11-26", "2026-12-25"
+    ],
+    "early_closes": {
+      "2025-07-03": "13:00", "2025-11-28": "13:00", "2025-12-24": "13:00",
+      "2026-11-27": "13:00", "2026-12-24": "13:00"
+    }
+  },
+  "NYSE": {
+    "holidays": [
+      "2025-01-01", "2025-01-09", "2025-01-20", "2025-02-17", "2025-04-18", "2025-05-26",
+      "2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27", "2025-12-25",
+      "2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25", "2026-06-19",
+      "2026-07-03", "2026-09-07", "2026-11-26", "2026-12-25"
+    ],
+    "early_closes": {
+      "2025-07-03": "13:00", "2025-11-28": "13:00", "2025-12-24": "13:00",
+      "2026-11-27": "13:00", "2026-12-24": "13:00"
+    }
+  }
+}'::jsonb,
+    'Kalender bursa per exchange: hari libur (YYYY-MM-DD), early close, serta override time_zone / trading_days / sessions',
+    NOW(),
+    NOW()
+);
@@ -0,0 +1,3 @@
+DELETE FROM jobs WHERE type = 'corporate_action_adjuster';
+
+DROP TABLE IF EXISTS corporate_actions;
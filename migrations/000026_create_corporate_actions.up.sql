@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id SERIAL PRIMARY KEY,
+    exchange VARCHAR(50) NOT NULL,
+    stock_code VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    ex_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    numerator DECIMAL(20, 8) DEFAULT 0,
+    denominator DECIMAL(20, 8) DEFAULT 0,
+    amount DECIMAL(20, 8) DEFAULT 0,
+    price_factor DECIMAL(20, 10) DEFAULT 0,
+    source VARCHAR(50),
+    applied_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_corporate_actions_unique ON corporate_actions(exchange, stock_code, type, ex_date);
+CREATE INDEX IF NOT EXISTS idx_corporate_actions_pending ON corporate_actions(type, applied_at);
+
+WITH job AS (
+    INSERT INTO public.jobs
+    ("name", description, "type", payload, retry_policy, timeout, created_at, updated_at)
+    VALUES('🧾 Corporate Action Adjuster', 'Mengecek split / rights issue emiten yang ada di posisi aktif, lalu menyesuaikan harga beli, TP/SL dan quantity posisi serta memberi notifikasi ke pemilik posisi.', 'corporate_action_adjuster', '{"data_range":"1m","data_interval":"1d"}'::jsonb, '{"max_retries": 0, "backoff_strategy": "string", "initial_interval": "string"}'::jsonb, 900, NOW(), NOW())
+    RETURNING id
+)
+INSERT INTO public.task_schedules
+(job_id, cron_expression, next_execution, last_execution, is_active, created_at, updated_at)
+SELECT id, '30 7 * * *', NULL, NULL, true, NOW(), NOW() FROM job;
@@ -0,0 +1,4 @@
+DELETE FROM system_parameters WHERE name = 'ANALYSIS_TIMEFRAME_PROFILES';
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS timeframe_profile;
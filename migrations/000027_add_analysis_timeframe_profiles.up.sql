@@ -0,0 +1,28 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS timeframe_profile VARCHAR(50) DEFAULT '';
+
+-- profil bawaan (scalp / swing / position) tersedia di aplikasi, "profiles" di sini menambah atau menimpanya.
+-- default kosong berarti exchange tanpa profil tetap memakai DEFAULT_ANALYSIS_TIMEFRAMES
+INSERT INTO system_parameters (
+    name,
+    value,
+    description,
+    created_at,
+    updated_at
+)
+VALUES (
+    'ANALYSIS_TIMEFRAME_PROFILES',
+    '{
+  "default": "",
+  "profiles": {},
+  "exchanges": {
+    "BINANCE": "scalp",
+    "BINANCE_FUTURES": "scalp",
+    "IDX": "swing"
+  },
+  "symbols": {}
+}'::jsonb,
+    'Profil timeframe analisa: profiles (nama -> daftar interval, range, weight, is_main), exchanges & symbols (EXCHANGE:SYMBOL) -> nama profil, default',
+    NOW(),
+    NOW()
+);
@@ -0,0 +1,6 @@
+UPDATE task_execution_history SET status = 'failed' WHERE status = 'dead_letter';
+
+UPDATE public.jobs
+SET retry_policy = '{"max_retries": 0, "backoff_strategy": "string", "initial_interval": "string"}'::jsonb, updated_at = NOW();
+
+ALTER TABLE task_execution_history DROP COLUMN IF EXISTS attempt;
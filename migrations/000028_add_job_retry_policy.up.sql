@@ -0,0 +1,15 @@
+ALTER TABLE task_execution_history ADD COLUMN IF NOT EXISTS attempt INT NOT NULL DEFAULT 1;
+
+-- ganti placeholder retry_policy seed dengan policy yang benar-benar dipakai scheduler
+UPDATE public.jobs
+SET retry_policy = '{"max_retries": 2, "backoff_strategy": "exponential", "initial_interval": "1m", "max_interval": "5m", "retry_on_exit_codes": [500]}'::jsonb, updated_at = NOW()
+WHERE "type" IN ('stock_analyzer', 'stock_position_monitor') AND retry_policy->>'backoff_strategy' = 'string';
+
+UPDATE public.jobs
+SET retry_policy = '{"max_retries": 3, "backoff_strategy": "exponential", "initial_interval": "2m", "max_interval": "15m", "retry_on_exit_codes": [500]}'::jsonb, updated_at = NOW()
+WHERE "type" = 'corporate_action_adjuster' AND retry_policy->>'backoff_strategy' = 'string';
+
+-- price alert jalan tiap 3 menit, cukup menunggu tick cron berikutnya
+UPDATE public.jobs
+SET retry_policy = '{"max_retries": 0, "backoff_strategy": "fixed", "initial_interval": "30s"}'::jsonb, updated_at = NOW()
+WHERE retry_policy->>'backoff_strategy' = 'string';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_task_execution_history_running;
+
+ALTER TABLE task_execution_history DROP COLUMN IF EXISTS instance_id;
+
+ALTER TABLE task_schedules DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE task_schedules DROP COLUMN IF EXISTS locked_by;
@@ -0,0 +1,6 @@
+ALTER TABLE task_schedules ADD COLUMN IF NOT EXISTS locked_by VARCHAR(100);
+ALTER TABLE task_schedules ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE task_execution_history ADD COLUMN IF NOT EXISTS instance_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_task_execution_history_running ON task_execution_history(schedule_id, instance_id, status);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_task_execution_history_parent;
+
+ALTER TABLE task_execution_history DROP COLUMN IF EXISTS parent_id;
+
+DROP TABLE IF EXISTS job_dependencies;
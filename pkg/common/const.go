@@ -13,17 +13,28 @@ const (
 const (
 	EXCHANGE_IDX     = "IDX"
 	EXCHANGE_NASDAQ  = "NASDAQ"
+	EXCHANGE_NYSE    = "NYSE"
 	EXCHANGE_BINANCE = "BINANCE"
+	// EXCHANGE_BINANCE_FUTURES binance USDⓈ-M perpetual futures
+	EXCHANGE_BINANCE_FUTURES = "BINANCE_FUTURES"
+	EXCHANGE_BYBIT           = "BYBIT"
 )
 
-func GetExchangeList() []string {
-	return []string{
-		EXCHANGE_IDX,
-		EXCHANGE_NASDAQ,
-		EXCHANGE_BINANCE,
-	}
+// IsCryptoExchange true untuk exchange crypto yang buka 24/7
+func IsCryptoExchange(exchange string) bool {
+	return IsBinanceExchange(exchange) || exchange == EXCHANGE_BYBIT
+}
+
+// IsBinanceExchange true untuk binance spot maupun futures (crypto, timestamp candle dalam ms)
+func IsBinanceExchange(exchange string) bool {
+	return exchange == EXCHANGE_BINANCE || exchange == EXCHANGE_BINANCE_FUTURES
 }
 
 const (
 	KEY_LOG_HOOK_SEND_ALERT = "send_alert"
 )
+
+const (
+	// IDX_LOT_SIZE 1 lot di bursa IDX = 100 lembar
+	IDX_LOT_SIZE = 100
+)
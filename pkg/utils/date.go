@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"golang-trading/pkg/common"
 	"log"
 	"math"
 	"time"
@@ -34,6 +35,11 @@ func GetNowWithOnlyHour() time.Time {
 	)
 }
 
+// TruncateDay jam 00:00 pada tanggal & zona waktu t
+func TruncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
 func PrettyDate(date time.Time) string {
 	return fmt.Sprintf("%02d %s %d - %02d:%02d WIB",
 		date.Day(),
@@ -74,11 +80,14 @@ func RemainingDays(maxHoldingDays int, buyTime time.Time) int {
 }
 
 func DaysSince(date time.Time) int {
-	// Normalisasi waktu ke 00:00 UTC
+	return DaysBetween(date, time.Now())
+}
+
+// DaysBetween jumlah hari penuh dari tanggal date (dinormalisasi ke 00:00 UTC) sampai waktu to
+func DaysBetween(date time.Time, to time.Time) int {
 	normalizedDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
-	now := time.Now().UTC()
 
-	duration := now.Sub(normalizedDate)
+	duration := to.UTC().Sub(normalizedDate)
 	return int(duration.Hours() / 24)
 }
 
@@ -111,3 +120,51 @@ func MapPeriodeStringToUnixMs(periode string) (int64, int64) {
 	startTime, endTime := MapPeriodeStringToUnix(periode)
 	return startTime * 1000, endTime * 1000
 }
+
+// IntervalToDuration mapping interval candle (1m-30m, 1h-12h, 1d, 1w/1wk, 1mo) ke durasi, 0 jika tidak dikenal
+func IntervalToDuration(interval string) time.Duration {
+	switch interval {
+	case "1m":
+		return time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "30m":
+		return 30 * time.Minute
+	case "1h", "60m":
+		return time.Hour
+	case "2h":
+		return 2 * time.Hour
+	case "4h":
+		return 4 * time.Hour
+	case "6h":
+		return 6 * time.Hour
+	case "12h":
+		return 12 * time.Hour
+	case "1d":
+		return 24 * time.Hour
+	case "1w", "1wk":
+		return 7 * 24 * time.Hour
+	case "1mo", "1M":
+		return 30 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// CandleTimestampToTime timestamp candle binance dalam ms, yahoo finance dalam second
+func CandleTimestampToTime(exchange string, ts int64) time.Time {
+	if common.IsBinanceExchange(exchange) {
+		return time.UnixMilli(ts).UTC()
+	}
+	return time.Unix(ts, 0).UTC()
+}
+
+// TimeToCandleTimestamp kebalikan dari CandleTimestampToTime
+func TimeToCandleTimestamp(exchange string, t time.Time) int64 {
+	if common.IsBinanceExchange(exchange) {
+		return t.UnixMilli()
+	}
+	return t.Unix()
+}
@@ -293,7 +293,7 @@ func FormatPrice(price float64, exchange string) string {
 	case common.EXCHANGE_IDX:
 		formatted = fmt.Sprintf("%.0f", price)
 
-	case common.EXCHANGE_NASDAQ:
+	case common.EXCHANGE_NASDAQ, common.EXCHANGE_NYSE:
 		switch {
 		case price >= 1:
 			formatted = fmt.Sprintf("%.2f", price)
@@ -303,7 +303,7 @@ func FormatPrice(price float64, exchange string) string {
 			formatted = fmt.Sprintf("%.6f", price)
 		}
 
-	case common.EXCHANGE_BINANCE:
+	case common.EXCHANGE_BINANCE, common.EXCHANGE_BINANCE_FUTURES, common.EXCHANGE_BYBIT:
 		switch {
 		case price >= 1000:
 			formatted = fmt.Sprintf("%.2f", price)